@@ -2,24 +2,30 @@
 
 package mocks
 
-import mock "github.com/stretchr/testify/mock"
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
 
 // StoreInterface is an autogenerated mock type for the StoreInterface type
 type StoreInterface struct {
 	mock.Mock
 }
 
-// UpdateAnalytics provides a mock function with given fields: dishID, restaurantID
-func (_m *StoreInterface) UpdateAnalytics(dishID int, restaurantID int) error {
-	ret := _m.Called(dishID, restaurantID)
+// UpdateAnalytics provides a mock function with given fields: ctx, dishID, restaurantID, reviewedAt
+func (_m *StoreInterface) UpdateAnalytics(ctx context.Context, dishID int, restaurantID int, reviewedAt time.Time) error {
+	ret := _m.Called(ctx, dishID, restaurantID, reviewedAt)
 
 	if len(ret) == 0 {
 		panic("no return value specified for UpdateAnalytics")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(int, int) error); ok {
-		r0 = rf(dishID, restaurantID)
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, time.Time) error); ok {
+		r0 = rf(ctx, dishID, restaurantID, reviewedAt)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -27,17 +33,17 @@ func (_m *StoreInterface) UpdateAnalytics(dishID int, restaurantID int) error {
 	return r0
 }
 
-// UpdateDishRating provides a mock function with given fields: dishID, restaurantID
-func (_m *StoreInterface) UpdateDishRating(dishID int, restaurantID int) error {
-	ret := _m.Called(dishID, restaurantID)
+// UpdateDishRating provides a mock function with given fields: ctx, dishID, restaurantID
+func (_m *StoreInterface) UpdateDishRating(ctx context.Context, dishID int, restaurantID int) error {
+	ret := _m.Called(ctx, dishID, restaurantID)
 
 	if len(ret) == 0 {
 		panic("no return value specified for UpdateDishRating")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(int, int) error); ok {
-		r0 = rf(dishID, restaurantID)
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) error); ok {
+		r0 = rf(ctx, dishID, restaurantID)
 	} else {
 		r0 = ret.Error(0)
 	}
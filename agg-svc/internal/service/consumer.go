@@ -3,9 +3,13 @@ package service
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"log"
+	"time"
 
 	"overcooked-simplified/agg-svc/internal/domain"
+	"overcooked-simplified/metrics"
+	"overcooked-simplified/tracing"
 
 	"github.com/segmentio/kafka-go"
 )
@@ -26,39 +30,58 @@ func (c *Consumer) Start(ctx context.Context) {
 	log.Println("Starting Aggregation Service consumer...")
 	for {
 		message, err := c.Reader.ReadMessage(ctx)
+		if ctx.Err() != nil {
+			log.Println("Aggregation Service consumer stopped")
+			return
+		}
 		if err != nil {
 			log.Printf("Error reading message: %v", err)
 			continue
 		}
 
-		var msg domain.KafkaMessage
-		if err := json.Unmarshal(message.Value, &msg); err != nil {
-			log.Printf("Error unmarshaling message: %v", err)
-			continue
+		// a message in hand is finished even if ctx ends meanwhile
+		messageCtx, span := tracing.StartConsume(context.WithoutCancel(ctx), message)
+		err = c.handle(messageCtx, message)
+		if err != nil {
+			log.Printf("Error processing message at offset %d: %v", message.Offset, err)
 		}
+		metrics.KafkaConsumed(message, err)
+		tracing.End(span, err)
+	}
+}
 
-		if msg.Type == "new_review" {
-			c.ProcessReview(msg)
-		}
+func (c *Consumer) handle(ctx context.Context, message kafka.Message) error {
+	var msg domain.KafkaMessage
+	if err := json.Unmarshal(message.Value, &msg); err != nil {
+		return fmt.Errorf("unmarshal message: %w", err)
 	}
+	if msg.Type == "new_review" {
+		return c.ProcessReview(ctx, msg)
+	}
+	return nil
 }
 
-func (c *Consumer) ProcessReview(msg domain.KafkaMessage) {
+// ProcessReview recomputes the dish rating and the restaurant's analytics
+// for a new review. Other message types are ignored.
+func (c *Consumer) ProcessReview(ctx context.Context, msg domain.KafkaMessage) error {
 	if msg.Type != "new_review" {
-		return
+		return nil
 	}
 	log.Printf("Processing review: DishID=%d, RestaurantID=%d, Rating=%d",
 		msg.DishID, msg.RestaurantID, msg.Rating)
 
-	if err := c.Store.UpdateDishRating(msg.DishID, msg.RestaurantID); err != nil {
-		log.Printf("Error updating dish rating: %v", err)
-		return
+	if err := c.Store.UpdateDishRating(ctx, msg.DishID, msg.RestaurantID); err != nil {
+		return fmt.Errorf("update dish rating: %w", err)
 	}
 
-	if err := c.Store.UpdateAnalytics(msg.DishID, msg.RestaurantID); err != nil {
-		log.Printf("Error updating analytics: %v", err)
-		return
+	reviewedAt := msg.Timestamp
+	if reviewedAt.IsZero() {
+		reviewedAt = time.Now()
+	}
+	if err := c.Store.UpdateAnalytics(ctx, msg.DishID, msg.RestaurantID, reviewedAt); err != nil {
+		return fmt.Errorf("update analytics: %w", err)
 	}
 
 	log.Printf("Successfully processed review for dish %d", msg.DishID)
+	return nil
 }
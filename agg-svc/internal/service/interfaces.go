@@ -2,18 +2,20 @@ package service
 
 import (
 	"context"
+	"time"
+
 	"overcooked-simplified/agg-svc/internal/domain"
 	"overcooked-simplified/agg-svc/internal/storage"
 )
 
 type StoreInterface interface {
-	UpdateDishRating(dishID, restaurantID int) error
-	UpdateAnalytics(dishID, restaurantID int) error
+	UpdateDishRating(ctx context.Context, dishID, restaurantID int) error
+	UpdateAnalytics(ctx context.Context, dishID, restaurantID int, reviewedAt time.Time) error
 }
 
 type ConsumerInterface interface {
 	Start(ctx context.Context)
-	ProcessReview(msg domain.KafkaMessage)
+	ProcessReview(ctx context.Context, msg domain.KafkaMessage) error
 }
 
 var _ StoreInterface = (*storage.Store)(nil)
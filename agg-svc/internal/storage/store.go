@@ -13,19 +13,17 @@ import (
 type Store struct {
 	db  *sql.DB
 	rdb *redis.Client
-	ctx context.Context
 }
 
 func NewStore(db *sql.DB, rdb *redis.Client) *Store {
 	return &Store{
 		db:  db,
 		rdb: rdb,
-		ctx: context.Background(),
 	}
 }
 
-func (s *Store) UpdateDishRating(dishID, restaurantID int) error {
-	_, err := s.db.Exec(`
+func (s *Store) UpdateDishRating(ctx context.Context, dishID, restaurantID int) error {
+	_, err := s.db.ExecContext(ctx, `
 		UPDATE dishes
 		SET avg_rating = (
 			SELECT ROUND(AVG(rating::numeric), 2)
@@ -45,7 +43,7 @@ func (s *Store) UpdateDishRating(dishID, restaurantID int) error {
 
 	var avgRating float64
 	var reviewCount int
-	if err := s.db.QueryRow(`
+	if err := s.db.QueryRowContext(ctx, `
 		SELECT COALESCE(avg_rating, 0), COALESCE(review_count, 0)
 		FROM dishes
 		WHERE id = $1 AND restaurant_id = $2
@@ -54,31 +52,45 @@ func (s *Store) UpdateDishRating(dishID, restaurantID int) error {
 	}
 
 	key := fmt.Sprintf("dish:%d:%d", restaurantID, dishID)
-	s.rdb.HSet(s.ctx, key, map[string]interface{}{
+	s.rdb.HSet(ctx, key, map[string]interface{}{
 		"avg_rating":   avgRating,
 		"review_count": reviewCount,
 		"last_updated": time.Now().Unix(),
 	})
-	s.rdb.Expire(s.ctx, key, 24*time.Hour)
+	s.rdb.Expire(ctx, key, 24*time.Hour)
 	return nil
 }
 
-func (s *Store) UpdateAnalytics(dishID, restaurantID int) error {
-	today := time.Now().Format("2006-01-02")
-	dailyKey := fmt.Sprintf("analytics:daily:%s:%d", today, restaurantID)
-	s.rdb.ZIncrBy(s.ctx, dailyKey, 1, strconv.Itoa(dishID))
-	s.rdb.Expire(s.ctx, dailyKey, 7*24*time.Hour)
+// DailyKey is the daily ranking key for a review left at reviewedAt. The day
+// is taken in the restaurant's time zone, so a late-evening review counts
+// towards the evening it was left; an empty or unknown zone counts as UTC.
+func DailyKey(restaurantID int, reviewedAt time.Time, timeZone string) string {
+	loc, err := time.LoadLocation(timeZone)
+	if err != nil || timeZone == "" {
+		loc = time.UTC
+	}
+	return fmt.Sprintf("analytics:daily:%s:%d", reviewedAt.In(loc).Format("2006-01-02"), restaurantID)
+}
+
+func (s *Store) UpdateAnalytics(ctx context.Context, dishID, restaurantID int, reviewedAt time.Time) error {
+	var timeZone string
+	if err := s.db.QueryRowContext(ctx, "SELECT time_zone FROM restaurants WHERE id = $1", restaurantID).Scan(&timeZone); err != nil {
+		timeZone = ""
+	}
+	dailyKey := DailyKey(restaurantID, reviewedAt, timeZone)
+	s.rdb.ZIncrBy(ctx, dailyKey, 1, strconv.Itoa(dishID))
+	s.rdb.Expire(ctx, dailyKey, 7*24*time.Hour)
 
 	allTimeKey := fmt.Sprintf("analytics:alltime:%d", restaurantID)
 	var avgRating float64
-	if err := s.db.QueryRow(`
+	if err := s.db.QueryRowContext(ctx, `
 		SELECT COALESCE(avg_rating, 0)
 		FROM dishes
 		WHERE id = $1 AND restaurant_id = $2
 	`, dishID, restaurantID).Scan(&avgRating); err != nil {
 		return err
 	}
-	s.rdb.ZAdd(s.ctx, allTimeKey, redis.Z{
+	s.rdb.ZAdd(ctx, allTimeKey, redis.Z{
 		Score:  avgRating,
 		Member: strconv.Itoa(dishID),
 	})
@@ -1,19 +1,28 @@
 package tests
 
 import (
+	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"overcooked-simplified/agg-svc/internal/domain"
 	"overcooked-simplified/agg-svc/internal/mocks"
 	"overcooked-simplified/agg-svc/internal/service"
+	"overcooked-simplified/agg-svc/internal/storage"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
 func TestConsumer_ProcessReview(t *testing.T) {
+	reviewedAt := time.Date(2026, 10, 17, 21, 30, 0, 0, time.UTC)
+
 	tests := []struct {
 		name           string
 		inputMessage   domain.KafkaMessage
 		setupMockStore func(*mocks.StoreInterface)
+		wantErr        bool
 	}{
 		{
 			name: "success",
@@ -22,10 +31,11 @@ func TestConsumer_ProcessReview(t *testing.T) {
 				DishID:       1,
 				RestaurantID: 10,
 				Rating:       5,
+				Timestamp:    reviewedAt,
 			},
 			setupMockStore: func(mockStore *mocks.StoreInterface) {
-				mockStore.On("UpdateDishRating", 1, 10).Return(nil)
-				mockStore.On("UpdateAnalytics", 1, 10).Return(nil)
+				mockStore.On("UpdateDishRating", mock.Anything, 1, 10).Return(nil)
+				mockStore.On("UpdateAnalytics", mock.Anything, 1, 10, reviewedAt).Return(nil)
 			},
 		},
 		{
@@ -37,8 +47,9 @@ func TestConsumer_ProcessReview(t *testing.T) {
 				Rating:       5,
 			},
 			setupMockStore: func(mockStore *mocks.StoreInterface) {
-				mockStore.On("UpdateDishRating", 1, 10).Return(errors.New("db connection failed"))
+				mockStore.On("UpdateDishRating", mock.Anything, 1, 10).Return(errors.New("db connection failed"))
 			},
+			wantErr: true,
 		},
 		{
 			name: "UpdateAnalytics error",
@@ -49,9 +60,10 @@ func TestConsumer_ProcessReview(t *testing.T) {
 				Rating:       5,
 			},
 			setupMockStore: func(mockStore *mocks.StoreInterface) {
-				mockStore.On("UpdateDishRating", 1, 10).Return(nil)
-				mockStore.On("UpdateAnalytics", 1, 10).Return(errors.New("redis error"))
+				mockStore.On("UpdateDishRating", mock.Anything, 1, 10).Return(nil)
+				mockStore.On("UpdateAnalytics", mock.Anything, 1, 10, mock.AnythingOfType("time.Time")).Return(errors.New("redis error"))
 			},
+			wantErr: true,
 		},
 	}
 
@@ -64,7 +76,13 @@ func TestConsumer_ProcessReview(t *testing.T) {
 				Store: mockStore,
 			}
 
-			consumer.ProcessReview(testCase.inputMessage)
+			err := consumer.ProcessReview(context.Background(), testCase.inputMessage)
+
+			if testCase.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
 			mockStore.AssertExpectations(t)
 		})
 	}
@@ -83,7 +101,16 @@ func TestConsumer_InvalidMessageType(t *testing.T) {
 		Rating:       5,
 	}
 
-	consumer.ProcessReview(message)
+	assert.NoError(t, consumer.ProcessReview(context.Background(), message))
 	mockStore.AssertNotCalled(t, "UpdateDishRating")
 	mockStore.AssertNotCalled(t, "UpdateAnalytics")
 }
+
+func TestDailyKey(t *testing.T) {
+	// 21:30 UTC is already the next day in Moscow
+	reviewedAt := time.Date(2026, 10, 17, 21, 30, 0, 0, time.UTC)
+
+	assert.Equal(t, "analytics:daily:2026-10-18:10", storage.DailyKey(10, reviewedAt, "Europe/Moscow"))
+	assert.Equal(t, "analytics:daily:2026-10-17:10", storage.DailyKey(10, reviewedAt, "America/New_York"))
+	assert.Equal(t, "analytics:daily:2026-10-17:10", storage.DailyKey(10, reviewedAt, ""))
+}
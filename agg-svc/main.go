@@ -2,23 +2,83 @@ package main
 
 import (
 	"context"
+	"log"
+	"os"
+	"os/signal"
 	"overcooked-simplified/agg-svc/internal/service"
 	"overcooked-simplified/agg-svc/internal/storage"
+	"syscall"
+	_ "time/tzdata" // restaurant time zones; the alpine image has no zoneinfo
 
 	"overcooked-simplified/config"
+	"overcooked-simplified/database"
+	"overcooked-simplified/health"
+	"overcooked-simplified/metrics"
+	"overcooked-simplified/server"
+	"overcooked-simplified/tracing"
+
+	"github.com/gorilla/mux"
 )
 
 func main() {
-	db := config.MustInitPostgres()
-	defer db.Close()
+	cfg := config.MustLoad(":8084", config.NeedPostgres|config.NeedRedis|config.NeedKafka)
 
-	rdb := config.MustInitRedis()
-	defer rdb.Close()
+	shutdownTracing, err := tracing.Init("agg-svc", cfg.Tracing)
+	if err != nil {
+		log.Fatal("Failed to set up tracing: ", err)
+	}
 
-	store := storage.NewStore(db, rdb)
-	reader := config.NewKafkaReader("reviews", "agg-svc-consumer")
-	defer reader.Close()
+	db := config.MustInitPostgres(cfg.Postgres, cfg.Startup)
+
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		err := database.RunCommand(db, os.Args[2:])
+		db.Close()
+		if err != nil {
+			log.Fatal("Migration failed: ", err)
+		}
+		return
+	}
+	database.MustMigrate(db, cfg.Migrate.OnStart, cfg.Migrate.SeedDemoData)
+
+	rdb := config.MustInitRedis(cfg.Redis, cfg.Startup)
+
+	config.MustWaitForKafka(cfg.Kafka, cfg.Startup)
 
+	// the consumer has no API; the server is there for the probes and metrics
+	checker := health.NewChecker("agg-svc")
+	checker.Add("postgres", db.PingContext)
+	checker.Add("redis", func(ctx context.Context) error { return rdb.Ping(ctx).Err() })
+	checker.Add("kafka", func(ctx context.Context) error { return config.PingKafka(ctx, cfg.Kafka) })
+	router := mux.NewRouter()
+	metrics.Instrument(router)
+	checker.RegisterRoutes(router)
+
+	store := storage.NewStore(db, rdb)
+	reader := config.NewKafkaReader(cfg.Kafka, "reviews", "agg-svc-consumer")
 	consumer := service.NewConsumer(reader, store)
-	consumer.Start(context.Background())
+
+	// the consumer stops on the same signal as the server and finishes the
+	// message in hand before the reader and the pools are closed
+	ctx, stopConsumer := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stopConsumer()
+	consumed := make(chan struct{})
+	go func() {
+		defer close(consumed)
+		consumer.Start(ctx)
+	}()
+
+	srv := server.New("agg-svc", cfg.HTTP, router)
+	srv.Health = checker
+	srv.OnShutdown("consumer", func() error {
+		stopConsumer()
+		<-consumed
+		return nil
+	})
+	srv.OnShutdown("kafka reader", reader.Close)
+	srv.OnShutdown("redis", rdb.Close)
+	srv.OnShutdown("postgres", db.Close)
+	srv.OnShutdown("tracing", func() error { return shutdownTracing(context.Background()) })
+	if err := srv.Run(); err != nil {
+		log.Fatal("Server stopped with errors: ", err)
+	}
 }
@@ -2,16 +2,25 @@ package httpapi
 
 import (
 	"encoding/json"
+	"log"
 	"net/http"
 	"strconv"
 
+	"overcooked-simplified/analytics-svc/internal/domain"
 	"overcooked-simplified/analytics-svc/internal/service"
+	"overcooked-simplified/health"
+	"overcooked-simplified/i18n"
 
 	"github.com/gorilla/mux"
 )
 
 type Handler struct {
 	Analytics service.AnalyticsInterface
+	// Names translates dish names for Accept-Language and ?lang=; without it
+	// dishes keep the names they have in their restaurant's language.
+	Names service.DishNamer
+	// Health serves /livez and /readyz when set.
+	Health *health.Checker
 }
 
 func NewHandler(svc service.AnalyticsInterface) *Handler {
@@ -22,6 +31,9 @@ func (h *Handler) RegisterRoutes(r *mux.Router) {
 	r.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
 		json.NewEncoder(w).Encode(map[string]string{"status": "ok"})
 	}).Methods("GET")
+	if h.Health != nil {
+		h.Health.RegisterRoutes(r)
+	}
 	r.HandleFunc("/api/analytics/top-today", h.getTopToday).Methods("GET")
 	r.HandleFunc("/api/analytics/top-alltime", h.getTopAllTime).Methods("GET")
 	r.HandleFunc("/api/restaurants/{restaurantId}/analytics", h.getAnalytics).Methods("GET")
@@ -29,25 +41,31 @@ func (h *Handler) RegisterRoutes(r *mux.Router) {
 	r.HandleFunc("/api/restaurants/{restaurantId}/top-dishes", h.getTopDishes).Methods("GET")
 	r.HandleFunc("/api/restaurants/{restaurantId}/analytics/rating-distribution", h.getRatingDistribution).Methods("GET")
 	r.HandleFunc("/api/analytics/rating-distribution", h.getGlobalRatingDistribution).Methods("GET")
+	r.HandleFunc("/api/restaurants/{restaurantId}/analytics/dishes/{dishId}/variants", h.getVariantRatings).Methods("GET")
+	r.HandleFunc("/api/restaurants/{restaurantId}/analytics/dishes/{dishId}/price-changes", h.getPriceImpact).Methods("GET")
+	r.HandleFunc("/api/restaurants/{restaurantId}/analytics/tables", h.getTableRatings).Methods("GET")
+	r.HandleFunc("/api/restaurants/{restaurantId}/analytics/sections", h.getSectionRatings).Methods("GET")
 }
 
 func (h *Handler) getTopToday(w http.ResponseWriter, r *http.Request) {
-	data, err := h.Analytics.TopToday()
+	data, err := h.Analytics.TopToday(r.Context())
 	if err != nil {
 		w.WriteHeader(http.StatusOK)
 		json.NewEncoder(w).Encode([]interface{}{})
 		return
 	}
+	h.localizeNames(w, r, dishRefs(data)...)
 	json.NewEncoder(w).Encode(data)
 }
 
 func (h *Handler) getTopAllTime(w http.ResponseWriter, r *http.Request) {
-	data, err := h.Analytics.TopAllTime()
+	data, err := h.Analytics.TopAllTime(r.Context())
 	if err != nil {
 		w.WriteHeader(http.StatusOK)
 		json.NewEncoder(w).Encode([]interface{}{})
 		return
 	}
+	h.localizeNames(w, r, dishRefs(data)...)
 	json.NewEncoder(w).Encode(data)
 }
 
@@ -57,14 +75,15 @@ func (h *Handler) getAnalytics(w http.ResponseWriter, r *http.Request) {
 	if period == "" {
 		period = "all"
 	}
-	response := h.Analytics.AnalyticsForRestaurant(restaurantID, period)
+	response := h.Analytics.AnalyticsForRestaurant(r.Context(), restaurantID, period)
+	h.localizeNames(w, r, response.MostPopularDish, response.BestRatedDish, response.MostPopularToday)
 	json.NewEncoder(w).Encode(response)
 }
 
 func (h *Handler) getDishStats(w http.ResponseWriter, r *http.Request) {
 	restaurantID, _ := strconv.Atoi(mux.Vars(r)["restaurantId"])
 	dishID, _ := strconv.Atoi(mux.Vars(r)["dishId"])
-	stats, err := h.Analytics.DishStats(restaurantID, dishID)
+	stats, err := h.Analytics.DishStats(r.Context(), restaurantID, dishID)
 	if err != nil || stats == nil {
 		http.Error(w, "Dish stats not found", http.StatusNotFound)
 		return
@@ -79,17 +98,93 @@ func (h *Handler) getTopDishes(w http.ResponseWriter, r *http.Request) {
 		limitStr = "10"
 	}
 	limit, _ := strconv.Atoi(limitStr)
-	data, _ := h.Analytics.TopDishes(restaurantID, limit)
+	data, _ := h.Analytics.TopDishes(r.Context(), restaurantID, limit)
+	h.localizeNames(w, r, dishRefs(data)...)
 	json.NewEncoder(w).Encode(data)
 }
 
 func (h *Handler) getRatingDistribution(w http.ResponseWriter, r *http.Request) {
 	restaurantID, _ := strconv.Atoi(mux.Vars(r)["restaurantId"])
-	data, _ := h.Analytics.RatingDistribution(restaurantID)
+	data, _ := h.Analytics.RatingDistribution(r.Context(), restaurantID)
 	json.NewEncoder(w).Encode(data)
 }
 
 func (h *Handler) getGlobalRatingDistribution(w http.ResponseWriter, r *http.Request) {
-	data, _ := h.Analytics.GlobalDistribution()
+	data, _ := h.Analytics.GlobalDistribution(r.Context())
 	json.NewEncoder(w).Encode(data)
 }
+
+func (h *Handler) getVariantRatings(w http.ResponseWriter, r *http.Request) {
+	restaurantID, _ := strconv.Atoi(mux.Vars(r)["restaurantId"])
+	dishID, _ := strconv.Atoi(mux.Vars(r)["dishId"])
+	data, err := h.Analytics.VariantRatings(r.Context(), restaurantID, dishID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	json.NewEncoder(w).Encode(data)
+}
+
+// defaultPriceWindowDays is how far around a price change getPriceImpact
+// looks when the request does not set window_days.
+const defaultPriceWindowDays = 30
+
+func (h *Handler) getPriceImpact(w http.ResponseWriter, r *http.Request) {
+	restaurantID, _ := strconv.Atoi(mux.Vars(r)["restaurantId"])
+	dishID, _ := strconv.Atoi(mux.Vars(r)["dishId"])
+	windowDays := defaultPriceWindowDays
+	if raw := r.URL.Query().Get("window_days"); raw != "" {
+		days, err := strconv.Atoi(raw)
+		if err != nil || days <= 0 || days > 365 {
+			http.Error(w, "window_days must be between 1 and 365", http.StatusBadRequest)
+			return
+		}
+		windowDays = days
+	}
+	data, err := h.Analytics.PriceImpact(r.Context(), restaurantID, dishID, windowDays)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	json.NewEncoder(w).Encode(data)
+}
+
+func (h *Handler) getTableRatings(w http.ResponseWriter, r *http.Request) {
+	restaurantID, _ := strconv.Atoi(mux.Vars(r)["restaurantId"])
+	data, err := h.Analytics.TableRatings(r.Context(), restaurantID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	json.NewEncoder(w).Encode(data)
+}
+
+func (h *Handler) getSectionRatings(w http.ResponseWriter, r *http.Request) {
+	restaurantID, _ := strconv.Atoi(mux.Vars(r)["restaurantId"])
+	data, err := h.Analytics.SectionRatings(r.Context(), restaurantID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	json.NewEncoder(w).Encode(data)
+}
+
+// localizeNames translates dish names in place; nil dishes are skipped. A
+// failed lookup leaves the names as they are.
+func (h *Handler) localizeNames(w http.ResponseWriter, r *http.Request, dishes ...*domain.DishAnalytics) {
+	w.Header().Add("Vary", "Accept-Language")
+	if h.Names == nil {
+		return
+	}
+	if err := h.Names.LocalizeDishNames(r.Context(), dishes, i18n.Requested(r)); err != nil {
+		log.Printf("localize dish names: %v", err)
+	}
+}
+
+func dishRefs(dishes []domain.DishAnalytics) []*domain.DishAnalytics {
+	refs := make([]*domain.DishAnalytics, len(dishes))
+	for i := range dishes {
+		refs[i] = &dishes[i]
+	}
+	return refs
+}
@@ -1,20 +1,19 @@
 package httpapi
 
 import (
-	"log"
 	"net/http"
 
+	"overcooked-simplified/metrics"
+	"overcooked-simplified/tracing"
+
 	"github.com/gorilla/mux"
 	"github.com/rs/cors"
 )
 
 func NewRouter(handler *Handler) http.Handler {
 	r := mux.NewRouter()
+	metrics.Instrument(r)
+	tracing.Instrument(r)
 	handler.RegisterRoutes(r)
 	return cors.Default().Handler(r)
 }
-
-func StartServer(addr string, handler http.Handler) {
-	log.Printf("Analytics Service starting on %s", addr)
-	log.Fatal(http.ListenAndServe(addr, handler))
-}
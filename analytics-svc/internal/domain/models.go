@@ -1,5 +1,7 @@
 package domain
 
+import "time"
+
 type DishAnalytics struct {
 	DishID       int     `json:"dish_id"`
 	DishName     string  `json:"dish_name"`
@@ -8,6 +10,54 @@ type DishAnalytics struct {
 	ReviewCount  int     `json:"review_count"`
 }
 
+// VariantRating aggregates reviews of a dish by the modifier options that were
+// ordered with it. Variant is empty for the dish ordered without options.
+type VariantRating struct {
+	Variant     string  `json:"variant"`
+	AvgRating   float64 `json:"avg_rating"`
+	ReviewCount int     `json:"review_count"`
+}
+
+// PriceChangeImpact compares a dish before and after one price change. Both
+// periods are WindowDays long but never reach past the neighbouring changes.
+type PriceChangeImpact struct {
+	OldPrice      float64   `json:"old_price"`
+	NewPrice      float64   `json:"new_price"`
+	ChangedAt     time.Time `json:"changed_at"`
+	WindowDays    int       `json:"window_days"`
+	RatingBefore  float64   `json:"avg_rating_before"`
+	RatingAfter   float64   `json:"avg_rating_after"`
+	ReviewsBefore int       `json:"reviews_before"`
+	ReviewsAfter  int       `json:"reviews_after"`
+	OrderedBefore int       `json:"ordered_before"`
+	OrderedAfter  int       `json:"ordered_after"`
+}
+
+// TableRating sums up the guests' experience at one table: ratings of the
+// visit left from the table QR code and ratings of dishes ordered there.
+type TableRating struct {
+	TableID        int     `json:"table_id"`
+	Label          string  `json:"label"`
+	Section        string  `json:"section"`
+	Orders         int     `json:"orders"`
+	AvgVisitRating float64 `json:"avg_visit_rating"`
+	VisitRatings   int     `json:"visit_ratings"`
+	AvgDishRating  float64 `json:"avg_dish_rating"`
+	DishReviews    int     `json:"dish_reviews"`
+}
+
+// SectionRating is TableRating summed over the tables of a section, e.g. a
+// waiter's zone. Averages are weighted by the number of ratings.
+type SectionRating struct {
+	Section        string  `json:"section"`
+	Tables         int     `json:"tables"`
+	Orders         int     `json:"orders"`
+	AvgVisitRating float64 `json:"avg_visit_rating"`
+	VisitRatings   int     `json:"visit_ratings"`
+	AvgDishRating  float64 `json:"avg_dish_rating"`
+	DishReviews    int     `json:"dish_reviews"`
+}
+
 type AnalyticsResponse struct {
 	MostPopularDish  *DishAnalytics `json:"most_popular_dish,omitempty"`
 	BestRatedDish    *DishAnalytics `json:"best_rated_dish,omitempty"`
@@ -3,6 +3,7 @@
 package mocks
 
 import (
+	context "context"
 	domain "overcooked-simplified/analytics-svc/internal/domain"
 
 	mock "github.com/stretchr/testify/mock"
@@ -13,17 +14,17 @@ type AnalyticsInterface struct {
 	mock.Mock
 }
 
-// AnalyticsForRestaurant provides a mock function with given fields: restaurantID, period
-func (_m *AnalyticsInterface) AnalyticsForRestaurant(restaurantID int, period string) domain.AnalyticsResponse {
-	ret := _m.Called(restaurantID, period)
+// AnalyticsForRestaurant provides a mock function with given fields: ctx, restaurantID, period
+func (_m *AnalyticsInterface) AnalyticsForRestaurant(ctx context.Context, restaurantID int, period string) domain.AnalyticsResponse {
+	ret := _m.Called(ctx, restaurantID, period)
 
 	if len(ret) == 0 {
 		panic("no return value specified for AnalyticsForRestaurant")
 	}
 
 	var r0 domain.AnalyticsResponse
-	if rf, ok := ret.Get(0).(func(int, string) domain.AnalyticsResponse); ok {
-		r0 = rf(restaurantID, period)
+	if rf, ok := ret.Get(0).(func(context.Context, int, string) domain.AnalyticsResponse); ok {
+		r0 = rf(ctx, restaurantID, period)
 	} else {
 		r0 = ret.Get(0).(domain.AnalyticsResponse)
 	}
@@ -31,9 +32,9 @@ func (_m *AnalyticsInterface) AnalyticsForRestaurant(restaurantID int, period st
 	return r0
 }
 
-// DishStats provides a mock function with given fields: restaurantID, dishID
-func (_m *AnalyticsInterface) DishStats(restaurantID int, dishID int) (map[string]interface{}, error) {
-	ret := _m.Called(restaurantID, dishID)
+// DishStats provides a mock function with given fields: ctx, restaurantID, dishID
+func (_m *AnalyticsInterface) DishStats(ctx context.Context, restaurantID int, dishID int) (map[string]interface{}, error) {
+	ret := _m.Called(ctx, restaurantID, dishID)
 
 	if len(ret) == 0 {
 		panic("no return value specified for DishStats")
@@ -41,19 +42,19 @@ func (_m *AnalyticsInterface) DishStats(restaurantID int, dishID int) (map[strin
 
 	var r0 map[string]interface{}
 	var r1 error
-	if rf, ok := ret.Get(0).(func(int, int) (map[string]interface{}, error)); ok {
-		return rf(restaurantID, dishID)
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) (map[string]interface{}, error)); ok {
+		return rf(ctx, restaurantID, dishID)
 	}
-	if rf, ok := ret.Get(0).(func(int, int) map[string]interface{}); ok {
-		r0 = rf(restaurantID, dishID)
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) map[string]interface{}); ok {
+		r0 = rf(ctx, restaurantID, dishID)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(map[string]interface{})
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(int, int) error); ok {
-		r1 = rf(restaurantID, dishID)
+	if rf, ok := ret.Get(1).(func(context.Context, int, int) error); ok {
+		r1 = rf(ctx, restaurantID, dishID)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -61,9 +62,9 @@ func (_m *AnalyticsInterface) DishStats(restaurantID int, dishID int) (map[strin
 	return r0, r1
 }
 
-// GlobalDistribution provides a mock function with no fields
-func (_m *AnalyticsInterface) GlobalDistribution() (map[string]int, error) {
-	ret := _m.Called()
+// GlobalDistribution provides a mock function with given fields: ctx
+func (_m *AnalyticsInterface) GlobalDistribution(ctx context.Context) (map[string]int, error) {
+	ret := _m.Called(ctx)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GlobalDistribution")
@@ -71,19 +72,19 @@ func (_m *AnalyticsInterface) GlobalDistribution() (map[string]int, error) {
 
 	var r0 map[string]int
 	var r1 error
-	if rf, ok := ret.Get(0).(func() (map[string]int, error)); ok {
-		return rf()
+	if rf, ok := ret.Get(0).(func(context.Context) (map[string]int, error)); ok {
+		return rf(ctx)
 	}
-	if rf, ok := ret.Get(0).(func() map[string]int); ok {
-		r0 = rf()
+	if rf, ok := ret.Get(0).(func(context.Context) map[string]int); ok {
+		r0 = rf(ctx)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(map[string]int)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func() error); ok {
-		r1 = rf()
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -91,9 +92,39 @@ func (_m *AnalyticsInterface) GlobalDistribution() (map[string]int, error) {
 	return r0, r1
 }
 
-// RatingDistribution provides a mock function with given fields: restaurantID
-func (_m *AnalyticsInterface) RatingDistribution(restaurantID int) (map[string]int, error) {
-	ret := _m.Called(restaurantID)
+// PriceImpact provides a mock function with given fields: ctx, restaurantID, dishID, windowDays
+func (_m *AnalyticsInterface) PriceImpact(ctx context.Context, restaurantID int, dishID int, windowDays int) ([]domain.PriceChangeImpact, error) {
+	ret := _m.Called(ctx, restaurantID, dishID, windowDays)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PriceImpact")
+	}
+
+	var r0 []domain.PriceChangeImpact
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, int) ([]domain.PriceChangeImpact, error)); ok {
+		return rf(ctx, restaurantID, dishID, windowDays)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, int) []domain.PriceChangeImpact); ok {
+		r0 = rf(ctx, restaurantID, dishID, windowDays)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.PriceChangeImpact)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int, int) error); ok {
+		r1 = rf(ctx, restaurantID, dishID, windowDays)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RatingDistribution provides a mock function with given fields: ctx, restaurantID
+func (_m *AnalyticsInterface) RatingDistribution(ctx context.Context, restaurantID int) (map[string]int, error) {
+	ret := _m.Called(ctx, restaurantID)
 
 	if len(ret) == 0 {
 		panic("no return value specified for RatingDistribution")
@@ -101,19 +132,49 @@ func (_m *AnalyticsInterface) RatingDistribution(restaurantID int) (map[string]i
 
 	var r0 map[string]int
 	var r1 error
-	if rf, ok := ret.Get(0).(func(int) (map[string]int, error)); ok {
-		return rf(restaurantID)
+	if rf, ok := ret.Get(0).(func(context.Context, int) (map[string]int, error)); ok {
+		return rf(ctx, restaurantID)
 	}
-	if rf, ok := ret.Get(0).(func(int) map[string]int); ok {
-		r0 = rf(restaurantID)
+	if rf, ok := ret.Get(0).(func(context.Context, int) map[string]int); ok {
+		r0 = rf(ctx, restaurantID)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(map[string]int)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(int) error); ok {
-		r1 = rf(restaurantID)
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, restaurantID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SectionRatings provides a mock function with given fields: ctx, restaurantID
+func (_m *AnalyticsInterface) SectionRatings(ctx context.Context, restaurantID int) ([]domain.SectionRating, error) {
+	ret := _m.Called(ctx, restaurantID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SectionRatings")
+	}
+
+	var r0 []domain.SectionRating
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) ([]domain.SectionRating, error)); ok {
+		return rf(ctx, restaurantID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) []domain.SectionRating); ok {
+		r0 = rf(ctx, restaurantID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.SectionRating)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, restaurantID)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -121,9 +182,39 @@ func (_m *AnalyticsInterface) RatingDistribution(restaurantID int) (map[string]i
 	return r0, r1
 }
 
-// TopAllTime provides a mock function with no fields
-func (_m *AnalyticsInterface) TopAllTime() ([]domain.DishAnalytics, error) {
-	ret := _m.Called()
+// TableRatings provides a mock function with given fields: ctx, restaurantID
+func (_m *AnalyticsInterface) TableRatings(ctx context.Context, restaurantID int) ([]domain.TableRating, error) {
+	ret := _m.Called(ctx, restaurantID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for TableRatings")
+	}
+
+	var r0 []domain.TableRating
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) ([]domain.TableRating, error)); ok {
+		return rf(ctx, restaurantID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) []domain.TableRating); ok {
+		r0 = rf(ctx, restaurantID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.TableRating)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, restaurantID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// TopAllTime provides a mock function with given fields: ctx
+func (_m *AnalyticsInterface) TopAllTime(ctx context.Context) ([]domain.DishAnalytics, error) {
+	ret := _m.Called(ctx)
 
 	if len(ret) == 0 {
 		panic("no return value specified for TopAllTime")
@@ -131,19 +222,19 @@ func (_m *AnalyticsInterface) TopAllTime() ([]domain.DishAnalytics, error) {
 
 	var r0 []domain.DishAnalytics
 	var r1 error
-	if rf, ok := ret.Get(0).(func() ([]domain.DishAnalytics, error)); ok {
-		return rf()
+	if rf, ok := ret.Get(0).(func(context.Context) ([]domain.DishAnalytics, error)); ok {
+		return rf(ctx)
 	}
-	if rf, ok := ret.Get(0).(func() []domain.DishAnalytics); ok {
-		r0 = rf()
+	if rf, ok := ret.Get(0).(func(context.Context) []domain.DishAnalytics); ok {
+		r0 = rf(ctx)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]domain.DishAnalytics)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func() error); ok {
-		r1 = rf()
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -151,9 +242,9 @@ func (_m *AnalyticsInterface) TopAllTime() ([]domain.DishAnalytics, error) {
 	return r0, r1
 }
 
-// TopDishes provides a mock function with given fields: restaurantID, limit
-func (_m *AnalyticsInterface) TopDishes(restaurantID int, limit int) ([]domain.DishAnalytics, error) {
-	ret := _m.Called(restaurantID, limit)
+// TopDishes provides a mock function with given fields: ctx, restaurantID, limit
+func (_m *AnalyticsInterface) TopDishes(ctx context.Context, restaurantID int, limit int) ([]domain.DishAnalytics, error) {
+	ret := _m.Called(ctx, restaurantID, limit)
 
 	if len(ret) == 0 {
 		panic("no return value specified for TopDishes")
@@ -161,19 +252,19 @@ func (_m *AnalyticsInterface) TopDishes(restaurantID int, limit int) ([]domain.D
 
 	var r0 []domain.DishAnalytics
 	var r1 error
-	if rf, ok := ret.Get(0).(func(int, int) ([]domain.DishAnalytics, error)); ok {
-		return rf(restaurantID, limit)
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) ([]domain.DishAnalytics, error)); ok {
+		return rf(ctx, restaurantID, limit)
 	}
-	if rf, ok := ret.Get(0).(func(int, int) []domain.DishAnalytics); ok {
-		r0 = rf(restaurantID, limit)
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) []domain.DishAnalytics); ok {
+		r0 = rf(ctx, restaurantID, limit)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]domain.DishAnalytics)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(int, int) error); ok {
-		r1 = rf(restaurantID, limit)
+	if rf, ok := ret.Get(1).(func(context.Context, int, int) error); ok {
+		r1 = rf(ctx, restaurantID, limit)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -181,9 +272,9 @@ func (_m *AnalyticsInterface) TopDishes(restaurantID int, limit int) ([]domain.D
 	return r0, r1
 }
 
-// TopToday provides a mock function with no fields
-func (_m *AnalyticsInterface) TopToday() ([]domain.DishAnalytics, error) {
-	ret := _m.Called()
+// TopToday provides a mock function with given fields: ctx
+func (_m *AnalyticsInterface) TopToday(ctx context.Context) ([]domain.DishAnalytics, error) {
+	ret := _m.Called(ctx)
 
 	if len(ret) == 0 {
 		panic("no return value specified for TopToday")
@@ -191,19 +282,49 @@ func (_m *AnalyticsInterface) TopToday() ([]domain.DishAnalytics, error) {
 
 	var r0 []domain.DishAnalytics
 	var r1 error
-	if rf, ok := ret.Get(0).(func() ([]domain.DishAnalytics, error)); ok {
-		return rf()
+	if rf, ok := ret.Get(0).(func(context.Context) ([]domain.DishAnalytics, error)); ok {
+		return rf(ctx)
 	}
-	if rf, ok := ret.Get(0).(func() []domain.DishAnalytics); ok {
-		r0 = rf()
+	if rf, ok := ret.Get(0).(func(context.Context) []domain.DishAnalytics); ok {
+		r0 = rf(ctx)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]domain.DishAnalytics)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func() error); ok {
-		r1 = rf()
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// VariantRatings provides a mock function with given fields: ctx, restaurantID, dishID
+func (_m *AnalyticsInterface) VariantRatings(ctx context.Context, restaurantID int, dishID int) ([]domain.VariantRating, error) {
+	ret := _m.Called(ctx, restaurantID, dishID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for VariantRatings")
+	}
+
+	var r0 []domain.VariantRating
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) ([]domain.VariantRating, error)); ok {
+		return rf(ctx, restaurantID, dishID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) []domain.VariantRating); ok {
+		r0 = rf(ctx, restaurantID, dishID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.VariantRating)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int) error); ok {
+		r1 = rf(ctx, restaurantID, dishID)
 	} else {
 		r1 = ret.Error(1)
 	}
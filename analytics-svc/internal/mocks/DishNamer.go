@@ -0,0 +1,49 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	domain "overcooked-simplified/analytics-svc/internal/domain"
+
+	language "golang.org/x/text/language"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// DishNamer is an autogenerated mock type for the DishNamer type
+type DishNamer struct {
+	mock.Mock
+}
+
+// LocalizeDishNames provides a mock function with given fields: ctx, dishes, requested
+func (_m *DishNamer) LocalizeDishNames(ctx context.Context, dishes []*domain.DishAnalytics, requested []language.Tag) error {
+	ret := _m.Called(ctx, dishes, requested)
+
+	if len(ret) == 0 {
+		panic("no return value specified for LocalizeDishNames")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, []*domain.DishAnalytics, []language.Tag) error); ok {
+		r0 = rf(ctx, dishes, requested)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewDishNamer creates a new instance of DishNamer. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewDishNamer(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *DishNamer {
+	mock := &DishNamer{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -6,7 +6,6 @@ import (
 	"sort"
 	"strconv"
 	"strings"
-	"time"
 
 	"overcooked-simplified/analytics-svc/internal/domain"
 
@@ -16,29 +15,25 @@ import (
 type AnalyticsService struct {
 	db  *sql.DB
 	rdb *redis.Client
-	ctx context.Context
 }
 
 func NewAnalyticsService(db *sql.DB, rdb *redis.Client) *AnalyticsService {
 	return &AnalyticsService{
 		db:  db,
 		rdb: rdb,
-		ctx: context.Background(),
 	}
 }
 
-func (s *AnalyticsService) TopToday() ([]domain.DishAnalytics, error) {
-	today := time.Now().Format("2006-01-02")
-	pattern := "analytics:daily:" + today + ":*"
-	keys, err := s.rdb.Keys(s.ctx, pattern).Result()
-
+func (s *AnalyticsService) TopToday(ctx context.Context) ([]domain.DishAnalytics, error) {
+	// every restaurant has its own "today", so its key is built separately
+	keys, err := s.todayKeys(ctx)
 	if err != nil || len(keys) == 0 {
-		return s.topTodayFromDB()
+		return s.topTodayFromDB(ctx)
 	}
 
 	var all []domain.DishAnalytics
 	for _, key := range keys {
-		result, err := s.rdb.ZRevRangeWithScores(s.ctx, key, 0, 4).Result()
+		result, err := s.rdb.ZRevRangeWithScores(ctx, key, 0, 4).Result()
 		if err != nil || len(result) == 0 {
 			continue
 		}
@@ -46,7 +41,7 @@ func (s *AnalyticsService) TopToday() ([]domain.DishAnalytics, error) {
 			dishID, _ := strconv.Atoi(member.Member.(string))
 			var dishName string
 			var restID int
-			if err := s.db.QueryRow("SELECT name, restaurant_id FROM dishes WHERE id = $1", dishID).Scan(&dishName, &restID); err != nil {
+			if err := s.db.QueryRowContext(ctx, "SELECT name, restaurant_id FROM dishes WHERE id = $1", dishID).Scan(&dishName, &restID); err != nil {
 				continue
 			}
 			all = append(all, domain.DishAnalytics{
@@ -59,7 +54,7 @@ func (s *AnalyticsService) TopToday() ([]domain.DishAnalytics, error) {
 	}
 
 	if len(all) == 0 {
-		return s.topTodayFromDB()
+		return s.topTodayFromDB(ctx)
 	}
 
 	sort.Slice(all, func(i, j int) bool { return all[i].Score > all[j].Score })
@@ -69,13 +64,14 @@ func (s *AnalyticsService) TopToday() ([]domain.DishAnalytics, error) {
 	return all, nil
 }
 
-func (s *AnalyticsService) topTodayFromDB() ([]domain.DishAnalytics, error) {
-	rows, err := s.db.Query(`
+func (s *AnalyticsService) topTodayFromDB(ctx context.Context) ([]domain.DishAnalytics, error) {
+	rows, err := s.db.QueryContext(ctx, `
 		SELECT d.id, d.name, d.restaurant_id, COUNT(oi.id) as score
 		FROM dishes d
 		JOIN order_items oi ON d.id = oi.dish_id
 		JOIN orders o ON oi.order_id = o.id
-		WHERE o.created_at::date = CURRENT_DATE
+		JOIN restaurants r ON r.id = o.restaurant_id
+		WHERE (o.created_at AT TIME ZONE 'UTC' AT TIME ZONE r.time_zone)::date = (CURRENT_TIMESTAMP AT TIME ZONE r.time_zone)::date
 		GROUP BY d.id, d.name, d.restaurant_id
 		ORDER BY score DESC
 		LIMIT 10
@@ -96,15 +92,15 @@ func (s *AnalyticsService) topTodayFromDB() ([]domain.DishAnalytics, error) {
 	return dishes, nil
 }
 
-func (s *AnalyticsService) TopAllTime() ([]domain.DishAnalytics, error) {
-	keys, err := s.rdb.Keys(s.ctx, "analytics:alltime:*").Result()
+func (s *AnalyticsService) TopAllTime(ctx context.Context) ([]domain.DishAnalytics, error) {
+	keys, err := s.rdb.Keys(ctx, "analytics:alltime:*").Result()
 	if err != nil || len(keys) == 0 {
-		return s.topAllTimeFromDB()
+		return s.topAllTimeFromDB(ctx)
 	}
 
 	var all []domain.DishAnalytics
 	for _, key := range keys {
-		result, err := s.rdb.ZRevRangeWithScores(s.ctx, key, 0, 9).Result()
+		result, err := s.rdb.ZRevRangeWithScores(ctx, key, 0, 9).Result()
 		if err != nil {
 			continue
 		}
@@ -113,7 +109,7 @@ func (s *AnalyticsService) TopAllTime() ([]domain.DishAnalytics, error) {
 			var dishName string
 			var restID, reviewCount int
 
-			if err := s.db.QueryRow("SELECT name, restaurant_id, COALESCE(review_count,0) FROM dishes WHERE id = $1", dishID).
+			if err := s.db.QueryRowContext(ctx, "SELECT name, restaurant_id, COALESCE(review_count,0) FROM dishes WHERE id = $1", dishID).
 				Scan(&dishName, &restID, &reviewCount); err != nil {
 				continue
 			}
@@ -135,8 +131,8 @@ func (s *AnalyticsService) TopAllTime() ([]domain.DishAnalytics, error) {
 	return all, nil
 }
 
-func (s *AnalyticsService) topAllTimeFromDB() ([]domain.DishAnalytics, error) {
-	rows, err := s.db.Query(`
+func (s *AnalyticsService) topAllTimeFromDB(ctx context.Context) ([]domain.DishAnalytics, error) {
+	rows, err := s.db.QueryContext(ctx, `
 		SELECT id, name, restaurant_id, COALESCE(avg_rating, 0) as score, review_count
 		FROM dishes
 		WHERE avg_rating > 0
@@ -157,15 +153,17 @@ func (s *AnalyticsService) topAllTimeFromDB() ([]domain.DishAnalytics, error) {
 	return dishes, nil
 }
 
-func (s *AnalyticsService) MostPopularDish(restaurantID int, date string) (*domain.DishAnalytics, error) {
+// MostPopularDish reads the daily ranking of the restaurant; date is a day in
+// the restaurant's own time zone.
+func (s *AnalyticsService) MostPopularDish(ctx context.Context, restaurantID int, date string) (*domain.DishAnalytics, error) {
 	dailyKey := "analytics:daily:" + date + ":" + strconv.Itoa(restaurantID)
-	result, err := s.rdb.ZRevRangeWithScores(s.ctx, dailyKey, 0, 0).Result()
+	result, err := s.rdb.ZRevRangeWithScores(ctx, dailyKey, 0, 0).Result()
 	if err != nil || len(result) == 0 {
 		return nil, nil
 	}
 	dishID, _ := strconv.Atoi(result[0].Member.(string))
 	var dishName string
-	if err := s.db.QueryRow("SELECT name FROM dishes WHERE id = $1", dishID).Scan(&dishName); err != nil {
+	if err := s.db.QueryRowContext(ctx, "SELECT name FROM dishes WHERE id = $1", dishID).Scan(&dishName); err != nil {
 		return nil, nil
 	}
 	return &domain.DishAnalytics{
@@ -176,9 +174,9 @@ func (s *AnalyticsService) MostPopularDish(restaurantID int, date string) (*doma
 	}, nil
 }
 
-func (s *AnalyticsService) BestRatedDish(restaurantID int) (*domain.DishAnalytics, error) {
+func (s *AnalyticsService) BestRatedDish(ctx context.Context, restaurantID int) (*domain.DishAnalytics, error) {
 	allTimeKey := "analytics:alltime:" + strconv.Itoa(restaurantID)
-	result, err := s.rdb.ZRevRangeWithScores(s.ctx, allTimeKey, 0, 0).Result()
+	result, err := s.rdb.ZRevRangeWithScores(ctx, allTimeKey, 0, 0).Result()
 	if err != nil || len(result) == 0 {
 		return nil, nil
 	}
@@ -186,7 +184,7 @@ func (s *AnalyticsService) BestRatedDish(restaurantID int) (*domain.DishAnalytic
 
 	var dishName string
 	var reviewCount int
-	if err := s.db.QueryRow(`
+	if err := s.db.QueryRowContext(ctx, `
 		SELECT name, COALESCE(review_count, 0)
 		FROM dishes
 		WHERE id = $1 AND restaurant_id = $2
@@ -203,38 +201,38 @@ func (s *AnalyticsService) BestRatedDish(restaurantID int) (*domain.DishAnalytic
 	}, nil
 }
 
-func (s *AnalyticsService) AnalyticsForRestaurant(restaurantID int, period string) domain.AnalyticsResponse {
+func (s *AnalyticsService) AnalyticsForRestaurant(ctx context.Context, restaurantID int, period string) domain.AnalyticsResponse {
 	response := domain.AnalyticsResponse{}
 	switch period {
 	case "today":
-		today := time.Now().Format("2006-01-02")
-		if popular, _ := s.MostPopularDish(restaurantID, today); popular != nil {
+		today := s.restaurantToday(ctx, restaurantID)
+		if popular, _ := s.MostPopularDish(ctx, restaurantID, today); popular != nil {
 			response.MostPopularToday = popular
 		}
 	case "day":
-		today := time.Now().Format("2006-01-02")
-		if popular, _ := s.MostPopularDish(restaurantID, today); popular != nil {
+		today := s.restaurantToday(ctx, restaurantID)
+		if popular, _ := s.MostPopularDish(ctx, restaurantID, today); popular != nil {
 			response.MostPopularDish = popular
 		}
 	case "all":
-		if best, _ := s.BestRatedDish(restaurantID); best != nil {
+		if best, _ := s.BestRatedDish(ctx, restaurantID); best != nil {
 			response.BestRatedDish = best
 		}
 	default:
-		today := time.Now().Format("2006-01-02")
-		if popular, _ := s.MostPopularDish(restaurantID, today); popular != nil {
+		today := s.restaurantToday(ctx, restaurantID)
+		if popular, _ := s.MostPopularDish(ctx, restaurantID, today); popular != nil {
 			response.MostPopularToday = popular
 		}
-		if best, _ := s.BestRatedDish(restaurantID); best != nil {
+		if best, _ := s.BestRatedDish(ctx, restaurantID); best != nil {
 			response.BestRatedDish = best
 		}
 	}
 	return response
 }
 
-func (s *AnalyticsService) DishStats(restaurantID, dishID int) (map[string]interface{}, error) {
+func (s *AnalyticsService) DishStats(ctx context.Context, restaurantID, dishID int) (map[string]interface{}, error) {
 	dishKey := "dish:" + strconv.Itoa(restaurantID) + ":" + strconv.Itoa(dishID)
-	stats, err := s.rdb.HGetAll(s.ctx, dishKey).Result()
+	stats, err := s.rdb.HGetAll(ctx, dishKey).Result()
 	if err != nil || len(stats) == 0 {
 		return nil, err
 	}
@@ -248,9 +246,9 @@ func (s *AnalyticsService) DishStats(restaurantID, dishID int) (map[string]inter
 	}, nil
 }
 
-func (s *AnalyticsService) TopDishes(restaurantID, limit int) ([]domain.DishAnalytics, error) {
+func (s *AnalyticsService) TopDishes(ctx context.Context, restaurantID, limit int) ([]domain.DishAnalytics, error) {
 	allTimeKey := "analytics:alltime:" + strconv.Itoa(restaurantID)
-	results, err := s.rdb.ZRevRangeWithScores(s.ctx, allTimeKey, 0, int64(limit-1)).Result()
+	results, err := s.rdb.ZRevRangeWithScores(ctx, allTimeKey, 0, int64(limit-1)).Result()
 	if err != nil {
 		return []domain.DishAnalytics{}, nil
 	}
@@ -259,7 +257,7 @@ func (s *AnalyticsService) TopDishes(restaurantID, limit int) ([]domain.DishAnal
 	for _, result := range results {
 		dishID, _ := strconv.Atoi(result.Member.(string))
 		var dishName string
-		if err := s.db.QueryRow("SELECT name FROM dishes WHERE id = $1", dishID).Scan(&dishName); err != nil {
+		if err := s.db.QueryRowContext(ctx, "SELECT name FROM dishes WHERE id = $1", dishID).Scan(&dishName); err != nil {
 			continue
 		}
 		top = append(top, domain.DishAnalytics{
@@ -272,8 +270,8 @@ func (s *AnalyticsService) TopDishes(restaurantID, limit int) ([]domain.DishAnal
 	return top, nil
 }
 
-func (s *AnalyticsService) RatingDistribution(restaurantID int) (map[string]int, error) {
-	rows, err := s.db.Query(`
+func (s *AnalyticsService) RatingDistribution(ctx context.Context, restaurantID int) (map[string]int, error) {
+	rows, err := s.db.QueryContext(ctx, `
 		SELECT rating, COUNT(*) as count
 		FROM reviews
 		WHERE restaurant_id = $1
@@ -294,8 +292,8 @@ func (s *AnalyticsService) RatingDistribution(restaurantID int) (map[string]int,
 	return distribution, nil
 }
 
-func (s *AnalyticsService) GlobalDistribution() (map[string]int, error) {
-	rows, err := s.db.Query(`
+func (s *AnalyticsService) GlobalDistribution(ctx context.Context) (map[string]int, error) {
+	rows, err := s.db.QueryContext(ctx, `
 		SELECT rating, COUNT(*) as count
 		FROM reviews
 		GROUP BY rating
@@ -315,6 +313,106 @@ func (s *AnalyticsService) GlobalDistribution() (map[string]int, error) {
 	return distribution, nil
 }
 
+// VariantRatings splits a dish's reviews by the options ordered with it.
+// Reviews are keyed by the base dish and the check, so a review counts towards
+// every variant of the dish on that check.
+func (s *AnalyticsService) VariantRatings(ctx context.Context, restaurantID, dishID int) ([]domain.VariantRating, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		WITH item_variants AS (
+			SELECT oi.order_id, oi.dish_id,
+			       COALESCE(string_agg(oio.name, ', ' ORDER BY oio.group_name, oio.name), '') AS variant
+			FROM order_items oi
+			LEFT JOIN order_item_options oio ON oio.order_item_id = oi.id
+			WHERE oi.dish_id = $1
+			GROUP BY oi.id, oi.order_id, oi.dish_id
+		)
+		SELECT iv.variant, ROUND(AVG(r.rating)::numeric, 2), COUNT(r.id)
+		FROM item_variants iv
+		JOIN reviews r ON r.order_id = iv.order_id AND r.dish_id = iv.dish_id
+		WHERE r.restaurant_id = $2
+		GROUP BY iv.variant
+		ORDER BY 2 DESC, 3 DESC
+	`, dishID, restaurantID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	variants := []domain.VariantRating{}
+	for rows.Next() {
+		var v domain.VariantRating
+		if err := rows.Scan(&v.Variant, &v.AvgRating, &v.ReviewCount); err != nil {
+			continue
+		}
+		variants = append(variants, v)
+	}
+	return variants, nil
+}
+
+// PriceImpact reports average rating and ordered quantity of a dish around
+// each of its price changes, newest change first. The initial price of the
+// dish is not a change and is skipped.
+func (s *AnalyticsService) PriceImpact(ctx context.Context, restaurantID, dishID, windowDays int) ([]domain.PriceChangeImpact, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		WITH changes AS (
+			SELECT h.price, h.changed_at,
+			       LAG(h.price) OVER w AS old_price,
+			       LAG(h.changed_at) OVER w AS prev_at,
+			       LEAD(h.changed_at) OVER w AS next_at
+			FROM dish_price_history h
+			JOIN dishes d ON d.id = h.dish_id
+			WHERE h.dish_id = $1 AND d.restaurant_id = $2
+			WINDOW w AS (ORDER BY h.changed_at, h.id)
+		), periods AS (
+			SELECT old_price, price, changed_at,
+			       GREATEST(changed_at - make_interval(days => $3), COALESCE(prev_at, '-infinity')) AS before_from,
+			       LEAST(changed_at + make_interval(days => $3), COALESCE(next_at, 'infinity')) AS after_to
+			FROM changes
+			WHERE old_price IS NOT NULL
+		)
+		SELECT p.old_price, p.price, p.changed_at,
+		       COALESCE(ROUND(rb.avg::numeric, 2), 0), rb.cnt,
+		       COALESCE(ROUND(ra.avg::numeric, 2), 0), ra.cnt,
+		       ob.qty, oa.qty
+		FROM periods p
+		CROSS JOIN LATERAL (
+			SELECT AVG(rating) AS avg, COUNT(*) AS cnt FROM reviews
+			WHERE dish_id = $1 AND restaurant_id = $2 AND created_at >= p.before_from AND created_at < p.changed_at
+		) rb
+		CROSS JOIN LATERAL (
+			SELECT AVG(rating) AS avg, COUNT(*) AS cnt FROM reviews
+			WHERE dish_id = $1 AND restaurant_id = $2 AND created_at >= p.changed_at AND created_at < p.after_to
+		) ra
+		CROSS JOIN LATERAL (
+			SELECT COALESCE(SUM(oi.quantity), 0) AS qty FROM order_items oi
+			JOIN orders o ON o.id = oi.order_id
+			WHERE oi.dish_id = $1 AND o.restaurant_id = $2 AND o.created_at >= p.before_from AND o.created_at < p.changed_at
+		) ob
+		CROSS JOIN LATERAL (
+			SELECT COALESCE(SUM(oi.quantity), 0) AS qty FROM order_items oi
+			JOIN orders o ON o.id = oi.order_id
+			WHERE oi.dish_id = $1 AND o.restaurant_id = $2 AND o.created_at >= p.changed_at AND o.created_at < p.after_to
+		) oa
+		ORDER BY p.changed_at DESC
+	`, dishID, restaurantID, windowDays)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	impacts := []domain.PriceChangeImpact{}
+	for rows.Next() {
+		c := domain.PriceChangeImpact{WindowDays: windowDays}
+		if err := rows.Scan(&c.OldPrice, &c.NewPrice, &c.ChangedAt,
+			&c.RatingBefore, &c.ReviewsBefore, &c.RatingAfter, &c.ReviewsAfter,
+			&c.OrderedBefore, &c.OrderedAfter); err != nil {
+			return nil, err
+		}
+		impacts = append(impacts, c)
+	}
+	return impacts, rows.Err()
+}
+
 func (s *AnalyticsService) extractRestaurantIDFromKey(key string) int {
 	parts := strings.Split(key, ":")
 	if len(parts) >= 5 {
@@ -1,17 +1,31 @@
 package service
 
 import (
+	"context"
+
 	"overcooked-simplified/analytics-svc/internal/domain"
+
+	"golang.org/x/text/language"
 )
 
 type AnalyticsInterface interface {
-	TopToday() ([]domain.DishAnalytics, error)
-	TopAllTime() ([]domain.DishAnalytics, error)
-	AnalyticsForRestaurant(restaurantID int, period string) domain.AnalyticsResponse
-	DishStats(restaurantID, dishID int) (map[string]interface{}, error)
-	TopDishes(restaurantID, limit int) ([]domain.DishAnalytics, error)
-	RatingDistribution(restaurantID int) (map[string]int, error)
-	GlobalDistribution() (map[string]int, error)
+	TopToday(ctx context.Context) ([]domain.DishAnalytics, error)
+	TopAllTime(ctx context.Context) ([]domain.DishAnalytics, error)
+	AnalyticsForRestaurant(ctx context.Context, restaurantID int, period string) domain.AnalyticsResponse
+	DishStats(ctx context.Context, restaurantID, dishID int) (map[string]interface{}, error)
+	TopDishes(ctx context.Context, restaurantID, limit int) ([]domain.DishAnalytics, error)
+	RatingDistribution(ctx context.Context, restaurantID int) (map[string]int, error)
+	GlobalDistribution(ctx context.Context) (map[string]int, error)
+	VariantRatings(ctx context.Context, restaurantID, dishID int) ([]domain.VariantRating, error)
+	PriceImpact(ctx context.Context, restaurantID, dishID, windowDays int) ([]domain.PriceChangeImpact, error)
+	TableRatings(ctx context.Context, restaurantID int) ([]domain.TableRating, error)
+	SectionRatings(ctx context.Context, restaurantID int) ([]domain.SectionRating, error)
+}
+
+// DishNamer translates dish names in analytics responses.
+type DishNamer interface {
+	LocalizeDishNames(ctx context.Context, dishes []*domain.DishAnalytics, requested []language.Tag) error
 }
 
 var _ AnalyticsInterface = (*AnalyticsService)(nil)
+var _ DishNamer = (*AnalyticsService)(nil)
@@ -0,0 +1,68 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+
+	"overcooked-simplified/analytics-svc/internal/domain"
+	"overcooked-simplified/i18n"
+
+	"github.com/lib/pq"
+	"golang.org/x/text/language"
+)
+
+// LocalizeDishNames replaces dish names with their translations into the
+// requested languages, the same way dish-svc localizes its menus.
+func (s *AnalyticsService) LocalizeDishNames(ctx context.Context, dishes []*domain.DishAnalytics, requested []language.Tag) error {
+	if len(requested) == 0 || len(dishes) == 0 {
+		return nil
+	}
+	dishIDs := make([]int, 0, len(dishes))
+	for _, dish := range dishes {
+		if dish != nil {
+			dishIDs = append(dishIDs, dish.DishID)
+		}
+	}
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT d.id, d.name, r.locale, t.lang, t.name
+		FROM dishes d
+		JOIN restaurants r ON r.id = d.restaurant_id
+		LEFT JOIN dish_translations t ON t.dish_id = d.id
+		WHERE d.id = ANY($1)`, pq.Array(dishIDs))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	names := make(map[int]*i18n.Names)
+	for rows.Next() {
+		var dishID int
+		var name, locale string
+		var lang, translated sql.NullString
+		if err := rows.Scan(&dishID, &name, &locale, &lang, &translated); err != nil {
+			return err
+		}
+		n := names[dishID]
+		if n == nil {
+			n = &i18n.Names{Default: name, Locale: locale, Translations: map[string]string{}}
+			names[dishID] = n
+		}
+		if lang.Valid {
+			n.Translations[lang.String] = translated.String
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, dish := range dishes {
+		if dish == nil {
+			continue
+		}
+		if n := names[dish.DishID]; n != nil {
+			dish.DishName = n.In(requested)
+		}
+	}
+	return nil
+}
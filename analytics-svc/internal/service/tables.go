@@ -0,0 +1,109 @@
+package service
+
+import (
+	"context"
+	"math"
+	"sort"
+
+	"overcooked-simplified/analytics-svc/internal/domain"
+)
+
+// TableRatings reports every table of a restaurant, including tables nobody
+// has rated yet, ordered by section and label.
+func (s *AnalyticsService) TableRatings(ctx context.Context, restaurantID int) ([]domain.TableRating, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		WITH visits AS (
+			SELECT table_id, AVG(rating) AS avg, COUNT(*) AS cnt
+			FROM visit_ratings
+			WHERE restaurant_id = $1 AND table_id IS NOT NULL
+			GROUP BY table_id
+		), dish_reviews AS (
+			SELECT o.table_id, AVG(r.rating) AS avg, COUNT(r.id) AS cnt
+			FROM reviews r
+			JOIN orders o ON o.id = r.order_id
+			WHERE o.restaurant_id = $1 AND o.table_id IS NOT NULL
+			GROUP BY o.table_id
+		), table_orders AS (
+			SELECT table_id, COUNT(*) AS cnt
+			FROM orders
+			WHERE restaurant_id = $1 AND table_id IS NOT NULL
+			GROUP BY table_id
+		)
+		SELECT t.id, t.label, t.section, COALESCE(o.cnt, 0),
+		       COALESCE(ROUND(v.avg::numeric, 2), 0), COALESCE(v.cnt, 0),
+		       COALESCE(ROUND(d.avg::numeric, 2), 0), COALESCE(d.cnt, 0)
+		FROM tables t
+		LEFT JOIN visits v ON v.table_id = t.id
+		LEFT JOIN dish_reviews d ON d.table_id = t.id
+		LEFT JOIN table_orders o ON o.table_id = t.id
+		WHERE t.restaurant_id = $1
+		ORDER BY t.section, t.label, t.id
+	`, restaurantID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tables := []domain.TableRating{}
+	for rows.Next() {
+		var t domain.TableRating
+		if err := rows.Scan(&t.TableID, &t.Label, &t.Section, &t.Orders,
+			&t.AvgVisitRating, &t.VisitRatings, &t.AvgDishRating, &t.DishReviews); err != nil {
+			return nil, err
+		}
+		tables = append(tables, t)
+	}
+	return tables, rows.Err()
+}
+
+func (s *AnalyticsService) SectionRatings(ctx context.Context, restaurantID int) ([]domain.SectionRating, error) {
+	tables, err := s.TableRatings(ctx, restaurantID)
+	if err != nil {
+		return nil, err
+	}
+	return GroupBySection(tables), nil
+}
+
+// GroupBySection sums table ratings up by section, worst rated visits first,
+// so a weak zone stands out.
+func GroupBySection(tables []domain.TableRating) []domain.SectionRating {
+	index := make(map[string]int)
+	sections := []domain.SectionRating{}
+	for _, t := range tables {
+		i, ok := index[t.Section]
+		if !ok {
+			i = len(sections)
+			index[t.Section] = i
+			sections = append(sections, domain.SectionRating{Section: t.Section})
+		}
+		section := &sections[i]
+		section.Tables++
+		section.Orders += t.Orders
+		section.AvgVisitRating += t.AvgVisitRating * float64(t.VisitRatings)
+		section.VisitRatings += t.VisitRatings
+		section.AvgDishRating += t.AvgDishRating * float64(t.DishReviews)
+		section.DishReviews += t.DishReviews
+	}
+	for i := range sections {
+		section := &sections[i]
+		if section.VisitRatings > 0 {
+			section.AvgVisitRating = roundRating(section.AvgVisitRating / float64(section.VisitRatings))
+		}
+		if section.DishReviews > 0 {
+			section.AvgDishRating = roundRating(section.AvgDishRating / float64(section.DishReviews))
+		}
+	}
+	// sections without visit ratings go last
+	sort.SliceStable(sections, func(i, j int) bool {
+		a, b := sections[i], sections[j]
+		if (a.VisitRatings == 0) != (b.VisitRatings == 0) {
+			return b.VisitRatings == 0
+		}
+		return a.AvgVisitRating < b.AvgVisitRating
+	})
+	return sections
+}
+
+func roundRating(v float64) float64 {
+	return math.Round(v*100) / 100
+}
@@ -0,0 +1,48 @@
+package service
+
+import (
+	"context"
+	"strconv"
+	"time"
+)
+
+// LocalDate is the calendar date of t in the given IANA time zone, the day a
+// restaurant there considers "today". An empty or unknown zone counts as UTC.
+func LocalDate(t time.Time, timeZone string) string {
+	loc, err := time.LoadLocation(timeZone)
+	if err != nil || timeZone == "" {
+		loc = time.UTC
+	}
+	return t.In(loc).Format("2006-01-02")
+}
+
+// restaurantToday is the current date in the restaurant's time zone.
+func (s *AnalyticsService) restaurantToday(ctx context.Context, restaurantID int) string {
+	var timeZone string
+	if err := s.db.QueryRowContext(ctx, "SELECT time_zone FROM restaurants WHERE id = $1", restaurantID).Scan(&timeZone); err != nil {
+		timeZone = ""
+	}
+	return LocalDate(time.Now(), timeZone)
+}
+
+// todayKeys lists the daily analytics key of every restaurant for its own
+// current date.
+func (s *AnalyticsService) todayKeys(ctx context.Context) ([]string, error) {
+	rows, err := s.db.QueryContext(ctx, "SELECT id, time_zone FROM restaurants")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	now := time.Now()
+	var keys []string
+	for rows.Next() {
+		var id int
+		var timeZone string
+		if err := rows.Scan(&id, &timeZone); err != nil {
+			return nil, err
+		}
+		keys = append(keys, "analytics:daily:"+LocalDate(now, timeZone)+":"+strconv.Itoa(id))
+	}
+	return keys, rows.Err()
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/gorilla/mux"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"golang.org/x/text/language"
 )
 
 func TestGetAnalyticsHandler(t *testing.T) {
@@ -52,7 +53,7 @@ func TestGetAnalyticsHandler(t *testing.T) {
 			req = mux.SetURLVars(req, map[string]string{"restaurantId": testCase.restaurantID})
 			w := httptest.NewRecorder()
 
-			mockAnalytics.On("AnalyticsForRestaurant", mock.AnythingOfType("int"), testCase.period).
+			mockAnalytics.On("AnalyticsForRestaurant", mock.Anything, mock.AnythingOfType("int"), testCase.period).
 				Return(testCase.mockResponse)
 
 			r := mux.NewRouter()
@@ -109,7 +110,7 @@ func TestGetDishStatsHandler(t *testing.T) {
 			})
 			w := httptest.NewRecorder()
 
-			mockAnalytics.On("DishStats", restID, dishID).Return(testCase.mockStats, testCase.mockError)
+			mockAnalytics.On("DishStats", mock.Anything, restID, dishID).Return(testCase.mockStats, testCase.mockError)
 
 			r := mux.NewRouter()
 			handler.RegisterRoutes(r)
@@ -125,7 +126,7 @@ func TestGetTopTodayHandler(t *testing.T) {
 	mockAnalytics := new(mocks.AnalyticsInterface)
 	handler := httpapi.NewHandler(mockAnalytics)
 
-	mockAnalytics.On("TopToday").Return([]domain.DishAnalytics{
+	mockAnalytics.On("TopToday", mock.Anything).Return([]domain.DishAnalytics{
 		{DishID: 1, DishName: "Pizza", Score: 5.0},
 	}, nil)
 
@@ -139,3 +140,112 @@ func TestGetTopTodayHandler(t *testing.T) {
 	assert.Equal(t, http.StatusOK, w.Code)
 	mockAnalytics.AssertExpectations(t)
 }
+
+func TestGetTopTodayHandler_Localized(t *testing.T) {
+	mockAnalytics := new(mocks.AnalyticsInterface)
+	mockNames := new(mocks.DishNamer)
+	handler := httpapi.NewHandler(mockAnalytics)
+	handler.Names = mockNames
+
+	mockAnalytics.On("TopToday", mock.Anything).Return([]domain.DishAnalytics{
+		{DishID: 1, DishName: "Плов", Score: 5.0},
+	}, nil)
+	mockNames.On("LocalizeDishNames", mock.Anything, mock.Anything, []language.Tag{language.English}).
+		Run(func(args mock.Arguments) {
+			args.Get(1).([]*domain.DishAnalytics)[0].DishName = "Pilaf"
+		}).
+		Return(nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/analytics/top-today", nil)
+	req.Header.Set("Accept-Language", "en")
+	w := httptest.NewRecorder()
+
+	r := mux.NewRouter()
+	handler.RegisterRoutes(r)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"dish_name":"Pilaf"`)
+	assert.Equal(t, "Accept-Language", w.Header().Get("Vary"))
+	mockNames.AssertExpectations(t)
+}
+
+func TestGetVariantRatingsHandler(t *testing.T) {
+	mockAnalytics := new(mocks.AnalyticsInterface)
+	handler := httpapi.NewHandler(mockAnalytics)
+
+	mockAnalytics.On("VariantRatings", mock.Anything, 3, 7).Return([]domain.VariantRating{
+		{Variant: "40 см, Сыр", AvgRating: 4.8, ReviewCount: 5},
+		{Variant: "30 см", AvgRating: 4.1, ReviewCount: 9},
+	}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/restaurants/3/analytics/dishes/7/variants", nil)
+	w := httptest.NewRecorder()
+
+	r := mux.NewRouter()
+	handler.RegisterRoutes(r)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "40 см, Сыр")
+	mockAnalytics.AssertExpectations(t)
+}
+
+func TestGetSectionRatingsHandler(t *testing.T) {
+	mockAnalytics := new(mocks.AnalyticsInterface)
+	handler := httpapi.NewHandler(mockAnalytics)
+
+	mockAnalytics.On("SectionRatings", mock.Anything, 3).Return([]domain.SectionRating{
+		{Section: "Веранда", Tables: 4, AvgVisitRating: 3.2, VisitRatings: 12},
+	}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/restaurants/3/analytics/sections", nil)
+	w := httptest.NewRecorder()
+
+	r := mux.NewRouter()
+	handler.RegisterRoutes(r)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"avg_visit_rating":3.2`)
+	mockAnalytics.AssertExpectations(t)
+}
+
+func TestGetPriceImpactHandler(t *testing.T) {
+	tests := []struct {
+		name       string
+		query      string
+		wantCode   int
+		wantWindow int
+	}{
+		{name: "default window", wantCode: http.StatusOK, wantWindow: 30},
+		{name: "custom window", query: "?window_days=14", wantCode: http.StatusOK, wantWindow: 14},
+		{name: "invalid window", query: "?window_days=0", wantCode: http.StatusBadRequest},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			mockAnalytics := new(mocks.AnalyticsInterface)
+			handler := httpapi.NewHandler(mockAnalytics)
+
+			if testCase.wantWindow != 0 {
+				mockAnalytics.On("PriceImpact", mock.Anything, 3, 7, testCase.wantWindow).Return([]domain.PriceChangeImpact{
+					{OldPrice: 450, NewPrice: 490, WindowDays: testCase.wantWindow, RatingBefore: 4.6, RatingAfter: 4.2},
+				}, nil).Once()
+			}
+
+			req := httptest.NewRequest(http.MethodGet, "/api/restaurants/3/analytics/dishes/7/price-changes"+testCase.query, nil)
+			w := httptest.NewRecorder()
+
+			r := mux.NewRouter()
+			handler.RegisterRoutes(r)
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, testCase.wantCode, w.Code)
+			if testCase.wantCode == http.StatusOK {
+				assert.Contains(t, w.Body.String(), `"avg_rating_after":4.2`)
+			}
+			mockAnalytics.AssertExpectations(t)
+		})
+	}
+}
@@ -1,13 +1,17 @@
 package tests
 
 import (
+	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"overcooked-simplified/analytics-svc/internal/domain"
 	"overcooked-simplified/analytics-svc/internal/mocks"
+	"overcooked-simplified/analytics-svc/internal/service"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
 func TestAnalyticsService_TopToday(t *testing.T) {
@@ -20,7 +24,7 @@ func TestAnalyticsService_TopToday(t *testing.T) {
 		{
 			name: "success from redis",
 			mockRedis: func(mockAnalytics *mocks.AnalyticsInterface) {
-				mockAnalytics.On("TopToday").Return([]domain.DishAnalytics{
+				mockAnalytics.On("TopToday", mock.Anything).Return([]domain.DishAnalytics{
 					{DishID: 1, DishName: "Pizza", Score: 5.0},
 				}, nil)
 			},
@@ -30,7 +34,7 @@ func TestAnalyticsService_TopToday(t *testing.T) {
 		{
 			name: "redis empty fallback to db",
 			mockRedis: func(mockAnalytics *mocks.AnalyticsInterface) {
-				mockAnalytics.On("TopToday").Return([]domain.DishAnalytics{}, nil)
+				mockAnalytics.On("TopToday", mock.Anything).Return([]domain.DishAnalytics{}, nil)
 			},
 			wantLen: 0,
 			wantErr: false,
@@ -42,7 +46,7 @@ func TestAnalyticsService_TopToday(t *testing.T) {
 			mockAnalytics := new(mocks.AnalyticsInterface)
 			testCase.mockRedis(mockAnalytics)
 
-			result, err := mockAnalytics.TopToday()
+			result, err := mockAnalytics.TopToday(context.Background())
 
 			if testCase.wantErr {
 				assert.Error(t, err)
@@ -83,9 +87,9 @@ func TestAnalyticsService_AnalyticsForRestaurant(t *testing.T) {
 	for _, testCase := range tests {
 		t.Run(testCase.name, func(t *testing.T) {
 			mockAnalytics := new(mocks.AnalyticsInterface)
-			mockAnalytics.On("AnalyticsForRestaurant", testCase.restaurantID, testCase.period).Return(testCase.mockResult)
+			mockAnalytics.On("AnalyticsForRestaurant", mock.Anything, testCase.restaurantID, testCase.period).Return(testCase.mockResult)
 
-			result := mockAnalytics.AnalyticsForRestaurant(testCase.restaurantID, testCase.period)
+			result := mockAnalytics.AnalyticsForRestaurant(context.Background(), testCase.restaurantID, testCase.period)
 
 			assert.Equal(t, testCase.mockResult, result)
 			mockAnalytics.AssertExpectations(t)
@@ -125,9 +129,9 @@ func TestAnalyticsService_DishStats(t *testing.T) {
 	for _, testCase := range tests {
 		t.Run(testCase.name, func(t *testing.T) {
 			mockAnalytics := new(mocks.AnalyticsInterface)
-			mockAnalytics.On("DishStats", testCase.restaurantID, testCase.dishID).Return(testCase.mockStats, testCase.mockError)
+			mockAnalytics.On("DishStats", mock.Anything, testCase.restaurantID, testCase.dishID).Return(testCase.mockStats, testCase.mockError)
 
-			result, err := mockAnalytics.DishStats(testCase.restaurantID, testCase.dishID)
+			result, err := mockAnalytics.DishStats(context.Background(), testCase.restaurantID, testCase.dishID)
 
 			if testCase.wantErr {
 				assert.Error(t, err)
@@ -139,3 +143,28 @@ func TestAnalyticsService_DishStats(t *testing.T) {
 		})
 	}
 }
+
+func TestGroupBySection(t *testing.T) {
+	sections := service.GroupBySection([]domain.TableRating{
+		{TableID: 1, Label: "1", Section: "Зал", Orders: 10, AvgVisitRating: 5, VisitRatings: 3, AvgDishRating: 4.5, DishReviews: 2},
+		{TableID: 2, Label: "2", Section: "Зал", Orders: 4, AvgVisitRating: 3, VisitRatings: 1},
+		{TableID: 3, Label: "В1", Section: "Веранда", Orders: 7, AvgVisitRating: 2.5, VisitRatings: 2, AvgDishRating: 3, DishReviews: 4},
+		{TableID: 4, Label: "Б1", Section: "Бар"},
+	})
+
+	if assert.Len(t, sections, 3) {
+		assert.Equal(t, domain.SectionRating{Section: "Веранда", Tables: 1, Orders: 7, AvgVisitRating: 2.5, VisitRatings: 2, AvgDishRating: 3, DishReviews: 4}, sections[0])
+		assert.Equal(t, domain.SectionRating{Section: "Зал", Tables: 2, Orders: 14, AvgVisitRating: 4.5, VisitRatings: 4, AvgDishRating: 4.5, DishReviews: 2}, sections[1])
+		assert.Equal(t, "Бар", sections[2].Section, "sections nobody rated go last")
+	}
+}
+
+func TestLocalDate(t *testing.T) {
+	// 21:30 UTC is already the next day in Moscow and still the same day in New York
+	at := time.Date(2026, 10, 17, 21, 30, 0, 0, time.UTC)
+
+	assert.Equal(t, "2026-10-18", service.LocalDate(at, "Europe/Moscow"))
+	assert.Equal(t, "2026-10-17", service.LocalDate(at, "America/New_York"))
+	assert.Equal(t, "2026-10-17", service.LocalDate(at, ""))
+	assert.Equal(t, "2026-10-17", service.LocalDate(at, "Mars/Olympus"))
+}
@@ -1,22 +1,56 @@
 package main
 
 import (
+	"context"
+	"log"
+	"os"
 	httpapi "overcooked-simplified/analytics-svc/internal/api/http"
 	"overcooked-simplified/analytics-svc/internal/service"
+	_ "time/tzdata" // restaurant time zones; the alpine image has no zoneinfo
 
 	"overcooked-simplified/config"
+	"overcooked-simplified/database"
+	"overcooked-simplified/health"
+	"overcooked-simplified/server"
+	"overcooked-simplified/tracing"
 )
 
 func main() {
-	db := config.MustInitPostgres()
-	defer db.Close()
+	cfg := config.MustLoad(":8083", config.NeedPostgres|config.NeedRedis)
 
-	rdb := config.MustInitRedis()
-	defer rdb.Close()
+	shutdownTracing, err := tracing.Init("analytics-svc", cfg.Tracing)
+	if err != nil {
+		log.Fatal("Failed to set up tracing: ", err)
+	}
+
+	db := config.MustInitPostgres(cfg.Postgres, cfg.Startup)
+
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		err := database.RunCommand(db, os.Args[2:])
+		db.Close()
+		if err != nil {
+			log.Fatal("Migration failed: ", err)
+		}
+		return
+	}
+	database.MustMigrate(db, cfg.Migrate.OnStart, cfg.Migrate.SeedDemoData)
+
+	rdb := config.MustInitRedis(cfg.Redis, cfg.Startup)
 
 	analyticsSvc := service.NewAnalyticsService(db, rdb)
 	handler := httpapi.NewHandler(analyticsSvc)
+	handler.Names = analyticsSvc
+	handler.Health = health.NewChecker("analytics-svc")
+	handler.Health.Add("postgres", db.PingContext)
+	handler.Health.Add("redis", func(ctx context.Context) error { return rdb.Ping(ctx).Err() })
 	router := httpapi.NewRouter(handler)
 
-	httpapi.StartServer(":8083", router)
+	srv := server.New("analytics-svc", cfg.HTTP, router)
+	srv.Health = handler.Health
+	srv.OnShutdown("redis", rdb.Close)
+	srv.OnShutdown("postgres", db.Close)
+	srv.OnShutdown("tracing", func() error { return shutdownTracing(context.Background()) })
+	if err := srv.Run(); err != nil {
+		log.Fatal("Server stopped with errors: ", err)
+	}
 }
@@ -8,6 +8,10 @@ import (
 	"net/http"
 	"strings"
 
+	"overcooked-simplified/health"
+	"overcooked-simplified/metrics"
+	"overcooked-simplified/tracing"
+
 	"github.com/gorilla/mux"
 )
 
@@ -24,6 +28,8 @@ type Config struct {
 type Gateway struct {
 	config Config
 	client HTTPClient
+	// Health serves /livez and /readyz when set.
+	Health *health.Checker
 }
 
 func NewGateway(config Config, client HTTPClient) *Gateway {
@@ -50,7 +56,7 @@ func (g *Gateway) ProxyRequest(w http.ResponseWriter, r *http.Request, targetURL
 		url += "?" + r.URL.RawQuery
 	}
 
-	req, err := http.NewRequest(r.Method, url, r.Body)
+	req, err := http.NewRequestWithContext(r.Context(), r.Method, url, r.Body)
 	if err != nil {
 		log.Printf("ERROR: Failed to create request: %v", err)
 		http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -61,7 +67,10 @@ func (g *Gateway) ProxyRequest(w http.ResponseWriter, r *http.Request, targetURL
 		req.Header[k] = v
 	}
 
+	// replaces the caller's traceparent with this hop's
+	req, span := tracing.StartClient(req, r.Method)
 	resp, err := g.client.Do(req)
+	tracing.EndClient(span, resp, err)
 	if err != nil {
 		log.Printf("ERROR: Failed to proxy to %s: %v", targetURL, err)
 		http.Error(w, err.Error(), http.StatusBadGateway)
@@ -88,7 +97,10 @@ func (g *Gateway) RouteHandler(w http.ResponseWriter, r *http.Request) {
 		if len(parts) >= 5 && parts[2] == "cafe" {
 			cafeID := parts[3]
 			r.URL.Path = fmt.Sprintf("/api/restaurants/%s/dishes", cafeID)
-			log.Printf("[GATEWAY] Rewrote cafe menu path to: %s", r.URL.Path)
+			query := r.URL.Query()
+			query.Set("grouped", "true")
+			r.URL.RawQuery = query.Encode()
+			log.Printf("[GATEWAY] Rewrote cafe menu path to: %s?%s", r.URL.Path, r.URL.RawQuery)
 			g.ProxyRequest(w, r, g.config.DishSvcURL)
 			return
 		}
@@ -121,7 +133,12 @@ func (g *Gateway) RouteHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if strings.HasPrefix(path, "/api/check") || strings.HasPrefix(path, "/api/orders") {
+	if strings.HasPrefix(path, "/api/visit-ratings") {
+		g.ProxyRequest(w, r, g.config.RateSvcURL)
+		return
+	}
+
+	if strings.HasPrefix(path, "/api/check") || strings.HasPrefix(path, "/api/orders") || strings.HasPrefix(path, "/api/files/") || strings.HasPrefix(path, "/api/tables/") || path == "/api/search" {
 		g.ProxyRequest(w, r, g.config.DishSvcURL)
 		return
 	}
@@ -131,6 +148,12 @@ func (g *Gateway) RouteHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// short review links from receipt QR codes and menu links from table ones
+	if strings.HasPrefix(path, "/r/") || strings.HasPrefix(path, "/t/") {
+		g.ProxyRequest(w, r, g.config.DishSvcURL)
+		return
+	}
+
 	if path == "/api/reviews" && r.Method == "POST" {
 		g.ProxyRequest(w, r, g.config.RateSvcURL)
 		return
@@ -147,7 +170,12 @@ func (g *Gateway) RouteHandler(w http.ResponseWriter, r *http.Request) {
 
 func (g *Gateway) SetupRoutes() http.Handler {
 	r := mux.NewRouter()
+	metrics.Instrument(r)
+	tracing.Instrument(r)
 	r.HandleFunc("/health", g.HealthCheck).Methods("GET")
+	if g.Health != nil {
+		g.Health.RegisterRoutes(r)
+	}
 	r.PathPrefix("/api/").HandlerFunc(g.RouteHandler)
 	r.PathPrefix("/static/").Handler(http.StripPrefix("/static/", http.FileServer(http.Dir("./frontend/"))))
 	r.PathPrefix("/").HandlerFunc(g.RouteHandler)
@@ -44,7 +44,9 @@ func TestGateway_RouteHandler_CafeMenu(t *testing.T) {
 	}
 	mockResp.Header.Set("Content-Type", "application/json")
 
-	mockClient.On("Do", mock.Anything).Return(mockResp, nil).Once()
+	mockClient.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		return req.URL.Path == "/api/restaurants/10/dishes" && req.URL.Query().Get("grouped") == "true"
+	})).Return(mockResp, nil).Once()
 
 	req := httptest.NewRequest(http.MethodGet, "/api/cafe/10/menu", nil)
 	rr := httptest.NewRecorder()
@@ -104,3 +106,54 @@ func TestGateway_RouteHandler_ReviewsRoute(t *testing.T) {
 
 	assert.Equal(t, http.StatusCreated, rr.Code)
 }
+
+func TestGateway_RouteHandler_ShortReviewLink(t *testing.T) {
+	mockClient := mocks.NewHTTPClient(t)
+	gw := gateway.NewGateway(gateway.Config{
+		DishSvcURL: "http://dish-svc",
+	}, mockClient)
+
+	mockResp := &http.Response{
+		StatusCode: http.StatusFound,
+		Body:       io.NopCloser(strings.NewReader("")),
+		Header:     make(http.Header),
+	}
+	mockResp.Header.Set("Location", "/review.html?check_id=42")
+
+	mockClient.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		return req.URL.String() == "http://dish-svc/r/16"
+	})).Return(mockResp, nil).Once()
+
+	req := httptest.NewRequest(http.MethodGet, "/r/16", nil)
+	rr := httptest.NewRecorder()
+
+	gw.RouteHandler(rr, req)
+
+	assert.Equal(t, http.StatusFound, rr.Code)
+	assert.Equal(t, "/review.html?check_id=42", rr.Header().Get("Location"))
+}
+
+func TestGateway_RouteHandler_Search(t *testing.T) {
+	mockClient := mocks.NewHTTPClient(t)
+	gw := gateway.NewGateway(gateway.Config{
+		DishSvcURL: "http://dish-svc",
+	}, mockClient)
+
+	mockResp := &http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(strings.NewReader(`{"query":"плов","restaurants":[],"dishes":[]}`)),
+		Header:     make(http.Header),
+	}
+
+	mockClient.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		return req.URL.String() == "http://dish-svc/api/search?q=%D0%BF%D0%BB%D0%BE%D0%B2&max_price=500"
+	})).Return(mockResp, nil).Once()
+
+	req := httptest.NewRequest(http.MethodGet, "/api/search?q=%D0%BF%D0%BB%D0%BE%D0%B2&max_price=500", nil)
+	rr := httptest.NewRecorder()
+
+	gw.RouteHandler(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"dishes":[]`)
+}
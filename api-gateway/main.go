@@ -1,41 +1,55 @@
 package main
 
 import (
+	"context"
 	"log"
 	"net/http"
-	"os"
 
 	"overcooked-simplified/api-gateway/internal/gateway"
+	"overcooked-simplified/config"
+	"overcooked-simplified/health"
+	"overcooked-simplified/server"
+	"overcooked-simplified/tracing"
 
 	"github.com/rs/cors"
 )
 
 func main() {
-	config := gateway.Config{
-		DishSvcURL:      getEnv("DISH_SVC_URL", "http://localhost:8081"),
-		RateSvcURL:      getEnv("RATE_SVC_URL", "http://localhost:8082"),
-		AnalyticsSvcURL: getEnv("ANALYTICS_SVC_URL", "http://localhost:8083"),
+	cfg := config.MustLoad(":8080", 0)
+
+	shutdownTracing, err := tracing.Init("api-gateway", cfg.Tracing)
+	if err != nil {
+		log.Fatal("Failed to set up tracing: ", err)
+	}
+
+	services := gateway.Config{
+		DishSvcURL:      cfg.Gateway.DishSvcURL,
+		RateSvcURL:      cfg.Gateway.RateSvcURL,
+		AnalyticsSvcURL: cfg.Gateway.AnalyticsSvcURL,
 	}
 
-	gw := gateway.NewGateway(config, &http.Client{})
+	// redirects, like the short review links, go back to the browser as they are
+	gw := gateway.NewGateway(services, &http.Client{
+		CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse },
+	})
 
+	// the gateway has no dependencies of its own; each service reports its own readiness
+	gw.Health = health.NewChecker("api-gateway")
 	r := gw.SetupRoutes()
 
 	c := cors.New(cors.Options{
 		AllowedOrigins:   []string{"http://localhost:8080", "http://127.0.0.1:8080", "*"},
 		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
 		AllowedHeaders:   []string{"*"},
+		ExposedHeaders:   []string{"X-Next-Cursor"},
 		AllowCredentials: true,
 	})
 	handler := c.Handler(r)
 
-	log.Println("API Gateway starting on port 8080")
-	log.Fatal(http.ListenAndServe(":8080", handler))
-}
-
-func getEnv(key, defaultValue string) string {
-	if value := os.Getenv(key); value != "" {
-		return value
+	srv := server.New("api-gateway", cfg.HTTP, handler)
+	srv.Health = gw.Health
+	srv.OnShutdown("tracing", func() error { return shutdownTracing(context.Background()) })
+	if err := srv.Run(); err != nil {
+		log.Fatal("Server stopped with errors: ", err)
 	}
-	return defaultValue
 }
@@ -0,0 +1,184 @@
+package config
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+
+	"overcooked-simplified/metrics"
+	"overcooked-simplified/tracing"
+
+	"github.com/lib/pq"
+	"github.com/redis/go-redis/v9"
+	"github.com/segmentio/kafka-go"
+)
+
+// DSN is the lib/pq connection string. Values are quoted, so passwords may
+// contain spaces and quotes.
+func (c PostgresConfig) DSN() string {
+	params := []string{
+		"host=" + quoteDSN(c.Host),
+		"port=" + strconv.Itoa(c.Port),
+		"user=" + quoteDSN(c.User),
+		"password=" + quoteDSN(c.Password),
+		"dbname=" + quoteDSN(c.Name),
+		"sslmode=" + quoteDSN(c.SSLMode),
+	}
+	for _, param := range [][2]string{{"sslrootcert", c.SSLRootCert}, {"sslcert", c.SSLCert}, {"sslkey", c.SSLKey}} {
+		if param[1] != "" {
+			params = append(params, param[0]+"="+quoteDSN(param[1]))
+		}
+	}
+	return strings.Join(params, " ")
+}
+
+func quoteDSN(value string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(value) + "'"
+}
+
+// MustInitPostgres waits up to startup.Timeout for the database to accept
+// connections.
+func MustInitPostgres(cfg PostgresConfig, startup StartupConfig) *sql.DB {
+	connector, err := pq.NewConnector(cfg.DSN())
+	if err != nil {
+		log.Fatal("Failed to connect to database:", err)
+	}
+	db := sql.OpenDB(tracing.Connector(metrics.Connector(connector)))
+
+	if err := Retry(startup, "Postgres", db.PingContext); err != nil {
+		log.Fatal("Failed to ping database:", err)
+	}
+
+	db.SetMaxOpenConns(cfg.MaxOpenConns)
+	db.SetMaxIdleConns(cfg.MaxIdleConns)
+	db.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+
+	return db
+}
+
+func MustInitRedis(cfg RedisConfig, startup StartupConfig) *redis.Client {
+	tlsConfig, err := cfg.TLS.Build(cfg.Host)
+	if err != nil {
+		log.Fatal("Invalid Redis TLS settings:", err)
+	}
+	client := redis.NewClient(&redis.Options{
+		Addr:      net.JoinHostPort(cfg.Host, strconv.Itoa(cfg.Port)),
+		Password:  cfg.Password,
+		DB:        cfg.DB,
+		TLSConfig: tlsConfig,
+	})
+	client.AddHook(metrics.RedisHook{})
+	client.AddHook(tracing.RedisHook{})
+
+	ping := func(ctx context.Context) error { return client.Ping(ctx).Err() }
+	if err := Retry(startup, "Redis", ping); err != nil {
+		log.Fatal("Failed to connect to Redis:", err)
+	}
+
+	return client
+}
+
+// PingKafka asks the brokers for the cluster's metadata, which fails unless
+// one of them answers.
+func PingKafka(ctx context.Context, cfg KafkaConfig) error {
+	tlsConfig, err := cfg.TLS.Build("")
+	if err != nil {
+		return err
+	}
+	dialer := &kafka.Dialer{Timeout: kafka.DefaultDialer.Timeout, DualStack: true, TLS: tlsConfig}
+	err = errors.New("no Kafka brokers configured")
+	for _, broker := range cfg.Brokers {
+		var conn *kafka.Conn
+		if conn, err = dialer.DialContext(ctx, "tcp", broker); err != nil {
+			continue
+		}
+		if deadline, ok := ctx.Deadline(); ok {
+			conn.SetDeadline(deadline)
+		}
+		_, err = conn.Brokers()
+		conn.Close()
+		if err == nil {
+			return nil
+		}
+	}
+	return err
+}
+
+// MustWaitForKafka waits up to startup.Timeout for a broker to answer.
+// Readers and writers connect lazily, so without it the first message finds
+// out that Kafka is still starting.
+func MustWaitForKafka(cfg KafkaConfig, startup StartupConfig) {
+	ping := func(ctx context.Context) error { return PingKafka(ctx, cfg) }
+	if err := Retry(startup, "Kafka", ping); err != nil {
+		log.Fatal("Failed to connect to Kafka:", err)
+	}
+}
+
+func NewKafkaReader(cfg KafkaConfig, topic, groupID string) *kafka.Reader {
+	tlsConfig, err := cfg.TLS.Build("")
+	if err != nil {
+		log.Fatal("Invalid Kafka TLS settings:", err)
+	}
+	return kafka.NewReader(kafka.ReaderConfig{
+		Brokers: cfg.Brokers,
+		Topic:   topic,
+		GroupID: groupID,
+		Dialer:  &kafka.Dialer{Timeout: kafka.DefaultDialer.Timeout, DualStack: true, TLS: tlsConfig},
+	})
+}
+
+func NewKafkaWriter(cfg KafkaConfig, topic string) *kafka.Writer {
+	tlsConfig, err := cfg.TLS.Build("")
+	if err != nil {
+		log.Fatal("Invalid Kafka TLS settings:", err)
+	}
+	return &kafka.Writer{
+		Addr:      kafka.TCP(cfg.Brokers...),
+		Topic:     topic,
+		Balancer:  &kafka.LeastBytes{},
+		Transport: &kafka.Transport{TLS: tlsConfig},
+	}
+}
+
+// Build returns nil when TLS is off. With an empty ServerName the client
+// checks the certificate against defaultServerName, or against the host it
+// dials when that is empty too.
+func (t TLSConfig) Build(defaultServerName string) (*tls.Config, error) {
+	if !t.Enabled {
+		return nil, nil
+	}
+	cfg := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		ServerName:         t.ServerName,
+		InsecureSkipVerify: t.InsecureSkipVerify,
+	}
+	if cfg.ServerName == "" {
+		cfg.ServerName = defaultServerName
+	}
+	if t.CAFile != "" {
+		pem, err := os.ReadFile(t.CAFile)
+		if err != nil {
+			return nil, err
+		}
+		cfg.RootCAs = x509.NewCertPool()
+		if !cfg.RootCAs.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates in %s", t.CAFile)
+		}
+	}
+	if t.CertFile != "" {
+		cert, err := tls.LoadX509KeyPair(t.CertFile, t.KeyFile)
+		if err != nil {
+			return nil, err
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+	return cfg, nil
+}
@@ -1,67 +1,296 @@
+// Package config loads the settings shared by all services: defaults, then
+// an optional YAML file named by CONFIG_FILE, then environment variables.
+// Any variable can be read from a file instead by setting NAME_FILE, which
+// is how Docker and Kubernetes mount secrets.
 package config
 
 import (
-	"context"
-	"database/sql"
+	"errors"
+	"fmt"
 	"log"
+	"net"
+	"net/url"
 	"os"
+	"sort"
 	"time"
 
-	_ "github.com/lib/pq"
-	"github.com/redis/go-redis/v9"
-	"github.com/segmentio/kafka-go"
+	"overcooked-simplified/tracing"
 )
 
-func MustInitPostgres() *sql.DB {
-	dbHost := os.Getenv("DB_HOST")
-	dbPort := os.Getenv("DB_PORT")
-	dbName := os.Getenv("DB_NAME")
-	dbUser := os.Getenv("DB_USER")
-	dbPassword := os.Getenv("DB_PASSWORD")
+// Needs tells Load which connections a service uses; only those are
+// checked for required fields.
+type Needs int
 
-	connStr := "host=" + dbHost + " port=" + dbPort + " user=" + dbUser +
-		" password=" + dbPassword + " dbname=" + dbName + " sslmode=disable"
+const (
+	NeedPostgres Needs = 1 << iota
+	NeedRedis
+	NeedKafka
+)
+
+type Config struct {
+	HTTP          HTTPConfig     `yaml:"http"`
+	Postgres      PostgresConfig `yaml:"postgres"`
+	Redis         RedisConfig    `yaml:"redis"`
+	Kafka         KafkaConfig    `yaml:"kafka"`
+	Startup       StartupConfig  `yaml:"startup"`
+	Tracing       tracing.Config `yaml:"tracing"`
+	Migrate       MigrateConfig  `yaml:"migrate"`
+	Blob          BlobConfig     `yaml:"blob"`
+	Gateway       GatewayConfig  `yaml:"gateway"`
+	PublicBaseURL string         `yaml:"public_base_url" env:"PUBLIC_BASE_URL" default:"http://localhost"`
+}
+
+type HTTPConfig struct {
+	// Addr is where the service listens; each service has its own default.
+	Addr              string        `yaml:"addr" env:"HTTP_ADDR"`
+	ReadHeaderTimeout time.Duration `yaml:"read_header_timeout" env:"HTTP_READ_HEADER_TIMEOUT" default:"5s"`
+	ReadTimeout       time.Duration `yaml:"read_timeout" env:"HTTP_READ_TIMEOUT" default:"30s"`
+	WriteTimeout      time.Duration `yaml:"write_timeout" env:"HTTP_WRITE_TIMEOUT" default:"60s"`
+	IdleTimeout       time.Duration `yaml:"idle_timeout" env:"HTTP_IDLE_TIMEOUT" default:"2m"`
+	MaxHeaderBytes    int           `yaml:"max_header_bytes" env:"HTTP_MAX_HEADER_BYTES" default:"65536"`
+	// DrainPeriod is how long /readyz fails before the listener closes, so
+	// load balancers stop sending new requests; ShutdownTimeout is how long
+	// requests in flight then get to finish.
+	DrainPeriod     time.Duration `yaml:"drain_period" env:"HTTP_DRAIN_PERIOD" default:"5s"`
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout" env:"HTTP_SHUTDOWN_TIMEOUT" default:"15s"`
+}
+
+type PostgresConfig struct {
+	Host     string `yaml:"host" env:"DB_HOST" default:"localhost"`
+	Port     int    `yaml:"port" env:"DB_PORT" default:"5432"`
+	Name     string `yaml:"name" env:"DB_NAME"`
+	User     string `yaml:"user" env:"DB_USER"`
+	Password string `yaml:"password" env:"DB_PASSWORD"`
+	// SSLMode is libpq's sslmode; verify-full checks the certificate and
+	// the host name against SSLRootCert.
+	SSLMode         string        `yaml:"sslmode" env:"DB_SSLMODE" default:"disable"`
+	SSLRootCert     string        `yaml:"sslrootcert" env:"DB_SSLROOTCERT"`
+	SSLCert         string        `yaml:"sslcert" env:"DB_SSLCERT"`
+	SSLKey          string        `yaml:"sslkey" env:"DB_SSLKEY"`
+	MaxOpenConns    int           `yaml:"max_open_conns" env:"DB_MAX_OPEN_CONNS" default:"25"`
+	MaxIdleConns    int           `yaml:"max_idle_conns" env:"DB_MAX_IDLE_CONNS" default:"5"`
+	ConnMaxLifetime time.Duration `yaml:"conn_max_lifetime" env:"DB_CONN_MAX_LIFETIME" default:"1h"`
+}
+
+type RedisConfig struct {
+	Host     string    `yaml:"host" env:"REDIS_HOST" default:"localhost"`
+	Port     int       `yaml:"port" env:"REDIS_PORT" default:"6379"`
+	Password string    `yaml:"password" env:"REDIS_PASSWORD"`
+	DB       int       `yaml:"db" env:"REDIS_DB" default:"0"`
+	TLS      TLSConfig `yaml:"tls" envPrefix:"REDIS_TLS_"`
+}
+
+type KafkaConfig struct {
+	Brokers []string  `yaml:"brokers" env:"KAFKA_BROKER"`
+	TLS     TLSConfig `yaml:"tls" envPrefix:"KAFKA_TLS_"`
+}
+
+// TLSConfig is a client TLS setup. Without CAFile the system roots are
+// trusted; CertFile and KeyFile add a client certificate.
+type TLSConfig struct {
+	Enabled            bool   `yaml:"enabled" env:"ENABLED"`
+	CAFile             string `yaml:"ca_file" env:"CA_FILE"`
+	CertFile           string `yaml:"cert_file" env:"CERT_FILE"`
+	KeyFile            string `yaml:"key_file" env:"KEY_FILE"`
+	ServerName         string `yaml:"server_name" env:"SERVER_NAME"`
+	InsecureSkipVerify bool   `yaml:"insecure_skip_verify" env:"INSECURE_SKIP_VERIFY"`
+}
+
+// StartupConfig bounds how long a service waits for Postgres, Redis and
+// Kafka to come up before it gives up.
+type StartupConfig struct {
+	Timeout    time.Duration `yaml:"timeout" env:"STARTUP_TIMEOUT" default:"2m"`
+	MaxBackoff time.Duration `yaml:"max_backoff" env:"STARTUP_MAX_BACKOFF" default:"10s"`
+}
+
+type MigrateConfig struct {
+	OnStart      bool `yaml:"on_start" env:"MIGRATE_ON_START" default:"true"`
+	SeedDemoData bool `yaml:"seed_demo_data" env:"SEED_DEMO_DATA"`
+}
+
+// BlobConfig selects where dish-svc keeps uploads: "fs" under Dir, served
+// under URL, or "s3".
+type BlobConfig struct {
+	Backend    string   `yaml:"backend" env:"BLOB_BACKEND" default:"fs"`
+	Dir        string   `yaml:"dir" env:"UPLOADS_DIR" default:"./uploads"`
+	URL        string   `yaml:"url" env:"UPLOADS_URL" default:"/uploads"`
+	SigningKey string   `yaml:"signing_key" env:"BLOB_SIGNING_KEY"`
+	S3         S3Config `yaml:"s3"`
+}
+
+type S3Config struct {
+	Endpoint  string `yaml:"endpoint" env:"S3_ENDPOINT"`
+	Region    string `yaml:"region" env:"S3_REGION"`
+	Bucket    string `yaml:"bucket" env:"S3_BUCKET"`
+	AccessKey string `yaml:"access_key" env:"S3_ACCESS_KEY"`
+	SecretKey string `yaml:"secret_key" env:"S3_SECRET_KEY"`
+	PublicURL string `yaml:"public_url" env:"S3_PUBLIC_URL"`
+	PathStyle bool   `yaml:"path_style" env:"S3_PATH_STYLE" default:"true"`
+}
+
+// GatewayConfig is where api-gateway finds the services.
+type GatewayConfig struct {
+	DishSvcURL      string `yaml:"dish_svc_url" env:"DISH_SVC_URL" default:"http://localhost:8081"`
+	RateSvcURL      string `yaml:"rate_svc_url" env:"RATE_SVC_URL" default:"http://localhost:8082"`
+	AnalyticsSvcURL string `yaml:"analytics_svc_url" env:"ANALYTICS_SVC_URL" default:"http://localhost:8083"`
+}
+
+// Load reads the configuration of a service listening on httpAddr by
+// default (empty for services without HTTP) and checks it. The error lists
+// every missing or invalid field, one per line.
+func Load(httpAddr string, needs Needs) (*Config, error) {
+	cfg := &Config{}
+	var errs []error
+	setDefaults(cfg)
+	cfg.HTTP.Addr = httpAddr
+	if path, err := lookup("CONFIG_FILE"); err != nil {
+		errs = append(errs, err)
+	} else if path != "" {
+		if err := loadYAML(cfg, path); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	errs = append(errs, loadEnv(cfg)...)
+	errs = append(errs, cfg.validate(needs)...)
+	return cfg, errors.Join(errs...)
+}
 
-	db, err := sql.Open("postgres", connStr)
+func MustLoad(httpAddr string, needs Needs) *Config {
+	cfg, err := Load(httpAddr, needs)
 	if err != nil {
-		log.Fatal("Failed to connect to database:", err)
+		log.Fatalf("Invalid configuration:\n%v", err)
 	}
+	return cfg
+}
 
-	if err = db.Ping(); err != nil {
-		log.Fatal("Failed to ping database:", err)
+func (c *Config) validate(needs Needs) []error {
+	var errs []error
+	check := func(ok bool, format string, args ...interface{}) {
+		if !ok {
+			errs = append(errs, fmt.Errorf(format, args...))
+		}
 	}
 
-	db.SetMaxOpenConns(25)
-	db.SetMaxIdleConns(5)
-	db.SetConnMaxLifetime(time.Hour)
+	if c.HTTP.Addr != "" {
+		_, port, err := net.SplitHostPort(c.HTTP.Addr)
+		check(err == nil && port != "", "HTTP_ADDR: %q is not a host:port address", c.HTTP.Addr)
+	}
+	for name, timeout := range map[string]time.Duration{
+		"HTTP_READ_HEADER_TIMEOUT": c.HTTP.ReadHeaderTimeout,
+		"HTTP_READ_TIMEOUT":        c.HTTP.ReadTimeout,
+		"HTTP_WRITE_TIMEOUT":       c.HTTP.WriteTimeout,
+		"HTTP_IDLE_TIMEOUT":        c.HTTP.IdleTimeout,
+		"HTTP_SHUTDOWN_TIMEOUT":    c.HTTP.ShutdownTimeout,
+	} {
+		check(timeout > 0, "%s must be positive", name)
+	}
+	check(c.HTTP.DrainPeriod >= 0, "HTTP_DRAIN_PERIOD must not be negative")
+	check(c.HTTP.MaxHeaderBytes >= 4096, "HTTP_MAX_HEADER_BYTES must be at least 4096")
 
-	return db
-}
+	check(c.Startup.Timeout > 0, "STARTUP_TIMEOUT must be positive")
+	check(c.Startup.MaxBackoff > 0, "STARTUP_MAX_BACKOFF must be positive")
+
+	if needs&NeedPostgres != 0 {
+		pg := c.Postgres
+		check(pg.Host != "", "DB_HOST is required")
+		check(validPort(pg.Port), "DB_PORT: %d is not a port", pg.Port)
+		check(pg.Name != "", "DB_NAME is required")
+		check(pg.User != "", "DB_USER is required")
+		switch pg.SSLMode {
+		case "disable", "allow", "prefer", "require", "verify-ca", "verify-full":
+		default:
+			errs = append(errs, fmt.Errorf("DB_SSLMODE: %q is not a libpq sslmode", pg.SSLMode))
+		}
+		check((pg.SSLCert == "") == (pg.SSLKey == ""), "DB_SSLCERT and DB_SSLKEY go together")
+		errs = append(errs, checkFiles(map[string]string{"DB_SSLROOTCERT": pg.SSLRootCert, "DB_SSLCERT": pg.SSLCert, "DB_SSLKEY": pg.SSLKey})...)
+		check(pg.MaxOpenConns > 0, "DB_MAX_OPEN_CONNS must be positive")
+		check(pg.MaxIdleConns >= 0 && pg.MaxIdleConns <= pg.MaxOpenConns, "DB_MAX_IDLE_CONNS must be between 0 and DB_MAX_OPEN_CONNS")
+		check(pg.ConnMaxLifetime >= 0, "DB_CONN_MAX_LIFETIME must not be negative")
+	}
+
+	if needs&NeedRedis != 0 {
+		check(c.Redis.Host != "", "REDIS_HOST is required")
+		check(validPort(c.Redis.Port), "REDIS_PORT: %d is not a port", c.Redis.Port)
+		check(c.Redis.DB >= 0, "REDIS_DB must not be negative")
+		errs = append(errs, c.Redis.TLS.validate("REDIS_TLS_")...)
+	}
+
+	if needs&NeedKafka != 0 {
+		check(len(c.Kafka.Brokers) > 0, "KAFKA_BROKER is required")
+		for _, broker := range c.Kafka.Brokers {
+			_, port, err := net.SplitHostPort(broker)
+			check(err == nil && port != "", "KAFKA_BROKER: %q is not a host:port address", broker)
+		}
+		errs = append(errs, c.Kafka.TLS.validate("KAFKA_TLS_")...)
+	}
 
-func MustInitRedis() *redis.Client {
-	client := redis.NewClient(&redis.Options{
-		Addr: os.Getenv("REDIS_HOST") + ":" + os.Getenv("REDIS_PORT"),
-	})
+	switch c.Tracing.Exporter {
+	case "none", "stdout":
+	case "otlp":
+		_, port, err := net.SplitHostPort(c.Tracing.OTLPEndpoint)
+		check(err == nil && port != "", "OTLP_ENDPOINT: %q is not a host:port address", c.Tracing.OTLPEndpoint)
+	default:
+		errs = append(errs, fmt.Errorf("TRACING_EXPORTER: %q, expected none, stdout or otlp", c.Tracing.Exporter))
+	}
+	check(c.Tracing.SampleRatio >= 0 && c.Tracing.SampleRatio <= 1, "TRACING_SAMPLE_RATIO must be between 0 and 1")
 
-	if err := client.Ping(context.Background()).Err(); err != nil {
-		log.Fatal("Failed to connect to Redis:", err)
+	switch c.Blob.Backend {
+	case "fs":
+	case "s3":
+		s3 := c.Blob.S3
+		check(s3.Endpoint != "", "S3_ENDPOINT is required with BLOB_BACKEND=s3")
+		check(s3.Bucket != "", "S3_BUCKET is required with BLOB_BACKEND=s3")
+		check(s3.AccessKey != "" && s3.SecretKey != "", "S3_ACCESS_KEY and S3_SECRET_KEY are required with BLOB_BACKEND=s3")
+	default:
+		errs = append(errs, fmt.Errorf("BLOB_BACKEND: %q, expected fs or s3", c.Blob.Backend))
 	}
 
-	return client
+	for name, value := range map[string]string{
+		"DISH_SVC_URL":      c.Gateway.DishSvcURL,
+		"RATE_SVC_URL":      c.Gateway.RateSvcURL,
+		"ANALYTICS_SVC_URL": c.Gateway.AnalyticsSvcURL,
+	} {
+		u, err := url.Parse(value)
+		check(err == nil && u.Scheme != "" && u.Host != "", "%s: %q is not an absolute URL", name, value)
+	}
+	sortErrors(errs)
+	return errs
 }
 
-func NewKafkaReader(topic, groupID string) *kafka.Reader {
-	return kafka.NewReader(kafka.ReaderConfig{
-		Brokers: []string{os.Getenv("KAFKA_BROKER")},
-		Topic:   topic,
-		GroupID: groupID,
-	})
+func (t TLSConfig) validate(prefix string) []error {
+	if !t.Enabled {
+		return nil
+	}
+	var errs []error
+	if (t.CertFile == "") != (t.KeyFile == "") {
+		errs = append(errs, fmt.Errorf("%sCERT_FILE and %sKEY_FILE go together", prefix, prefix))
+	}
+	return append(errs, checkFiles(map[string]string{
+		prefix + "CA_FILE":   t.CAFile,
+		prefix + "CERT_FILE": t.CertFile,
+		prefix + "KEY_FILE":  t.KeyFile,
+	})...)
 }
 
-func NewKafkaWriter(topic string) *kafka.Writer {
-	return &kafka.Writer{
-		Addr:     kafka.TCP(os.Getenv("KAFKA_BROKER")),
-		Topic:    topic,
-		Balancer: &kafka.LeastBytes{},
+// checkFiles reports the named files that are set but cannot be read.
+func checkFiles(files map[string]string) []error {
+	var errs []error
+	for name, path := range files {
+		if path == "" {
+			continue
+		}
+		if _, err := os.Stat(path); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %v", name, err))
+		}
 	}
+	return errs
+}
+
+func validPort(port int) bool {
+	return port > 0 && port < 1<<16
+}
+
+// sortErrors keeps the report stable; some checks walk maps.
+func sortErrors(errs []error) {
+	sort.Slice(errs, func(i, j int) bool { return errs[i].Error() < errs[j].Error() })
 }
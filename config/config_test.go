@@ -0,0 +1,121 @@
+package config_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"overcooked-simplified/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setConfigEnv sets the variables Load reads, clearing the ones a test does
+// not mention so the machine's environment does not leak in.
+func setConfigEnv(t *testing.T, env map[string]string) {
+	for _, name := range []string{"CONFIG_FILE", "HTTP_ADDR", "DB_HOST", "DB_PORT", "DB_NAME", "DB_USER",
+		"DB_PASSWORD", "DB_PASSWORD_FILE", "DB_SSLMODE", "DB_MAX_OPEN_CONNS", "REDIS_HOST", "KAFKA_BROKER",
+		"KAFKA_TLS_ENABLED", "KAFKA_TLS_CA_FILE", "BLOB_BACKEND", "DISH_SVC_URL"} {
+		t.Setenv(name, env[name])
+	}
+}
+
+func writeFile(t *testing.T, name, content string) string {
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestLoadConfig(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		setConfigEnv(t, map[string]string{"DB_NAME": "overcooked", "DB_USER": "postgres"})
+
+		cfg, err := config.Load(":8081", config.NeedPostgres)
+
+		require.NoError(t, err)
+		assert.Equal(t, ":8081", cfg.HTTP.Addr)
+		assert.Equal(t, "localhost", cfg.Postgres.Host)
+		assert.Equal(t, 5432, cfg.Postgres.Port)
+		assert.Equal(t, "disable", cfg.Postgres.SSLMode)
+		assert.Equal(t, 25, cfg.Postgres.MaxOpenConns)
+		assert.Equal(t, time.Hour, cfg.Postgres.ConnMaxLifetime)
+		assert.True(t, cfg.Migrate.OnStart)
+		assert.Equal(t, "fs", cfg.Blob.Backend)
+	})
+
+	t.Run("env over yaml over defaults", func(t *testing.T) {
+		file := writeFile(t, "config.yaml", `
+http:
+  addr: ":9000"
+postgres:
+  host: db.internal
+  name: fromfile
+  user: app
+  max_open_conns: 50
+startup:
+  timeout: 30s
+kafka:
+  brokers: [kafka-1:9092]
+`)
+		setConfigEnv(t, map[string]string{
+			"CONFIG_FILE":  file,
+			"DB_NAME":      "overcooked",
+			"KAFKA_BROKER": "kafka-1:9092, kafka-2:9092",
+		})
+
+		cfg, err := config.Load(":8082", config.NeedPostgres|config.NeedKafka)
+
+		require.NoError(t, err)
+		assert.Equal(t, ":9000", cfg.HTTP.Addr)
+		assert.Equal(t, "db.internal", cfg.Postgres.Host)
+		assert.Equal(t, "overcooked", cfg.Postgres.Name)
+		assert.Equal(t, 50, cfg.Postgres.MaxOpenConns)
+		assert.Equal(t, 30*time.Second, cfg.Startup.Timeout)
+		assert.Equal(t, []string{"kafka-1:9092", "kafka-2:9092"}, cfg.Kafka.Brokers)
+	})
+
+	t.Run("secret from file", func(t *testing.T) {
+		setConfigEnv(t, map[string]string{
+			"DB_NAME":          "overcooked",
+			"DB_USER":          "postgres",
+			"DB_PASSWORD_FILE": writeFile(t, "db_password", "s3cret pass\n"),
+		})
+
+		cfg, err := config.Load(":8081", config.NeedPostgres)
+
+		require.NoError(t, err)
+		assert.Equal(t, "s3cret pass", cfg.Postgres.Password)
+		assert.Contains(t, cfg.Postgres.DSN(), "password='s3cret pass'")
+	})
+
+	t.Run("every problem at once", func(t *testing.T) {
+		setConfigEnv(t, map[string]string{
+			"DB_PASSWORD":       "x",
+			"DB_PASSWORD_FILE":  "/run/secrets/db_password",
+			"DB_PORT":           "postgres",
+			"DB_SSLMODE":        "on",
+			"KAFKA_TLS_ENABLED": "true",
+			"KAFKA_TLS_CA_FILE": "/no/such/ca.crt",
+			"BLOB_BACKEND":      "ftp",
+			"DISH_SVC_URL":      "dish-svc:8081",
+		})
+
+		_, err := config.Load(":8082", config.NeedPostgres|config.NeedKafka)
+
+		require.Error(t, err)
+		for _, field := range []string{"DB_PASSWORD and DB_PASSWORD_FILE", "DB_PORT", "DB_NAME", "DB_USER",
+			"DB_SSLMODE", "KAFKA_BROKER", "KAFKA_TLS_CA_FILE", "BLOB_BACKEND", "DISH_SVC_URL"} {
+			assert.Contains(t, err.Error(), field)
+		}
+	})
+
+	t.Run("unknown yaml field", func(t *testing.T) {
+		setConfigEnv(t, map[string]string{"CONFIG_FILE": writeFile(t, "config.yaml", "postgres:\n  hots: db\n")})
+
+		_, err := config.Load(":8080", 0)
+
+		assert.ErrorContains(t, err, "hots")
+	})
+}
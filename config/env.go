@@ -0,0 +1,139 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Fields are described by struct tags: env is the variable name, default
+// the value before the YAML file and the environment are read, and envPrefix
+// on a nested struct is put in front of the names inside it.
+
+func setDefaults(cfg *Config) {
+	walk(reflect.ValueOf(cfg).Elem(), "", func(field reflect.Value, tag reflect.StructTag, _ string) {
+		if value, ok := tag.Lookup("default"); ok {
+			// defaults are ours, so a bad one is a bug
+			if err := setValue(field, value); err != nil {
+				panic(fmt.Sprintf("config: bad default %q: %v", value, err))
+			}
+		}
+	})
+}
+
+func loadYAML(cfg *Config, path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("CONFIG_FILE: %v", err)
+	}
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(cfg); err != nil {
+		return fmt.Errorf("CONFIG_FILE %s: %v", path, err)
+	}
+	return nil
+}
+
+func loadEnv(cfg *Config) []error {
+	var errs []error
+	walk(reflect.ValueOf(cfg).Elem(), "", func(field reflect.Value, _ reflect.StructTag, name string) {
+		if name == "" {
+			return
+		}
+		value, err := lookup(name)
+		if err != nil {
+			errs = append(errs, err)
+			return
+		}
+		if value == "" {
+			return
+		}
+		if err := setValue(field, value); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %v", name, err))
+		}
+	})
+	return errs
+}
+
+// lookup returns the variable, or the contents of the file named by
+// NAME_FILE without the trailing newline.
+func lookup(name string) (string, error) {
+	value := os.Getenv(name)
+	path := os.Getenv(name + "_FILE")
+	if path == "" {
+		return value, nil
+	}
+	if value != "" {
+		return "", fmt.Errorf("%s and %s_FILE are both set", name, name)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("%s_FILE: %v", name, err)
+	}
+	return strings.TrimRight(string(data), "\r\n"), nil
+}
+
+// walk calls fn for every leaf field with its tag and full variable name.
+func walk(v reflect.Value, prefix string, fn func(field reflect.Value, tag reflect.StructTag, name string)) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field, structField := v.Field(i), t.Field(i)
+		if field.Kind() == reflect.Struct && field.Type() != reflect.TypeOf(time.Duration(0)) {
+			walk(field, prefix+structField.Tag.Get("envPrefix"), fn)
+			continue
+		}
+		name := ""
+		if env := structField.Tag.Get("env"); env != "" {
+			name = prefix + env
+		}
+		fn(field, structField.Tag, name)
+	}
+}
+
+func setValue(field reflect.Value, value string) error {
+	switch field.Interface().(type) {
+	case string:
+		field.SetString(value)
+	case int:
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("%q is not a number", value)
+		}
+		field.SetInt(int64(n))
+	case float64:
+		f, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return fmt.Errorf("%q is not a number", value)
+		}
+		field.SetFloat(f)
+	case bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("%q is not true or false", value)
+		}
+		field.SetBool(b)
+	case time.Duration:
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return fmt.Errorf("%q is not a duration like 30s or 1h", value)
+		}
+		field.SetInt(int64(d))
+	case []string:
+		var items []string
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		field.Set(reflect.ValueOf(items))
+	default:
+		return fmt.Errorf("unsupported field type %s", field.Type())
+	}
+	return nil
+}
@@ -0,0 +1,39 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+)
+
+const initialBackoff = 500 * time.Millisecond
+
+// Retry calls fn until it succeeds or startup.Timeout passes, waiting twice
+// as long after each failure, up to startup.MaxBackoff. The context given to
+// fn ends with the deadline, so a hanging attempt does not outlive it.
+func Retry(startup StartupConfig, name string, fn func(ctx context.Context) error) error {
+	ctx, cancel := context.WithTimeout(context.Background(), startup.Timeout)
+	defer cancel()
+
+	backoff := initialBackoff
+	for attempt := 1; ; attempt++ {
+		err := fn(ctx)
+		if err == nil {
+			return nil
+		}
+		if ctx.Err() != nil {
+			return fmt.Errorf("%s is not available after %s: %w", name, startup.Timeout, err)
+		}
+		if backoff > startup.MaxBackoff {
+			backoff = startup.MaxBackoff
+		}
+		log.Printf("%s is not available yet (attempt %d), retrying in %s: %v", name, attempt, backoff, err)
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%s is not available after %s: %w", name, startup.Timeout, err)
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
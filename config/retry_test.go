@@ -0,0 +1,39 @@
+package config_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"overcooked-simplified/config"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetry(t *testing.T) {
+	startup := config.StartupConfig{Timeout: 5 * time.Second, MaxBackoff: time.Millisecond}
+
+	t.Run("succeeds once the dependency is up", func(t *testing.T) {
+		attempts := 0
+		err := config.Retry(startup, "Postgres", func(context.Context) error {
+			if attempts++; attempts < 3 {
+				return errors.New("connection refused")
+			}
+			return nil
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 3, attempts)
+	})
+
+	t.Run("gives up at the deadline", func(t *testing.T) {
+		down := errors.New("connection refused")
+		startup := config.StartupConfig{Timeout: 20 * time.Millisecond, MaxBackoff: 5 * time.Millisecond}
+
+		err := config.Retry(startup, "Kafka", func(context.Context) error { return down })
+
+		assert.ErrorIs(t, err, down)
+		assert.ErrorContains(t, err, "Kafka")
+	})
+}
@@ -0,0 +1,83 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"strconv"
+	"text/tabwriter"
+)
+
+const commandUsage = `usage: <service> migrate [command]
+
+commands:
+  up        apply pending migrations (the default)
+  down [N]  revert the last N applied migrations, 1 by default
+  status    list migrations and whether they are applied
+  seed      apply pending migrations, then load the demo data into an empty database`
+
+// RunCommand runs the migrate subcommand every service binary has, with the
+// arguments after "migrate".
+func RunCommand(db *sql.DB, args []string) error {
+	ctx := context.Background()
+	migrator := NewMigrator(db)
+	command := "up"
+	if len(args) > 0 {
+		command, args = args[0], args[1:]
+	}
+
+	switch command {
+	case "up":
+		applied, err := migrator.Up(ctx)
+		fmt.Printf("Applied %d migration(s)\n", applied)
+		return err
+	case "down":
+		steps := 1
+		if len(args) > 0 {
+			n, err := strconv.Atoi(args[0])
+			if err != nil || n < 1 {
+				return fmt.Errorf("bad number of steps %q\n%s", args[0], commandUsage)
+			}
+			steps = n
+		}
+		reverted, err := migrator.Down(ctx, steps)
+		fmt.Printf("Reverted %d migration(s)\n", reverted)
+		return err
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "VERSION\tNAME\tSTATUS")
+		for _, status := range statuses {
+			state := "pending"
+			if status.Applied {
+				state = "applied"
+			}
+			name := status.Name
+			if name == "" {
+				name = "(unknown to this build)"
+			}
+			fmt.Fprintf(w, "%d\t%s\t%s\n", status.Version, name, state)
+		}
+		return w.Flush()
+	case "seed":
+		if _, err := migrator.Up(ctx); err != nil {
+			return err
+		}
+		seeded, err := migrator.Seed(ctx)
+		if err != nil {
+			return err
+		}
+		if seeded {
+			fmt.Println("Demo data loaded")
+		} else {
+			fmt.Println("Database already has restaurants, demo data skipped")
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown migrate command %q\n%s", command, commandUsage)
+	}
+}
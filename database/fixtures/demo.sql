@@ -0,0 +1,56 @@
+-- Демо-данные: три ресторана с блюдами и тремя чеками.
+-- Загружаются только в пустую базу (`migrate seed` или SEED_DEMO_DATA=true).
+-- Ссылки ищутся по названиям, поэтому ID не обязаны начинаться с 1.
+
+-- Рестораны
+INSERT INTO restaurants (name, address, description) VALUES
+    ('Чайхана "Самарканд"', 'ул. Пушкина, д. 10', 'Традиционная узбекская кухня'),
+    ('Суши-бар "Токио"', 'ул. Ленина, д. 25', 'Автентичная японская кухня'),
+    ('Пиццерия "Наполетана"', 'ул. Гагарина, д. 5', 'Итальянская пицца по традиционным рецептам');
+
+-- Блюда
+INSERT INTO dishes (restaurant_id, name, description, price)
+SELECT r.id, d.name, d.description, d.price
+FROM (VALUES
+    ('Чайхана "Самарканд"', 'Плов', 'Традиционный узбекский плов', 350.00),
+    ('Чайхана "Самарканд"', 'Шашлык', 'Свиной шашлык на углях', 280.00),
+    ('Чайхана "Самарканд"', 'Лагман', 'Домашняя лапша с мясом', 320.00),
+    ('Суши-бар "Токио"', 'Ролл Филадельфия', 'Классический ролл с лососем', 420.00),
+    ('Суши-бар "Токио"', 'Суши с тунцом', 'Традиционные суши', 180.00),
+    ('Суши-бар "Токио"', 'Суп Мисо', 'Традиционный японский суп', 120.00),
+    ('Пиццерия "Наполетана"', 'Маргарита', 'Традиционная итальянская пицца', 450.00),
+    ('Пиццерия "Наполетана"', 'Пепперони', 'Пицца с пепперони и сыром', 520.00),
+    ('Пиццерия "Наполетана"', 'Четыре сезона', 'Пицца с 4 видами сыра', 580.00)
+) AS d (restaurant, name, description, price)
+JOIN restaurants r ON r.name = d.restaurant;
+
+UPDATE dishes SET external_sku = 'dish-' || id WHERE external_sku IS NULL;
+
+-- Начальные цены блюд в истории цен
+INSERT INTO dish_price_history (dish_id, price, changed_at)
+SELECT id, price, created_at FROM dishes;
+
+-- Чеки: Самарканд 2×Плов + 1×Шашлык = 980, Токио 1×Филадельфия + 2×Мисо = 660,
+-- Наполетана 2×Маргарита + 1×Пепперони = 1420
+INSERT INTO orders (restaurant_id, total_amount, status)
+SELECT r.id, o.total, 'completed'
+FROM (VALUES
+    ('Чайхана "Самарканд"', 980.00),
+    ('Суши-бар "Токио"', 660.00),
+    ('Пиццерия "Наполетана"', 1420.00)
+) AS o (restaurant, total)
+JOIN restaurants r ON r.name = o.restaurant
+ORDER BY r.id;
+
+INSERT INTO order_items (order_id, dish_id, quantity, price)
+SELECT o.id, d.id, i.quantity, d.price
+FROM (VALUES
+    ('Плов', 2),
+    ('Шашлык', 1),
+    ('Ролл Филадельфия', 1),
+    ('Суп Мисо', 2),
+    ('Маргарита', 2),
+    ('Пепперони', 1)
+) AS i (dish, quantity)
+JOIN dishes d ON d.name = i.dish
+JOIN orders o ON o.restaurant_id = d.restaurant_id;
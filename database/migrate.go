@@ -0,0 +1,272 @@
+// Package database keeps the Postgres schema shared by all services as
+// versioned migrations, plus the demo data.
+package database
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"fmt"
+	"io/fs"
+	"log"
+	"regexp"
+	"sort"
+	"strconv"
+)
+
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+//go:embed fixtures/demo.sql
+var demoData string
+
+// lockID keys the advisory lock that serializes migrations, so services
+// starting at the same time apply each migration once.
+const lockID int64 = 7_231_045_117
+
+// Migration is one version of the schema. Down may be empty, which makes
+// the migration irreversible.
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+}
+
+// MigrationStatus tells whether a migration is applied. Migrations applied
+// by a newer build are listed with an empty Name.
+type MigrationStatus struct {
+	Version int
+	Name    string
+	Applied bool
+}
+
+var migrationFileName = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+// LoadMigrations reads <version>_<name>.up.sql and the matching .down.sql
+// files, ordered by version.
+func LoadMigrations(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, err
+	}
+	byVersion := make(map[int]*Migration)
+	for _, entry := range entries {
+		match := migrationFileName.FindStringSubmatch(entry.Name())
+		if match == nil {
+			return nil, fmt.Errorf("unexpected migration file %s", entry.Name())
+		}
+		version, _ := strconv.Atoi(match[1])
+		data, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return nil, err
+		}
+		m := byVersion[version]
+		if m == nil {
+			m = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = m
+		}
+		if m.Name != match[2] {
+			return nil, fmt.Errorf("migration %d is named both %s and %s", version, m.Name, match[2])
+		}
+		if match[3] == "up" {
+			m.Up = string(data)
+		} else {
+			m.Down = string(data)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == "" {
+			return nil, fmt.Errorf("migration %d_%s has no up file", m.Version, m.Name)
+		}
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+// Migrations returns the migrations built into the binary.
+func Migrations() []Migration {
+	sub, err := fs.Sub(migrationFiles, "migrations")
+	if err != nil {
+		panic(err)
+	}
+	migrations, err := LoadMigrations(sub)
+	if err != nil {
+		panic(err)
+	}
+	return migrations
+}
+
+type Migrator struct {
+	DB         *sql.DB
+	Migrations []Migration
+}
+
+func NewMigrator(db *sql.DB) *Migrator {
+	return &Migrator{DB: db, Migrations: Migrations()}
+}
+
+// Up applies the pending migrations in order, each in its own transaction,
+// and returns how many it applied.
+func (m *Migrator) Up(ctx context.Context) (int, error) {
+	applied := 0
+	err := m.locked(ctx, func(conn *sql.Conn, done map[int]bool) error {
+		for _, migration := range m.Migrations {
+			if done[migration.Version] {
+				continue
+			}
+			log.Printf("Applying migration %d_%s", migration.Version, migration.Name)
+			if err := inTx(ctx, conn, migration.Up,
+				"INSERT INTO schema_migrations (version, name) VALUES ($1, $2)", migration.Version, migration.Name); err != nil {
+				return fmt.Errorf("migration %d_%s: %w", migration.Version, migration.Name, err)
+			}
+			applied++
+		}
+		return nil
+	})
+	return applied, err
+}
+
+// Down reverts the last steps applied migrations, newest first.
+func (m *Migrator) Down(ctx context.Context, steps int) (int, error) {
+	reverted := 0
+	err := m.locked(ctx, func(conn *sql.Conn, done map[int]bool) error {
+		for i := len(m.Migrations) - 1; i >= 0 && reverted < steps; i-- {
+			migration := m.Migrations[i]
+			if !done[migration.Version] {
+				continue
+			}
+			if migration.Down == "" {
+				return fmt.Errorf("migration %d_%s cannot be reverted", migration.Version, migration.Name)
+			}
+			log.Printf("Reverting migration %d_%s", migration.Version, migration.Name)
+			if err := inTx(ctx, conn, migration.Down,
+				"DELETE FROM schema_migrations WHERE version = $1", migration.Version); err != nil {
+				return fmt.Errorf("revert %d_%s: %w", migration.Version, migration.Name, err)
+			}
+			reverted++
+		}
+		return nil
+	})
+	return reverted, err
+}
+
+func (m *Migrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	var statuses []MigrationStatus
+	err := m.locked(ctx, func(conn *sql.Conn, done map[int]bool) error {
+		known := make(map[int]bool, len(m.Migrations))
+		for _, migration := range m.Migrations {
+			known[migration.Version] = true
+			statuses = append(statuses, MigrationStatus{Version: migration.Version, Name: migration.Name, Applied: done[migration.Version]})
+		}
+		for version := range done {
+			if !known[version] {
+				statuses = append(statuses, MigrationStatus{Version: version, Applied: true})
+			}
+		}
+		return nil
+	})
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Version < statuses[j].Version })
+	return statuses, err
+}
+
+// Seed loads the demo data into a database without restaurants and reports
+// whether it did. The schema must be migrated already.
+func (m *Migrator) Seed(ctx context.Context) (bool, error) {
+	seeded := false
+	err := m.locked(ctx, func(conn *sql.Conn, _ map[int]bool) error {
+		var hasData bool
+		if err := conn.QueryRowContext(ctx, "SELECT EXISTS (SELECT 1 FROM restaurants)").Scan(&hasData); err != nil {
+			return err
+		}
+		if hasData {
+			return nil
+		}
+		if err := inTx(ctx, conn, demoData, ""); err != nil {
+			return fmt.Errorf("demo data: %w", err)
+		}
+		seeded = true
+		return nil
+	})
+	return seeded, err
+}
+
+// locked runs fn on one connection holding the migration lock, with the
+// versions applied so far. Advisory locks belong to the session, so every
+// statement has to go through that connection.
+func (m *Migrator) locked(ctx context.Context, fn func(conn *sql.Conn, applied map[int]bool) error) error {
+	conn, err := m.DB.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, "SELECT pg_advisory_lock($1)", lockID); err != nil {
+		return fmt.Errorf("lock migrations: %w", err)
+	}
+	defer conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock($1)", lockID)
+
+	if _, err := conn.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (
+		version BIGINT PRIMARY KEY,
+		name TEXT NOT NULL,
+		applied_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+	)`); err != nil {
+		return err
+	}
+	rows, err := conn.QueryContext(ctx, "SELECT version FROM schema_migrations")
+	if err != nil {
+		return err
+	}
+	applied := make(map[int]bool)
+	for rows.Next() {
+		var version int
+		if err := rows.Scan(&version); err != nil {
+			rows.Close()
+			return err
+		}
+		applied[version] = true
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	return fn(conn, applied)
+}
+
+// inTx runs script and then the bookkeeping statement, if any, in one
+// transaction.
+func inTx(ctx context.Context, conn *sql.Conn, script, bookkeeping string, args ...interface{}) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	if _, err := tx.ExecContext(ctx, script); err != nil {
+		return err
+	}
+	if bookkeeping != "" {
+		if _, err := tx.ExecContext(ctx, bookkeeping, args...); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// MustMigrate brings the schema up to date when a service starts, unless
+// onStart is off, and loads the demo data into an empty database with seed.
+func MustMigrate(db *sql.DB, onStart, seed bool) {
+	migrator := NewMigrator(db)
+	if onStart {
+		if _, err := migrator.Up(context.Background()); err != nil {
+			log.Fatal("Failed to migrate database: ", err)
+		}
+	}
+	if seed {
+		if _, err := migrator.Seed(context.Background()); err != nil {
+			log.Fatal("Failed to load demo data: ", err)
+		}
+	}
+}
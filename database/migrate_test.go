@@ -0,0 +1,129 @@
+package database_test
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"testing"
+	"testing/fstest"
+
+	"overcooked-simplified/database"
+
+	_ "github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadMigrations(t *testing.T) {
+	tests := []struct {
+		name    string
+		files   fstest.MapFS
+		want    []database.Migration
+		wantErr bool
+	}{
+		{
+			name: "ordered by version",
+			files: fstest.MapFS{
+				"0010_add_tables.up.sql":   {Data: []byte("CREATE TABLE t ();")},
+				"0002_baseline.up.sql":     {Data: []byte("CREATE TABLE b ();")},
+				"0002_baseline.down.sql":   {Data: []byte("DROP TABLE b;")},
+				"0010_add_tables.down.sql": {Data: []byte("DROP TABLE t;")},
+			},
+			want: []database.Migration{
+				{Version: 2, Name: "baseline", Up: "CREATE TABLE b ();", Down: "DROP TABLE b;"},
+				{Version: 10, Name: "add_tables", Up: "CREATE TABLE t ();", Down: "DROP TABLE t;"},
+			},
+		},
+		{
+			name:  "irreversible",
+			files: fstest.MapFS{"0001_backfill.up.sql": {Data: []byte("UPDATE x SET y = 1;")}},
+			want:  []database.Migration{{Version: 1, Name: "backfill", Up: "UPDATE x SET y = 1;"}},
+		},
+		{name: "down without up", files: fstest.MapFS{"0001_baseline.down.sql": {Data: []byte("DROP TABLE b;")}}, wantErr: true},
+		{name: "stray file", files: fstest.MapFS{"README.md": {Data: []byte("hi")}}, wantErr: true},
+		{
+			name: "one version, two names",
+			files: fstest.MapFS{
+				"0001_baseline.up.sql": {Data: []byte("CREATE TABLE b ();")},
+				"0001_other.down.sql":  {Data: []byte("DROP TABLE b;")},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			migrations, err := database.LoadMigrations(testCase.files)
+
+			if testCase.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.want, migrations)
+		})
+	}
+}
+
+func TestBuiltInMigrations(t *testing.T) {
+	migrations := database.Migrations()
+
+	assert.NotEmpty(t, migrations)
+	for i, migration := range migrations {
+		assert.Equal(t, i+1, migration.Version, "versions have no gaps")
+		if migration.Version == 1 {
+			// the baseline adopts databases that predate migrations, reverting
+			// it would drop their data
+			assert.Empty(t, migration.Down, "the baseline cannot be reverted")
+			continue
+		}
+		assert.NotEmpty(t, migration.Down, "migration %d_%s can be reverted", migration.Version, migration.Name)
+	}
+}
+
+// TestMigrationsUpDownUp runs the real migrations against TEST_POSTGRES_DSN in
+// a schema of its own, so other packages' tests keep their tables.
+func TestMigrationsUpDownUp(t *testing.T) {
+	dsn := os.Getenv("TEST_POSTGRES_DSN")
+	if dsn == "" {
+		t.Skip("TEST_POSTGRES_DSN is not set")
+	}
+	db, err := sql.Open("postgres", dsn)
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	// one connection, so SET search_path holds for every statement
+	db.SetMaxOpenConns(1)
+
+	ctx := context.Background()
+	schema := fmt.Sprintf("migrate_test_%d", os.Getpid())
+	_, err = db.ExecContext(ctx, "CREATE SCHEMA "+schema)
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Exec("DROP SCHEMA " + schema + " CASCADE") })
+	// public stays on the path for the pg_trgm operator classes
+	_, err = db.ExecContext(ctx, "SET search_path TO "+schema+", public")
+	require.NoError(t, err)
+
+	migrator := database.NewMigrator(db)
+	all := len(database.Migrations())
+
+	applied, err := migrator.Up(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, all, applied)
+
+	reverted, err := migrator.Down(ctx, all-1)
+	require.NoError(t, err)
+	assert.Equal(t, all-1, reverted)
+	statuses, err := migrator.Status(ctx)
+	require.NoError(t, err)
+	for _, status := range statuses {
+		assert.Equal(t, status.Version == 1, status.Applied, "migration %d_%s", status.Version, status.Name)
+	}
+
+	_, err = migrator.Down(ctx, 1)
+	assert.ErrorContains(t, err, "cannot be reverted", "the baseline stays")
+
+	applied, err = migrator.Up(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, all-1, applied)
+}
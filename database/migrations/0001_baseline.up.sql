@@ -0,0 +1,62 @@
+-- Схема на момент перехода на миграции (бывший database/schema.sql).
+-- Всё через IF NOT EXISTS, чтобы миграция применялась и к уже работающей базе.
+-- Всё, что появилось позже, — в следующих миграциях.
+
+-- Таблица ресторанов (с image_url сразу)
+CREATE TABLE IF NOT EXISTS restaurants (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    address TEXT,
+    description TEXT,
+    image_url TEXT,  -- Сразу добавили поле для фото
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Таблица блюд (с каскадным удалением сразу)
+CREATE TABLE IF NOT EXISTS dishes (
+    id SERIAL PRIMARY KEY,
+    restaurant_id INTEGER REFERENCES restaurants(id) ON DELETE CASCADE,  -- Каскад сразу прописан
+    name VARCHAR(255) NOT NULL,
+    description TEXT,
+    price DECIMAL(10, 2),
+    image_url TEXT,
+    avg_rating DECIMAL(3,2) DEFAULT 0,
+    review_count INTEGER DEFAULT 0,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Таблица заказов
+CREATE TABLE IF NOT EXISTS orders (
+    id SERIAL PRIMARY KEY,
+    restaurant_id INTEGER REFERENCES restaurants(id) ON DELETE CASCADE,
+    total_amount DECIMAL(10, 2),
+    status VARCHAR(50) DEFAULT 'pending',
+    qr_code BYTEA,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Таблица позиций заказов
+CREATE TABLE IF NOT EXISTS order_items (
+    id SERIAL PRIMARY KEY,
+    order_id INTEGER REFERENCES orders(id) ON DELETE CASCADE,
+    dish_id INTEGER REFERENCES dishes(id) ON DELETE CASCADE,
+    quantity INTEGER NOT NULL DEFAULT 1,
+    price DECIMAL(10, 2),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Таблица отзывов
+CREATE TABLE IF NOT EXISTS reviews (
+    id SERIAL PRIMARY KEY,
+    dish_id INTEGER REFERENCES dishes(id) ON DELETE CASCADE,
+    order_id INTEGER REFERENCES orders(id) ON DELETE CASCADE,
+    restaurant_id INTEGER REFERENCES restaurants(id) ON DELETE CASCADE,
+    rating INTEGER CHECK (rating >= 1 AND rating <= 5),
+    comment TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT unique_review_per_order UNIQUE (dish_id, order_id)
+);
+
+-- Базы, созданные ещё раньше, до этих колонок (их дописывал EnsureSchema)
+ALTER TABLE restaurants ADD COLUMN IF NOT EXISTS description TEXT;
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS qr_code BYTEA;
@@ -0,0 +1,3 @@
+ALTER TABLE dishes DROP COLUMN IF EXISTS sort_order;
+ALTER TABLE dishes DROP COLUMN IF EXISTS category_id;
+DROP TABLE IF EXISTS menu_categories;
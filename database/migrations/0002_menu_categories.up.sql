@@ -0,0 +1,12 @@
+-- Категории (разделы) меню
+CREATE TABLE IF NOT EXISTS menu_categories (
+    id SERIAL PRIMARY KEY,
+    restaurant_id INTEGER REFERENCES restaurants(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    sort_order INTEGER NOT NULL DEFAULT 0,
+    visible BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+ALTER TABLE dishes ADD COLUMN IF NOT EXISTS category_id INTEGER REFERENCES menu_categories(id) ON DELETE SET NULL;
+ALTER TABLE dishes ADD COLUMN IF NOT EXISTS sort_order INTEGER NOT NULL DEFAULT 0;
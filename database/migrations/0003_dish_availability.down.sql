@@ -0,0 +1,2 @@
+ALTER TABLE dishes DROP COLUMN IF EXISTS availability_windows;
+ALTER TABLE dishes DROP COLUMN IF EXISTS stop_listed;
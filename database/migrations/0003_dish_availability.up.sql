@@ -0,0 +1,2 @@
+ALTER TABLE dishes ADD COLUMN IF NOT EXISTS stop_listed BOOLEAN NOT NULL DEFAULT FALSE;       -- Стоп-лист
+ALTER TABLE dishes ADD COLUMN IF NOT EXISTS availability_windows JSONB NOT NULL DEFAULT '[]'; -- Часы доступности, например завтраки до 11:00
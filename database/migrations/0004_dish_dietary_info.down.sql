@@ -0,0 +1,3 @@
+ALTER TABLE dishes DROP COLUMN IF EXISTS nutrition;
+ALTER TABLE dishes DROP COLUMN IF EXISTS dietary_tags;
+ALTER TABLE dishes DROP COLUMN IF EXISTS allergens;
@@ -0,0 +1,3 @@
+ALTER TABLE dishes ADD COLUMN IF NOT EXISTS allergens TEXT[] NOT NULL DEFAULT '{}';    -- 14 аллергенов ЕС: gluten, milk, nuts, ...
+ALTER TABLE dishes ADD COLUMN IF NOT EXISTS dietary_tags TEXT[] NOT NULL DEFAULT '{}'; -- vegan, halal, gluten-free, ...
+ALTER TABLE dishes ADD COLUMN IF NOT EXISTS nutrition JSONB;                           -- КБЖУ на порцию
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS order_item_options;
+DROP TABLE IF EXISTS modifier_options;
+DROP TABLE IF EXISTS modifier_groups;
@@ -0,0 +1,27 @@
+-- Группы модификаторов блюда (размер, добавки) и их опции
+CREATE TABLE IF NOT EXISTS modifier_groups (
+    id SERIAL PRIMARY KEY,
+    dish_id INTEGER REFERENCES dishes(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    min_select INTEGER NOT NULL DEFAULT 0,
+    max_select INTEGER NOT NULL DEFAULT 1,
+    sort_order INTEGER NOT NULL DEFAULT 0
+);
+
+CREATE TABLE IF NOT EXISTS modifier_options (
+    id SERIAL PRIMARY KEY,
+    group_id INTEGER REFERENCES modifier_groups(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    price_delta DECIMAL(10, 2) NOT NULL DEFAULT 0,
+    sort_order INTEGER NOT NULL DEFAULT 0
+);
+
+-- Выбранные опции позиции чека (название и цена копируются на момент заказа)
+CREATE TABLE IF NOT EXISTS order_item_options (
+    id SERIAL PRIMARY KEY,
+    order_item_id INTEGER REFERENCES order_items(id) ON DELETE CASCADE,
+    option_id INTEGER REFERENCES modifier_options(id) ON DELETE SET NULL,
+    group_name VARCHAR(255) NOT NULL DEFAULT '',
+    name VARCHAR(255) NOT NULL,
+    price_delta DECIMAL(10, 2) NOT NULL DEFAULT 0
+);
@@ -0,0 +1,14 @@
+-- История цен блюд (пишется при создании блюда и каждом изменении цены)
+CREATE TABLE IF NOT EXISTS dish_price_history (
+    id SERIAL PRIMARY KEY,
+    dish_id INTEGER REFERENCES dishes(id) ON DELETE CASCADE,
+    price DECIMAL(10, 2),
+    changed_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_dish_price_history_dish ON dish_price_history (dish_id, changed_at);
+
+-- Начальные цены для уже существующих блюд
+INSERT INTO dish_price_history (dish_id, price, changed_at)
+SELECT d.id, d.price, d.created_at FROM dishes d
+WHERE NOT EXISTS (SELECT 1 FROM dish_price_history h WHERE h.dish_id = d.id);
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS menu_versions;
+DROP TABLE IF EXISTS menu_drafts;
+ALTER TABLE orders DROP COLUMN IF EXISTS menu_version;
+ALTER TABLE restaurants DROP COLUMN IF EXISTS menu_version;
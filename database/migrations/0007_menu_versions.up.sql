@@ -0,0 +1,24 @@
+ALTER TABLE restaurants ADD COLUMN IF NOT EXISTS menu_version INTEGER NOT NULL DEFAULT 0;  -- Текущая опубликованная версия меню
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS menu_version INTEGER NOT NULL DEFAULT 0;       -- Версия меню, по которой оформлен чек
+
+-- Черновик меню: изменения блюд и категорий до публикации
+CREATE TABLE IF NOT EXISTS menu_drafts (
+    id SERIAL PRIMARY KEY,
+    restaurant_id INTEGER REFERENCES restaurants(id) ON DELETE CASCADE,
+    entity VARCHAR(16) NOT NULL,  -- dish | category
+    action VARCHAR(16) NOT NULL,  -- upsert | delete
+    entity_id INTEGER NOT NULL DEFAULT 0,  -- 0 для новых блюд и категорий
+    payload JSONB,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Опубликованные версии меню (снимок категорий и блюд)
+CREATE TABLE IF NOT EXISTS menu_versions (
+    id SERIAL PRIMARY KEY,
+    restaurant_id INTEGER REFERENCES restaurants(id) ON DELETE CASCADE,
+    version INTEGER NOT NULL,
+    rolled_back_from INTEGER,  -- Версия, к которой откатили меню
+    snapshot JSONB NOT NULL,
+    published_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (restaurant_id, version)
+);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_dishes_restaurant_sku;
+ALTER TABLE dishes DROP COLUMN IF EXISTS external_sku;
@@ -0,0 +1,4 @@
+-- Артикул для импорта/экспорта меню, по умолчанию dish-<id>
+ALTER TABLE dishes ADD COLUMN IF NOT EXISTS external_sku VARCHAR(64);
+UPDATE dishes SET external_sku = 'dish-' || id WHERE external_sku IS NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_dishes_restaurant_sku ON dishes (restaurant_id, external_sku);
@@ -0,0 +1,3 @@
+-- Удалённые записи при откате становятся снова видимыми
+ALTER TABLE dishes DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE restaurants DROP COLUMN IF EXISTS deleted_at;
@@ -0,0 +1,3 @@
+-- Мягкое удаление: заказы и отзывы сохраняются для аналитики
+ALTER TABLE restaurants ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP;
+ALTER TABLE dishes ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP;
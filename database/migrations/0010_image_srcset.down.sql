@@ -0,0 +1,4 @@
+ALTER TABLE dishes DROP COLUMN IF EXISTS image_webp_srcset;
+ALTER TABLE dishes DROP COLUMN IF EXISTS image_srcset;
+ALTER TABLE restaurants DROP COLUMN IF EXISTS image_webp_srcset;
+ALTER TABLE restaurants DROP COLUMN IF EXISTS image_srcset;
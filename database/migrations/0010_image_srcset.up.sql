@@ -0,0 +1,5 @@
+-- Миниатюры для <img srcset>: "url 320w, url 640w, ..." и то же в WebP
+ALTER TABLE restaurants ADD COLUMN IF NOT EXISTS image_srcset TEXT;
+ALTER TABLE restaurants ADD COLUMN IF NOT EXISTS image_webp_srcset TEXT;
+ALTER TABLE dishes ADD COLUMN IF NOT EXISTS image_srcset TEXT;
+ALTER TABLE dishes ADD COLUMN IF NOT EXISTS image_webp_srcset TEXT;
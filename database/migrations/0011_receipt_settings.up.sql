@@ -0,0 +1,11 @@
+-- Оформление печатного чека и QR-кода для отзыва
+CREATE TABLE IF NOT EXISTS receipt_settings (
+    restaurant_id INTEGER PRIMARY KEY REFERENCES restaurants(id) ON DELETE CASCADE,
+    qr_size INTEGER NOT NULL DEFAULT 160,         -- Сторона QR-кода в CSS-пикселях
+    qr_level CHAR(1) NOT NULL DEFAULT 'M',        -- Уровень коррекции ошибок: L, M, Q, H
+    qr_color VARCHAR(7) NOT NULL DEFAULT '#000000',
+    background_color VARCHAR(7) NOT NULL DEFAULT '#ffffff',
+    accent_color VARCHAR(7) NOT NULL DEFAULT '#111827',
+    logo_url TEXT,                                -- Логотип поверх QR-кода
+    footer_text TEXT
+);
@@ -0,0 +1,2 @@
+ALTER TABLE receipt_settings DROP COLUMN IF EXISTS printer_qr;
+ALTER TABLE receipt_settings DROP COLUMN IF EXISTS paper_width;
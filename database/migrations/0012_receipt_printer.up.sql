@@ -0,0 +1,2 @@
+ALTER TABLE receipt_settings ADD COLUMN IF NOT EXISTS paper_width INTEGER NOT NULL DEFAULT 80;       -- Ширина ленты термопринтера, мм: 58 или 80
+ALTER TABLE receipt_settings ADD COLUMN IF NOT EXISTS printer_qr VARCHAR(8) NOT NULL DEFAULT 'native'; -- QR на термопринтере: native (команда ESC/POS) или raster
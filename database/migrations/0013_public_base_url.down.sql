@@ -0,0 +1 @@
+ALTER TABLE restaurants DROP COLUMN IF EXISTS public_base_url;
@@ -0,0 +1 @@
+ALTER TABLE restaurants ADD COLUMN IF NOT EXISTS public_base_url TEXT;  -- Свой домен для ссылок в QR-кодах вместо PUBLIC_BASE_URL
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS visit_ratings;
+ALTER TABLE orders DROP COLUMN IF EXISTS table_id;
+DROP TABLE IF EXISTS tables;
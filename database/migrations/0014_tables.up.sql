@@ -0,0 +1,22 @@
+-- Столы зала: статичный QR-код на столе открывает меню и оценку визита
+CREATE TABLE IF NOT EXISTS tables (
+    id SERIAL PRIMARY KEY,
+    restaurant_id INTEGER REFERENCES restaurants(id) ON DELETE CASCADE,
+    label VARCHAR(64) NOT NULL,               -- Номер или название стола
+    section VARCHAR(64) NOT NULL DEFAULT '',  -- Зона зала (веранда, второй этаж, зона официанта)
+    code VARCHAR(16) NOT NULL UNIQUE,         -- Код из QR-кода: /t/<code>
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS table_id INTEGER REFERENCES tables(id) ON DELETE SET NULL;  -- Стол, за которым сделан заказ
+
+-- Оценки визита, оставленные по QR-коду стола; заказ можно привязать позже
+CREATE TABLE IF NOT EXISTS visit_ratings (
+    id SERIAL PRIMARY KEY,
+    restaurant_id INTEGER REFERENCES restaurants(id) ON DELETE CASCADE,
+    table_id INTEGER REFERENCES tables(id) ON DELETE SET NULL,
+    order_id INTEGER REFERENCES orders(id) ON DELETE SET NULL,
+    rating INTEGER CHECK (rating >= 1 AND rating <= 5),
+    comment TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
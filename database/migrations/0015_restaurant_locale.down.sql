@@ -0,0 +1,3 @@
+ALTER TABLE restaurants DROP COLUMN IF EXISTS opening_hours;
+ALTER TABLE restaurants DROP COLUMN IF EXISTS locale;
+ALTER TABLE restaurants DROP COLUMN IF EXISTS time_zone;
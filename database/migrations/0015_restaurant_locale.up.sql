@@ -0,0 +1,3 @@
+ALTER TABLE restaurants ADD COLUMN IF NOT EXISTS time_zone VARCHAR(64) NOT NULL DEFAULT 'UTC';  -- Часовой пояс IANA: по нему считается «сегодня» в аналитике
+ALTER TABLE restaurants ADD COLUMN IF NOT EXISTS locale VARCHAR(35) NOT NULL DEFAULT 'ru-RU';   -- Язык и формат по умолчанию (BCP 47)
+ALTER TABLE restaurants ADD COLUMN IF NOT EXISTS opening_hours JSONB;  -- Часы работы: {"weekly": [...], "exceptions": [...]}
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS dish_translations;
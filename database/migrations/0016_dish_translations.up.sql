@@ -0,0 +1,8 @@
+-- Переводы названий и описаний блюд; исходный текст блюда — на языке ресторана (restaurants.locale)
+CREATE TABLE IF NOT EXISTS dish_translations (
+    dish_id INTEGER REFERENCES dishes(id) ON DELETE CASCADE,
+    lang VARCHAR(35) NOT NULL,  -- Тег BCP 47: en, en-GB, uz-Latn
+    name VARCHAR(255) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    PRIMARY KEY (dish_id, lang)
+);
@@ -0,0 +1,18 @@
+-- Триграммы для поиска с опечатками (GET /api/search)
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- Полнотекстовый поиск сразу по-русски и по-английски: название важнее описания, описание важнее адреса
+ALTER TABLE restaurants ADD COLUMN IF NOT EXISTS search_vector tsvector GENERATED ALWAYS AS (
+    setweight(to_tsvector('russian', COALESCE(name, '')), 'A') || setweight(to_tsvector('english', COALESCE(name, '')), 'A') ||
+    setweight(to_tsvector('russian', COALESCE(description, '')), 'B') || setweight(to_tsvector('english', COALESCE(description, '')), 'B') ||
+    setweight(to_tsvector('russian', COALESCE(address, '')), 'C')
+) STORED;
+ALTER TABLE dishes ADD COLUMN IF NOT EXISTS search_vector tsvector GENERATED ALWAYS AS (
+    setweight(to_tsvector('russian', COALESCE(name, '')), 'A') || setweight(to_tsvector('english', COALESCE(name, '')), 'A') ||
+    setweight(to_tsvector('russian', COALESCE(description, '')), 'B') || setweight(to_tsvector('english', COALESCE(description, '')), 'B')
+) STORED;
+
+CREATE INDEX IF NOT EXISTS idx_restaurants_search ON restaurants USING gin (search_vector);
+CREATE INDEX IF NOT EXISTS idx_restaurants_name_trgm ON restaurants USING gin (name gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_dishes_search ON dishes USING gin (search_vector);
+CREATE INDEX IF NOT EXISTS idx_dishes_name_trgm ON dishes USING gin (name gin_trgm_ops);
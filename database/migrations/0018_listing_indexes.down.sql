@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_orders_restaurant_created;
+DROP INDEX IF EXISTS idx_orders_created;
+DROP INDEX IF EXISTS idx_restaurants_created;
@@ -0,0 +1,4 @@
+-- Постраничные списки ресторанов и чеков идут по (created_at, id)
+CREATE INDEX IF NOT EXISTS idx_restaurants_created ON restaurants (created_at, id);
+CREATE INDEX IF NOT EXISTS idx_orders_created ON orders (created_at, id);
+CREATE INDEX IF NOT EXISTS idx_orders_restaurant_created ON orders (restaurant_id, created_at, id);
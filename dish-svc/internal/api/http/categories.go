@@ -0,0 +1,137 @@
+package httpapi
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"overcooked-simplified/dish-svc/internal/domain"
+
+	"github.com/gorilla/mux"
+)
+
+func (h *Handler) getGroupedMenu(w http.ResponseWriter, r *http.Request, restaurantID int, filter domain.DishFilter) {
+	sections, err := h.Categories.Menu(r.Context(), restaurantID, filter)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	h.localizeMenu(w, r, restaurantID, sections)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(sections)
+}
+
+func (h *Handler) createCategory(w http.ResponseWriter, r *http.Request) {
+	restaurantID, _ := strconv.Atoi(mux.Vars(r)["restaurantId"])
+	cat := domain.MenuCategory{Visible: true}
+	if err := json.NewDecoder(r.Body).Decode(&cat); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if cat.Name == "" {
+		http.Error(w, "Category name is required", http.StatusBadRequest)
+		return
+	}
+	cat.RestaurantID = restaurantID
+	if err := h.Categories.Create(r.Context(), &cat); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(cat)
+}
+
+func (h *Handler) getCategories(w http.ResponseWriter, r *http.Request) {
+	restaurantID, _ := strconv.Atoi(mux.Vars(r)["restaurantId"])
+	categories, err := h.Categories.List(r.Context(), restaurantID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if categories == nil {
+		categories = []domain.MenuCategory{}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(categories)
+}
+
+func (h *Handler) updateCategory(w http.ResponseWriter, r *http.Request) {
+	restaurantID, _ := strconv.Atoi(mux.Vars(r)["restaurantId"])
+	categoryID, _ := strconv.Atoi(mux.Vars(r)["categoryId"])
+	cat := domain.MenuCategory{Visible: true}
+	if err := json.NewDecoder(r.Body).Decode(&cat); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	cat.ID = categoryID
+	cat.RestaurantID = restaurantID
+	if err := h.Categories.Update(r.Context(), &cat); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			http.Error(w, "Category not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(cat)
+}
+
+func (h *Handler) deleteCategory(w http.ResponseWriter, r *http.Request) {
+	restaurantID, _ := strconv.Atoi(mux.Vars(r)["restaurantId"])
+	categoryID, _ := strconv.Atoi(mux.Vars(r)["categoryId"])
+	rows, err := h.Categories.Delete(r.Context(), restaurantID, categoryID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if rows == 0 {
+		http.Error(w, "Category not found", http.StatusNotFound)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) reorderCategories(w http.ResponseWriter, r *http.Request) {
+	restaurantID, _ := strconv.Atoi(mux.Vars(r)["restaurantId"])
+	var payload struct {
+		CategoryIDs []int `json:"category_ids"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := h.Categories.Reorder(r.Context(), restaurantID, payload.CategoryIDs); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) reorderCategoryDishes(w http.ResponseWriter, r *http.Request) {
+	restaurantID, _ := strconv.Atoi(mux.Vars(r)["restaurantId"])
+	categoryID, _ := strconv.Atoi(mux.Vars(r)["categoryId"])
+	var payload struct {
+		DishIDs []int `json:"dish_ids"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := h.Categories.ReorderDishes(r.Context(), restaurantID, categoryID, payload.DishIDs); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,61 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
+const (
+	defaultSignedURLTTL = 15 * time.Minute
+	maxSignedURLTTL     = 7 * 24 * time.Hour
+)
+
+// signFileURL turns the URL of an uploaded file into a temporary one:
+// {"url": "/uploads/abc.jpg", "ttl_seconds": 600}.
+func (h *Handler) signFileURL(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		URL        string `json:"url"`
+		TTLSeconds int    `json:"ttl_seconds"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if h.Blobs == nil {
+		http.Error(w, "File storage is not configured", http.StatusNotImplemented)
+		return
+	}
+	ttl := defaultSignedURLTTL
+	if req.TTLSeconds != 0 {
+		ttl = time.Duration(req.TTLSeconds) * time.Second
+	}
+	if ttl <= 0 || ttl > maxSignedURLTTL {
+		http.Error(w, "ttl_seconds must be between 1 and 604800", http.StatusBadRequest)
+		return
+	}
+	key, ok := h.Blobs.KeyFromURL(req.URL)
+	if !ok {
+		http.Error(w, "Not a URL of an uploaded file", http.StatusBadRequest)
+		return
+	}
+	exists, err := h.Blobs.Exists(key)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if !exists {
+		http.Error(w, "File not found", http.StatusNotFound)
+		return
+	}
+	signed, err := h.Blobs.SignedURL(key, ttl)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"url":        signed,
+		"expires_at": time.Now().Add(ttl).UTC(),
+	})
+}
@@ -1,36 +1,64 @@
 package httpapi
 
 import (
+	"database/sql"
 	"encoding/json"
-	"io"
+	"errors"
 	"log"
+	"mime/multipart"
 	"net/http"
-	"os"
 	"overcooked-simplified/dish-svc/internal/domain"
 	"overcooked-simplified/dish-svc/internal/service"
-	"path/filepath"
+	"overcooked-simplified/health"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gorilla/mux"
 )
 
-type Handler struct {
+const maxImageUploadSize = 10 << 20
+
+// Deps are the services a Handler is built from. Blobs, Translations and
+// Health are optional; the routes of any other service left nil fail on use,
+// so tests set only the ones they call.
+type Deps struct {
 	Restaurants service.RestaurantServiceInterface
 	Dishes      service.DishServiceInterface
 	Orders      service.OrderServiceInterface
+	Categories  service.CategoryServiceInterface
+	Modifiers   service.ModifierServiceInterface
+	Menu        service.MenuVersionServiceInterface
+	Imports     service.MenuImportServiceInterface
+	// Blobs is where uploads live; it is optional and only needed for
+	// signed file URLs.
+	Blobs service.BlobStore
+	// Receipts renders printable receipts and keeps their settings.
+	Receipts service.ReceiptServiceInterface
+	// Tables keeps the dining tables and their static QR codes.
+	Tables service.TableServiceInterface
+	// Translations keeps dish translations; without it menus are shown in
+	// the restaurant's own language only.
+	Translations service.TranslationServiceInterface
+	// Search finds restaurants and dishes by text.
+	Search service.SearchServiceInterface
+	// Health serves /livez and /readyz when set.
+	Health *health.Checker
 }
 
-func NewHandler(restSvc service.RestaurantServiceInterface, dishSvc service.DishServiceInterface, orderSvc service.OrderServiceInterface) *Handler {
-	return &Handler{
-		Restaurants: restSvc,
-		Dishes:      dishSvc,
-		Orders:      orderSvc,
-	}
+type Handler struct {
+	Deps
+}
+
+func NewHandler(deps Deps) *Handler {
+	return &Handler{Deps: deps}
 }
 
 func (h *Handler) RegisterRoutes(r *mux.Router) {
 	r.HandleFunc("/health", h.healthCheck).Methods("GET")
+	if h.Health != nil {
+		h.Health.RegisterRoutes(r)
+	}
 
 	r.HandleFunc("/api/restaurants", h.createRestaurant).Methods("POST")
 	r.HandleFunc("/api/restaurants", h.getRestaurants).Methods("GET")
@@ -38,6 +66,18 @@ func (h *Handler) RegisterRoutes(r *mux.Router) {
 	r.HandleFunc("/api/restaurants/{id}", h.updateRestaurant).Methods("PUT")
 	r.HandleFunc("/api/restaurants/{id}", h.deleteRestaurant).Methods("DELETE")
 	r.HandleFunc("/api/restaurants/{id}/image", h.uploadRestaurantImage).Methods("POST")
+	r.HandleFunc("/api/restaurants/{id}/restore", h.restoreRestaurant).Methods("POST")
+	r.HandleFunc("/api/restaurants/{id}/purge", h.purgeRestaurant).Methods("DELETE")
+	r.HandleFunc("/api/restaurants/{id}/receipt-settings", h.getReceiptSettings).Methods("GET")
+	r.HandleFunc("/api/restaurants/{id}/receipt-settings", h.updateReceiptSettings).Methods("PUT")
+	r.HandleFunc("/api/restaurants/{id}/receipt-settings/logo", h.uploadReceiptLogo).Methods("POST")
+
+	r.HandleFunc("/api/restaurants/{restaurantId}/tables", h.createTable).Methods("POST")
+	r.HandleFunc("/api/restaurants/{restaurantId}/tables", h.getTables).Methods("GET")
+	r.HandleFunc("/api/restaurants/{restaurantId}/tables/{tableId}", h.updateTable).Methods("PUT")
+	r.HandleFunc("/api/restaurants/{restaurantId}/tables/{tableId}", h.deleteTable).Methods("DELETE")
+	r.HandleFunc("/api/restaurants/{restaurantId}/tables/{tableId}/qrcode", h.getTableQRCode).Methods("GET")
+	r.HandleFunc("/api/tables/{code}", h.getTableByCode).Methods("GET")
 
 	r.HandleFunc("/api/restaurants/{restaurantId}/dishes", h.createDish).Methods("POST")
 	r.HandleFunc("/api/restaurants/{restaurantId}/dishes", h.getRestaurantDishes).Methods("GET")
@@ -45,12 +85,61 @@ func (h *Handler) RegisterRoutes(r *mux.Router) {
 	r.HandleFunc("/api/restaurants/{restaurantId}/dishes/{dishId}", h.updateDish).Methods("PUT")
 	r.HandleFunc("/api/restaurants/{restaurantId}/dishes/{dishId}", h.deleteDish).Methods("DELETE")
 	r.HandleFunc("/api/restaurants/{restaurantId}/dishes/{dishId}/image", h.uploadDishImage).Methods("POST")
+	r.HandleFunc("/api/restaurants/{restaurantId}/dishes/{dishId}/availability", h.updateDishAvailability).Methods("PUT")
+	r.HandleFunc("/api/restaurants/{restaurantId}/dishes/{dishId}/prices", h.getDishPrices).Methods("GET")
+	r.HandleFunc("/api/restaurants/{restaurantId}/dishes/{dishId}/restore", h.restoreDish).Methods("POST")
+	r.HandleFunc("/api/restaurants/{restaurantId}/dishes/{dishId}/purge", h.purgeDish).Methods("DELETE")
+	r.HandleFunc("/api/restaurants/{restaurantId}/stop-list", h.getStopList).Methods("GET")
+	r.HandleFunc("/api/restaurants/{restaurantId}/dishes/{dishId}/translations", h.getDishTranslations).Methods("GET")
+	r.HandleFunc("/api/restaurants/{restaurantId}/dishes/{dishId}/translations/{lang}", h.putDishTranslation).Methods("PUT")
+	r.HandleFunc("/api/restaurants/{restaurantId}/dishes/{dishId}/translations/{lang}", h.deleteDishTranslation).Methods("DELETE")
+	r.HandleFunc("/api/restaurants/{restaurantId}/dishes/{dishId}/modifiers", h.getModifierGroups).Methods("GET")
+	r.HandleFunc("/api/restaurants/{restaurantId}/dishes/{dishId}/modifiers", h.createModifierGroup).Methods("POST")
+	r.HandleFunc("/api/restaurants/{restaurantId}/dishes/{dishId}/modifiers/{groupId}", h.updateModifierGroup).Methods("PUT")
+	r.HandleFunc("/api/restaurants/{restaurantId}/dishes/{dishId}/modifiers/{groupId}", h.deleteModifierGroup).Methods("DELETE")
+
+	r.HandleFunc("/api/restaurants/{restaurantId}/categories", h.createCategory).Methods("POST")
+	r.HandleFunc("/api/restaurants/{restaurantId}/categories", h.getCategories).Methods("GET")
+	r.HandleFunc("/api/restaurants/{restaurantId}/categories/reorder", h.reorderCategories).Methods("POST")
+	r.HandleFunc("/api/restaurants/{restaurantId}/categories/{categoryId}", h.updateCategory).Methods("PUT")
+	r.HandleFunc("/api/restaurants/{restaurantId}/categories/{categoryId}", h.deleteCategory).Methods("DELETE")
+	r.HandleFunc("/api/restaurants/{restaurantId}/categories/{categoryId}/dishes/reorder", h.reorderCategoryDishes).Methods("POST")
+
+	r.HandleFunc("/api/restaurants/{restaurantId}/menu/draft", h.getMenuDraft).Methods("GET")
+	r.HandleFunc("/api/restaurants/{restaurantId}/menu/draft", h.discardMenuDraft).Methods("DELETE")
+	r.HandleFunc("/api/restaurants/{restaurantId}/menu/draft/preview", h.previewMenuDraft).Methods("GET")
+	r.HandleFunc("/api/restaurants/{restaurantId}/menu/draft/dishes", h.stageDish).Methods("POST")
+	r.HandleFunc("/api/restaurants/{restaurantId}/menu/draft/dishes/{dishId}", h.stageDish).Methods("PUT")
+	r.HandleFunc("/api/restaurants/{restaurantId}/menu/draft/dishes/{dishId}", h.stageDishDeletion).Methods("DELETE")
+	r.HandleFunc("/api/restaurants/{restaurantId}/menu/draft/categories", h.stageCategory).Methods("POST")
+	r.HandleFunc("/api/restaurants/{restaurantId}/menu/draft/categories/{categoryId}", h.stageCategory).Methods("PUT")
+	r.HandleFunc("/api/restaurants/{restaurantId}/menu/draft/categories/{categoryId}", h.stageCategoryDeletion).Methods("DELETE")
+	r.HandleFunc("/api/restaurants/{restaurantId}/menu/draft/changes/{changeId}", h.dropMenuDraftChange).Methods("DELETE")
+	r.HandleFunc("/api/restaurants/{restaurantId}/menu/publish", h.publishMenu).Methods("POST")
+	r.HandleFunc("/api/restaurants/{restaurantId}/menu/import", h.importMenu).Methods("POST")
+	r.HandleFunc("/api/restaurants/{restaurantId}/menu/export", h.exportMenu).Methods("GET")
+	r.HandleFunc("/api/restaurants/{restaurantId}/menu/versions", h.getMenuVersions).Methods("GET")
+	r.HandleFunc("/api/restaurants/{restaurantId}/menu/versions/{version}", h.getMenuVersion).Methods("GET")
+	r.HandleFunc("/api/restaurants/{restaurantId}/menu/versions/{version}/rollback", h.rollbackMenu).Methods("POST")
+
+	r.HandleFunc("/api/search", h.search).Methods("GET")
+
+	r.HandleFunc("/api/files/sign", h.signFileURL).Methods("POST")
+	if files, ok := h.Blobs.(http.Handler); ok {
+		// backends without their own signed URLs, like the file system one
+		r.PathPrefix("/api/files/").Methods("GET", "HEAD").Handler(http.StripPrefix("/api/files", files))
+	}
 
 	r.HandleFunc("/api/orders", h.createOrder).Methods("POST")
 	r.HandleFunc("/api/orders", h.getOrders).Methods("GET")
+	r.HandleFunc("/api/orders/qrcodes/regenerate", h.regenerateQRCodes).Methods("POST")
 	r.HandleFunc("/api/orders/{id}", h.getOrder).Methods("GET")
 	r.HandleFunc("/api/orders/{id}/qrcode", h.getOrderQRCode).Methods("GET")
+	r.HandleFunc("/api/orders/{id}/receipt", h.getOrderReceipt).Methods("GET")
+	r.HandleFunc("/api/orders/{id}/receipt.escpos", h.getOrderReceiptESCPOS).Methods("GET")
 	r.HandleFunc("/api/check/{id}", h.getOrder).Methods("GET")
+	r.HandleFunc("/r/{code}", h.followReviewLink).Methods("GET")
+	r.HandleFunc("/t/{code}", h.followTableLink).Methods("GET")
 }
 
 func (h *Handler) healthCheck(w http.ResponseWriter, r *http.Request) {
@@ -69,7 +158,11 @@ func (h *Handler) createRestaurant(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
-	if err := h.Restaurants.Create(&rest); err != nil {
+	if err := h.Restaurants.Create(r.Context(), &rest); err != nil {
+		if invalidRestaurant(err) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
@@ -77,19 +170,41 @@ func (h *Handler) createRestaurant(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(rest)
 }
 
+// invalidRestaurant reports whether err is about the restaurant payload
+// rather than about storing it.
+func invalidRestaurant(err error) bool {
+	return errors.Is(err, service.ErrInvalidBaseURL) ||
+		errors.Is(err, service.ErrInvalidTimeZone) ||
+		errors.Is(err, service.ErrInvalidLocale) ||
+		errors.Is(err, service.ErrInvalidOpeningHours)
+}
+
 func (h *Handler) getRestaurants(w http.ResponseWriter, r *http.Request) {
-	restaurants, err := h.Restaurants.List()
+	if r.URL.Query().Get("deleted") == "true" {
+		h.getDeletedRestaurants(w, r)
+		return
+	}
+	filter, err := restaurantFilterFromQuery(r.URL.Query())
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	restaurants, next, err := h.Restaurants.List(r.Context(), filter)
+	if err != nil {
+		writeListError(w, err)
 		return
 	}
+	if restaurants == nil {
+		restaurants = []domain.Restaurant{}
+	}
+	writeNextCursor(w, next)
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(restaurants)
 }
 
 func (h *Handler) getRestaurant(w http.ResponseWriter, r *http.Request) {
 	id, _ := strconv.Atoi(mux.Vars(r)["id"])
-	rest, err := h.Restaurants.Get(id)
+	rest, err := h.Restaurants.Get(r.Context(), id)
 	if err != nil {
 		http.Error(w, "Restaurant not found", http.StatusNotFound)
 		return
@@ -106,7 +221,11 @@ func (h *Handler) updateRestaurant(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	rest.ID = id
-	if err := h.Restaurants.Update(&rest); err != nil {
+	if err := h.Restaurants.Update(r.Context(), &rest); err != nil {
+		if invalidRestaurant(err) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
 		if err.Error() == "sql: no rows in result set" {
 			http.Error(w, "Restaurant not found", http.StatusNotFound)
 			return
@@ -120,7 +239,7 @@ func (h *Handler) updateRestaurant(w http.ResponseWriter, r *http.Request) {
 
 func (h *Handler) deleteRestaurant(w http.ResponseWriter, r *http.Request) {
 	id, _ := strconv.Atoi(mux.Vars(r)["id"])
-	rows, err := h.Restaurants.Delete(id)
+	rows, err := h.Restaurants.Delete(r.Context(), id)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -134,47 +253,19 @@ func (h *Handler) deleteRestaurant(w http.ResponseWriter, r *http.Request) {
 
 func (h *Handler) uploadRestaurantImage(w http.ResponseWriter, r *http.Request) {
 	id, _ := strconv.Atoi(mux.Vars(r)["id"])
-	if err := r.ParseMultipartForm(10 << 20); err != nil {
-		http.Error(w, "File too large", http.StatusBadRequest)
-		return
-	}
-
-	file, handler, err := r.FormFile("image")
-	if err != nil {
-		http.Error(w, "Error retrieving file", http.StatusBadRequest)
+	file, ok := imageUpload(w, r)
+	if !ok {
 		return
 	}
 	defer file.Close()
 
-	uploadDir := "./uploads"
-	if err := os.MkdirAll(uploadDir, 0755); err != nil {
-		log.Printf("❌ Failed to create uploads dir: %v", err) // Добавьте лог!
-		http.Error(w, "Failed to create upload directory", http.StatusInternalServerError)
-		return
-	}
-
-	filename := "restaurant_" + strconv.Itoa(id) + "_" + handler.Filename
-	path := filepath.Join(uploadDir, filename)
-
-	dst, err := os.Create(path)
+	image, err := h.Restaurants.UpdateImage(r.Context(), id, file)
 	if err != nil {
-		http.Error(w, "Failed to save file", http.StatusInternalServerError)
-		return
-	}
-	defer dst.Close()
-
-	if _, err := io.Copy(dst, file); err != nil {
-		http.Error(w, "Failed to save file", http.StatusInternalServerError)
-		return
-	}
-
-	imageURL := "/uploads/" + filename
-	if err := h.Restaurants.UpdateImage(id, imageURL); err != nil {
-		http.Error(w, "Failed to update restaurant", http.StatusInternalServerError)
+		writeImageError(w, err, "Restaurant not found")
 		return
 	}
-
-	json.NewEncoder(w).Encode(map[string]string{"image_url": imageURL})
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(image)
 }
 
 func (h *Handler) createDish(w http.ResponseWriter, r *http.Request) {
@@ -185,7 +276,11 @@ func (h *Handler) createDish(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	dish.RestaurantID = restaurantID
-	if err := h.Dishes.Create(&dish); err != nil {
+	if err := h.Dishes.Create(r.Context(), &dish); err != nil {
+		if isDishValidationError(err) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
@@ -195,38 +290,99 @@ func (h *Handler) createDish(w http.ResponseWriter, r *http.Request) {
 
 func (h *Handler) getRestaurantDishes(w http.ResponseWriter, r *http.Request) {
 	restaurantID, _ := strconv.Atoi(mux.Vars(r)["restaurantId"])
-	dishes, err := h.Dishes.List(restaurantID)
+	filter, err := dishFilterFromQuery(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if r.URL.Query().Get("grouped") == "true" {
+		h.getGroupedMenu(w, r, restaurantID, filter)
+		return
+	}
+	if r.URL.Query().Get("deleted") == "true" {
+		h.getDeletedDishes(w, r, restaurantID)
+		return
+	}
+	dishes, err := h.Dishes.List(r.Context(), restaurantID, filter)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
+	h.localizeDishes(w, r, restaurantID, dishes)
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(dishes)
 }
 
+func dishFilterFromQuery(r *http.Request) (domain.DishFilter, error) {
+	var filter domain.DishFilter
+	query := r.URL.Query()
+	if query.Get("available") == "true" {
+		now := time.Now()
+		filter.AvailableAt = &now
+	}
+
+	var err error
+	if filter.ExcludeAllergens, err = service.NormalizeAllergens(splitList(query.Get("exclude_allergens"))); err != nil {
+		return filter, err
+	}
+	if filter.Tags, err = service.NormalizeDietaryTags(splitList(query.Get("tags"))); err != nil {
+		return filter, err
+	}
+	return filter, nil
+}
+
+func splitList(value string) []string {
+	if value == "" {
+		return nil
+	}
+	return strings.Split(value, ",")
+}
+
+// isDishValidationError reports whether err is caused by bad dish input rather
+// than a storage failure.
+func isDishValidationError(err error) bool {
+	return errors.Is(err, service.ErrInvalidAvailability) ||
+		errors.Is(err, service.ErrUnknownAllergen) ||
+		errors.Is(err, service.ErrUnknownDietaryTag) ||
+		errors.Is(err, service.ErrInvalidNutrition) ||
+		errors.Is(err, service.ErrUnknownCategory)
+}
+
 func (h *Handler) getDish(w http.ResponseWriter, r *http.Request) {
 	restaurantID, _ := strconv.Atoi(mux.Vars(r)["restaurantId"])
 	dishID, _ := strconv.Atoi(mux.Vars(r)["dishId"])
-	dish, err := h.Dishes.Get(restaurantID, dishID)
+	dish, err := h.Dishes.Get(r.Context(), restaurantID, dishID)
 	if err != nil {
 		http.Error(w, "Dish not found", http.StatusNotFound)
 		return
 	}
+	dishes := []domain.Dish{*dish}
+	h.localizeDishes(w, r, restaurantID, dishes)
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(dish)
+	json.NewEncoder(w).Encode(dishes[0])
 }
 
 func (h *Handler) updateDish(w http.ResponseWriter, r *http.Request) {
 	restaurantID, _ := strconv.Atoi(mux.Vars(r)["restaurantId"])
 	dishID, _ := strconv.Atoi(mux.Vars(r)["dishId"])
-	var dish domain.Dish
-	if err := json.NewDecoder(r.Body).Decode(&dish); err != nil {
+	// Decode on top of the stored dish so fields the client did not send
+	// (category, allergens, ...) keep their values.
+	dish, err := h.Dishes.Get(r.Context(), restaurantID, dishID)
+	if err != nil {
+		http.Error(w, "Dish not found", http.StatusNotFound)
+		return
+	}
+	if err := json.NewDecoder(r.Body).Decode(dish); err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 	dish.ID = dishID
 	dish.RestaurantID = restaurantID
-	if err := h.Dishes.Update(&dish); err != nil {
+	if err := h.Dishes.Update(r.Context(), dish); err != nil {
+		if isDishValidationError(err) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
@@ -237,7 +393,7 @@ func (h *Handler) updateDish(w http.ResponseWriter, r *http.Request) {
 func (h *Handler) deleteDish(w http.ResponseWriter, r *http.Request) {
 	restaurantID, _ := strconv.Atoi(mux.Vars(r)["restaurantId"])
 	dishID, _ := strconv.Atoi(mux.Vars(r)["dishId"])
-	rows, err := h.Dishes.Delete(restaurantID, dishID)
+	rows, err := h.Dishes.Delete(r.Context(), restaurantID, dishID)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -249,67 +405,111 @@ func (h *Handler) deleteDish(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusNoContent)
 }
 
-func (h *Handler) uploadDishImage(w http.ResponseWriter, r *http.Request) {
+func (h *Handler) updateDishAvailability(w http.ResponseWriter, r *http.Request) {
 	restaurantID, _ := strconv.Atoi(mux.Vars(r)["restaurantId"])
 	dishID, _ := strconv.Atoi(mux.Vars(r)["dishId"])
-
-	if err := r.ParseMultipartForm(10 << 20); err != nil {
-		http.Error(w, "File too large", http.StatusBadRequest)
+	var payload struct {
+		StopListed bool                        `json:"stop_listed"`
+		Windows    []domain.AvailabilityWindow `json:"availability_windows"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
-
-	file, handler, err := r.FormFile("image")
+	rows, err := h.Dishes.SetAvailability(r.Context(), restaurantID, dishID, payload.StopListed, payload.Windows)
 	if err != nil {
-		http.Error(w, "Error retrieving the file", http.StatusBadRequest)
+		if errors.Is(err, service.ErrInvalidAvailability) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
-	defer file.Close()
-
-	allowedTypes := map[string]bool{
-		"image/jpeg": true,
-		"image/png":  true,
-		"image/gif":  true,
-		"image/webp": true,
+	if rows == 0 {
+		http.Error(w, "Dish not found", http.StatusNotFound)
+		return
 	}
-	if !allowedTypes[handler.Header.Get("Content-Type")] {
-		http.Error(w, "Invalid file type. Only JPEG, PNG, GIF, WebP allowed", http.StatusBadRequest)
+	dish, err := h.Dishes.Get(r.Context(), restaurantID, dishID)
+	if err != nil {
+		http.Error(w, "Dish not found", http.StatusNotFound)
 		return
 	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(dish)
+}
 
-	uploadDir := "./uploads"
-	if err := os.MkdirAll(uploadDir, 0755); err != nil {
-		http.Error(w, "Failed to create upload directory", http.StatusInternalServerError)
+func (h *Handler) getStopList(w http.ResponseWriter, r *http.Request) {
+	restaurantID, _ := strconv.Atoi(mux.Vars(r)["restaurantId"])
+	dishes, err := h.Dishes.StopList(r.Context(), restaurantID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(dishes)
+}
 
-	filename := "dish_" + strconv.Itoa(restaurantID) + "_" + strconv.Itoa(dishID) + "_" + handler.Filename
-	path := filepath.Join(uploadDir, filename)
-
-	dst, err := os.Create(path)
+func (h *Handler) getDishPrices(w http.ResponseWriter, r *http.Request) {
+	restaurantID, _ := strconv.Atoi(mux.Vars(r)["restaurantId"])
+	dishID, _ := strconv.Atoi(mux.Vars(r)["dishId"])
+	history, err := h.Dishes.PriceHistory(r.Context(), restaurantID, dishID)
+	if errors.Is(err, sql.ErrNoRows) {
+		http.Error(w, "Dish not found", http.StatusNotFound)
+		return
+	}
 	if err != nil {
-		http.Error(w, "Failed to create file", http.StatusInternalServerError)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
-	defer dst.Close()
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(history)
+}
 
-	if _, err := io.Copy(dst, file); err != nil {
-		http.Error(w, "Failed to save file", http.StatusInternalServerError)
+func (h *Handler) uploadDishImage(w http.ResponseWriter, r *http.Request) {
+	restaurantID, _ := strconv.Atoi(mux.Vars(r)["restaurantId"])
+	dishID, _ := strconv.Atoi(mux.Vars(r)["dishId"])
+	file, ok := imageUpload(w, r)
+	if !ok {
 		return
 	}
+	defer file.Close()
 
-	imageURL := "/uploads/" + filename
-	if err := h.Dishes.UpdateImage(restaurantID, dishID, imageURL); err != nil {
-		http.Error(w, "Failed to update dish", http.StatusInternalServerError)
+	image, err := h.Dishes.UpdateImage(r.Context(), restaurantID, dishID, file)
+	if err != nil {
+		writeImageError(w, err, "Dish not found")
 		return
 	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(image)
+}
 
-	response := map[string]string{
-		"message":   "Image uploaded successfully",
-		"image_url": imageURL,
+// imageUpload returns the "image" file of a multipart upload. The file name
+// and content type sent by the client are ignored; the image pipeline sniffs
+// the type and names files by content.
+func imageUpload(w http.ResponseWriter, r *http.Request) (multipart.File, bool) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxImageUploadSize)
+	if err := r.ParseMultipartForm(maxImageUploadSize); err != nil {
+		http.Error(w, "File too large", http.StatusRequestEntityTooLarge)
+		return nil, false
 	}
+	file, _, err := r.FormFile("image")
+	if err != nil {
+		http.Error(w, "Error retrieving the file", http.StatusBadRequest)
+		return nil, false
+	}
+	return file, true
+}
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(response)
+func writeImageError(w http.ResponseWriter, err error, notFound string) {
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		http.Error(w, notFound, http.StatusNotFound)
+	case errors.Is(err, service.ErrUnsupportedImage):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		log.Printf("image upload failed: %v", err)
+		http.Error(w, "Failed to save image", http.StatusInternalServerError)
+	}
 }
 
 func (h *Handler) createOrder(w http.ResponseWriter, r *http.Request) {
@@ -319,7 +519,17 @@ func (h *Handler) createOrder(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := h.Orders.Create(&order); err != nil {
+	if err := h.Orders.Create(r.Context(), &order); err != nil {
+		var validationErr *service.OrderValidationError
+		if errors.As(err, &validationErr) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"error": "invalid order items",
+				"items": validationErr.Items,
+			})
+			return
+		}
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
@@ -332,7 +542,7 @@ func (h *Handler) createOrder(w http.ResponseWriter, r *http.Request) {
 
 func (h *Handler) getOrder(w http.ResponseWriter, r *http.Request) {
 	orderID, _ := strconv.Atoi(mux.Vars(r)["id"])
-	order, err := h.Orders.Get(orderID)
+	order, err := h.Orders.Get(r.Context(), orderID)
 	if err != nil {
 		http.Error(w, "Order not found", http.StatusNotFound)
 		return
@@ -342,18 +552,27 @@ func (h *Handler) getOrder(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *Handler) getOrders(w http.ResponseWriter, r *http.Request) {
-	orders, err := h.Orders.List()
+	filter, err := orderFilterFromQuery(r.URL.Query())
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
+	orders, next, err := h.Orders.List(r.Context(), filter)
+	if err != nil {
+		writeListError(w, err)
+		return
+	}
+	if orders == nil {
+		orders = []domain.Order{}
+	}
+	writeNextCursor(w, next)
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(orders)
 }
 
 func (h *Handler) getOrderQRCode(w http.ResponseWriter, r *http.Request) {
 	orderID, _ := strconv.Atoi(mux.Vars(r)["id"])
-	qrCode, err := h.Orders.GetQRCode(orderID)
+	qrCode, err := h.Orders.GetQRCode(r.Context(), orderID)
 	if err != nil {
 		http.Error(w, "Order not found", http.StatusNotFound)
 		return
@@ -367,3 +586,43 @@ func (h *Handler) getOrderQRCode(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusOK)
 	w.Write(qrCode)
 }
+
+// regenerateQRCodes rewrites the stored QR codes after the public base URL
+// has changed, of one restaurant with ?restaurant_id= or of all orders.
+func (h *Handler) regenerateQRCodes(w http.ResponseWriter, r *http.Request) {
+	restaurantID := 0
+	if raw := r.URL.Query().Get("restaurant_id"); raw != "" {
+		id, err := strconv.Atoi(raw)
+		if err != nil || id <= 0 {
+			http.Error(w, "Invalid restaurant_id", http.StatusBadRequest)
+			return
+		}
+		restaurantID = id
+	}
+	count, err := h.Orders.RegenerateQRCodes(r.Context(), restaurantID)
+	if errors.Is(err, sql.ErrNoRows) {
+		http.Error(w, "Restaurant not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]int{"regenerated": count})
+}
+
+// followReviewLink resolves the short link from a QR code to the review page
+// on the same host, so links on a restaurant's own domain stay there.
+func (h *Handler) followReviewLink(w http.ResponseWriter, r *http.Request) {
+	orderID, ok := service.ParseShortCode(mux.Vars(r)["code"])
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	if _, err := h.Orders.Get(r.Context(), orderID); err != nil {
+		http.NotFound(w, r)
+		return
+	}
+	http.Redirect(w, r, service.ReviewPath(orderID), http.StatusFound)
+}
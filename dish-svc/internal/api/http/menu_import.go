@@ -0,0 +1,84 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"overcooked-simplified/dish-svc/internal/service"
+
+	"github.com/gorilla/mux"
+)
+
+// maxMenuImportSize bounds the request body of a menu import.
+const maxMenuImportSize = 5 << 20
+
+// menuFormat picks the import/export format from ?format=, falling back to
+// the Content-Type of an upload and then to JSON.
+func menuFormat(r *http.Request) string {
+	if format := strings.ToLower(r.URL.Query().Get("format")); format != "" {
+		return format
+	}
+	if strings.Contains(r.Header.Get("Content-Type"), "csv") {
+		return service.MenuFormatCSV
+	}
+	return service.MenuFormatJSON
+}
+
+func (h *Handler) importMenu(w http.ResponseWriter, r *http.Request) {
+	restaurantID, _ := strconv.Atoi(mux.Vars(r)["restaurantId"])
+	dryRun := r.URL.Query().Get("dry_run") == "true"
+	body := http.MaxBytesReader(w, r.Body, maxMenuImportSize)
+
+	report, err := h.Imports.Import(r.Context(), restaurantID, menuFormat(r), body, dryRun)
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		switch {
+		case errors.As(err, &tooLarge):
+			http.Error(w, "Import file too large", http.StatusRequestEntityTooLarge)
+		case errors.Is(err, service.ErrUnsupportedMenuFormat), errors.Is(err, service.ErrMalformedMenuImport):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if len(report.Errors) > 0 && !dryRun {
+		// nothing was written; the report says which rows to fix
+		w.WriteHeader(http.StatusBadRequest)
+	}
+	json.NewEncoder(w).Encode(report)
+}
+
+func (h *Handler) exportMenu(w http.ResponseWriter, r *http.Request) {
+	restaurantID, _ := strconv.Atoi(mux.Vars(r)["restaurantId"])
+	format := menuFormat(r)
+	if format != service.MenuFormatCSV && format != service.MenuFormatJSON {
+		http.Error(w, fmt.Sprintf("Unsupported format %q", format), http.StatusBadRequest)
+		return
+	}
+
+	rows, err := h.Imports.Export(r.Context(), restaurantID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	filename := fmt.Sprintf("menu-%d.%s", restaurantID, format)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	if format == service.MenuFormatCSV {
+		w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		if err := service.WriteMenuCSV(w, rows); err != nil {
+			log.Printf("export menu %d: %v", restaurantID, err)
+		}
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(rows)
+}
@@ -0,0 +1,212 @@
+package httpapi
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"overcooked-simplified/dish-svc/internal/domain"
+	"overcooked-simplified/dish-svc/internal/service"
+
+	"github.com/gorilla/mux"
+)
+
+func (h *Handler) getMenuDraft(w http.ResponseWriter, r *http.Request) {
+	restaurantID, _ := strconv.Atoi(mux.Vars(r)["restaurantId"])
+	changes, err := h.Menu.Draft(r.Context(), restaurantID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(changes)
+}
+
+func (h *Handler) discardMenuDraft(w http.ResponseWriter, r *http.Request) {
+	restaurantID, _ := strconv.Atoi(mux.Vars(r)["restaurantId"])
+	if _, err := h.Menu.Discard(r.Context(), restaurantID); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) previewMenuDraft(w http.ResponseWriter, r *http.Request) {
+	restaurantID, _ := strconv.Atoi(mux.Vars(r)["restaurantId"])
+	sections, err := h.Menu.Preview(r.Context(), restaurantID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(sections)
+}
+
+// stageDish handles both new dishes (POST) and edits of existing ones (PUT).
+// An edit is decoded on top of the live dish, like updateDish.
+func (h *Handler) stageDish(w http.ResponseWriter, r *http.Request) {
+	restaurantID, _ := strconv.Atoi(mux.Vars(r)["restaurantId"])
+	dish := &domain.Dish{}
+	if raw, ok := mux.Vars(r)["dishId"]; ok {
+		dishID, _ := strconv.Atoi(raw)
+		live, err := h.Dishes.Get(r.Context(), restaurantID, dishID)
+		if err != nil {
+			http.Error(w, "Dish not found", http.StatusNotFound)
+			return
+		}
+		dish = live
+	}
+	dishID := dish.ID
+	if err := json.NewDecoder(r.Body).Decode(dish); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	dish.ID = dishID
+	dish.RestaurantID = restaurantID
+
+	change, err := h.Menu.StageDish(r.Context(), dish)
+	if err != nil {
+		writeMenuDraftError(w, err)
+		return
+	}
+	writeMenuDraftChange(w, change)
+}
+
+func (h *Handler) stageDishDeletion(w http.ResponseWriter, r *http.Request) {
+	restaurantID, _ := strconv.Atoi(mux.Vars(r)["restaurantId"])
+	dishID, _ := strconv.Atoi(mux.Vars(r)["dishId"])
+	change, err := h.Menu.StageDishDeletion(r.Context(), restaurantID, dishID)
+	if err != nil {
+		writeMenuDraftError(w, err)
+		return
+	}
+	writeMenuDraftChange(w, change)
+}
+
+func (h *Handler) stageCategory(w http.ResponseWriter, r *http.Request) {
+	restaurantID, _ := strconv.Atoi(mux.Vars(r)["restaurantId"])
+	categoryID, _ := strconv.Atoi(mux.Vars(r)["categoryId"])
+	cat := domain.MenuCategory{Visible: true}
+	if err := json.NewDecoder(r.Body).Decode(&cat); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	cat.ID = categoryID
+	cat.RestaurantID = restaurantID
+
+	change, err := h.Menu.StageCategory(r.Context(), &cat)
+	if err != nil {
+		writeMenuDraftError(w, err)
+		return
+	}
+	writeMenuDraftChange(w, change)
+}
+
+func (h *Handler) stageCategoryDeletion(w http.ResponseWriter, r *http.Request) {
+	restaurantID, _ := strconv.Atoi(mux.Vars(r)["restaurantId"])
+	categoryID, _ := strconv.Atoi(mux.Vars(r)["categoryId"])
+	change, err := h.Menu.StageCategoryDeletion(r.Context(), restaurantID, categoryID)
+	if err != nil {
+		writeMenuDraftError(w, err)
+		return
+	}
+	writeMenuDraftChange(w, change)
+}
+
+func (h *Handler) dropMenuDraftChange(w http.ResponseWriter, r *http.Request) {
+	restaurantID, _ := strconv.Atoi(mux.Vars(r)["restaurantId"])
+	changeID, _ := strconv.Atoi(mux.Vars(r)["changeId"])
+	rows, err := h.Menu.DropChange(r.Context(), restaurantID, changeID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if rows == 0 {
+		http.Error(w, "Draft change not found", http.StatusNotFound)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) publishMenu(w http.ResponseWriter, r *http.Request) {
+	restaurantID, _ := strconv.Atoi(mux.Vars(r)["restaurantId"])
+	version, err := h.Menu.Publish(r.Context(), restaurantID)
+	switch {
+	case errors.Is(err, service.ErrEmptyDraft):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	case errors.Is(err, sql.ErrNoRows):
+		// the draft refers to a dish or category deleted since it was staged
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	case err != nil:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(version)
+}
+
+func (h *Handler) getMenuVersions(w http.ResponseWriter, r *http.Request) {
+	restaurantID, _ := strconv.Atoi(mux.Vars(r)["restaurantId"])
+	versions, err := h.Menu.Versions(r.Context(), restaurantID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(versions)
+}
+
+func (h *Handler) getMenuVersion(w http.ResponseWriter, r *http.Request) {
+	restaurantID, _ := strconv.Atoi(mux.Vars(r)["restaurantId"])
+	number, _ := strconv.Atoi(mux.Vars(r)["version"])
+	version, err := h.Menu.Version(r.Context(), restaurantID, number)
+	if errors.Is(err, sql.ErrNoRows) {
+		http.Error(w, "Menu version not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(version)
+}
+
+func (h *Handler) rollbackMenu(w http.ResponseWriter, r *http.Request) {
+	restaurantID, _ := strconv.Atoi(mux.Vars(r)["restaurantId"])
+	number, _ := strconv.Atoi(mux.Vars(r)["version"])
+	version, err := h.Menu.Rollback(r.Context(), restaurantID, number)
+	if errors.Is(err, sql.ErrNoRows) {
+		http.Error(w, "Menu version not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(version)
+}
+
+func writeMenuDraftChange(w http.ResponseWriter, change *domain.MenuDraftChange) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(change)
+}
+
+func writeMenuDraftError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, service.ErrInvalidDraftChange), isDishValidationError(err):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, sql.ErrNoRows):
+		http.Error(w, "Dish or category not found", http.StatusNotFound)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
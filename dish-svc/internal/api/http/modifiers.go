@@ -0,0 +1,90 @@
+package httpapi
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"overcooked-simplified/dish-svc/internal/domain"
+	"overcooked-simplified/dish-svc/internal/service"
+
+	"github.com/gorilla/mux"
+)
+
+func (h *Handler) getModifierGroups(w http.ResponseWriter, r *http.Request) {
+	restaurantID, _ := strconv.Atoi(mux.Vars(r)["restaurantId"])
+	dishID, _ := strconv.Atoi(mux.Vars(r)["dishId"])
+	groups, err := h.Modifiers.List(r.Context(), restaurantID, dishID)
+	if err != nil {
+		writeModifierError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(groups)
+}
+
+func (h *Handler) createModifierGroup(w http.ResponseWriter, r *http.Request) {
+	restaurantID, _ := strconv.Atoi(mux.Vars(r)["restaurantId"])
+	dishID, _ := strconv.Atoi(mux.Vars(r)["dishId"])
+	group := domain.ModifierGroup{MaxSelect: 1}
+	if err := json.NewDecoder(r.Body).Decode(&group); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	group.DishID = dishID
+	if err := h.Modifiers.Create(r.Context(), restaurantID, &group); err != nil {
+		writeModifierError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(group)
+}
+
+func (h *Handler) updateModifierGroup(w http.ResponseWriter, r *http.Request) {
+	restaurantID, _ := strconv.Atoi(mux.Vars(r)["restaurantId"])
+	dishID, _ := strconv.Atoi(mux.Vars(r)["dishId"])
+	groupID, _ := strconv.Atoi(mux.Vars(r)["groupId"])
+	group := domain.ModifierGroup{MaxSelect: 1}
+	if err := json.NewDecoder(r.Body).Decode(&group); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	group.ID = groupID
+	group.DishID = dishID
+	if err := h.Modifiers.Update(r.Context(), restaurantID, &group); err != nil {
+		writeModifierError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(group)
+}
+
+func (h *Handler) deleteModifierGroup(w http.ResponseWriter, r *http.Request) {
+	restaurantID, _ := strconv.Atoi(mux.Vars(r)["restaurantId"])
+	dishID, _ := strconv.Atoi(mux.Vars(r)["dishId"])
+	groupID, _ := strconv.Atoi(mux.Vars(r)["groupId"])
+	rows, err := h.Modifiers.Delete(r.Context(), restaurantID, dishID, groupID)
+	if err != nil {
+		writeModifierError(w, err)
+		return
+	}
+	if rows == 0 {
+		http.Error(w, "Modifier group not found", http.StatusNotFound)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func writeModifierError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, service.ErrInvalidModifierGroup):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, sql.ErrNoRows):
+		http.Error(w, "Dish or modifier group not found", http.StatusNotFound)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,104 @@
+package httpapi
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"overcooked-simplified/dish-svc/internal/domain"
+	"overcooked-simplified/dish-svc/internal/service"
+)
+
+// nextCursorHeader carries the cursor of the next page, so listings keep
+// returning plain arrays. It is absent on the last page.
+const nextCursorHeader = "X-Next-Cursor"
+
+func restaurantFilterFromQuery(values url.Values) (domain.RestaurantFilter, error) {
+	filter := domain.RestaurantFilter{
+		Name:   values.Get("name"),
+		Sort:   values.Get("sort"),
+		Cursor: values.Get("cursor"),
+	}
+	if value := values.Get("has_image"); value != "" {
+		hasImage, err := strconv.ParseBool(value)
+		if err != nil {
+			return filter, fmt.Errorf("bad has_image: %q", value)
+		}
+		filter.HasImage = &hasImage
+	}
+	var err error
+	filter.Limit, err = optionalInt(values, "limit")
+	return filter, err
+}
+
+func orderFilterFromQuery(values url.Values) (domain.OrderFilter, error) {
+	filter := domain.OrderFilter{
+		Status: values.Get("status"),
+		Sort:   values.Get("sort"),
+		Cursor: values.Get("cursor"),
+	}
+	var err error
+	if filter.RestaurantID, err = optionalInt(values, "restaurant_id"); err != nil {
+		return filter, err
+	}
+	if filter.Limit, err = optionalInt(values, "limit"); err != nil {
+		return filter, err
+	}
+	if filter.MinTotal, err = optionalFloat(values, "min_total"); err != nil {
+		return filter, err
+	}
+	if filter.From, err = optionalTime(values, "from", false); err != nil {
+		return filter, err
+	}
+	filter.To, err = optionalTime(values, "to", true)
+	return filter, err
+}
+
+func optionalInt(values url.Values, name string) (int, error) {
+	value := values.Get(name)
+	if value == "" {
+		return 0, nil
+	}
+	number, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("bad %s: %q", name, value)
+	}
+	return number, nil
+}
+
+// optionalTime reads an RFC 3339 time or a UTC date. A date used as the end
+// of a range covers the whole day, so to=2024-05-31 includes May 31.
+func optionalTime(values url.Values, name string, end bool) (*time.Time, error) {
+	value := values.Get(name)
+	if value == "" {
+		return nil, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return &t, nil
+	}
+	t, err := time.Parse(time.DateOnly, value)
+	if err != nil {
+		return nil, fmt.Errorf("bad %s: %q", name, value)
+	}
+	if end {
+		t = t.AddDate(0, 0, 1)
+	}
+	return &t, nil
+}
+
+func writeNextCursor(w http.ResponseWriter, next string) {
+	if next != "" {
+		w.Header().Set(nextCursorHeader, next)
+	}
+}
+
+func writeListError(w http.ResponseWriter, err error) {
+	if errors.Is(err, service.ErrInvalidPage) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	http.Error(w, err.Error(), http.StatusInternalServerError)
+}
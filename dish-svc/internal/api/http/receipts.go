@@ -0,0 +1,111 @@
+package httpapi
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+
+	"overcooked-simplified/dish-svc/internal/domain"
+	"overcooked-simplified/dish-svc/internal/service"
+
+	"github.com/gorilla/mux"
+)
+
+// getOrderReceipt renders the printable receipt: ?format=pdf|svg|html, html
+// by default.
+func (h *Handler) getOrderReceipt(w http.ResponseWriter, r *http.Request) {
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = service.ReceiptHTML
+	}
+	h.writeReceipt(w, r, format)
+}
+
+// getOrderReceiptESCPOS returns the receipt as ESC/POS commands to send to a
+// thermal printer as they are.
+func (h *Handler) getOrderReceiptESCPOS(w http.ResponseWriter, r *http.Request) {
+	h.writeReceipt(w, r, service.ReceiptESCPOS)
+}
+
+func (h *Handler) writeReceipt(w http.ResponseWriter, r *http.Request, format string) {
+	orderID, _ := strconv.Atoi(mux.Vars(r)["id"])
+	body, contentType, err := h.Receipts.Render(r.Context(), orderID, format)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		http.Error(w, "Order not found", http.StatusNotFound)
+		return
+	case errors.Is(err, service.ErrUnsupportedReceiptFormat):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	case err != nil:
+		log.Printf("render receipt %d: %v", orderID, err)
+		http.Error(w, "Failed to render receipt", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", contentType)
+	switch format {
+	case service.ReceiptPDF:
+		w.Header().Set("Content-Disposition", fmt.Sprintf(`inline; filename="receipt-%d.pdf"`, orderID))
+	case service.ReceiptESCPOS:
+		w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="receipt-%d.escpos"`, orderID))
+	}
+	w.Write(body)
+}
+
+func (h *Handler) getReceiptSettings(w http.ResponseWriter, r *http.Request) {
+	id, _ := strconv.Atoi(mux.Vars(r)["id"])
+	settings, err := h.Receipts.Settings(r.Context(), id)
+	if err != nil {
+		writeReceiptSettingsError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(settings)
+}
+
+func (h *Handler) updateReceiptSettings(w http.ResponseWriter, r *http.Request) {
+	var settings domain.ReceiptSettings
+	if err := json.NewDecoder(r.Body).Decode(&settings); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	settings.RestaurantID, _ = strconv.Atoi(mux.Vars(r)["id"])
+	if err := h.Receipts.UpdateSettings(r.Context(), &settings); err != nil {
+		writeReceiptSettingsError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(settings)
+}
+
+func (h *Handler) uploadReceiptLogo(w http.ResponseWriter, r *http.Request) {
+	id, _ := strconv.Atoi(mux.Vars(r)["id"])
+	file, ok := imageUpload(w, r)
+	if !ok {
+		return
+	}
+	defer file.Close()
+
+	settings, err := h.Receipts.UpdateLogo(r.Context(), id, file)
+	if err != nil {
+		writeImageError(w, err, "Restaurant not found")
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(settings)
+}
+
+func writeReceiptSettingsError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		http.Error(w, "Restaurant not found", http.StatusNotFound)
+	case errors.Is(err, service.ErrInvalidReceiptSettings):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,75 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"overcooked-simplified/dish-svc/internal/domain"
+	"overcooked-simplified/dish-svc/internal/service"
+)
+
+func (h *Handler) search(w http.ResponseWriter, r *http.Request) {
+	query, err := searchQueryFromURL(r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	results, err := h.Search.Search(r.Context(), query)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidSearch) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(results)
+}
+
+// searchQueryFromURL reads the search parameters; only malformed numbers are
+// rejected here, the ranges are the service's business.
+func searchQueryFromURL(values url.Values) (domain.SearchQuery, error) {
+	query := domain.SearchQuery{
+		Text:             values.Get("q"),
+		ExcludeAllergens: splitList(values.Get("exclude_allergens")),
+	}
+	var err error
+	if query.MinPrice, err = optionalFloat(values, "min_price"); err != nil {
+		return query, err
+	}
+	if query.MaxPrice, err = optionalFloat(values, "max_price"); err != nil {
+		return query, err
+	}
+	if query.MinRating, err = optionalFloat(values, "min_rating"); err != nil {
+		return query, err
+	}
+	if value := values.Get("restaurant_id"); value != "" {
+		if query.RestaurantID, err = strconv.Atoi(value); err != nil {
+			return query, fmt.Errorf("bad restaurant_id: %q", value)
+		}
+	}
+	if value := values.Get("limit"); value != "" {
+		if query.Limit, err = strconv.Atoi(value); err != nil {
+			return query, fmt.Errorf("bad limit: %q", value)
+		}
+	}
+	return query, nil
+}
+
+func optionalFloat(values url.Values, name string) (*float64, error) {
+	value := values.Get(name)
+	if value == "" {
+		return nil, nil
+	}
+	number, err := strconv.ParseFloat(value, 64)
+	if err != nil || math.IsNaN(number) || math.IsInf(number, 0) {
+		return nil, fmt.Errorf("bad %s: %q", name, value)
+	}
+	return &number, nil
+}
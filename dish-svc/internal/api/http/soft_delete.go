@@ -0,0 +1,115 @@
+package httpapi
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"overcooked-simplified/dish-svc/internal/service"
+
+	"github.com/gorilla/mux"
+)
+
+// purgeRequest must repeat the name of the restaurant or dish being purged.
+type purgeRequest struct {
+	Confirm string `json:"confirm"`
+}
+
+func (h *Handler) getDeletedRestaurants(w http.ResponseWriter, r *http.Request) {
+	restaurants, err := h.Restaurants.ListDeleted(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(restaurants)
+}
+
+func (h *Handler) restoreRestaurant(w http.ResponseWriter, r *http.Request) {
+	id, _ := strconv.Atoi(mux.Vars(r)["id"])
+	rows, err := h.Restaurants.Restore(r.Context(), id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if rows == 0 {
+		http.Error(w, "Deleted restaurant not found", http.StatusNotFound)
+		return
+	}
+	rest, err := h.Restaurants.Get(r.Context(), id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(rest)
+}
+
+func (h *Handler) purgeRestaurant(w http.ResponseWriter, r *http.Request) {
+	id, _ := strconv.Atoi(mux.Vars(r)["id"])
+	var req purgeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Body must be {\"confirm\": \"<restaurant name>\"}", http.StatusBadRequest)
+		return
+	}
+	if err := h.Restaurants.Purge(r.Context(), id, req.Confirm); err != nil {
+		writePurgeError(w, err, "Restaurant not found")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) getDeletedDishes(w http.ResponseWriter, r *http.Request, restaurantID int) {
+	dishes, err := h.Dishes.ListDeleted(r.Context(), restaurantID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(dishes)
+}
+
+func (h *Handler) restoreDish(w http.ResponseWriter, r *http.Request) {
+	restaurantID, _ := strconv.Atoi(mux.Vars(r)["restaurantId"])
+	dishID, _ := strconv.Atoi(mux.Vars(r)["dishId"])
+	rows, err := h.Dishes.Restore(r.Context(), restaurantID, dishID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if rows == 0 {
+		http.Error(w, "Deleted dish not found", http.StatusNotFound)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) purgeDish(w http.ResponseWriter, r *http.Request) {
+	restaurantID, _ := strconv.Atoi(mux.Vars(r)["restaurantId"])
+	dishID, _ := strconv.Atoi(mux.Vars(r)["dishId"])
+	var req purgeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Body must be {\"confirm\": \"<dish name>\"}", http.StatusBadRequest)
+		return
+	}
+	if err := h.Dishes.Purge(r.Context(), restaurantID, dishID, req.Confirm); err != nil {
+		writePurgeError(w, err, "Dish not found")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func writePurgeError(w http.ResponseWriter, err error, notFound string) {
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		http.Error(w, notFound, http.StatusNotFound)
+	case errors.Is(err, service.ErrNotDeleted):
+		http.Error(w, err.Error(), http.StatusConflict)
+	case errors.Is(err, service.ErrPurgeNotConfirmed):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,128 @@
+package httpapi
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"overcooked-simplified/dish-svc/internal/domain"
+	"overcooked-simplified/dish-svc/internal/service"
+
+	"github.com/gorilla/mux"
+)
+
+func tableQRLink(table *domain.Table) string {
+	return fmt.Sprintf("/api/restaurants/%d/tables/%d/qrcode", table.RestaurantID, table.ID)
+}
+
+func (h *Handler) createTable(w http.ResponseWriter, r *http.Request) {
+	var table domain.Table
+	if err := json.NewDecoder(r.Body).Decode(&table); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	table.RestaurantID, _ = strconv.Atoi(mux.Vars(r)["restaurantId"])
+	if err := h.Tables.Create(r.Context(), &table); err != nil {
+		writeTableError(w, err)
+		return
+	}
+	table.QRCode = tableQRLink(&table)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(table)
+}
+
+func (h *Handler) getTables(w http.ResponseWriter, r *http.Request) {
+	restaurantID, _ := strconv.Atoi(mux.Vars(r)["restaurantId"])
+	tables, err := h.Tables.List(r.Context(), restaurantID)
+	if err != nil {
+		writeTableError(w, err)
+		return
+	}
+	for i := range tables {
+		tables[i].QRCode = tableQRLink(&tables[i])
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(tables)
+}
+
+func (h *Handler) updateTable(w http.ResponseWriter, r *http.Request) {
+	var table domain.Table
+	if err := json.NewDecoder(r.Body).Decode(&table); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	table.RestaurantID, _ = strconv.Atoi(mux.Vars(r)["restaurantId"])
+	table.ID, _ = strconv.Atoi(mux.Vars(r)["tableId"])
+	if err := h.Tables.Update(r.Context(), &table); err != nil {
+		writeTableError(w, err)
+		return
+	}
+	table.QRCode = tableQRLink(&table)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(table)
+}
+
+func (h *Handler) deleteTable(w http.ResponseWriter, r *http.Request) {
+	restaurantID, _ := strconv.Atoi(mux.Vars(r)["restaurantId"])
+	tableID, _ := strconv.Atoi(mux.Vars(r)["tableId"])
+	rows, err := h.Tables.Delete(r.Context(), restaurantID, tableID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if rows == 0 {
+		http.Error(w, "Table not found", http.StatusNotFound)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) getTableQRCode(w http.ResponseWriter, r *http.Request) {
+	restaurantID, _ := strconv.Atoi(mux.Vars(r)["restaurantId"])
+	tableID, _ := strconv.Atoi(mux.Vars(r)["tableId"])
+	qrCode, err := h.Tables.QRCode(r.Context(), restaurantID, tableID)
+	if err != nil {
+		writeTableError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "image/png")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`inline; filename="table-%d.png"`, tableID))
+	w.Write(qrCode)
+}
+
+// getTableByCode tells the guest page which restaurant and table a scanned
+// QR code belongs to.
+func (h *Handler) getTableByCode(w http.ResponseWriter, r *http.Request) {
+	table, err := h.Tables.ByCode(r.Context(), mux.Vars(r)["code"])
+	if err != nil {
+		writeTableError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(table)
+}
+
+// followTableLink opens the menu of the table's restaurant on the same host.
+func (h *Handler) followTableLink(w http.ResponseWriter, r *http.Request) {
+	table, err := h.Tables.ByCode(r.Context(), mux.Vars(r)["code"])
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+	http.Redirect(w, r, service.MenuPath(table.Code), http.StatusFound)
+}
+
+func writeTableError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		http.Error(w, "Table not found", http.StatusNotFound)
+	case errors.Is(err, service.ErrInvalidTable):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,95 @@
+package httpapi
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"overcooked-simplified/dish-svc/internal/domain"
+	"overcooked-simplified/dish-svc/internal/service"
+	"overcooked-simplified/i18n"
+
+	"github.com/gorilla/mux"
+)
+
+func (h *Handler) getDishTranslations(w http.ResponseWriter, r *http.Request) {
+	restaurantID, _ := strconv.Atoi(mux.Vars(r)["restaurantId"])
+	dishID, _ := strconv.Atoi(mux.Vars(r)["dishId"])
+	translations, err := h.Translations.List(r.Context(), restaurantID, dishID)
+	if err != nil {
+		writeTranslationError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(translations)
+}
+
+func (h *Handler) putDishTranslation(w http.ResponseWriter, r *http.Request) {
+	restaurantID, _ := strconv.Atoi(mux.Vars(r)["restaurantId"])
+	dishID, _ := strconv.Atoi(mux.Vars(r)["dishId"])
+	var tr domain.DishTranslation
+	if err := json.NewDecoder(r.Body).Decode(&tr); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	tr.DishID = dishID
+	tr.Lang = mux.Vars(r)["lang"]
+	if err := h.Translations.Put(r.Context(), restaurantID, &tr); err != nil {
+		writeTranslationError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(tr)
+}
+
+func (h *Handler) deleteDishTranslation(w http.ResponseWriter, r *http.Request) {
+	restaurantID, _ := strconv.Atoi(mux.Vars(r)["restaurantId"])
+	dishID, _ := strconv.Atoi(mux.Vars(r)["dishId"])
+	rows, err := h.Translations.Delete(r.Context(), restaurantID, dishID, mux.Vars(r)["lang"])
+	if err != nil {
+		writeTranslationError(w, err)
+		return
+	}
+	if rows == 0 {
+		http.Error(w, "Translation not found", http.StatusNotFound)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// localizeDishes shows dishes in the language the request asks for. Without
+// translations configured, or when localizing fails, dishes keep their own
+// text: a menu in the restaurant's language beats an error.
+func (h *Handler) localizeDishes(w http.ResponseWriter, r *http.Request, restaurantID int, dishes []domain.Dish) {
+	w.Header().Add("Vary", "Accept-Language")
+	if h.Translations == nil {
+		return
+	}
+	if err := h.Translations.Localize(r.Context(), restaurantID, dishes, i18n.Requested(r)); err != nil {
+		log.Printf("localize dishes of restaurant %d: %v", restaurantID, err)
+	}
+}
+
+func (h *Handler) localizeMenu(w http.ResponseWriter, r *http.Request, restaurantID int, sections []domain.MenuSection) {
+	w.Header().Add("Vary", "Accept-Language")
+	if h.Translations == nil {
+		return
+	}
+	if err := h.Translations.LocalizeMenu(r.Context(), restaurantID, sections, i18n.Requested(r)); err != nil {
+		log.Printf("localize menu of restaurant %d: %v", restaurantID, err)
+	}
+}
+
+func writeTranslationError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, service.ErrInvalidTranslation):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, sql.ErrNoRows):
+		http.Error(w, "Dish not found", http.StatusNotFound)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -3,38 +3,359 @@ package domain
 import "time"
 
 type Restaurant struct {
-	ID          int       `json:"id"`
-	Name        string    `json:"name"`
-	Address     string    `json:"address"`
-	Description string    `json:"description"`
-	ImageURL    string    `json:"image_url"`
-	CreatedAt   time.Time `json:"created_at"`
+	ID            int           `json:"id"`
+	Name          string        `json:"name"`
+	Address       string        `json:"address"`
+	Description   string        `json:"description"`
+	ImageURL      string        `json:"image_url"`
+	ImageSrcset   string        `json:"image_srcset,omitempty"`
+	ImageWebP     string        `json:"image_webp_srcset,omitempty"`
+	PublicBaseURL string        `json:"public_base_url,omitempty"`
+	TimeZone      string        `json:"time_zone"`
+	Locale        string        `json:"locale"`
+	OpeningHours  *OpeningHours `json:"opening_hours,omitempty"`
+	OpenNow       *bool         `json:"open_now,omitempty"`
+	CreatedAt     time.Time     `json:"created_at"`
+	DeletedAt     *time.Time    `json:"deleted_at,omitempty"`
+}
+
+// OpeningHours is a restaurant's weekly schedule plus dated exceptions, read
+// in the restaurant's time zone. Weekly windows work like dish availability
+// windows; an empty Weekly means open around the clock.
+type OpeningHours struct {
+	Weekly     []AvailabilityWindow `json:"weekly"`
+	Exceptions []HoursException     `json:"exceptions,omitempty"`
+}
+
+// HoursException replaces the weekly schedule on Date ("YYYY-MM-DD"), e.g. a
+// holiday or a short day. No windows means closed all day; weekdays of the
+// windows are ignored.
+type HoursException struct {
+	Date    string               `json:"date"`
+	Note    string               `json:"note,omitempty"`
+	Windows []AvailabilityWindow `json:"windows,omitempty"`
+}
+
+// ImageSet is an uploaded image: URL is the full-size picture, the srcsets
+// list it together with its thumbnails as "url 640w" candidates.
+type ImageSet struct {
+	URL        string `json:"image_url"`
+	Srcset     string `json:"image_srcset"`
+	WebPSrcset string `json:"image_webp_srcset"`
 }
 
 type Dish struct {
-	ID           int       `json:"dish_id"`
+	ID                  int                  `json:"dish_id"`
+	RestaurantID        int                  `json:"restaurant_id"`
+	CategoryID          *int                 `json:"category_id"`
+	SKU                 string               `json:"sku"`
+	Name                string               `json:"name"`
+	Description         string               `json:"description"`
+	Price               float64              `json:"price"`
+	ImageURL            string               `json:"image_url"`
+	ImageSrcset         string               `json:"image_srcset,omitempty"`
+	ImageWebP           string               `json:"image_webp_srcset,omitempty"`
+	SortOrder           int                  `json:"sort_order"`
+	StopListed          bool                 `json:"stop_listed"`
+	AvailabilityWindows []AvailabilityWindow `json:"availability_windows"`
+	Allergens           []string             `json:"allergens"`
+	DietaryTags         []string             `json:"dietary_tags"`
+	Nutrition           *NutritionFacts      `json:"nutrition,omitempty"`
+	Lang                string               `json:"lang,omitempty"`
+	CreatedAt           time.Time            `json:"created_at"`
+	DeletedAt           *time.Time           `json:"deleted_at,omitempty"`
+}
+
+// DishTranslation is a dish's name and description in one more language;
+// Lang is a BCP 47 tag. A dish's own name is in the restaurant's locale, and
+// Dish.Lang is set when a translation replaced it.
+type DishTranslation struct {
+	DishID      int    `json:"dish_id"`
+	Lang        string `json:"lang"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+}
+
+// MenuRow is one dish in a menu import or export. Rows are matched to the
+// restaurant's dishes by SKU; Category is a category name.
+type MenuRow struct {
+	SKU         string          `json:"sku"`
+	Category    string          `json:"category,omitempty"`
+	Name        string          `json:"name"`
+	Description string          `json:"description,omitempty"`
+	Price       float64         `json:"price"`
+	Allergens   []string        `json:"allergens,omitempty"`
+	DietaryTags []string        `json:"dietary_tags,omitempty"`
+	Nutrition   *NutritionFacts `json:"nutrition,omitempty"`
+}
+
+// MenuRowError points at a data row of an import, counting from 1.
+type MenuRowError struct {
+	Row     int    `json:"row"`
+	SKU     string `json:"sku,omitempty"`
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message"`
+}
+
+type MenuImportReport struct {
+	DryRun            bool           `json:"dry_run"`
+	Rows              int            `json:"rows"`
+	Created           int            `json:"created"`
+	Updated           int            `json:"updated"`
+	CategoriesCreated int            `json:"categories_created"`
+	MenuVersion       int            `json:"menu_version,omitempty"`
+	Errors            []MenuRowError `json:"errors"`
+}
+
+// NutritionFacts are given per serving.
+type NutritionFacts struct {
+	Calories      float64 `json:"calories"`
+	Protein       float64 `json:"protein"`
+	Fat           float64 `json:"fat"`
+	Carbohydrates float64 `json:"carbohydrates"`
+	ServingGrams  float64 `json:"serving_grams,omitempty"`
+}
+
+// AvailabilityWindow limits when a dish can be ordered, e.g. breakfast from
+// 08:00 to 11:00. Times are "HH:MM"; a window whose To is before From runs past
+// midnight. Empty Weekdays means every day (0 is Sunday, as in time.Weekday).
+type AvailabilityWindow struct {
+	Weekdays []int  `json:"weekdays,omitempty"`
+	From     string `json:"from"`
+	To       string `json:"to"`
+}
+
+// DishFilter narrows dish listings. Zero value returns every dish.
+type DishFilter struct {
+	AvailableAt      *time.Time
+	ExcludeAllergens []string
+	Tags             []string
+}
+
+// RestaurantFilter narrows and pages GET /api/restaurants. Sort is a column
+// name, descending with a leading "-". Cursor is the opaque token of the
+// previous page; the service decodes it into After, which continues after
+// the last restaurant of that page.
+type RestaurantFilter struct {
+	Name     string
+	HasImage *bool
+	Sort     string
+	Limit    int
+	Cursor   string
+	After    *PageCursor
+}
+
+// OrderFilter narrows and pages GET /api/orders the same way. From is
+// inclusive, To exclusive.
+type OrderFilter struct {
+	RestaurantID int
+	Status       string
+	From         *time.Time
+	To           *time.Time
+	MinTotal     *float64
+	Sort         string
+	Limit        int
+	Cursor       string
+	After        *PageCursor
+}
+
+// PageCursor is the position of the last row of a page in keyset order: the
+// value of the sort column, as text Postgres can cast back, and the ID that
+// breaks ties.
+type PageCursor struct {
+	Value string `json:"v"`
+	ID    int    `json:"id"`
+}
+
+type PriceChange struct {
+	DishID    int       `json:"dish_id"`
+	Price     float64   `json:"price"`
+	ChangedAt time.Time `json:"changed_at"`
+}
+
+// Menu draft entities and actions.
+const (
+	DraftEntityDish     = "dish"
+	DraftEntityCategory = "category"
+
+	DraftActionUpsert = "upsert"
+	DraftActionDelete = "delete"
+)
+
+// MenuDraftChange is one staged edit of the menu. EntityID is zero for a dish
+// or category that does not exist yet. Dish or Category carries the new state
+// of an upsert and is nil for a delete.
+type MenuDraftChange struct {
+	ID           int           `json:"id"`
+	RestaurantID int           `json:"restaurant_id"`
+	Entity       string        `json:"entity"`
+	Action       string        `json:"action"`
+	EntityID     int           `json:"entity_id,omitempty"`
+	Dish         *Dish         `json:"dish,omitempty"`
+	Category     *MenuCategory `json:"category,omitempty"`
+	CreatedAt    time.Time     `json:"created_at"`
+}
+
+// MenuVersion is a published menu. Categories and Dishes hold the snapshot
+// taken at publish time and are only filled when a single version is loaded.
+type MenuVersion struct {
+	RestaurantID   int            `json:"restaurant_id"`
+	Version        int            `json:"version"`
+	RolledBackFrom *int           `json:"rolled_back_from,omitempty"`
+	Current        bool           `json:"current"`
+	PublishedAt    time.Time      `json:"published_at"`
+	Categories     []MenuCategory `json:"categories,omitempty"`
+	Dishes         []Dish         `json:"dishes,omitempty"`
+}
+
+type MenuCategory struct {
+	ID           int       `json:"id"`
 	RestaurantID int       `json:"restaurant_id"`
 	Name         string    `json:"name"`
-	Description  string    `json:"description"`
-	Price        float64   `json:"price"`
-	ImageURL     string    `json:"image_url"`
+	SortOrder    int       `json:"sort_order"`
+	Visible      bool      `json:"visible"`
 	CreatedAt    time.Time `json:"created_at"`
 }
 
+// MenuSection is one category of the grouped menu. Category is nil for the
+// section holding dishes that are not assigned to any category.
+type MenuSection struct {
+	Category *MenuCategory `json:"category"`
+	Dishes   []Dish        `json:"dishes"`
+}
+
 type Order struct {
 	ID             int         `json:"id"`
 	RestaurantID   int         `json:"restaurant_id"`
 	RestaurantName string      `json:"cafe_name"`
+	TableID        *int        `json:"table_id,omitempty"`
+	TableLabel     string      `json:"table_label,omitempty"`
 	TotalAmount    float64     `json:"total_amount"`
 	Status         string      `json:"status"`
 	QRCode         string      `json:"qr_code,omitempty"`
+	MenuVersion    int         `json:"menu_version"`
 	CreatedAt      time.Time   `json:"created_at"`
 	Items          []OrderItem `json:"items"`
 }
 
+// Table is a table in the dining room. Its static QR code links to
+// /t/<Code>, which opens the menu and lets guests rate the visit before any
+// order is linked. Section groups tables in analytics, e.g. by waiter zone.
+// RestaurantName is only filled when the table is looked up by its code.
+type Table struct {
+	ID             int       `json:"id"`
+	RestaurantID   int       `json:"restaurant_id"`
+	RestaurantName string    `json:"cafe_name,omitempty"`
+	Label          string    `json:"label"`
+	Section        string    `json:"section"`
+	Code           string    `json:"code"`
+	QRCode         string    `json:"qr_code,omitempty"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// OrderItem.Price is the unit price including the selected options.
 type OrderItem struct {
-	DishID   int     `json:"dish_id"`
-	DishName string  `json:"dish_name"`
-	Quantity int     `json:"quantity"`
-	Price    float64 `json:"price"`
+	ID       int               `json:"id,omitempty"`
+	DishID   int               `json:"dish_id"`
+	DishName string            `json:"dish_name"`
+	Quantity int               `json:"quantity"`
+	Price    float64           `json:"price"`
+	Options  []OrderItemOption `json:"options,omitempty"`
+}
+
+// OrderItemOption is a modifier option chosen for an order item. Name and
+// PriceDelta are copied at order time so receipts survive menu edits.
+type OrderItemOption struct {
+	OptionID   int     `json:"option_id"`
+	GroupName  string  `json:"group_name,omitempty"`
+	Name       string  `json:"name"`
+	PriceDelta float64 `json:"price_delta"`
+}
+
+// ReceiptSettings is how a restaurant's printed receipts look. QRSize is the
+// side of the review QR code in CSS pixels, QRLevel its error correction
+// level: L, M, Q or H. The logo is drawn over the middle of the QR code, which
+// needs level Q or H to stay readable. Colors are "#rrggbb". PaperWidth (58
+// or 80 mm) and PrinterQR ("native" or "raster") describe the thermal printer
+// ESC/POS receipts are sent to.
+type ReceiptSettings struct {
+	RestaurantID    int    `json:"restaurant_id"`
+	QRSize          int    `json:"qr_size"`
+	QRLevel         string `json:"qr_level"`
+	QRColor         string `json:"qr_color"`
+	BackgroundColor string `json:"background_color"`
+	AccentColor     string `json:"accent_color"`
+	LogoURL         string `json:"logo_url,omitempty"`
+	FooterText      string `json:"footer_text,omitempty"`
+	PaperWidth      int    `json:"paper_width"`
+	PrinterQR       string `json:"printer_qr"`
+}
+
+// ModifierGroup is a set of options for a dish such as "Size" or "Extras".
+// A guest must pick between MinSelect and MaxSelect options of the group.
+type ModifierGroup struct {
+	ID        int              `json:"id"`
+	DishID    int              `json:"dish_id"`
+	Name      string           `json:"name"`
+	MinSelect int              `json:"min_select"`
+	MaxSelect int              `json:"max_select"`
+	SortOrder int              `json:"sort_order"`
+	Options   []ModifierOption `json:"options"`
+}
+
+type ModifierOption struct {
+	ID         int     `json:"id"`
+	GroupID    int     `json:"group_id"`
+	Name       string  `json:"name"`
+	PriceDelta float64 `json:"price_delta"`
+	SortOrder  int     `json:"sort_order"`
+}
+
+type OrderItemError struct {
+	Index   int    `json:"index"`
+	DishID  int    `json:"dish_id"`
+	Message string `json:"message"`
+}
+
+// SearchQuery is a full-text search over restaurants and dishes. Nil bounds
+// and an empty RestaurantID do not filter.
+type SearchQuery struct {
+	Text             string
+	MinPrice         *float64
+	MaxPrice         *float64
+	MinRating        *float64
+	ExcludeAllergens []string
+	RestaurantID     int
+	Limit            int
+}
+
+// SearchResults holds restaurant and dish hits, best match first. Score mixes
+// full-text rank with trigram similarity and is only comparable within one
+// response.
+type SearchResults struct {
+	Query       string          `json:"query"`
+	Restaurants []RestaurantHit `json:"restaurants"`
+	Dishes      []DishHit       `json:"dishes"`
+}
+
+type RestaurantHit struct {
+	ID          int     `json:"id"`
+	Name        string  `json:"name"`
+	Address     string  `json:"address"`
+	Description string  `json:"description"`
+	ImageURL    string  `json:"image_url"`
+	Score       float64 `json:"score"`
+}
+
+type DishHit struct {
+	ID             int      `json:"id"`
+	RestaurantID   int      `json:"restaurant_id"`
+	RestaurantName string   `json:"cafe_name"`
+	Name           string   `json:"name"`
+	Description    string   `json:"description"`
+	Price          float64  `json:"price"`
+	AvgRating      float64  `json:"avg_rating"`
+	Allergens      []string `json:"allergens"`
+	ImageURL       string   `json:"image_url"`
+	Score          float64  `json:"score"`
 }
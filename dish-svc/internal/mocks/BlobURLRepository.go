@@ -0,0 +1,56 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// BlobURLRepository is an autogenerated mock type for the BlobURLRepository type
+type BlobURLRepository struct {
+	mock.Mock
+}
+
+// RewriteImageURLs provides a mock function with given fields: ctx, oldPrefix, newPrefix
+func (_m *BlobURLRepository) RewriteImageURLs(ctx context.Context, oldPrefix string, newPrefix string) (int64, error) {
+	ret := _m.Called(ctx, oldPrefix, newPrefix)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RewriteImageURLs")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (int64, error)); ok {
+		return rf(ctx, oldPrefix, newPrefix)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) int64); ok {
+		r0 = rf(ctx, oldPrefix, newPrefix)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, oldPrefix, newPrefix)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewBlobURLRepository creates a new instance of BlobURLRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewBlobURLRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *BlobURLRepository {
+	mock := &BlobURLRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,159 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	domain "overcooked-simplified/dish-svc/internal/domain"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// CategoryRepository is an autogenerated mock type for the CategoryRepository type
+type CategoryRepository struct {
+	mock.Mock
+}
+
+// CreateCategory provides a mock function with given fields: ctx, cat
+func (_m *CategoryRepository) CreateCategory(ctx context.Context, cat *domain.MenuCategory) error {
+	ret := _m.Called(ctx, cat)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateCategory")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.MenuCategory) error); ok {
+		r0 = rf(ctx, cat)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteCategory provides a mock function with given fields: ctx, restaurantID, categoryID
+func (_m *CategoryRepository) DeleteCategory(ctx context.Context, restaurantID int, categoryID int) (int64, error) {
+	ret := _m.Called(ctx, restaurantID, categoryID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteCategory")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) (int64, error)); ok {
+		return rf(ctx, restaurantID, categoryID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) int64); ok {
+		r0 = rf(ctx, restaurantID, categoryID)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int) error); ok {
+		r1 = rf(ctx, restaurantID, categoryID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListCategories provides a mock function with given fields: ctx, restaurantID
+func (_m *CategoryRepository) ListCategories(ctx context.Context, restaurantID int) ([]domain.MenuCategory, error) {
+	ret := _m.Called(ctx, restaurantID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListCategories")
+	}
+
+	var r0 []domain.MenuCategory
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) ([]domain.MenuCategory, error)); ok {
+		return rf(ctx, restaurantID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) []domain.MenuCategory); ok {
+		r0 = rf(ctx, restaurantID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.MenuCategory)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, restaurantID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ReorderCategories provides a mock function with given fields: ctx, restaurantID, categoryIDs
+func (_m *CategoryRepository) ReorderCategories(ctx context.Context, restaurantID int, categoryIDs []int) error {
+	ret := _m.Called(ctx, restaurantID, categoryIDs)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReorderCategories")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, []int) error); ok {
+		r0 = rf(ctx, restaurantID, categoryIDs)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ReorderDishes provides a mock function with given fields: ctx, restaurantID, categoryID, dishIDs
+func (_m *CategoryRepository) ReorderDishes(ctx context.Context, restaurantID int, categoryID int, dishIDs []int) error {
+	ret := _m.Called(ctx, restaurantID, categoryID, dishIDs)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReorderDishes")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, []int) error); ok {
+		r0 = rf(ctx, restaurantID, categoryID, dishIDs)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateCategory provides a mock function with given fields: ctx, cat
+func (_m *CategoryRepository) UpdateCategory(ctx context.Context, cat *domain.MenuCategory) error {
+	ret := _m.Called(ctx, cat)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateCategory")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.MenuCategory) error); ok {
+		r0 = rf(ctx, cat)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewCategoryRepository creates a new instance of CategoryRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewCategoryRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *CategoryRepository {
+	mock := &CategoryRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -3,6 +3,7 @@
 package mocks
 
 import (
+	context "context"
 	domain "overcooked-simplified/dish-svc/internal/domain"
 
 	mock "github.com/stretchr/testify/mock"
@@ -13,17 +14,17 @@ type DishRepository struct {
 	mock.Mock
 }
 
-// CreateDish provides a mock function with given fields: dish
-func (_m *DishRepository) CreateDish(dish *domain.Dish) error {
-	ret := _m.Called(dish)
+// CreateDish provides a mock function with given fields: ctx, dish
+func (_m *DishRepository) CreateDish(ctx context.Context, dish *domain.Dish) error {
+	ret := _m.Called(ctx, dish)
 
 	if len(ret) == 0 {
 		panic("no return value specified for CreateDish")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(*domain.Dish) error); ok {
-		r0 = rf(dish)
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Dish) error); ok {
+		r0 = rf(ctx, dish)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -31,9 +32,9 @@ func (_m *DishRepository) CreateDish(dish *domain.Dish) error {
 	return r0
 }
 
-// DeleteDish provides a mock function with given fields: restaurantID, dishID
-func (_m *DishRepository) DeleteDish(restaurantID int, dishID int) (int64, error) {
-	ret := _m.Called(restaurantID, dishID)
+// DeleteDish provides a mock function with given fields: ctx, restaurantID, dishID
+func (_m *DishRepository) DeleteDish(ctx context.Context, restaurantID int, dishID int) (int64, error) {
+	ret := _m.Called(ctx, restaurantID, dishID)
 
 	if len(ret) == 0 {
 		panic("no return value specified for DeleteDish")
@@ -41,17 +42,17 @@ func (_m *DishRepository) DeleteDish(restaurantID int, dishID int) (int64, error
 
 	var r0 int64
 	var r1 error
-	if rf, ok := ret.Get(0).(func(int, int) (int64, error)); ok {
-		return rf(restaurantID, dishID)
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) (int64, error)); ok {
+		return rf(ctx, restaurantID, dishID)
 	}
-	if rf, ok := ret.Get(0).(func(int, int) int64); ok {
-		r0 = rf(restaurantID, dishID)
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) int64); ok {
+		r0 = rf(ctx, restaurantID, dishID)
 	} else {
 		r0 = ret.Get(0).(int64)
 	}
 
-	if rf, ok := ret.Get(1).(func(int, int) error); ok {
-		r1 = rf(restaurantID, dishID)
+	if rf, ok := ret.Get(1).(func(context.Context, int, int) error); ok {
+		r1 = rf(ctx, restaurantID, dishID)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -59,9 +60,39 @@ func (_m *DishRepository) DeleteDish(restaurantID int, dishID int) (int64, error
 	return r0, r1
 }
 
-// GetDish provides a mock function with given fields: restaurantID, dishID
-func (_m *DishRepository) GetDish(restaurantID int, dishID int) (*domain.Dish, error) {
-	ret := _m.Called(restaurantID, dishID)
+// GetCategory provides a mock function with given fields: ctx, restaurantID, categoryID
+func (_m *DishRepository) GetCategory(ctx context.Context, restaurantID int, categoryID int) (*domain.MenuCategory, error) {
+	ret := _m.Called(ctx, restaurantID, categoryID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetCategory")
+	}
+
+	var r0 *domain.MenuCategory
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) (*domain.MenuCategory, error)); ok {
+		return rf(ctx, restaurantID, categoryID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) *domain.MenuCategory); ok {
+		r0 = rf(ctx, restaurantID, categoryID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.MenuCategory)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int) error); ok {
+		r1 = rf(ctx, restaurantID, categoryID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetDish provides a mock function with given fields: ctx, restaurantID, dishID
+func (_m *DishRepository) GetDish(ctx context.Context, restaurantID int, dishID int) (*domain.Dish, error) {
+	ret := _m.Called(ctx, restaurantID, dishID)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetDish")
@@ -69,19 +100,137 @@ func (_m *DishRepository) GetDish(restaurantID int, dishID int) (*domain.Dish, e
 
 	var r0 *domain.Dish
 	var r1 error
-	if rf, ok := ret.Get(0).(func(int, int) (*domain.Dish, error)); ok {
-		return rf(restaurantID, dishID)
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) (*domain.Dish, error)); ok {
+		return rf(ctx, restaurantID, dishID)
 	}
-	if rf, ok := ret.Get(0).(func(int, int) *domain.Dish); ok {
-		r0 = rf(restaurantID, dishID)
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) *domain.Dish); ok {
+		r0 = rf(ctx, restaurantID, dishID)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*domain.Dish)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(int, int) error); ok {
-		r1 = rf(restaurantID, dishID)
+	if rf, ok := ret.Get(1).(func(context.Context, int, int) error); ok {
+		r1 = rf(ctx, restaurantID, dishID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetDishWithDeleted provides a mock function with given fields: ctx, restaurantID, dishID
+func (_m *DishRepository) GetDishWithDeleted(ctx context.Context, restaurantID int, dishID int) (*domain.Dish, error) {
+	ret := _m.Called(ctx, restaurantID, dishID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetDishWithDeleted")
+	}
+
+	var r0 *domain.Dish
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) (*domain.Dish, error)); ok {
+		return rf(ctx, restaurantID, dishID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) *domain.Dish); ok {
+		r0 = rf(ctx, restaurantID, dishID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Dish)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int) error); ok {
+		r1 = rf(ctx, restaurantID, dishID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetRestaurant provides a mock function with given fields: ctx, id
+func (_m *DishRepository) GetRestaurant(ctx context.Context, id int) (*domain.Restaurant, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetRestaurant")
+	}
+
+	var r0 *domain.Restaurant
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (*domain.Restaurant, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) *domain.Restaurant); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Restaurant)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ImageInUse provides a mock function with given fields: ctx, imageURL
+func (_m *DishRepository) ImageInUse(ctx context.Context, imageURL string) (bool, error) {
+	ret := _m.Called(ctx, imageURL)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ImageInUse")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (bool, error)); ok {
+		return rf(ctx, imageURL)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) bool); ok {
+		r0 = rf(ctx, imageURL)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, imageURL)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListDeletedDishes provides a mock function with given fields: ctx, restaurantID
+func (_m *DishRepository) ListDeletedDishes(ctx context.Context, restaurantID int) ([]domain.Dish, error) {
+	ret := _m.Called(ctx, restaurantID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListDeletedDishes")
+	}
+
+	var r0 []domain.Dish
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) ([]domain.Dish, error)); ok {
+		return rf(ctx, restaurantID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) []domain.Dish); ok {
+		r0 = rf(ctx, restaurantID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Dish)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, restaurantID)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -89,9 +238,9 @@ func (_m *DishRepository) GetDish(restaurantID int, dishID int) (*domain.Dish, e
 	return r0, r1
 }
 
-// ListDishes provides a mock function with given fields: restaurantID
-func (_m *DishRepository) ListDishes(restaurantID int) ([]domain.Dish, error) {
-	ret := _m.Called(restaurantID)
+// ListDishes provides a mock function with given fields: ctx, restaurantID
+func (_m *DishRepository) ListDishes(ctx context.Context, restaurantID int) ([]domain.Dish, error) {
+	ret := _m.Called(ctx, restaurantID)
 
 	if len(ret) == 0 {
 		panic("no return value specified for ListDishes")
@@ -99,19 +248,77 @@ func (_m *DishRepository) ListDishes(restaurantID int) ([]domain.Dish, error) {
 
 	var r0 []domain.Dish
 	var r1 error
-	if rf, ok := ret.Get(0).(func(int) ([]domain.Dish, error)); ok {
-		return rf(restaurantID)
+	if rf, ok := ret.Get(0).(func(context.Context, int) ([]domain.Dish, error)); ok {
+		return rf(ctx, restaurantID)
 	}
-	if rf, ok := ret.Get(0).(func(int) []domain.Dish); ok {
-		r0 = rf(restaurantID)
+	if rf, ok := ret.Get(0).(func(context.Context, int) []domain.Dish); ok {
+		r0 = rf(ctx, restaurantID)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]domain.Dish)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(int) error); ok {
-		r1 = rf(restaurantID)
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, restaurantID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListPriceHistory provides a mock function with given fields: ctx, restaurantID, dishID
+func (_m *DishRepository) ListPriceHistory(ctx context.Context, restaurantID int, dishID int) ([]domain.PriceChange, error) {
+	ret := _m.Called(ctx, restaurantID, dishID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListPriceHistory")
+	}
+
+	var r0 []domain.PriceChange
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) ([]domain.PriceChange, error)); ok {
+		return rf(ctx, restaurantID, dishID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) []domain.PriceChange); ok {
+		r0 = rf(ctx, restaurantID, dishID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.PriceChange)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int) error); ok {
+		r1 = rf(ctx, restaurantID, dishID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PurgeDish provides a mock function with given fields: ctx, restaurantID, dishID
+func (_m *DishRepository) PurgeDish(ctx context.Context, restaurantID int, dishID int) (int64, error) {
+	ret := _m.Called(ctx, restaurantID, dishID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PurgeDish")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) (int64, error)); ok {
+		return rf(ctx, restaurantID, dishID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) int64); ok {
+		r0 = rf(ctx, restaurantID, dishID)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int) error); ok {
+		r1 = rf(ctx, restaurantID, dishID)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -119,17 +326,45 @@ func (_m *DishRepository) ListDishes(restaurantID int) ([]domain.Dish, error) {
 	return r0, r1
 }
 
-// UpdateDish provides a mock function with given fields: dish
-func (_m *DishRepository) UpdateDish(dish *domain.Dish) error {
-	ret := _m.Called(dish)
+// RestoreDish provides a mock function with given fields: ctx, restaurantID, dishID
+func (_m *DishRepository) RestoreDish(ctx context.Context, restaurantID int, dishID int) (int64, error) {
+	ret := _m.Called(ctx, restaurantID, dishID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RestoreDish")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) (int64, error)); ok {
+		return rf(ctx, restaurantID, dishID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) int64); ok {
+		r0 = rf(ctx, restaurantID, dishID)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int) error); ok {
+		r1 = rf(ctx, restaurantID, dishID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateDish provides a mock function with given fields: ctx, dish
+func (_m *DishRepository) UpdateDish(ctx context.Context, dish *domain.Dish) error {
+	ret := _m.Called(ctx, dish)
 
 	if len(ret) == 0 {
 		panic("no return value specified for UpdateDish")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(*domain.Dish) error); ok {
-		r0 = rf(dish)
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Dish) error); ok {
+		r0 = rf(ctx, dish)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -137,17 +372,45 @@ func (_m *DishRepository) UpdateDish(dish *domain.Dish) error {
 	return r0
 }
 
-// UpdateDishImage provides a mock function with given fields: restaurantID, dishID, imageURL
-func (_m *DishRepository) UpdateDishImage(restaurantID int, dishID int, imageURL string) error {
-	ret := _m.Called(restaurantID, dishID, imageURL)
+// UpdateDishAvailability provides a mock function with given fields: ctx, restaurantID, dishID, stopListed, windows
+func (_m *DishRepository) UpdateDishAvailability(ctx context.Context, restaurantID int, dishID int, stopListed bool, windows []domain.AvailabilityWindow) (int64, error) {
+	ret := _m.Called(ctx, restaurantID, dishID, stopListed, windows)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateDishAvailability")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, bool, []domain.AvailabilityWindow) (int64, error)); ok {
+		return rf(ctx, restaurantID, dishID, stopListed, windows)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, bool, []domain.AvailabilityWindow) int64); ok {
+		r0 = rf(ctx, restaurantID, dishID, stopListed, windows)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int, bool, []domain.AvailabilityWindow) error); ok {
+		r1 = rf(ctx, restaurantID, dishID, stopListed, windows)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateDishImage provides a mock function with given fields: ctx, restaurantID, dishID, image
+func (_m *DishRepository) UpdateDishImage(ctx context.Context, restaurantID int, dishID int, image domain.ImageSet) error {
+	ret := _m.Called(ctx, restaurantID, dishID, image)
 
 	if len(ret) == 0 {
 		panic("no return value specified for UpdateDishImage")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(int, int, string) error); ok {
-		r0 = rf(restaurantID, dishID, imageURL)
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, domain.ImageSet) error); ok {
+		r0 = rf(ctx, restaurantID, dishID, image)
 	} else {
 		r0 = ret.Error(0)
 	}
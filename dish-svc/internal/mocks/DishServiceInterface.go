@@ -3,6 +3,8 @@
 package mocks
 
 import (
+	context "context"
+	io "io"
 	domain "overcooked-simplified/dish-svc/internal/domain"
 
 	mock "github.com/stretchr/testify/mock"
@@ -13,17 +15,17 @@ type DishServiceInterface struct {
 	mock.Mock
 }
 
-// Create provides a mock function with given fields: dish
-func (_m *DishServiceInterface) Create(dish *domain.Dish) error {
-	ret := _m.Called(dish)
+// Create provides a mock function with given fields: ctx, dish
+func (_m *DishServiceInterface) Create(ctx context.Context, dish *domain.Dish) error {
+	ret := _m.Called(ctx, dish)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Create")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(*domain.Dish) error); ok {
-		r0 = rf(dish)
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Dish) error); ok {
+		r0 = rf(ctx, dish)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -31,9 +33,9 @@ func (_m *DishServiceInterface) Create(dish *domain.Dish) error {
 	return r0
 }
 
-// Delete provides a mock function with given fields: restaurantID, dishID
-func (_m *DishServiceInterface) Delete(restaurantID int, dishID int) (int64, error) {
-	ret := _m.Called(restaurantID, dishID)
+// Delete provides a mock function with given fields: ctx, restaurantID, dishID
+func (_m *DishServiceInterface) Delete(ctx context.Context, restaurantID int, dishID int) (int64, error) {
+	ret := _m.Called(ctx, restaurantID, dishID)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Delete")
@@ -41,17 +43,17 @@ func (_m *DishServiceInterface) Delete(restaurantID int, dishID int) (int64, err
 
 	var r0 int64
 	var r1 error
-	if rf, ok := ret.Get(0).(func(int, int) (int64, error)); ok {
-		return rf(restaurantID, dishID)
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) (int64, error)); ok {
+		return rf(ctx, restaurantID, dishID)
 	}
-	if rf, ok := ret.Get(0).(func(int, int) int64); ok {
-		r0 = rf(restaurantID, dishID)
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) int64); ok {
+		r0 = rf(ctx, restaurantID, dishID)
 	} else {
 		r0 = ret.Get(0).(int64)
 	}
 
-	if rf, ok := ret.Get(1).(func(int, int) error); ok {
-		r1 = rf(restaurantID, dishID)
+	if rf, ok := ret.Get(1).(func(context.Context, int, int) error); ok {
+		r1 = rf(ctx, restaurantID, dishID)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -59,9 +61,9 @@ func (_m *DishServiceInterface) Delete(restaurantID int, dishID int) (int64, err
 	return r0, r1
 }
 
-// Get provides a mock function with given fields: restaurantID, dishID
-func (_m *DishServiceInterface) Get(restaurantID int, dishID int) (*domain.Dish, error) {
-	ret := _m.Called(restaurantID, dishID)
+// Get provides a mock function with given fields: ctx, restaurantID, dishID
+func (_m *DishServiceInterface) Get(ctx context.Context, restaurantID int, dishID int) (*domain.Dish, error) {
+	ret := _m.Called(ctx, restaurantID, dishID)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Get")
@@ -69,19 +71,19 @@ func (_m *DishServiceInterface) Get(restaurantID int, dishID int) (*domain.Dish,
 
 	var r0 *domain.Dish
 	var r1 error
-	if rf, ok := ret.Get(0).(func(int, int) (*domain.Dish, error)); ok {
-		return rf(restaurantID, dishID)
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) (*domain.Dish, error)); ok {
+		return rf(ctx, restaurantID, dishID)
 	}
-	if rf, ok := ret.Get(0).(func(int, int) *domain.Dish); ok {
-		r0 = rf(restaurantID, dishID)
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) *domain.Dish); ok {
+		r0 = rf(ctx, restaurantID, dishID)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*domain.Dish)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(int, int) error); ok {
-		r1 = rf(restaurantID, dishID)
+	if rf, ok := ret.Get(1).(func(context.Context, int, int) error); ok {
+		r1 = rf(ctx, restaurantID, dishID)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -89,9 +91,9 @@ func (_m *DishServiceInterface) Get(restaurantID int, dishID int) (*domain.Dish,
 	return r0, r1
 }
 
-// List provides a mock function with given fields: restaurantID
-func (_m *DishServiceInterface) List(restaurantID int) ([]domain.Dish, error) {
-	ret := _m.Called(restaurantID)
+// List provides a mock function with given fields: ctx, restaurantID, filter
+func (_m *DishServiceInterface) List(ctx context.Context, restaurantID int, filter domain.DishFilter) ([]domain.Dish, error) {
+	ret := _m.Called(ctx, restaurantID, filter)
 
 	if len(ret) == 0 {
 		panic("no return value specified for List")
@@ -99,19 +101,19 @@ func (_m *DishServiceInterface) List(restaurantID int) ([]domain.Dish, error) {
 
 	var r0 []domain.Dish
 	var r1 error
-	if rf, ok := ret.Get(0).(func(int) ([]domain.Dish, error)); ok {
-		return rf(restaurantID)
+	if rf, ok := ret.Get(0).(func(context.Context, int, domain.DishFilter) ([]domain.Dish, error)); ok {
+		return rf(ctx, restaurantID, filter)
 	}
-	if rf, ok := ret.Get(0).(func(int) []domain.Dish); ok {
-		r0 = rf(restaurantID)
+	if rf, ok := ret.Get(0).(func(context.Context, int, domain.DishFilter) []domain.Dish); ok {
+		r0 = rf(ctx, restaurantID, filter)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]domain.Dish)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(int) error); ok {
-		r1 = rf(restaurantID)
+	if rf, ok := ret.Get(1).(func(context.Context, int, domain.DishFilter) error); ok {
+		r1 = rf(ctx, restaurantID, filter)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -119,17 +121,77 @@ func (_m *DishServiceInterface) List(restaurantID int) ([]domain.Dish, error) {
 	return r0, r1
 }
 
-// Update provides a mock function with given fields: dish
-func (_m *DishServiceInterface) Update(dish *domain.Dish) error {
-	ret := _m.Called(dish)
+// ListDeleted provides a mock function with given fields: ctx, restaurantID
+func (_m *DishServiceInterface) ListDeleted(ctx context.Context, restaurantID int) ([]domain.Dish, error) {
+	ret := _m.Called(ctx, restaurantID)
 
 	if len(ret) == 0 {
-		panic("no return value specified for Update")
+		panic("no return value specified for ListDeleted")
+	}
+
+	var r0 []domain.Dish
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) ([]domain.Dish, error)); ok {
+		return rf(ctx, restaurantID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) []domain.Dish); ok {
+		r0 = rf(ctx, restaurantID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Dish)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, restaurantID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PriceHistory provides a mock function with given fields: ctx, restaurantID, dishID
+func (_m *DishServiceInterface) PriceHistory(ctx context.Context, restaurantID int, dishID int) ([]domain.PriceChange, error) {
+	ret := _m.Called(ctx, restaurantID, dishID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PriceHistory")
+	}
+
+	var r0 []domain.PriceChange
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) ([]domain.PriceChange, error)); ok {
+		return rf(ctx, restaurantID, dishID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) []domain.PriceChange); ok {
+		r0 = rf(ctx, restaurantID, dishID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.PriceChange)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int) error); ok {
+		r1 = rf(ctx, restaurantID, dishID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Purge provides a mock function with given fields: ctx, restaurantID, dishID, confirm
+func (_m *DishServiceInterface) Purge(ctx context.Context, restaurantID int, dishID int, confirm string) error {
+	ret := _m.Called(ctx, restaurantID, dishID, confirm)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Purge")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(*domain.Dish) error); ok {
-		r0 = rf(dish)
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, string) error); ok {
+		r0 = rf(ctx, restaurantID, dishID, confirm)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -137,17 +199,103 @@ func (_m *DishServiceInterface) Update(dish *domain.Dish) error {
 	return r0
 }
 
-// UpdateImage provides a mock function with given fields: restaurantID, dishID, imageURL
-func (_m *DishServiceInterface) UpdateImage(restaurantID int, dishID int, imageURL string) error {
-	ret := _m.Called(restaurantID, dishID, imageURL)
+// Restore provides a mock function with given fields: ctx, restaurantID, dishID
+func (_m *DishServiceInterface) Restore(ctx context.Context, restaurantID int, dishID int) (int64, error) {
+	ret := _m.Called(ctx, restaurantID, dishID)
 
 	if len(ret) == 0 {
-		panic("no return value specified for UpdateImage")
+		panic("no return value specified for Restore")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) (int64, error)); ok {
+		return rf(ctx, restaurantID, dishID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) int64); ok {
+		r0 = rf(ctx, restaurantID, dishID)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int) error); ok {
+		r1 = rf(ctx, restaurantID, dishID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SetAvailability provides a mock function with given fields: ctx, restaurantID, dishID, stopListed, windows
+func (_m *DishServiceInterface) SetAvailability(ctx context.Context, restaurantID int, dishID int, stopListed bool, windows []domain.AvailabilityWindow) (int64, error) {
+	ret := _m.Called(ctx, restaurantID, dishID, stopListed, windows)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetAvailability")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, bool, []domain.AvailabilityWindow) (int64, error)); ok {
+		return rf(ctx, restaurantID, dishID, stopListed, windows)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, bool, []domain.AvailabilityWindow) int64); ok {
+		r0 = rf(ctx, restaurantID, dishID, stopListed, windows)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int, bool, []domain.AvailabilityWindow) error); ok {
+		r1 = rf(ctx, restaurantID, dishID, stopListed, windows)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// StopList provides a mock function with given fields: ctx, restaurantID
+func (_m *DishServiceInterface) StopList(ctx context.Context, restaurantID int) ([]domain.Dish, error) {
+	ret := _m.Called(ctx, restaurantID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for StopList")
+	}
+
+	var r0 []domain.Dish
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) ([]domain.Dish, error)); ok {
+		return rf(ctx, restaurantID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) []domain.Dish); ok {
+		r0 = rf(ctx, restaurantID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Dish)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, restaurantID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Update provides a mock function with given fields: ctx, dish
+func (_m *DishServiceInterface) Update(ctx context.Context, dish *domain.Dish) error {
+	ret := _m.Called(ctx, dish)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Update")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(int, int, string) error); ok {
-		r0 = rf(restaurantID, dishID, imageURL)
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Dish) error); ok {
+		r0 = rf(ctx, dish)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -155,6 +303,36 @@ func (_m *DishServiceInterface) UpdateImage(restaurantID int, dishID int, imageU
 	return r0
 }
 
+// UpdateImage provides a mock function with given fields: ctx, restaurantID, dishID, upload
+func (_m *DishServiceInterface) UpdateImage(ctx context.Context, restaurantID int, dishID int, upload io.Reader) (*domain.ImageSet, error) {
+	ret := _m.Called(ctx, restaurantID, dishID, upload)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateImage")
+	}
+
+	var r0 *domain.ImageSet
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, io.Reader) (*domain.ImageSet, error)); ok {
+		return rf(ctx, restaurantID, dishID, upload)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, io.Reader) *domain.ImageSet); ok {
+		r0 = rf(ctx, restaurantID, dishID, upload)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.ImageSet)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int, io.Reader) error); ok {
+		r1 = rf(ctx, restaurantID, dishID, upload)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // NewDishServiceInterface creates a new instance of DishServiceInterface. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewDishServiceInterface(t interface {
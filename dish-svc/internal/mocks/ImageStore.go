@@ -0,0 +1,77 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	io "io"
+	domain "overcooked-simplified/dish-svc/internal/domain"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// ImageStore is an autogenerated mock type for the ImageStore type
+type ImageStore struct {
+	mock.Mock
+}
+
+// Remove provides a mock function with given fields: imageURL
+func (_m *ImageStore) Remove(imageURL string) error {
+	ret := _m.Called(imageURL)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Remove")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(imageURL)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Save provides a mock function with given fields: upload
+func (_m *ImageStore) Save(upload io.Reader) (*domain.ImageSet, error) {
+	ret := _m.Called(upload)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Save")
+	}
+
+	var r0 *domain.ImageSet
+	var r1 error
+	if rf, ok := ret.Get(0).(func(io.Reader) (*domain.ImageSet, error)); ok {
+		return rf(upload)
+	}
+	if rf, ok := ret.Get(0).(func(io.Reader) *domain.ImageSet); ok {
+		r0 = rf(upload)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.ImageSet)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(io.Reader) error); ok {
+		r1 = rf(upload)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewImageStore creates a new instance of ImageStore. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewImageStore(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *ImageStore {
+	mock := &ImageStore{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
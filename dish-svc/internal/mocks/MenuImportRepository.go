@@ -0,0 +1,59 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	domain "overcooked-simplified/dish-svc/internal/domain"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MenuImportRepository is an autogenerated mock type for the MenuImportRepository type
+type MenuImportRepository struct {
+	mock.Mock
+}
+
+// ImportMenu provides a mock function with given fields: ctx, restaurantID, rows
+func (_m *MenuImportRepository) ImportMenu(ctx context.Context, restaurantID int, rows []domain.MenuRow) (*domain.MenuImportReport, error) {
+	ret := _m.Called(ctx, restaurantID, rows)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ImportMenu")
+	}
+
+	var r0 *domain.MenuImportReport
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, []domain.MenuRow) (*domain.MenuImportReport, error)); ok {
+		return rf(ctx, restaurantID, rows)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, []domain.MenuRow) *domain.MenuImportReport); ok {
+		r0 = rf(ctx, restaurantID, rows)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.MenuImportReport)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, []domain.MenuRow) error); ok {
+		r1 = rf(ctx, restaurantID, rows)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewMenuImportRepository creates a new instance of MenuImportRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMenuImportRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MenuImportRepository {
+	mock := &MenuImportRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,90 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	io "io"
+	domain "overcooked-simplified/dish-svc/internal/domain"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MenuImportServiceInterface is an autogenerated mock type for the MenuImportServiceInterface type
+type MenuImportServiceInterface struct {
+	mock.Mock
+}
+
+// Export provides a mock function with given fields: ctx, restaurantID
+func (_m *MenuImportServiceInterface) Export(ctx context.Context, restaurantID int) ([]domain.MenuRow, error) {
+	ret := _m.Called(ctx, restaurantID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Export")
+	}
+
+	var r0 []domain.MenuRow
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) ([]domain.MenuRow, error)); ok {
+		return rf(ctx, restaurantID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) []domain.MenuRow); ok {
+		r0 = rf(ctx, restaurantID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.MenuRow)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, restaurantID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Import provides a mock function with given fields: ctx, restaurantID, format, body, dryRun
+func (_m *MenuImportServiceInterface) Import(ctx context.Context, restaurantID int, format string, body io.Reader, dryRun bool) (*domain.MenuImportReport, error) {
+	ret := _m.Called(ctx, restaurantID, format, body, dryRun)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Import")
+	}
+
+	var r0 *domain.MenuImportReport
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, string, io.Reader, bool) (*domain.MenuImportReport, error)); ok {
+		return rf(ctx, restaurantID, format, body, dryRun)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, string, io.Reader, bool) *domain.MenuImportReport); ok {
+		r0 = rf(ctx, restaurantID, format, body, dryRun)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.MenuImportReport)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, string, io.Reader, bool) error); ok {
+		r1 = rf(ctx, restaurantID, format, body, dryRun)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewMenuImportServiceInterface creates a new instance of MenuImportServiceInterface. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMenuImportServiceInterface(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MenuImportServiceInterface {
+	mock := &MenuImportServiceInterface{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,253 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	domain "overcooked-simplified/dish-svc/internal/domain"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MenuVersionRepository is an autogenerated mock type for the MenuVersionRepository type
+type MenuVersionRepository struct {
+	mock.Mock
+}
+
+// DeleteMenuDraftChange provides a mock function with given fields: ctx, restaurantID, changeID
+func (_m *MenuVersionRepository) DeleteMenuDraftChange(ctx context.Context, restaurantID int, changeID int) (int64, error) {
+	ret := _m.Called(ctx, restaurantID, changeID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteMenuDraftChange")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) (int64, error)); ok {
+		return rf(ctx, restaurantID, changeID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) int64); ok {
+		r0 = rf(ctx, restaurantID, changeID)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int) error); ok {
+		r1 = rf(ctx, restaurantID, changeID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DiscardMenuDraft provides a mock function with given fields: ctx, restaurantID
+func (_m *MenuVersionRepository) DiscardMenuDraft(ctx context.Context, restaurantID int) (int64, error) {
+	ret := _m.Called(ctx, restaurantID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DiscardMenuDraft")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (int64, error)); ok {
+		return rf(ctx, restaurantID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) int64); ok {
+		r0 = rf(ctx, restaurantID)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, restaurantID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetMenuVersion provides a mock function with given fields: ctx, restaurantID, version
+func (_m *MenuVersionRepository) GetMenuVersion(ctx context.Context, restaurantID int, version int) (*domain.MenuVersion, error) {
+	ret := _m.Called(ctx, restaurantID, version)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetMenuVersion")
+	}
+
+	var r0 *domain.MenuVersion
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) (*domain.MenuVersion, error)); ok {
+		return rf(ctx, restaurantID, version)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) *domain.MenuVersion); ok {
+		r0 = rf(ctx, restaurantID, version)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.MenuVersion)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int) error); ok {
+		r1 = rf(ctx, restaurantID, version)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListMenuDraft provides a mock function with given fields: ctx, restaurantID
+func (_m *MenuVersionRepository) ListMenuDraft(ctx context.Context, restaurantID int) ([]domain.MenuDraftChange, error) {
+	ret := _m.Called(ctx, restaurantID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListMenuDraft")
+	}
+
+	var r0 []domain.MenuDraftChange
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) ([]domain.MenuDraftChange, error)); ok {
+		return rf(ctx, restaurantID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) []domain.MenuDraftChange); ok {
+		r0 = rf(ctx, restaurantID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.MenuDraftChange)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, restaurantID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListMenuVersions provides a mock function with given fields: ctx, restaurantID
+func (_m *MenuVersionRepository) ListMenuVersions(ctx context.Context, restaurantID int) ([]domain.MenuVersion, error) {
+	ret := _m.Called(ctx, restaurantID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListMenuVersions")
+	}
+
+	var r0 []domain.MenuVersion
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) ([]domain.MenuVersion, error)); ok {
+		return rf(ctx, restaurantID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) []domain.MenuVersion); ok {
+		r0 = rf(ctx, restaurantID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.MenuVersion)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, restaurantID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PublishMenuDraft provides a mock function with given fields: ctx, restaurantID
+func (_m *MenuVersionRepository) PublishMenuDraft(ctx context.Context, restaurantID int) (*domain.MenuVersion, error) {
+	ret := _m.Called(ctx, restaurantID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PublishMenuDraft")
+	}
+
+	var r0 *domain.MenuVersion
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (*domain.MenuVersion, error)); ok {
+		return rf(ctx, restaurantID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) *domain.MenuVersion); ok {
+		r0 = rf(ctx, restaurantID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.MenuVersion)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, restaurantID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RollbackMenu provides a mock function with given fields: ctx, restaurantID, version
+func (_m *MenuVersionRepository) RollbackMenu(ctx context.Context, restaurantID int, version int) (*domain.MenuVersion, error) {
+	ret := _m.Called(ctx, restaurantID, version)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RollbackMenu")
+	}
+
+	var r0 *domain.MenuVersion
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) (*domain.MenuVersion, error)); ok {
+		return rf(ctx, restaurantID, version)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) *domain.MenuVersion); ok {
+		r0 = rf(ctx, restaurantID, version)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.MenuVersion)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int) error); ok {
+		r1 = rf(ctx, restaurantID, version)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// StageMenuChange provides a mock function with given fields: ctx, change
+func (_m *MenuVersionRepository) StageMenuChange(ctx context.Context, change *domain.MenuDraftChange) error {
+	ret := _m.Called(ctx, change)
+
+	if len(ret) == 0 {
+		panic("no return value specified for StageMenuChange")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.MenuDraftChange) error); ok {
+		r0 = rf(ctx, change)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewMenuVersionRepository creates a new instance of MenuVersionRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMenuVersionRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MenuVersionRepository {
+	mock := &MenuVersionRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
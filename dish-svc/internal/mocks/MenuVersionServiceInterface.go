@@ -0,0 +1,385 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	domain "overcooked-simplified/dish-svc/internal/domain"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MenuVersionServiceInterface is an autogenerated mock type for the MenuVersionServiceInterface type
+type MenuVersionServiceInterface struct {
+	mock.Mock
+}
+
+// Discard provides a mock function with given fields: ctx, restaurantID
+func (_m *MenuVersionServiceInterface) Discard(ctx context.Context, restaurantID int) (int64, error) {
+	ret := _m.Called(ctx, restaurantID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Discard")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (int64, error)); ok {
+		return rf(ctx, restaurantID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) int64); ok {
+		r0 = rf(ctx, restaurantID)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, restaurantID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Draft provides a mock function with given fields: ctx, restaurantID
+func (_m *MenuVersionServiceInterface) Draft(ctx context.Context, restaurantID int) ([]domain.MenuDraftChange, error) {
+	ret := _m.Called(ctx, restaurantID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Draft")
+	}
+
+	var r0 []domain.MenuDraftChange
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) ([]domain.MenuDraftChange, error)); ok {
+		return rf(ctx, restaurantID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) []domain.MenuDraftChange); ok {
+		r0 = rf(ctx, restaurantID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.MenuDraftChange)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, restaurantID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DropChange provides a mock function with given fields: ctx, restaurantID, changeID
+func (_m *MenuVersionServiceInterface) DropChange(ctx context.Context, restaurantID int, changeID int) (int64, error) {
+	ret := _m.Called(ctx, restaurantID, changeID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DropChange")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) (int64, error)); ok {
+		return rf(ctx, restaurantID, changeID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) int64); ok {
+		r0 = rf(ctx, restaurantID, changeID)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int) error); ok {
+		r1 = rf(ctx, restaurantID, changeID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Preview provides a mock function with given fields: ctx, restaurantID
+func (_m *MenuVersionServiceInterface) Preview(ctx context.Context, restaurantID int) ([]domain.MenuSection, error) {
+	ret := _m.Called(ctx, restaurantID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Preview")
+	}
+
+	var r0 []domain.MenuSection
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) ([]domain.MenuSection, error)); ok {
+		return rf(ctx, restaurantID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) []domain.MenuSection); ok {
+		r0 = rf(ctx, restaurantID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.MenuSection)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, restaurantID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Publish provides a mock function with given fields: ctx, restaurantID
+func (_m *MenuVersionServiceInterface) Publish(ctx context.Context, restaurantID int) (*domain.MenuVersion, error) {
+	ret := _m.Called(ctx, restaurantID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Publish")
+	}
+
+	var r0 *domain.MenuVersion
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (*domain.MenuVersion, error)); ok {
+		return rf(ctx, restaurantID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) *domain.MenuVersion); ok {
+		r0 = rf(ctx, restaurantID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.MenuVersion)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, restaurantID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Rollback provides a mock function with given fields: ctx, restaurantID, version
+func (_m *MenuVersionServiceInterface) Rollback(ctx context.Context, restaurantID int, version int) (*domain.MenuVersion, error) {
+	ret := _m.Called(ctx, restaurantID, version)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Rollback")
+	}
+
+	var r0 *domain.MenuVersion
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) (*domain.MenuVersion, error)); ok {
+		return rf(ctx, restaurantID, version)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) *domain.MenuVersion); ok {
+		r0 = rf(ctx, restaurantID, version)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.MenuVersion)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int) error); ok {
+		r1 = rf(ctx, restaurantID, version)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// StageCategory provides a mock function with given fields: ctx, cat
+func (_m *MenuVersionServiceInterface) StageCategory(ctx context.Context, cat *domain.MenuCategory) (*domain.MenuDraftChange, error) {
+	ret := _m.Called(ctx, cat)
+
+	if len(ret) == 0 {
+		panic("no return value specified for StageCategory")
+	}
+
+	var r0 *domain.MenuDraftChange
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.MenuCategory) (*domain.MenuDraftChange, error)); ok {
+		return rf(ctx, cat)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.MenuCategory) *domain.MenuDraftChange); ok {
+		r0 = rf(ctx, cat)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.MenuDraftChange)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *domain.MenuCategory) error); ok {
+		r1 = rf(ctx, cat)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// StageCategoryDeletion provides a mock function with given fields: ctx, restaurantID, categoryID
+func (_m *MenuVersionServiceInterface) StageCategoryDeletion(ctx context.Context, restaurantID int, categoryID int) (*domain.MenuDraftChange, error) {
+	ret := _m.Called(ctx, restaurantID, categoryID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for StageCategoryDeletion")
+	}
+
+	var r0 *domain.MenuDraftChange
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) (*domain.MenuDraftChange, error)); ok {
+		return rf(ctx, restaurantID, categoryID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) *domain.MenuDraftChange); ok {
+		r0 = rf(ctx, restaurantID, categoryID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.MenuDraftChange)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int) error); ok {
+		r1 = rf(ctx, restaurantID, categoryID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// StageDish provides a mock function with given fields: ctx, dish
+func (_m *MenuVersionServiceInterface) StageDish(ctx context.Context, dish *domain.Dish) (*domain.MenuDraftChange, error) {
+	ret := _m.Called(ctx, dish)
+
+	if len(ret) == 0 {
+		panic("no return value specified for StageDish")
+	}
+
+	var r0 *domain.MenuDraftChange
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Dish) (*domain.MenuDraftChange, error)); ok {
+		return rf(ctx, dish)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Dish) *domain.MenuDraftChange); ok {
+		r0 = rf(ctx, dish)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.MenuDraftChange)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *domain.Dish) error); ok {
+		r1 = rf(ctx, dish)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// StageDishDeletion provides a mock function with given fields: ctx, restaurantID, dishID
+func (_m *MenuVersionServiceInterface) StageDishDeletion(ctx context.Context, restaurantID int, dishID int) (*domain.MenuDraftChange, error) {
+	ret := _m.Called(ctx, restaurantID, dishID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for StageDishDeletion")
+	}
+
+	var r0 *domain.MenuDraftChange
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) (*domain.MenuDraftChange, error)); ok {
+		return rf(ctx, restaurantID, dishID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) *domain.MenuDraftChange); ok {
+		r0 = rf(ctx, restaurantID, dishID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.MenuDraftChange)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int) error); ok {
+		r1 = rf(ctx, restaurantID, dishID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Version provides a mock function with given fields: ctx, restaurantID, version
+func (_m *MenuVersionServiceInterface) Version(ctx context.Context, restaurantID int, version int) (*domain.MenuVersion, error) {
+	ret := _m.Called(ctx, restaurantID, version)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Version")
+	}
+
+	var r0 *domain.MenuVersion
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) (*domain.MenuVersion, error)); ok {
+		return rf(ctx, restaurantID, version)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) *domain.MenuVersion); ok {
+		r0 = rf(ctx, restaurantID, version)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.MenuVersion)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int) error); ok {
+		r1 = rf(ctx, restaurantID, version)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Versions provides a mock function with given fields: ctx, restaurantID
+func (_m *MenuVersionServiceInterface) Versions(ctx context.Context, restaurantID int) ([]domain.MenuVersion, error) {
+	ret := _m.Called(ctx, restaurantID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Versions")
+	}
+
+	var r0 []domain.MenuVersion
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) ([]domain.MenuVersion, error)); ok {
+		return rf(ctx, restaurantID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) []domain.MenuVersion); ok {
+		r0 = rf(ctx, restaurantID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.MenuVersion)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, restaurantID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewMenuVersionServiceInterface creates a new instance of MenuVersionServiceInterface. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMenuVersionServiceInterface(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MenuVersionServiceInterface {
+	mock := &MenuVersionServiceInterface{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,123 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	domain "overcooked-simplified/dish-svc/internal/domain"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// ModifierRepository is an autogenerated mock type for the ModifierRepository type
+type ModifierRepository struct {
+	mock.Mock
+}
+
+// CreateModifierGroup provides a mock function with given fields: ctx, group
+func (_m *ModifierRepository) CreateModifierGroup(ctx context.Context, group *domain.ModifierGroup) error {
+	ret := _m.Called(ctx, group)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateModifierGroup")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.ModifierGroup) error); ok {
+		r0 = rf(ctx, group)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteModifierGroup provides a mock function with given fields: ctx, dishID, groupID
+func (_m *ModifierRepository) DeleteModifierGroup(ctx context.Context, dishID int, groupID int) (int64, error) {
+	ret := _m.Called(ctx, dishID, groupID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteModifierGroup")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) (int64, error)); ok {
+		return rf(ctx, dishID, groupID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) int64); ok {
+		r0 = rf(ctx, dishID, groupID)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int) error); ok {
+		r1 = rf(ctx, dishID, groupID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListModifierGroups provides a mock function with given fields: ctx, dishIDs
+func (_m *ModifierRepository) ListModifierGroups(ctx context.Context, dishIDs []int) (map[int][]domain.ModifierGroup, error) {
+	ret := _m.Called(ctx, dishIDs)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListModifierGroups")
+	}
+
+	var r0 map[int][]domain.ModifierGroup
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []int) (map[int][]domain.ModifierGroup, error)); ok {
+		return rf(ctx, dishIDs)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []int) map[int][]domain.ModifierGroup); ok {
+		r0 = rf(ctx, dishIDs)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[int][]domain.ModifierGroup)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []int) error); ok {
+		r1 = rf(ctx, dishIDs)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateModifierGroup provides a mock function with given fields: ctx, group
+func (_m *ModifierRepository) UpdateModifierGroup(ctx context.Context, group *domain.ModifierGroup) error {
+	ret := _m.Called(ctx, group)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateModifierGroup")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.ModifierGroup) error); ok {
+		r0 = rf(ctx, group)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewModifierRepository creates a new instance of ModifierRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewModifierRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *ModifierRepository {
+	mock := &ModifierRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
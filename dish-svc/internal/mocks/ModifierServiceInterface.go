@@ -0,0 +1,123 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	domain "overcooked-simplified/dish-svc/internal/domain"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// ModifierServiceInterface is an autogenerated mock type for the ModifierServiceInterface type
+type ModifierServiceInterface struct {
+	mock.Mock
+}
+
+// Create provides a mock function with given fields: ctx, restaurantID, group
+func (_m *ModifierServiceInterface) Create(ctx context.Context, restaurantID int, group *domain.ModifierGroup) error {
+	ret := _m.Called(ctx, restaurantID, group)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Create")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, *domain.ModifierGroup) error); ok {
+		r0 = rf(ctx, restaurantID, group)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Delete provides a mock function with given fields: ctx, restaurantID, dishID, groupID
+func (_m *ModifierServiceInterface) Delete(ctx context.Context, restaurantID int, dishID int, groupID int) (int64, error) {
+	ret := _m.Called(ctx, restaurantID, dishID, groupID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Delete")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, int) (int64, error)); ok {
+		return rf(ctx, restaurantID, dishID, groupID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, int) int64); ok {
+		r0 = rf(ctx, restaurantID, dishID, groupID)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int, int) error); ok {
+		r1 = rf(ctx, restaurantID, dishID, groupID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// List provides a mock function with given fields: ctx, restaurantID, dishID
+func (_m *ModifierServiceInterface) List(ctx context.Context, restaurantID int, dishID int) ([]domain.ModifierGroup, error) {
+	ret := _m.Called(ctx, restaurantID, dishID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for List")
+	}
+
+	var r0 []domain.ModifierGroup
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) ([]domain.ModifierGroup, error)); ok {
+		return rf(ctx, restaurantID, dishID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) []domain.ModifierGroup); ok {
+		r0 = rf(ctx, restaurantID, dishID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.ModifierGroup)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int) error); ok {
+		r1 = rf(ctx, restaurantID, dishID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Update provides a mock function with given fields: ctx, restaurantID, group
+func (_m *ModifierServiceInterface) Update(ctx context.Context, restaurantID int, group *domain.ModifierGroup) error {
+	ret := _m.Called(ctx, restaurantID, group)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Update")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, *domain.ModifierGroup) error); ok {
+		r0 = rf(ctx, restaurantID, group)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewModifierServiceInterface creates a new instance of ModifierServiceInterface. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewModifierServiceInterface(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *ModifierServiceInterface {
+	mock := &ModifierServiceInterface{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -3,6 +3,7 @@
 package mocks
 
 import (
+	context "context"
 	domain "overcooked-simplified/dish-svc/internal/domain"
 
 	mock "github.com/stretchr/testify/mock"
@@ -13,17 +14,17 @@ type OrderRepository struct {
 	mock.Mock
 }
 
-// CreateOrder provides a mock function with given fields: order
-func (_m *OrderRepository) CreateOrder(order *domain.Order) error {
-	ret := _m.Called(order)
+// CreateOrder provides a mock function with given fields: ctx, order
+func (_m *OrderRepository) CreateOrder(ctx context.Context, order *domain.Order) error {
+	ret := _m.Called(ctx, order)
 
 	if len(ret) == 0 {
 		panic("no return value specified for CreateOrder")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(*domain.Order) error); ok {
-		r0 = rf(order)
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Order) error); ok {
+		r0 = rf(ctx, order)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -31,9 +32,39 @@ func (_m *OrderRepository) CreateOrder(order *domain.Order) error {
 	return r0
 }
 
-// GetOrder provides a mock function with given fields: orderID
-func (_m *OrderRepository) GetOrder(orderID int) (*domain.Order, []domain.OrderItem, error) {
-	ret := _m.Called(orderID)
+// GetDishesByIDs provides a mock function with given fields: ctx, dishIDs
+func (_m *OrderRepository) GetDishesByIDs(ctx context.Context, dishIDs []int) (map[int]domain.Dish, error) {
+	ret := _m.Called(ctx, dishIDs)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetDishesByIDs")
+	}
+
+	var r0 map[int]domain.Dish
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []int) (map[int]domain.Dish, error)); ok {
+		return rf(ctx, dishIDs)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []int) map[int]domain.Dish); ok {
+		r0 = rf(ctx, dishIDs)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[int]domain.Dish)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []int) error); ok {
+		r1 = rf(ctx, dishIDs)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetOrder provides a mock function with given fields: ctx, orderID
+func (_m *OrderRepository) GetOrder(ctx context.Context, orderID int) (*domain.Order, []domain.OrderItem, error) {
+	ret := _m.Called(ctx, orderID)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetOrder")
@@ -42,27 +73,27 @@ func (_m *OrderRepository) GetOrder(orderID int) (*domain.Order, []domain.OrderI
 	var r0 *domain.Order
 	var r1 []domain.OrderItem
 	var r2 error
-	if rf, ok := ret.Get(0).(func(int) (*domain.Order, []domain.OrderItem, error)); ok {
-		return rf(orderID)
+	if rf, ok := ret.Get(0).(func(context.Context, int) (*domain.Order, []domain.OrderItem, error)); ok {
+		return rf(ctx, orderID)
 	}
-	if rf, ok := ret.Get(0).(func(int) *domain.Order); ok {
-		r0 = rf(orderID)
+	if rf, ok := ret.Get(0).(func(context.Context, int) *domain.Order); ok {
+		r0 = rf(ctx, orderID)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*domain.Order)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(int) []domain.OrderItem); ok {
-		r1 = rf(orderID)
+	if rf, ok := ret.Get(1).(func(context.Context, int) []domain.OrderItem); ok {
+		r1 = rf(ctx, orderID)
 	} else {
 		if ret.Get(1) != nil {
 			r1 = ret.Get(1).([]domain.OrderItem)
 		}
 	}
 
-	if rf, ok := ret.Get(2).(func(int) error); ok {
-		r2 = rf(orderID)
+	if rf, ok := ret.Get(2).(func(context.Context, int) error); ok {
+		r2 = rf(ctx, orderID)
 	} else {
 		r2 = ret.Error(2)
 	}
@@ -70,9 +101,9 @@ func (_m *OrderRepository) GetOrder(orderID int) (*domain.Order, []domain.OrderI
 	return r0, r1, r2
 }
 
-// GetQRCode provides a mock function with given fields: orderID
-func (_m *OrderRepository) GetQRCode(orderID int) ([]byte, error) {
-	ret := _m.Called(orderID)
+// GetQRCode provides a mock function with given fields: ctx, orderID
+func (_m *OrderRepository) GetQRCode(ctx context.Context, orderID int) ([]byte, error) {
+	ret := _m.Called(ctx, orderID)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetQRCode")
@@ -80,19 +111,139 @@ func (_m *OrderRepository) GetQRCode(orderID int) ([]byte, error) {
 
 	var r0 []byte
 	var r1 error
-	if rf, ok := ret.Get(0).(func(int) ([]byte, error)); ok {
-		return rf(orderID)
+	if rf, ok := ret.Get(0).(func(context.Context, int) ([]byte, error)); ok {
+		return rf(ctx, orderID)
 	}
-	if rf, ok := ret.Get(0).(func(int) []byte); ok {
-		r0 = rf(orderID)
+	if rf, ok := ret.Get(0).(func(context.Context, int) []byte); ok {
+		r0 = rf(ctx, orderID)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]byte)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(int) error); ok {
-		r1 = rf(orderID)
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, orderID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetRestaurantWithDeleted provides a mock function with given fields: ctx, id
+func (_m *OrderRepository) GetRestaurantWithDeleted(ctx context.Context, id int) (*domain.Restaurant, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetRestaurantWithDeleted")
+	}
+
+	var r0 *domain.Restaurant
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (*domain.Restaurant, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) *domain.Restaurant); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Restaurant)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTable provides a mock function with given fields: ctx, restaurantID, tableID
+func (_m *OrderRepository) GetTable(ctx context.Context, restaurantID int, tableID int) (*domain.Table, error) {
+	ret := _m.Called(ctx, restaurantID, tableID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTable")
+	}
+
+	var r0 *domain.Table
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) (*domain.Table, error)); ok {
+		return rf(ctx, restaurantID, tableID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) *domain.Table); ok {
+		r0 = rf(ctx, restaurantID, tableID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Table)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int) error); ok {
+		r1 = rf(ctx, restaurantID, tableID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListModifierGroups provides a mock function with given fields: ctx, dishIDs
+func (_m *OrderRepository) ListModifierGroups(ctx context.Context, dishIDs []int) (map[int][]domain.ModifierGroup, error) {
+	ret := _m.Called(ctx, dishIDs)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListModifierGroups")
+	}
+
+	var r0 map[int][]domain.ModifierGroup
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []int) (map[int][]domain.ModifierGroup, error)); ok {
+		return rf(ctx, dishIDs)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []int) map[int][]domain.ModifierGroup); ok {
+		r0 = rf(ctx, dishIDs)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[int][]domain.ModifierGroup)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []int) error); ok {
+		r1 = rf(ctx, dishIDs)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListOrderIDs provides a mock function with given fields: ctx, restaurantID
+func (_m *OrderRepository) ListOrderIDs(ctx context.Context, restaurantID int) (map[int][]int, error) {
+	ret := _m.Called(ctx, restaurantID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListOrderIDs")
+	}
+
+	var r0 map[int][]int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (map[int][]int, error)); ok {
+		return rf(ctx, restaurantID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) map[int][]int); ok {
+		r0 = rf(ctx, restaurantID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[int][]int)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, restaurantID)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -100,9 +251,9 @@ func (_m *OrderRepository) GetQRCode(orderID int) ([]byte, error) {
 	return r0, r1
 }
 
-// ListOrders provides a mock function with no fields
-func (_m *OrderRepository) ListOrders() ([]domain.Order, error) {
-	ret := _m.Called()
+// ListOrders provides a mock function with given fields: ctx, filter
+func (_m *OrderRepository) ListOrders(ctx context.Context, filter domain.OrderFilter) ([]domain.Order, error) {
+	ret := _m.Called(ctx, filter)
 
 	if len(ret) == 0 {
 		panic("no return value specified for ListOrders")
@@ -110,19 +261,19 @@ func (_m *OrderRepository) ListOrders() ([]domain.Order, error) {
 
 	var r0 []domain.Order
 	var r1 error
-	if rf, ok := ret.Get(0).(func() ([]domain.Order, error)); ok {
-		return rf()
+	if rf, ok := ret.Get(0).(func(context.Context, domain.OrderFilter) ([]domain.Order, error)); ok {
+		return rf(ctx, filter)
 	}
-	if rf, ok := ret.Get(0).(func() []domain.Order); ok {
-		r0 = rf()
+	if rf, ok := ret.Get(0).(func(context.Context, domain.OrderFilter) []domain.Order); ok {
+		r0 = rf(ctx, filter)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]domain.Order)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func() error); ok {
-		r1 = rf()
+	if rf, ok := ret.Get(1).(func(context.Context, domain.OrderFilter) error); ok {
+		r1 = rf(ctx, filter)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -130,17 +281,17 @@ func (_m *OrderRepository) ListOrders() ([]domain.Order, error) {
 	return r0, r1
 }
 
-// SaveQRCode provides a mock function with given fields: orderID, qr
-func (_m *OrderRepository) SaveQRCode(orderID int, qr []byte) error {
-	ret := _m.Called(orderID, qr)
+// SaveQRCode provides a mock function with given fields: ctx, orderID, qr
+func (_m *OrderRepository) SaveQRCode(ctx context.Context, orderID int, qr []byte) error {
+	ret := _m.Called(ctx, orderID, qr)
 
 	if len(ret) == 0 {
 		panic("no return value specified for SaveQRCode")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(int, []byte) error); ok {
-		r0 = rf(orderID, qr)
+	if rf, ok := ret.Get(0).(func(context.Context, int, []byte) error); ok {
+		r0 = rf(ctx, orderID, qr)
 	} else {
 		r0 = ret.Error(0)
 	}
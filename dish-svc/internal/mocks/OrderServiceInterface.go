@@ -3,6 +3,7 @@
 package mocks
 
 import (
+	context "context"
 	domain "overcooked-simplified/dish-svc/internal/domain"
 
 	mock "github.com/stretchr/testify/mock"
@@ -13,17 +14,17 @@ type OrderServiceInterface struct {
 	mock.Mock
 }
 
-// Create provides a mock function with given fields: order
-func (_m *OrderServiceInterface) Create(order *domain.Order) error {
-	ret := _m.Called(order)
+// Create provides a mock function with given fields: ctx, order
+func (_m *OrderServiceInterface) Create(ctx context.Context, order *domain.Order) error {
+	ret := _m.Called(ctx, order)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Create")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(*domain.Order) error); ok {
-		r0 = rf(order)
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Order) error); ok {
+		r0 = rf(ctx, order)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -31,9 +32,9 @@ func (_m *OrderServiceInterface) Create(order *domain.Order) error {
 	return r0
 }
 
-// Get provides a mock function with given fields: orderID
-func (_m *OrderServiceInterface) Get(orderID int) (*domain.Order, error) {
-	ret := _m.Called(orderID)
+// Get provides a mock function with given fields: ctx, orderID
+func (_m *OrderServiceInterface) Get(ctx context.Context, orderID int) (*domain.Order, error) {
+	ret := _m.Called(ctx, orderID)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Get")
@@ -41,19 +42,19 @@ func (_m *OrderServiceInterface) Get(orderID int) (*domain.Order, error) {
 
 	var r0 *domain.Order
 	var r1 error
-	if rf, ok := ret.Get(0).(func(int) (*domain.Order, error)); ok {
-		return rf(orderID)
+	if rf, ok := ret.Get(0).(func(context.Context, int) (*domain.Order, error)); ok {
+		return rf(ctx, orderID)
 	}
-	if rf, ok := ret.Get(0).(func(int) *domain.Order); ok {
-		r0 = rf(orderID)
+	if rf, ok := ret.Get(0).(func(context.Context, int) *domain.Order); ok {
+		r0 = rf(ctx, orderID)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*domain.Order)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(int) error); ok {
-		r1 = rf(orderID)
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, orderID)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -61,9 +62,9 @@ func (_m *OrderServiceInterface) Get(orderID int) (*domain.Order, error) {
 	return r0, r1
 }
 
-// GetQRCode provides a mock function with given fields: orderID
-func (_m *OrderServiceInterface) GetQRCode(orderID int) ([]byte, error) {
-	ret := _m.Called(orderID)
+// GetQRCode provides a mock function with given fields: ctx, orderID
+func (_m *OrderServiceInterface) GetQRCode(ctx context.Context, orderID int) ([]byte, error) {
+	ret := _m.Called(ctx, orderID)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetQRCode")
@@ -71,19 +72,19 @@ func (_m *OrderServiceInterface) GetQRCode(orderID int) ([]byte, error) {
 
 	var r0 []byte
 	var r1 error
-	if rf, ok := ret.Get(0).(func(int) ([]byte, error)); ok {
-		return rf(orderID)
+	if rf, ok := ret.Get(0).(func(context.Context, int) ([]byte, error)); ok {
+		return rf(ctx, orderID)
 	}
-	if rf, ok := ret.Get(0).(func(int) []byte); ok {
-		r0 = rf(orderID)
+	if rf, ok := ret.Get(0).(func(context.Context, int) []byte); ok {
+		r0 = rf(ctx, orderID)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]byte)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(int) error); ok {
-		r1 = rf(orderID)
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, orderID)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -91,34 +92,41 @@ func (_m *OrderServiceInterface) GetQRCode(orderID int) ([]byte, error) {
 	return r0, r1
 }
 
-// List provides a mock function with no fields
-func (_m *OrderServiceInterface) List() ([]domain.Order, error) {
-	ret := _m.Called()
+// List provides a mock function with given fields: ctx, filter
+func (_m *OrderServiceInterface) List(ctx context.Context, filter domain.OrderFilter) ([]domain.Order, string, error) {
+	ret := _m.Called(ctx, filter)
 
 	if len(ret) == 0 {
 		panic("no return value specified for List")
 	}
 
 	var r0 []domain.Order
-	var r1 error
-	if rf, ok := ret.Get(0).(func() ([]domain.Order, error)); ok {
-		return rf()
+	var r1 string
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.OrderFilter) ([]domain.Order, string, error)); ok {
+		return rf(ctx, filter)
 	}
-	if rf, ok := ret.Get(0).(func() []domain.Order); ok {
-		r0 = rf()
+	if rf, ok := ret.Get(0).(func(context.Context, domain.OrderFilter) []domain.Order); ok {
+		r0 = rf(ctx, filter)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]domain.Order)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func() error); ok {
-		r1 = rf()
+	if rf, ok := ret.Get(1).(func(context.Context, domain.OrderFilter) string); ok {
+		r1 = rf(ctx, filter)
 	} else {
-		r1 = ret.Error(1)
+		r1 = ret.Get(1).(string)
 	}
 
-	return r0, r1
+	if rf, ok := ret.Get(2).(func(context.Context, domain.OrderFilter) error); ok {
+		r2 = rf(ctx, filter)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
 }
 
 // QRLink provides a mock function with given fields: orderID
@@ -139,17 +147,45 @@ func (_m *OrderServiceInterface) QRLink(orderID int) string {
 	return r0
 }
 
-// SaveQRCode provides a mock function with given fields: orderID, qr
-func (_m *OrderServiceInterface) SaveQRCode(orderID int, qr []byte) error {
-	ret := _m.Called(orderID, qr)
+// RegenerateQRCodes provides a mock function with given fields: ctx, restaurantID
+func (_m *OrderServiceInterface) RegenerateQRCodes(ctx context.Context, restaurantID int) (int, error) {
+	ret := _m.Called(ctx, restaurantID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RegenerateQRCodes")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (int, error)); ok {
+		return rf(ctx, restaurantID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) int); ok {
+		r0 = rf(ctx, restaurantID)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, restaurantID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SaveQRCode provides a mock function with given fields: ctx, orderID, qr
+func (_m *OrderServiceInterface) SaveQRCode(ctx context.Context, orderID int, qr []byte) error {
+	ret := _m.Called(ctx, orderID, qr)
 
 	if len(ret) == 0 {
 		panic("no return value specified for SaveQRCode")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(int, []byte) error); ok {
-		r0 = rf(orderID, qr)
+	if rf, ok := ret.Get(0).(func(context.Context, int, []byte) error); ok {
+		r0 = rf(ctx, orderID, qr)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -9,9 +9,9 @@ type QRGenerator struct {
 	mock.Mock
 }
 
-// Generate provides a mock function with given fields: orderID
-func (_m *QRGenerator) Generate(orderID int) ([]byte, error) {
-	ret := _m.Called(orderID)
+// Generate provides a mock function with given fields: content
+func (_m *QRGenerator) Generate(content string) ([]byte, error) {
+	ret := _m.Called(content)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Generate")
@@ -19,19 +19,19 @@ func (_m *QRGenerator) Generate(orderID int) ([]byte, error) {
 
 	var r0 []byte
 	var r1 error
-	if rf, ok := ret.Get(0).(func(int) ([]byte, error)); ok {
-		return rf(orderID)
+	if rf, ok := ret.Get(0).(func(string) ([]byte, error)); ok {
+		return rf(content)
 	}
-	if rf, ok := ret.Get(0).(func(int) []byte); ok {
-		r0 = rf(orderID)
+	if rf, ok := ret.Get(0).(func(string) []byte); ok {
+		r0 = rf(content)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]byte)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(int) error); ok {
-		r1 = rf(orderID)
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(content)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -0,0 +1,77 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	domain "overcooked-simplified/dish-svc/internal/domain"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// ReceiptRepository is an autogenerated mock type for the ReceiptRepository type
+type ReceiptRepository struct {
+	mock.Mock
+}
+
+// GetReceiptSettings provides a mock function with given fields: ctx, restaurantID
+func (_m *ReceiptRepository) GetReceiptSettings(ctx context.Context, restaurantID int) (*domain.ReceiptSettings, error) {
+	ret := _m.Called(ctx, restaurantID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetReceiptSettings")
+	}
+
+	var r0 *domain.ReceiptSettings
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (*domain.ReceiptSettings, error)); ok {
+		return rf(ctx, restaurantID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) *domain.ReceiptSettings); ok {
+		r0 = rf(ctx, restaurantID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.ReceiptSettings)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, restaurantID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SaveReceiptSettings provides a mock function with given fields: ctx, settings
+func (_m *ReceiptRepository) SaveReceiptSettings(ctx context.Context, settings *domain.ReceiptSettings) error {
+	ret := _m.Called(ctx, settings)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveReceiptSettings")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.ReceiptSettings) error); ok {
+		r0 = rf(ctx, settings)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewReceiptRepository creates a new instance of ReceiptRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewReceiptRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *ReceiptRepository {
+	mock := &ReceiptRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,145 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	io "io"
+	domain "overcooked-simplified/dish-svc/internal/domain"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// ReceiptServiceInterface is an autogenerated mock type for the ReceiptServiceInterface type
+type ReceiptServiceInterface struct {
+	mock.Mock
+}
+
+// Render provides a mock function with given fields: ctx, orderID, format
+func (_m *ReceiptServiceInterface) Render(ctx context.Context, orderID int, format string) ([]byte, string, error) {
+	ret := _m.Called(ctx, orderID, format)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Render")
+	}
+
+	var r0 []byte
+	var r1 string
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, string) ([]byte, string, error)); ok {
+		return rf(ctx, orderID, format)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, string) []byte); ok {
+		r0 = rf(ctx, orderID, format)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]byte)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, string) string); ok {
+		r1 = rf(ctx, orderID, format)
+	} else {
+		r1 = ret.Get(1).(string)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, int, string) error); ok {
+		r2 = rf(ctx, orderID, format)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// Settings provides a mock function with given fields: ctx, restaurantID
+func (_m *ReceiptServiceInterface) Settings(ctx context.Context, restaurantID int) (*domain.ReceiptSettings, error) {
+	ret := _m.Called(ctx, restaurantID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Settings")
+	}
+
+	var r0 *domain.ReceiptSettings
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (*domain.ReceiptSettings, error)); ok {
+		return rf(ctx, restaurantID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) *domain.ReceiptSettings); ok {
+		r0 = rf(ctx, restaurantID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.ReceiptSettings)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, restaurantID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateLogo provides a mock function with given fields: ctx, restaurantID, upload
+func (_m *ReceiptServiceInterface) UpdateLogo(ctx context.Context, restaurantID int, upload io.Reader) (*domain.ReceiptSettings, error) {
+	ret := _m.Called(ctx, restaurantID, upload)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateLogo")
+	}
+
+	var r0 *domain.ReceiptSettings
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, io.Reader) (*domain.ReceiptSettings, error)); ok {
+		return rf(ctx, restaurantID, upload)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, io.Reader) *domain.ReceiptSettings); ok {
+		r0 = rf(ctx, restaurantID, upload)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.ReceiptSettings)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, io.Reader) error); ok {
+		r1 = rf(ctx, restaurantID, upload)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateSettings provides a mock function with given fields: ctx, settings
+func (_m *ReceiptServiceInterface) UpdateSettings(ctx context.Context, settings *domain.ReceiptSettings) error {
+	ret := _m.Called(ctx, settings)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateSettings")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.ReceiptSettings) error); ok {
+		r0 = rf(ctx, settings)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewReceiptServiceInterface creates a new instance of ReceiptServiceInterface. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewReceiptServiceInterface(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *ReceiptServiceInterface {
+	mock := &ReceiptServiceInterface{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -3,6 +3,7 @@
 package mocks
 
 import (
+	context "context"
 	domain "overcooked-simplified/dish-svc/internal/domain"
 
 	mock "github.com/stretchr/testify/mock"
@@ -13,17 +14,17 @@ type RestaurantRepository struct {
 	mock.Mock
 }
 
-// CreateRestaurant provides a mock function with given fields: rest
-func (_m *RestaurantRepository) CreateRestaurant(rest *domain.Restaurant) error {
-	ret := _m.Called(rest)
+// CreateRestaurant provides a mock function with given fields: ctx, rest
+func (_m *RestaurantRepository) CreateRestaurant(ctx context.Context, rest *domain.Restaurant) error {
+	ret := _m.Called(ctx, rest)
 
 	if len(ret) == 0 {
 		panic("no return value specified for CreateRestaurant")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(*domain.Restaurant) error); ok {
-		r0 = rf(rest)
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Restaurant) error); ok {
+		r0 = rf(ctx, rest)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -31,9 +32,9 @@ func (_m *RestaurantRepository) CreateRestaurant(rest *domain.Restaurant) error
 	return r0
 }
 
-// DeleteRestaurant provides a mock function with given fields: id
-func (_m *RestaurantRepository) DeleteRestaurant(id int) (int64, error) {
-	ret := _m.Called(id)
+// DeleteRestaurant provides a mock function with given fields: ctx, id
+func (_m *RestaurantRepository) DeleteRestaurant(ctx context.Context, id int) (int64, error) {
+	ret := _m.Called(ctx, id)
 
 	if len(ret) == 0 {
 		panic("no return value specified for DeleteRestaurant")
@@ -41,17 +42,17 @@ func (_m *RestaurantRepository) DeleteRestaurant(id int) (int64, error) {
 
 	var r0 int64
 	var r1 error
-	if rf, ok := ret.Get(0).(func(int) (int64, error)); ok {
-		return rf(id)
+	if rf, ok := ret.Get(0).(func(context.Context, int) (int64, error)); ok {
+		return rf(ctx, id)
 	}
-	if rf, ok := ret.Get(0).(func(int) int64); ok {
-		r0 = rf(id)
+	if rf, ok := ret.Get(0).(func(context.Context, int) int64); ok {
+		r0 = rf(ctx, id)
 	} else {
 		r0 = ret.Get(0).(int64)
 	}
 
-	if rf, ok := ret.Get(1).(func(int) error); ok {
-		r1 = rf(id)
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, id)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -59,9 +60,9 @@ func (_m *RestaurantRepository) DeleteRestaurant(id int) (int64, error) {
 	return r0, r1
 }
 
-// GetRestaurant provides a mock function with given fields: id
-func (_m *RestaurantRepository) GetRestaurant(id int) (*domain.Restaurant, error) {
-	ret := _m.Called(id)
+// GetRestaurant provides a mock function with given fields: ctx, id
+func (_m *RestaurantRepository) GetRestaurant(ctx context.Context, id int) (*domain.Restaurant, error) {
+	ret := _m.Called(ctx, id)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetRestaurant")
@@ -69,19 +70,19 @@ func (_m *RestaurantRepository) GetRestaurant(id int) (*domain.Restaurant, error
 
 	var r0 *domain.Restaurant
 	var r1 error
-	if rf, ok := ret.Get(0).(func(int) (*domain.Restaurant, error)); ok {
-		return rf(id)
+	if rf, ok := ret.Get(0).(func(context.Context, int) (*domain.Restaurant, error)); ok {
+		return rf(ctx, id)
 	}
-	if rf, ok := ret.Get(0).(func(int) *domain.Restaurant); ok {
-		r0 = rf(id)
+	if rf, ok := ret.Get(0).(func(context.Context, int) *domain.Restaurant); ok {
+		r0 = rf(ctx, id)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*domain.Restaurant)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(int) error); ok {
-		r1 = rf(id)
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, id)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -89,9 +90,97 @@ func (_m *RestaurantRepository) GetRestaurant(id int) (*domain.Restaurant, error
 	return r0, r1
 }
 
-// ListRestaurants provides a mock function with no fields
-func (_m *RestaurantRepository) ListRestaurants() ([]domain.Restaurant, error) {
-	ret := _m.Called()
+// GetRestaurantWithDeleted provides a mock function with given fields: ctx, id
+func (_m *RestaurantRepository) GetRestaurantWithDeleted(ctx context.Context, id int) (*domain.Restaurant, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetRestaurantWithDeleted")
+	}
+
+	var r0 *domain.Restaurant
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (*domain.Restaurant, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) *domain.Restaurant); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Restaurant)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ImageInUse provides a mock function with given fields: ctx, imageURL
+func (_m *RestaurantRepository) ImageInUse(ctx context.Context, imageURL string) (bool, error) {
+	ret := _m.Called(ctx, imageURL)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ImageInUse")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (bool, error)); ok {
+		return rf(ctx, imageURL)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) bool); ok {
+		r0 = rf(ctx, imageURL)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, imageURL)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListDeletedRestaurants provides a mock function with given fields: ctx
+func (_m *RestaurantRepository) ListDeletedRestaurants(ctx context.Context) ([]domain.Restaurant, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListDeletedRestaurants")
+	}
+
+	var r0 []domain.Restaurant
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]domain.Restaurant, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []domain.Restaurant); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Restaurant)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListRestaurants provides a mock function with given fields: ctx, filter
+func (_m *RestaurantRepository) ListRestaurants(ctx context.Context, filter domain.RestaurantFilter) ([]domain.Restaurant, error) {
+	ret := _m.Called(ctx, filter)
 
 	if len(ret) == 0 {
 		panic("no return value specified for ListRestaurants")
@@ -99,19 +188,75 @@ func (_m *RestaurantRepository) ListRestaurants() ([]domain.Restaurant, error) {
 
 	var r0 []domain.Restaurant
 	var r1 error
-	if rf, ok := ret.Get(0).(func() ([]domain.Restaurant, error)); ok {
-		return rf()
+	if rf, ok := ret.Get(0).(func(context.Context, domain.RestaurantFilter) ([]domain.Restaurant, error)); ok {
+		return rf(ctx, filter)
 	}
-	if rf, ok := ret.Get(0).(func() []domain.Restaurant); ok {
-		r0 = rf()
+	if rf, ok := ret.Get(0).(func(context.Context, domain.RestaurantFilter) []domain.Restaurant); ok {
+		r0 = rf(ctx, filter)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]domain.Restaurant)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func() error); ok {
-		r1 = rf()
+	if rf, ok := ret.Get(1).(func(context.Context, domain.RestaurantFilter) error); ok {
+		r1 = rf(ctx, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PurgeRestaurant provides a mock function with given fields: ctx, id
+func (_m *RestaurantRepository) PurgeRestaurant(ctx context.Context, id int) (int64, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PurgeRestaurant")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (int64, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) int64); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RestoreRestaurant provides a mock function with given fields: ctx, id
+func (_m *RestaurantRepository) RestoreRestaurant(ctx context.Context, id int) (int64, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RestoreRestaurant")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (int64, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) int64); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, id)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -119,17 +264,17 @@ func (_m *RestaurantRepository) ListRestaurants() ([]domain.Restaurant, error) {
 	return r0, r1
 }
 
-// UpdateRestaurant provides a mock function with given fields: rest
-func (_m *RestaurantRepository) UpdateRestaurant(rest *domain.Restaurant) error {
-	ret := _m.Called(rest)
+// UpdateRestaurant provides a mock function with given fields: ctx, rest
+func (_m *RestaurantRepository) UpdateRestaurant(ctx context.Context, rest *domain.Restaurant) error {
+	ret := _m.Called(ctx, rest)
 
 	if len(ret) == 0 {
 		panic("no return value specified for UpdateRestaurant")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(*domain.Restaurant) error); ok {
-		r0 = rf(rest)
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Restaurant) error); ok {
+		r0 = rf(ctx, rest)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -137,17 +282,17 @@ func (_m *RestaurantRepository) UpdateRestaurant(rest *domain.Restaurant) error
 	return r0
 }
 
-// UpdateRestaurantImage provides a mock function with given fields: id, imageURL
-func (_m *RestaurantRepository) UpdateRestaurantImage(id int, imageURL string) error {
-	ret := _m.Called(id, imageURL)
+// UpdateRestaurantImage provides a mock function with given fields: ctx, id, image
+func (_m *RestaurantRepository) UpdateRestaurantImage(ctx context.Context, id int, image domain.ImageSet) error {
+	ret := _m.Called(ctx, id, image)
 
 	if len(ret) == 0 {
 		panic("no return value specified for UpdateRestaurantImage")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(int, string) error); ok {
-		r0 = rf(id, imageURL)
+	if rf, ok := ret.Get(0).(func(context.Context, int, domain.ImageSet) error); ok {
+		r0 = rf(ctx, id, image)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -3,6 +3,8 @@
 package mocks
 
 import (
+	context "context"
+	io "io"
 	domain "overcooked-simplified/dish-svc/internal/domain"
 
 	mock "github.com/stretchr/testify/mock"
@@ -13,17 +15,17 @@ type RestaurantServiceInterface struct {
 	mock.Mock
 }
 
-// Create provides a mock function with given fields: rest
-func (_m *RestaurantServiceInterface) Create(rest *domain.Restaurant) error {
-	ret := _m.Called(rest)
+// Create provides a mock function with given fields: ctx, rest
+func (_m *RestaurantServiceInterface) Create(ctx context.Context, rest *domain.Restaurant) error {
+	ret := _m.Called(ctx, rest)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Create")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(*domain.Restaurant) error); ok {
-		r0 = rf(rest)
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Restaurant) error); ok {
+		r0 = rf(ctx, rest)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -31,9 +33,9 @@ func (_m *RestaurantServiceInterface) Create(rest *domain.Restaurant) error {
 	return r0
 }
 
-// Delete provides a mock function with given fields: id
-func (_m *RestaurantServiceInterface) Delete(id int) (int64, error) {
-	ret := _m.Called(id)
+// Delete provides a mock function with given fields: ctx, id
+func (_m *RestaurantServiceInterface) Delete(ctx context.Context, id int) (int64, error) {
+	ret := _m.Called(ctx, id)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Delete")
@@ -41,17 +43,17 @@ func (_m *RestaurantServiceInterface) Delete(id int) (int64, error) {
 
 	var r0 int64
 	var r1 error
-	if rf, ok := ret.Get(0).(func(int) (int64, error)); ok {
-		return rf(id)
+	if rf, ok := ret.Get(0).(func(context.Context, int) (int64, error)); ok {
+		return rf(ctx, id)
 	}
-	if rf, ok := ret.Get(0).(func(int) int64); ok {
-		r0 = rf(id)
+	if rf, ok := ret.Get(0).(func(context.Context, int) int64); ok {
+		r0 = rf(ctx, id)
 	} else {
 		r0 = ret.Get(0).(int64)
 	}
 
-	if rf, ok := ret.Get(1).(func(int) error); ok {
-		r1 = rf(id)
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, id)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -59,9 +61,9 @@ func (_m *RestaurantServiceInterface) Delete(id int) (int64, error) {
 	return r0, r1
 }
 
-// Get provides a mock function with given fields: id
-func (_m *RestaurantServiceInterface) Get(id int) (*domain.Restaurant, error) {
-	ret := _m.Called(id)
+// Get provides a mock function with given fields: ctx, id
+func (_m *RestaurantServiceInterface) Get(ctx context.Context, id int) (*domain.Restaurant, error) {
+	ret := _m.Called(ctx, id)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Get")
@@ -69,19 +71,19 @@ func (_m *RestaurantServiceInterface) Get(id int) (*domain.Restaurant, error) {
 
 	var r0 *domain.Restaurant
 	var r1 error
-	if rf, ok := ret.Get(0).(func(int) (*domain.Restaurant, error)); ok {
-		return rf(id)
+	if rf, ok := ret.Get(0).(func(context.Context, int) (*domain.Restaurant, error)); ok {
+		return rf(ctx, id)
 	}
-	if rf, ok := ret.Get(0).(func(int) *domain.Restaurant); ok {
-		r0 = rf(id)
+	if rf, ok := ret.Get(0).(func(context.Context, int) *domain.Restaurant); ok {
+		r0 = rf(ctx, id)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*domain.Restaurant)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(int) error); ok {
-		r1 = rf(id)
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, id)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -89,29 +91,66 @@ func (_m *RestaurantServiceInterface) Get(id int) (*domain.Restaurant, error) {
 	return r0, r1
 }
 
-// List provides a mock function with no fields
-func (_m *RestaurantServiceInterface) List() ([]domain.Restaurant, error) {
-	ret := _m.Called()
+// List provides a mock function with given fields: ctx, filter
+func (_m *RestaurantServiceInterface) List(ctx context.Context, filter domain.RestaurantFilter) ([]domain.Restaurant, string, error) {
+	ret := _m.Called(ctx, filter)
 
 	if len(ret) == 0 {
 		panic("no return value specified for List")
 	}
 
+	var r0 []domain.Restaurant
+	var r1 string
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.RestaurantFilter) ([]domain.Restaurant, string, error)); ok {
+		return rf(ctx, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, domain.RestaurantFilter) []domain.Restaurant); ok {
+		r0 = rf(ctx, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Restaurant)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, domain.RestaurantFilter) string); ok {
+		r1 = rf(ctx, filter)
+	} else {
+		r1 = ret.Get(1).(string)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, domain.RestaurantFilter) error); ok {
+		r2 = rf(ctx, filter)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// ListDeleted provides a mock function with given fields: ctx
+func (_m *RestaurantServiceInterface) ListDeleted(ctx context.Context) ([]domain.Restaurant, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListDeleted")
+	}
+
 	var r0 []domain.Restaurant
 	var r1 error
-	if rf, ok := ret.Get(0).(func() ([]domain.Restaurant, error)); ok {
-		return rf()
+	if rf, ok := ret.Get(0).(func(context.Context) ([]domain.Restaurant, error)); ok {
+		return rf(ctx)
 	}
-	if rf, ok := ret.Get(0).(func() []domain.Restaurant); ok {
-		r0 = rf()
+	if rf, ok := ret.Get(0).(func(context.Context) []domain.Restaurant); ok {
+		r0 = rf(ctx)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]domain.Restaurant)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func() error); ok {
-		r1 = rf()
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -119,17 +158,17 @@ func (_m *RestaurantServiceInterface) List() ([]domain.Restaurant, error) {
 	return r0, r1
 }
 
-// Update provides a mock function with given fields: rest
-func (_m *RestaurantServiceInterface) Update(rest *domain.Restaurant) error {
-	ret := _m.Called(rest)
+// Purge provides a mock function with given fields: ctx, id, confirm
+func (_m *RestaurantServiceInterface) Purge(ctx context.Context, id int, confirm string) error {
+	ret := _m.Called(ctx, id, confirm)
 
 	if len(ret) == 0 {
-		panic("no return value specified for Update")
+		panic("no return value specified for Purge")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(*domain.Restaurant) error); ok {
-		r0 = rf(rest)
+	if rf, ok := ret.Get(0).(func(context.Context, int, string) error); ok {
+		r0 = rf(ctx, id, confirm)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -137,17 +176,45 @@ func (_m *RestaurantServiceInterface) Update(rest *domain.Restaurant) error {
 	return r0
 }
 
-// UpdateImage provides a mock function with given fields: id, imageURL
-func (_m *RestaurantServiceInterface) UpdateImage(id int, imageURL string) error {
-	ret := _m.Called(id, imageURL)
+// Restore provides a mock function with given fields: ctx, id
+func (_m *RestaurantServiceInterface) Restore(ctx context.Context, id int) (int64, error) {
+	ret := _m.Called(ctx, id)
 
 	if len(ret) == 0 {
-		panic("no return value specified for UpdateImage")
+		panic("no return value specified for Restore")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (int64, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) int64); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Update provides a mock function with given fields: ctx, rest
+func (_m *RestaurantServiceInterface) Update(ctx context.Context, rest *domain.Restaurant) error {
+	ret := _m.Called(ctx, rest)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Update")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(int, string) error); ok {
-		r0 = rf(id, imageURL)
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Restaurant) error); ok {
+		r0 = rf(ctx, rest)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -155,6 +222,36 @@ func (_m *RestaurantServiceInterface) UpdateImage(id int, imageURL string) error
 	return r0
 }
 
+// UpdateImage provides a mock function with given fields: ctx, id, upload
+func (_m *RestaurantServiceInterface) UpdateImage(ctx context.Context, id int, upload io.Reader) (*domain.ImageSet, error) {
+	ret := _m.Called(ctx, id, upload)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateImage")
+	}
+
+	var r0 *domain.ImageSet
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, io.Reader) (*domain.ImageSet, error)); ok {
+		return rf(ctx, id, upload)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, io.Reader) *domain.ImageSet); ok {
+		r0 = rf(ctx, id, upload)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.ImageSet)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, io.Reader) error); ok {
+		r1 = rf(ctx, id, upload)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // NewRestaurantServiceInterface creates a new instance of RestaurantServiceInterface. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewRestaurantServiceInterface(t interface {
@@ -0,0 +1,59 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	domain "overcooked-simplified/dish-svc/internal/domain"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// SearchServiceInterface is an autogenerated mock type for the SearchServiceInterface type
+type SearchServiceInterface struct {
+	mock.Mock
+}
+
+// Search provides a mock function with given fields: ctx, query
+func (_m *SearchServiceInterface) Search(ctx context.Context, query domain.SearchQuery) (*domain.SearchResults, error) {
+	ret := _m.Called(ctx, query)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Search")
+	}
+
+	var r0 *domain.SearchResults
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.SearchQuery) (*domain.SearchResults, error)); ok {
+		return rf(ctx, query)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, domain.SearchQuery) *domain.SearchResults); ok {
+		r0 = rf(ctx, query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.SearchResults)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, domain.SearchQuery) error); ok {
+		r1 = rf(ctx, query)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewSearchServiceInterface creates a new instance of SearchServiceInterface. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewSearchServiceInterface(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *SearchServiceInterface {
+	mock := &SearchServiceInterface{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
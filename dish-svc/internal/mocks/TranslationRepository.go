@@ -0,0 +1,105 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	domain "overcooked-simplified/dish-svc/internal/domain"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// TranslationRepository is an autogenerated mock type for the TranslationRepository type
+type TranslationRepository struct {
+	mock.Mock
+}
+
+// DeleteDishTranslation provides a mock function with given fields: ctx, dishID, lang
+func (_m *TranslationRepository) DeleteDishTranslation(ctx context.Context, dishID int, lang string) (int64, error) {
+	ret := _m.Called(ctx, dishID, lang)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteDishTranslation")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, string) (int64, error)); ok {
+		return rf(ctx, dishID, lang)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, string) int64); ok {
+		r0 = rf(ctx, dishID, lang)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, string) error); ok {
+		r1 = rf(ctx, dishID, lang)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListDishTranslations provides a mock function with given fields: ctx, dishIDs
+func (_m *TranslationRepository) ListDishTranslations(ctx context.Context, dishIDs []int) (map[int][]domain.DishTranslation, error) {
+	ret := _m.Called(ctx, dishIDs)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListDishTranslations")
+	}
+
+	var r0 map[int][]domain.DishTranslation
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []int) (map[int][]domain.DishTranslation, error)); ok {
+		return rf(ctx, dishIDs)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []int) map[int][]domain.DishTranslation); ok {
+		r0 = rf(ctx, dishIDs)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[int][]domain.DishTranslation)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []int) error); ok {
+		r1 = rf(ctx, dishIDs)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpsertDishTranslation provides a mock function with given fields: ctx, tr
+func (_m *TranslationRepository) UpsertDishTranslation(ctx context.Context, tr *domain.DishTranslation) error {
+	ret := _m.Called(ctx, tr)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpsertDishTranslation")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.DishTranslation) error); ok {
+		r0 = rf(ctx, tr)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewTranslationRepository creates a new instance of TranslationRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewTranslationRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *TranslationRepository {
+	mock := &TranslationRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
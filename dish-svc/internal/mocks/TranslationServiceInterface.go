@@ -0,0 +1,143 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	domain "overcooked-simplified/dish-svc/internal/domain"
+
+	language "golang.org/x/text/language"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// TranslationServiceInterface is an autogenerated mock type for the TranslationServiceInterface type
+type TranslationServiceInterface struct {
+	mock.Mock
+}
+
+// Delete provides a mock function with given fields: ctx, restaurantID, dishID, lang
+func (_m *TranslationServiceInterface) Delete(ctx context.Context, restaurantID int, dishID int, lang string) (int64, error) {
+	ret := _m.Called(ctx, restaurantID, dishID, lang)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Delete")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, string) (int64, error)); ok {
+		return rf(ctx, restaurantID, dishID, lang)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, string) int64); ok {
+		r0 = rf(ctx, restaurantID, dishID, lang)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int, string) error); ok {
+		r1 = rf(ctx, restaurantID, dishID, lang)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// List provides a mock function with given fields: ctx, restaurantID, dishID
+func (_m *TranslationServiceInterface) List(ctx context.Context, restaurantID int, dishID int) ([]domain.DishTranslation, error) {
+	ret := _m.Called(ctx, restaurantID, dishID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for List")
+	}
+
+	var r0 []domain.DishTranslation
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) ([]domain.DishTranslation, error)); ok {
+		return rf(ctx, restaurantID, dishID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) []domain.DishTranslation); ok {
+		r0 = rf(ctx, restaurantID, dishID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.DishTranslation)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int) error); ok {
+		r1 = rf(ctx, restaurantID, dishID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Localize provides a mock function with given fields: ctx, restaurantID, dishes, requested
+func (_m *TranslationServiceInterface) Localize(ctx context.Context, restaurantID int, dishes []domain.Dish, requested []language.Tag) error {
+	ret := _m.Called(ctx, restaurantID, dishes, requested)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Localize")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, []domain.Dish, []language.Tag) error); ok {
+		r0 = rf(ctx, restaurantID, dishes, requested)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// LocalizeMenu provides a mock function with given fields: ctx, restaurantID, sections, requested
+func (_m *TranslationServiceInterface) LocalizeMenu(ctx context.Context, restaurantID int, sections []domain.MenuSection, requested []language.Tag) error {
+	ret := _m.Called(ctx, restaurantID, sections, requested)
+
+	if len(ret) == 0 {
+		panic("no return value specified for LocalizeMenu")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, []domain.MenuSection, []language.Tag) error); ok {
+		r0 = rf(ctx, restaurantID, sections, requested)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Put provides a mock function with given fields: ctx, restaurantID, tr
+func (_m *TranslationServiceInterface) Put(ctx context.Context, restaurantID int, tr *domain.DishTranslation) error {
+	ret := _m.Called(ctx, restaurantID, tr)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Put")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, *domain.DishTranslation) error); ok {
+		r0 = rf(ctx, restaurantID, tr)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewTranslationServiceInterface creates a new instance of TranslationServiceInterface. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewTranslationServiceInterface(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *TranslationServiceInterface {
+	mock := &TranslationServiceInterface{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
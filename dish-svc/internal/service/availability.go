@@ -0,0 +1,123 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"overcooked-simplified/dish-svc/internal/domain"
+)
+
+var ErrInvalidAvailability = errors.New("invalid availability window")
+
+const clockLayout = "15:04"
+
+// DishAvailableAt reports whether the dish can be ordered at t. Stop-listed
+// dishes are never available; dishes without windows are available all day.
+func DishAvailableAt(dish domain.Dish, t time.Time) bool {
+	if dish.StopListed {
+		return false
+	}
+	if len(dish.AvailabilityWindows) == 0 {
+		return true
+	}
+	for _, window := range dish.AvailabilityWindows {
+		if windowContains(window, t) {
+			return true
+		}
+	}
+	return false
+}
+
+func windowContains(window domain.AvailabilityWindow, t time.Time) bool {
+	from, errFrom := time.Parse(clockLayout, window.From)
+	to, errTo := time.Parse(clockLayout, window.To)
+	if errFrom != nil || errTo != nil {
+		return false
+	}
+	minute := t.Hour()*60 + t.Minute()
+	start := from.Hour()*60 + from.Minute()
+	end := to.Hour()*60 + to.Minute()
+
+	day := t.Weekday()
+	if end <= start && minute < end {
+		// the part of an overnight window after midnight belongs to the previous day
+		day = (day + 6) % 7
+	}
+	if len(window.Weekdays) > 0 && !containsWeekday(window.Weekdays, day) {
+		return false
+	}
+
+	if end > start {
+		return minute >= start && minute < end
+	}
+	return minute >= start || minute < end
+}
+
+func containsWeekday(weekdays []int, day time.Weekday) bool {
+	for _, wd := range weekdays {
+		if time.Weekday(wd) == day {
+			return true
+		}
+	}
+	return false
+}
+
+func validateWindows(windows []domain.AvailabilityWindow) error {
+	for i, window := range windows {
+		if _, err := time.Parse(clockLayout, window.From); err != nil {
+			return fmt.Errorf("%w %d: bad from %q", ErrInvalidAvailability, i, window.From)
+		}
+		if _, err := time.Parse(clockLayout, window.To); err != nil {
+			return fmt.Errorf("%w %d: bad to %q", ErrInvalidAvailability, i, window.To)
+		}
+		if window.From == window.To {
+			return fmt.Errorf("%w %d: empty time range", ErrInvalidAvailability, i)
+		}
+		for _, wd := range window.Weekdays {
+			if wd < 0 || wd > 6 {
+				return fmt.Errorf("%w %d: weekday %d out of range", ErrInvalidAvailability, i, wd)
+			}
+		}
+	}
+	return nil
+}
+
+// inRestaurantZone moves filter.AvailableAt into the restaurant's time zone,
+// the one its dishes' availability windows are set in. A restaurant that is
+// gone has no dishes to filter and is left alone.
+func inRestaurantZone(ctx context.Context, repo DishRepository, restaurantID int, filter *domain.DishFilter) error {
+	if filter.AvailableAt == nil {
+		return nil
+	}
+	rest, err := repo.GetRestaurant(ctx, restaurantID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	at := filter.AvailableAt.In(RestaurantLocation(*rest))
+	filter.AvailableAt = &at
+	return nil
+}
+
+// FilterDishes applies filter to dishes, keeping their order.
+func FilterDishes(dishes []domain.Dish, filter domain.DishFilter) []domain.Dish {
+	filtered := make([]domain.Dish, 0, len(dishes))
+	for _, dish := range dishes {
+		if filter.AvailableAt != nil && !DishAvailableAt(dish, *filter.AvailableAt) {
+			continue
+		}
+		if hasAny(dish.Allergens, filter.ExcludeAllergens) {
+			continue
+		}
+		if !hasAll(dish.DietaryTags, filter.Tags) {
+			continue
+		}
+		filtered = append(filtered, dish)
+	}
+	return filtered
+}
@@ -0,0 +1,73 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"mime"
+	"path"
+)
+
+// BlobMigration summarizes a MigrateBlobs run.
+type BlobMigration struct {
+	Copied      int
+	Skipped     int
+	RowsUpdated int64
+	Deleted     int
+}
+
+// MigrateBlobs copies every blob from one store to another, points the stored
+// image URLs at the new store and, with removeSource, deletes the originals.
+// Blobs already present in the target are not copied again, so an
+// interrupted run can simply be repeated.
+func MigrateBlobs(ctx context.Context, from, to BlobStore, urls BlobURLRepository, removeSource bool) (*BlobMigration, error) {
+	keys, err := from.List("")
+	if err != nil {
+		return nil, fmt.Errorf("list source: %w", err)
+	}
+	report := &BlobMigration{}
+	for _, key := range keys {
+		exists, err := to.Exists(key)
+		if err != nil {
+			return report, fmt.Errorf("check %s: %w", key, err)
+		}
+		if exists {
+			report.Skipped++
+			continue
+		}
+		if err := copyBlob(from, to, key); err != nil {
+			return report, fmt.Errorf("copy %s: %w", key, err)
+		}
+		report.Copied++
+	}
+
+	if oldPrefix, newPrefix := from.URL(""), to.URL(""); oldPrefix != newPrefix {
+		if report.RowsUpdated, err = urls.RewriteImageURLs(ctx, oldPrefix, newPrefix); err != nil {
+			return report, fmt.Errorf("rewrite image URLs: %w", err)
+		}
+	}
+
+	if removeSource {
+		for _, key := range keys {
+			if err := from.Delete(key); err != nil {
+				return report, fmt.Errorf("delete %s: %w", key, err)
+			}
+			report.Deleted++
+		}
+	}
+	return report, nil
+}
+
+func copyBlob(from, to BlobStore, key string) error {
+	body, err := from.Get(key)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+	contentType := mime.TypeByExtension(path.Ext(key))
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	log.Printf("copying %s (%s)", key, contentType)
+	return to.Put(key, contentType, body)
+}
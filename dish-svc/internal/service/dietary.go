@@ -0,0 +1,144 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"overcooked-simplified/dish-svc/internal/domain"
+)
+
+var (
+	ErrUnknownAllergen   = errors.New("unknown allergen")
+	ErrUnknownDietaryTag = errors.New("unknown dietary tag")
+	ErrInvalidNutrition  = errors.New("invalid nutrition facts")
+)
+
+// Allergens are the 14 allergens that EU Regulation 1169/2011 requires to be
+// declared.
+var Allergens = []string{
+	"gluten", "crustaceans", "eggs", "fish", "peanuts", "soy", "milk",
+	"nuts", "celery", "mustard", "sesame", "sulphites", "lupin", "molluscs",
+}
+
+var DietaryTags = []string{
+	"vegan", "vegetarian", "halal", "kosher", "gluten-free", "lactose-free", "spicy",
+}
+
+var allergenAliases = map[string]string{
+	"cereals":         "gluten",
+	"wheat":           "gluten",
+	"shellfish":       "crustaceans",
+	"egg":             "eggs",
+	"peanut":          "peanuts",
+	"soya":            "soy",
+	"soybeans":        "soy",
+	"dairy":           "milk",
+	"lactose":         "milk",
+	"tree nuts":       "nuts",
+	"tree-nuts":       "nuts",
+	"sulfites":        "sulphites",
+	"sulphur dioxide": "sulphites",
+	"lupine":          "lupin",
+	"mollusks":        "molluscs",
+}
+
+var tagAliases = map[string]string{
+	"gluten free":  "gluten-free",
+	"gluten_free":  "gluten-free",
+	"lactose free": "lactose-free",
+	"lactose_free": "lactose-free",
+	"veggie":       "vegetarian",
+}
+
+// NormalizeAllergens lower-cases, resolves aliases, de-duplicates and sorts
+// allergen codes. Unknown codes are rejected.
+func NormalizeAllergens(values []string) ([]string, error) {
+	return normalizeCodes(values, Allergens, allergenAliases, ErrUnknownAllergen)
+}
+
+// NormalizeDietaryTags does the same as NormalizeAllergens for dietary tags.
+func NormalizeDietaryTags(values []string) ([]string, error) {
+	return normalizeCodes(values, DietaryTags, tagAliases, ErrUnknownDietaryTag)
+}
+
+func normalizeCodes(values, known []string, aliases map[string]string, errUnknown error) ([]string, error) {
+	valid := make(map[string]bool, len(known))
+	for _, code := range known {
+		valid[code] = true
+	}
+
+	seen := make(map[string]bool, len(values))
+	normalized := make([]string, 0, len(values))
+	for _, value := range values {
+		code := strings.ToLower(strings.TrimSpace(value))
+		if code == "" {
+			continue
+		}
+		if alias, ok := aliases[code]; ok {
+			code = alias
+		}
+		if !valid[code] {
+			return nil, fmt.Errorf("%w: %q", errUnknown, value)
+		}
+		if !seen[code] {
+			seen[code] = true
+			normalized = append(normalized, code)
+		}
+	}
+	sort.Strings(normalized)
+	return normalized, nil
+}
+
+func validateNutrition(facts *domain.NutritionFacts) error {
+	if facts == nil {
+		return nil
+	}
+	values := []float64{facts.Calories, facts.Protein, facts.Fat, facts.Carbohydrates, facts.ServingGrams}
+	for _, value := range values {
+		if value < 0 {
+			return fmt.Errorf("%w: values must not be negative", ErrInvalidNutrition)
+		}
+	}
+	return nil
+}
+
+// normalizeDietary validates and normalizes the dietary fields of a dish in
+// place.
+func normalizeDietary(dish *domain.Dish) error {
+	allergens, err := NormalizeAllergens(dish.Allergens)
+	if err != nil {
+		return err
+	}
+	tags, err := NormalizeDietaryTags(dish.DietaryTags)
+	if err != nil {
+		return err
+	}
+	if err := validateNutrition(dish.Nutrition); err != nil {
+		return err
+	}
+	dish.Allergens = allergens
+	dish.DietaryTags = tags
+	return nil
+}
+
+func hasAny(values, wanted []string) bool {
+	for _, w := range wanted {
+		for _, v := range values {
+			if v == w {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func hasAll(values, wanted []string) bool {
+	for _, w := range wanted {
+		if !hasAny(values, []string{w}) {
+			return false
+		}
+	}
+	return true
+}
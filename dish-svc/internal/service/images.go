@@ -0,0 +1,305 @@
+package service
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	_ "image/gif"
+
+	"github.com/HugoSmits86/nativewebp"
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+
+	"overcooked-simplified/dish-svc/internal/domain"
+)
+
+var ErrUnsupportedImage = errors.New("unsupported image")
+
+// imageTypes are the sniffed content types accepted for uploads.
+var imageTypes = map[string]bool{
+	"image/jpeg": true,
+	"image/png":  true,
+	"image/gif":  true,
+	"image/webp": true,
+}
+
+const (
+	maxImagePixels = 40_000_000
+	maxImageWidth  = 1920
+	jpegQuality    = 85
+)
+
+// imageWidths are the thumbnail widths listed in srcset next to the full
+// image. Widths not smaller than the image itself are skipped.
+var imageWidths = []int{320, 640, 1280}
+
+// hashedImageName matches files written by ImagePipeline: the content hash,
+// optionally followed by a width suffix.
+var hashedImageName = regexp.MustCompile(`^([0-9a-f]{32})(-\d+w)?\.(jpg|png|webp)$`)
+
+// ImagePipeline turns uploads into re-encoded images with thumbnails and
+// writes them to a BlobStore. Files are named by the hash of the upload, so
+// the same picture uploaded twice is stored once and client-provided names
+// never reach the store. Re-encoding drops EXIF and any other metadata.
+type ImagePipeline struct {
+	store BlobStore
+}
+
+func NewImagePipeline(store BlobStore) *ImagePipeline {
+	return &ImagePipeline{store: store}
+}
+
+type imageVariant struct {
+	img    image.Image
+	suffix string
+	width  int
+}
+
+func (p *ImagePipeline) Save(upload io.Reader) (*domain.ImageSet, error) {
+	data, err := io.ReadAll(upload)
+	if err != nil {
+		return nil, err
+	}
+	contentType := http.DetectContentType(data)
+	if !imageTypes[contentType] {
+		return nil, fmt.Errorf("%w: %s, only JPEG, PNG, GIF and WebP are allowed", ErrUnsupportedImage, contentType)
+	}
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnsupportedImage, err)
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 || cfg.Width*cfg.Height > maxImagePixels {
+		return nil, fmt.Errorf("%w: %dx%d is too large", ErrUnsupportedImage, cfg.Width, cfg.Height)
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnsupportedImage, err)
+	}
+	if contentType == "image/jpeg" {
+		// the orientation tag goes away with the rest of EXIF, so apply it first
+		img = applyOrientation(img, jpegOrientation(data))
+	}
+
+	sum := sha256.Sum256(data)
+	hash := hex.EncodeToString(sum[:16])
+	full := resizeToWidth(img, maxImageWidth)
+	ext := "jpg"
+	if !isOpaque(full) {
+		ext = "png"
+	}
+
+	variants := []imageVariant{}
+	for _, width := range imageWidths {
+		if width < full.Bounds().Dx() {
+			variants = append(variants, imageVariant{resizeToWidth(full, width), fmt.Sprintf("-%dw", width), width})
+		}
+	}
+	variants = append(variants, imageVariant{full, "", full.Bounds().Dx()})
+
+	set := &domain.ImageSet{}
+	var srcset, webpSrcset []string
+	for _, v := range variants {
+		name := hash + v.suffix + "." + ext
+		if err := p.write(name, "image/"+strings.Replace(ext, "jpg", "jpeg", 1), func(w io.Writer) error { return encodeImage(w, v.img, ext) }); err != nil {
+			return nil, err
+		}
+		webpName := hash + v.suffix + ".webp"
+		if err := p.write(webpName, "image/webp", func(w io.Writer) error { return nativewebp.Encode(w, v.img, nil) }); err != nil {
+			return nil, err
+		}
+		srcset = append(srcset, p.store.URL(name)+" "+strconv.Itoa(v.width)+"w")
+		webpSrcset = append(webpSrcset, p.store.URL(webpName)+" "+strconv.Itoa(v.width)+"w")
+		if v.suffix == "" {
+			set.URL = p.store.URL(name)
+		}
+	}
+	set.Srcset = strings.Join(srcset, ", ")
+	set.WebPSrcset = strings.Join(webpSrcset, ", ")
+	return set, nil
+}
+
+// write stores name unless an earlier upload of the same picture already did.
+func (p *ImagePipeline) write(name, contentType string, encode func(io.Writer) error) error {
+	exists, err := p.store.Exists(name)
+	if err != nil || exists {
+		return err
+	}
+	var buf bytes.Buffer
+	if err := encode(&buf); err != nil {
+		return err
+	}
+	return p.store.Put(name, contentType, &buf)
+}
+
+// Remove deletes the files behind an image URL returned by Save, including
+// all its thumbnails. URLs the store did not hand out are ignored; files
+// uploaded before the pipeline existed are removed by their name.
+func (p *ImagePipeline) Remove(imageURL string) error {
+	key, ok := p.store.KeyFromURL(imageURL)
+	if !ok {
+		return nil
+	}
+	keys := []string{key}
+	if m := hashedImageName.FindStringSubmatch(key); m != nil {
+		var err error
+		if keys, err = p.store.List(m[1]); err != nil {
+			return err
+		}
+	}
+	for _, key := range keys {
+		if err := p.store.Delete(key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func encodeImage(w io.Writer, img image.Image, ext string) error {
+	if ext == "png" {
+		return png.Encode(w, img)
+	}
+	return jpeg.Encode(w, img, &jpeg.Options{Quality: jpegQuality})
+}
+
+func isOpaque(img image.Image) bool {
+	if o, ok := img.(interface{ Opaque() bool }); ok {
+		return o.Opaque()
+	}
+	return false
+}
+
+// resizeToWidth scales img down to width, keeping the aspect ratio. Images
+// that are already narrow enough are copied as they are.
+func resizeToWidth(img image.Image, width int) image.Image {
+	b := img.Bounds()
+	if b.Dx() <= width {
+		dst := image.NewNRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+		draw.Draw(dst, dst.Bounds(), img, b.Min, draw.Src)
+		return opaqueIfPossible(dst)
+	}
+	height := b.Dy() * width / b.Dx()
+	if height < 1 {
+		height = 1
+	}
+	dst := image.NewNRGBA(image.Rect(0, 0, width, height))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), img, b, draw.Src, nil)
+	return opaqueIfPossible(dst)
+}
+
+// opaqueIfPossible returns an RGBA copy for fully opaque images so they are
+// stored as JPEG.
+func opaqueIfPossible(img *image.NRGBA) image.Image {
+	if !img.Opaque() {
+		return img
+	}
+	rgba := image.NewRGBA(img.Bounds())
+	draw.Draw(rgba, rgba.Bounds(), img, img.Bounds().Min, draw.Src)
+	return rgba
+}
+
+// jpegOrientation returns the EXIF orientation (1-8) of a JPEG, or 1 when it
+// has none.
+func jpegOrientation(data []byte) int {
+	if len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+		return 1
+	}
+	for i := 2; i+4 <= len(data); {
+		if data[i] != 0xFF {
+			return 1
+		}
+		marker := data[i+1]
+		size := int(binary.BigEndian.Uint16(data[i+2:]))
+		if marker == 0xDA || size < 2 || i+2+size > len(data) {
+			return 1
+		}
+		segment := data[i+4 : i+2+size]
+		if marker == 0xE1 && len(segment) > 14 && string(segment[:6]) == "Exif\x00\x00" {
+			return tiffOrientation(segment[6:])
+		}
+		i += 2 + size
+	}
+	return 1
+}
+
+func tiffOrientation(tiff []byte) int {
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 1
+	}
+	offset := int(order.Uint32(tiff[4:]))
+	if offset+2 > len(tiff) {
+		return 1
+	}
+	count := int(order.Uint16(tiff[offset:]))
+	for i := 0; i < count; i++ {
+		entry := offset + 2 + i*12
+		if entry+12 > len(tiff) {
+			return 1
+		}
+		if order.Uint16(tiff[entry:]) == 0x0112 {
+			if value := int(order.Uint16(tiff[entry+8:])); value >= 1 && value <= 8 {
+				return value
+			}
+			return 1
+		}
+	}
+	return 1
+}
+
+// applyOrientation rotates and flips img so it displays upright without the
+// EXIF orientation tag.
+func applyOrientation(img image.Image, orientation int) image.Image {
+	if orientation <= 1 || orientation > 8 {
+		return img
+	}
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	dw, dh := w, h
+	if orientation >= 5 {
+		dw, dh = h, w
+	}
+	dst := image.NewNRGBA(image.Rect(0, 0, dw, dh))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			var dx, dy int
+			switch orientation {
+			case 2:
+				dx, dy = w-1-x, y
+			case 3:
+				dx, dy = w-1-x, h-1-y
+			case 4:
+				dx, dy = x, h-1-y
+			case 5:
+				dx, dy = y, x
+			case 6:
+				dx, dy = h-1-y, x
+			case 7:
+				dx, dy = h-1-y, w-1-x
+			case 8:
+				dx, dy = y, w-1-x
+			}
+			dst.Set(dx, dy, color.NRGBAModel.Convert(img.At(b.Min.X+x, b.Min.Y+y)))
+		}
+	}
+	return dst
+}
+
+var _ ImageStore = (*ImagePipeline)(nil)
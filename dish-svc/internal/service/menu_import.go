@@ -0,0 +1,360 @@
+package service
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+
+	"overcooked-simplified/dish-svc/internal/domain"
+)
+
+const (
+	MenuFormatJSON = "json"
+	MenuFormatCSV  = "csv"
+)
+
+var (
+	ErrUnsupportedMenuFormat = errors.New("unsupported menu format")
+	ErrMalformedMenuImport   = errors.New("malformed menu import")
+)
+
+// menuCSVHeader lists the CSV columns in export order. Allergens and dietary
+// tags are separated by ';' inside their cell; empty nutrition cells mean the
+// dish has no nutrition facts.
+var menuCSVHeader = []string{
+	"sku", "category", "name", "description", "price", "allergens", "dietary_tags",
+	"calories", "protein", "fat", "carbohydrates", "serving_grams",
+}
+
+const maxSKULength = 64
+
+// MenuImportService imports and exports a restaurant's menu as rows keyed by
+// SKU, so an export can be edited and imported back.
+type MenuImportService struct {
+	repo       MenuImportRepository
+	dishes     DishRepository
+	categories CategoryRepository
+}
+
+func NewMenuImportService(repo MenuImportRepository, dishes DishRepository, categories CategoryRepository) *MenuImportService {
+	return &MenuImportService{repo: repo, dishes: dishes, categories: categories}
+}
+
+// Import parses and validates the whole file before writing anything. With
+// dryRun, or when any row is invalid, the report is returned without changes.
+func (s *MenuImportService) Import(ctx context.Context, restaurantID int, format string, body io.Reader, dryRun bool) (*domain.MenuImportReport, error) {
+	var rows []domain.MenuRow
+	var rowErrors []domain.MenuRowError
+	var err error
+	switch format {
+	case MenuFormatCSV:
+		rows, rowErrors, err = ParseMenuCSV(body)
+	case MenuFormatJSON:
+		if err = json.NewDecoder(body).Decode(&rows); err != nil {
+			err = fmt.Errorf("%w: %v", ErrMalformedMenuImport, err)
+		}
+	default:
+		err = fmt.Errorf("%w %q", ErrUnsupportedMenuFormat, format)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	report := &domain.MenuImportReport{DryRun: dryRun, Rows: len(rows)}
+	report.Errors = append([]domain.MenuRowError{}, rowErrors...)
+	report.Errors = append(report.Errors, validateMenuRows(rows, rowErrors)...)
+	if len(report.Errors) > 0 {
+		return report, nil
+	}
+	if !dryRun {
+		applied, err := s.repo.ImportMenu(ctx, restaurantID, rows)
+		if err != nil {
+			return nil, err
+		}
+		return applied, nil
+	}
+
+	dishes, err := s.dishes.ListDishes(ctx, restaurantID)
+	if err != nil {
+		return nil, err
+	}
+	categories, err := s.categories.ListCategories(ctx, restaurantID)
+	if err != nil {
+		return nil, err
+	}
+	skus := make(map[string]bool, len(dishes))
+	for _, dish := range dishes {
+		skus[dish.SKU] = true
+	}
+	categoryNames := make(map[string]bool, len(categories))
+	for _, cat := range categories {
+		categoryNames[strings.ToLower(cat.Name)] = true
+	}
+	for _, row := range rows {
+		if skus[row.SKU] {
+			report.Updated++
+		} else {
+			report.Created++
+		}
+		if key := strings.ToLower(row.Category); key != "" && !categoryNames[key] {
+			categoryNames[key] = true
+			report.CategoriesCreated++
+		}
+	}
+	return report, nil
+}
+
+// validateMenuRows normalizes rows in place and returns one error per bad
+// field. Rows that already failed to parse are skipped.
+func validateMenuRows(rows []domain.MenuRow, parseErrors []domain.MenuRowError) []domain.MenuRowError {
+	skip := make(map[int]bool, len(parseErrors))
+	for _, e := range parseErrors {
+		skip[e.Row] = true
+	}
+
+	errs := []domain.MenuRowError{}
+	firstRow := make(map[string]int, len(rows))
+	for i := range rows {
+		n := i + 1
+		if skip[n] {
+			continue
+		}
+		row := &rows[i]
+		row.SKU = strings.TrimSpace(row.SKU)
+		row.Name = strings.TrimSpace(row.Name)
+		row.Category = strings.TrimSpace(row.Category)
+		fail := func(field, message string) {
+			errs = append(errs, domain.MenuRowError{Row: n, SKU: row.SKU, Field: field, Message: message})
+		}
+
+		switch {
+		case row.SKU == "":
+			fail("sku", "sku is required")
+		case len(row.SKU) > maxSKULength:
+			fail("sku", fmt.Sprintf("sku is longer than %d characters", maxSKULength))
+		case firstRow[row.SKU] != 0:
+			fail("sku", fmt.Sprintf("duplicate sku, first used in row %d", firstRow[row.SKU]))
+		default:
+			firstRow[row.SKU] = n
+		}
+		if row.Name == "" {
+			fail("name", "name is required")
+		}
+		if row.Price < 0 || math.IsNaN(row.Price) || math.IsInf(row.Price, 0) {
+			fail("price", "price must be a non-negative number")
+		}
+		if allergens, err := NormalizeAllergens(row.Allergens); err != nil {
+			fail("allergens", err.Error())
+		} else {
+			row.Allergens = allergens
+		}
+		if tags, err := NormalizeDietaryTags(row.DietaryTags); err != nil {
+			fail("dietary_tags", err.Error())
+		} else {
+			row.DietaryTags = tags
+		}
+		if err := validateNutrition(row.Nutrition); err != nil {
+			fail("nutrition", err.Error())
+		}
+	}
+	return errs
+}
+
+// Export returns the menu in category order, dishes without a category last.
+func (s *MenuImportService) Export(ctx context.Context, restaurantID int) ([]domain.MenuRow, error) {
+	categories, err := s.categories.ListCategories(ctx, restaurantID)
+	if err != nil {
+		return nil, err
+	}
+	dishes, err := s.dishes.ListDishes(ctx, restaurantID)
+	if err != nil {
+		return nil, err
+	}
+
+	rows := make([]domain.MenuRow, 0, len(dishes))
+	for _, section := range groupAllDishes(categories, dishes) {
+		for _, dish := range section.Dishes {
+			row := domain.MenuRow{
+				SKU:         dish.SKU,
+				Name:        dish.Name,
+				Description: dish.Description,
+				Price:       dish.Price,
+				Allergens:   dish.Allergens,
+				DietaryTags: dish.DietaryTags,
+				Nutrition:   dish.Nutrition,
+			}
+			if section.Category != nil {
+				row.Category = section.Category.Name
+			}
+			rows = append(rows, row)
+		}
+	}
+	return rows, nil
+}
+
+// groupAllDishes is groupMenu without hiding anything: hidden categories keep
+// their dishes and empty categories are dropped.
+func groupAllDishes(categories []domain.MenuCategory, dishes []domain.Dish) []domain.MenuSection {
+	visible := make([]domain.MenuCategory, len(categories))
+	for i, cat := range categories {
+		cat.Visible = true
+		visible[i] = cat
+	}
+	sections := groupMenu(visible, dishes)
+	nonEmpty := sections[:0]
+	for _, section := range sections {
+		if len(section.Dishes) > 0 {
+			nonEmpty = append(nonEmpty, section)
+		}
+	}
+	return nonEmpty
+}
+
+// ParseMenuCSV reads rows under a header line. Columns may come in any order;
+// sku, name and price are required and unknown columns are ignored. Cells
+// that cannot be parsed are reported as row errors, the rest of the file is
+// still read.
+func ParseMenuCSV(r io.Reader) ([]domain.MenuRow, []domain.MenuRowError, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: read header: %v", ErrMalformedMenuImport, err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		columns[name] = i
+	}
+	for _, required := range []string{"sku", "name", "price"} {
+		if _, ok := columns[required]; !ok {
+			return nil, nil, fmt.Errorf("%w: missing column %q", ErrMalformedMenuImport, required)
+		}
+	}
+
+	rows := []domain.MenuRow{}
+	rowErrors := []domain.MenuRowError{}
+	for n := 1; ; n++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			var parseErr *csv.ParseError
+			if !errors.As(err, &parseErr) {
+				return nil, nil, err
+			}
+			rows = append(rows, domain.MenuRow{})
+			rowErrors = append(rowErrors, domain.MenuRowError{Row: n, Message: parseErr.Err.Error()})
+			continue
+		}
+		if len(record) != len(header) {
+			rows = append(rows, domain.MenuRow{})
+			rowErrors = append(rowErrors, domain.MenuRowError{Row: n,
+				Message: fmt.Sprintf("expected %d columns, got %d", len(header), len(record))})
+			continue
+		}
+
+		cell := func(name string) string {
+			if i, ok := columns[name]; ok {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+		row := domain.MenuRow{
+			SKU:         cell("sku"),
+			Category:    cell("category"),
+			Name:        cell("name"),
+			Description: cell("description"),
+			Allergens:   splitCell(cell("allergens")),
+			DietaryTags: splitCell(cell("dietary_tags")),
+		}
+		fail := func(field, message string) {
+			rowErrors = append(rowErrors, domain.MenuRowError{Row: n, SKU: row.SKU, Field: field, Message: message})
+		}
+
+		if row.Price, err = strconv.ParseFloat(cell("price"), 64); err != nil {
+			fail("price", fmt.Sprintf("price %q is not a number", cell("price")))
+		}
+		nutrition := domain.NutritionFacts{}
+		hasNutrition := false
+		for _, f := range []struct {
+			column string
+			value  *float64
+		}{
+			{"calories", &nutrition.Calories},
+			{"protein", &nutrition.Protein},
+			{"fat", &nutrition.Fat},
+			{"carbohydrates", &nutrition.Carbohydrates},
+			{"serving_grams", &nutrition.ServingGrams},
+		} {
+			raw := cell(f.column)
+			if raw == "" {
+				continue
+			}
+			hasNutrition = true
+			if *f.value, err = strconv.ParseFloat(raw, 64); err != nil {
+				fail(f.column, fmt.Sprintf("%s %q is not a number", f.column, raw))
+			}
+		}
+		if hasNutrition {
+			row.Nutrition = &nutrition
+		}
+		rows = append(rows, row)
+	}
+	return rows, rowErrors, nil
+}
+
+func splitCell(value string) []string {
+	if value == "" {
+		return nil
+	}
+	var items []string
+	for _, item := range strings.FieldsFunc(value, func(r rune) bool { return r == ';' || r == ',' }) {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// WriteMenuCSV writes rows in the format ParseMenuCSV reads.
+func WriteMenuCSV(w io.Writer, rows []domain.MenuRow) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(menuCSVHeader); err != nil {
+		return err
+	}
+	for _, row := range rows {
+		record := []string{
+			row.SKU, row.Category, row.Name, row.Description, formatNumber(row.Price),
+			strings.Join(row.Allergens, ";"), strings.Join(row.DietaryTags, ";"),
+			"", "", "", "", "",
+		}
+		if n := row.Nutrition; n != nil {
+			record[7] = formatNumber(n.Calories)
+			record[8] = formatNumber(n.Protein)
+			record[9] = formatNumber(n.Fat)
+			record[10] = formatNumber(n.Carbohydrates)
+			record[11] = formatNumber(n.ServingGrams)
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+func formatNumber(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
+
+var _ MenuImportServiceInterface = (*MenuImportService)(nil)
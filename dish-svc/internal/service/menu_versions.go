@@ -0,0 +1,243 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"overcooked-simplified/dish-svc/internal/domain"
+)
+
+var (
+	ErrEmptyDraft         = errors.New("menu draft has no changes")
+	ErrInvalidDraftChange = errors.New("invalid menu draft change")
+)
+
+// MenuVersionService stages menu edits in a draft and publishes them as
+// numbered menu versions. Guests keep seeing the published menu until the
+// draft is published.
+type MenuVersionService struct {
+	repo       MenuVersionRepository
+	dishes     DishRepository
+	categories CategoryRepository
+}
+
+func NewMenuVersionService(repo MenuVersionRepository, dishes DishRepository, categories CategoryRepository) *MenuVersionService {
+	return &MenuVersionService{repo: repo, dishes: dishes, categories: categories}
+}
+
+// StageDish stages a new dish (ID 0) or the new state of an existing one.
+func (s *MenuVersionService) StageDish(ctx context.Context, dish *domain.Dish) (*domain.MenuDraftChange, error) {
+	if dish.Name == "" || dish.Price < 0 {
+		return nil, fmt.Errorf("%w: dish needs a name and a non-negative price", ErrInvalidDraftChange)
+	}
+	if dish.ID != 0 {
+		if _, err := s.dishes.GetDish(ctx, dish.RestaurantID, dish.ID); err != nil {
+			return nil, err
+		}
+	}
+	if err := validateWindows(dish.AvailabilityWindows); err != nil {
+		return nil, err
+	}
+	if err := normalizeDietary(dish); err != nil {
+		return nil, err
+	}
+	return s.stage(ctx, &domain.MenuDraftChange{
+		RestaurantID: dish.RestaurantID,
+		Entity:       domain.DraftEntityDish,
+		Action:       domain.DraftActionUpsert,
+		EntityID:     dish.ID,
+		Dish:         dish,
+	})
+}
+
+func (s *MenuVersionService) StageDishDeletion(ctx context.Context, restaurantID, dishID int) (*domain.MenuDraftChange, error) {
+	if _, err := s.dishes.GetDish(ctx, restaurantID, dishID); err != nil {
+		return nil, err
+	}
+	return s.stage(ctx, &domain.MenuDraftChange{
+		RestaurantID: restaurantID,
+		Entity:       domain.DraftEntityDish,
+		Action:       domain.DraftActionDelete,
+		EntityID:     dishID,
+	})
+}
+
+// StageCategory stages a new category (ID 0) or the new state of an existing one.
+func (s *MenuVersionService) StageCategory(ctx context.Context, cat *domain.MenuCategory) (*domain.MenuDraftChange, error) {
+	if cat.Name == "" {
+		return nil, fmt.Errorf("%w: category name is required", ErrInvalidDraftChange)
+	}
+	if cat.ID != 0 {
+		if err := s.categoryExists(ctx, cat.RestaurantID, cat.ID); err != nil {
+			return nil, err
+		}
+	}
+	return s.stage(ctx, &domain.MenuDraftChange{
+		RestaurantID: cat.RestaurantID,
+		Entity:       domain.DraftEntityCategory,
+		Action:       domain.DraftActionUpsert,
+		EntityID:     cat.ID,
+		Category:     cat,
+	})
+}
+
+func (s *MenuVersionService) StageCategoryDeletion(ctx context.Context, restaurantID, categoryID int) (*domain.MenuDraftChange, error) {
+	if err := s.categoryExists(ctx, restaurantID, categoryID); err != nil {
+		return nil, err
+	}
+	return s.stage(ctx, &domain.MenuDraftChange{
+		RestaurantID: restaurantID,
+		Entity:       domain.DraftEntityCategory,
+		Action:       domain.DraftActionDelete,
+		EntityID:     categoryID,
+	})
+}
+
+func (s *MenuVersionService) stage(ctx context.Context, change *domain.MenuDraftChange) (*domain.MenuDraftChange, error) {
+	if err := s.repo.StageMenuChange(ctx, change); err != nil {
+		return nil, err
+	}
+	return change, nil
+}
+
+func (s *MenuVersionService) categoryExists(ctx context.Context, restaurantID, categoryID int) error {
+	categories, err := s.categories.ListCategories(ctx, restaurantID)
+	if err != nil {
+		return err
+	}
+	for _, cat := range categories {
+		if cat.ID == categoryID {
+			return nil
+		}
+	}
+	return sql.ErrNoRows
+}
+
+func (s *MenuVersionService) Draft(ctx context.Context, restaurantID int) ([]domain.MenuDraftChange, error) {
+	return s.repo.ListMenuDraft(ctx, restaurantID)
+}
+
+func (s *MenuVersionService) DropChange(ctx context.Context, restaurantID, changeID int) (int64, error) {
+	return s.repo.DeleteMenuDraftChange(ctx, restaurantID, changeID)
+}
+
+func (s *MenuVersionService) Discard(ctx context.Context, restaurantID int) (int64, error) {
+	return s.repo.DiscardMenuDraft(ctx, restaurantID)
+}
+
+// Preview returns the grouped menu guests would see once the draft is
+// published.
+func (s *MenuVersionService) Preview(ctx context.Context, restaurantID int) ([]domain.MenuSection, error) {
+	categories, err := s.categories.ListCategories(ctx, restaurantID)
+	if err != nil {
+		return nil, err
+	}
+	dishes, err := s.dishes.ListDishes(ctx, restaurantID)
+	if err != nil {
+		return nil, err
+	}
+	changes, err := s.repo.ListMenuDraft(ctx, restaurantID)
+	if err != nil {
+		return nil, err
+	}
+	categories, dishes = ApplyDraft(categories, dishes, changes)
+	return groupMenu(categories, dishes), nil
+}
+
+// ApplyDraft applies staged changes to copies of the live categories and
+// dishes the same way publishing does. New categories get the negated change
+// ID so they can be told apart from stored ones.
+func ApplyDraft(categories []domain.MenuCategory, dishes []domain.Dish, changes []domain.MenuDraftChange) ([]domain.MenuCategory, []domain.Dish) {
+	categories = append([]domain.MenuCategory(nil), categories...)
+	dishes = append([]domain.Dish(nil), dishes...)
+
+	for _, change := range changes {
+		switch change.Entity {
+		case domain.DraftEntityCategory:
+			idx := -1
+			for i := range categories {
+				if categories[i].ID == change.EntityID {
+					idx = i
+					break
+				}
+			}
+			switch {
+			case change.Action == domain.DraftActionDelete && idx >= 0:
+				categories = append(categories[:idx], categories[idx+1:]...)
+				for i := range dishes {
+					if dishes[i].CategoryID != nil && *dishes[i].CategoryID == change.EntityID {
+						dishes[i].CategoryID = nil
+					}
+				}
+			case change.Category != nil && idx >= 0:
+				// publishing only updates name and visibility
+				categories[idx].Name = change.Category.Name
+				categories[idx].Visible = change.Category.Visible
+			case change.Category != nil && change.EntityID == 0:
+				cat := *change.Category
+				cat.ID = -change.ID
+				cat.RestaurantID = change.RestaurantID
+				categories = append(categories, cat)
+			}
+		case domain.DraftEntityDish:
+			idx := -1
+			for i := range dishes {
+				if dishes[i].ID == change.EntityID {
+					idx = i
+					break
+				}
+			}
+			switch {
+			case change.Action == domain.DraftActionDelete && idx >= 0:
+				dishes = append(dishes[:idx], dishes[idx+1:]...)
+			case change.Dish != nil && idx >= 0:
+				// stop-list, availability and image are managed on the live dish
+				dish := *change.Dish
+				dish.ID = dishes[idx].ID
+				dish.RestaurantID = dishes[idx].RestaurantID
+				dish.StopListed = dishes[idx].StopListed
+				dish.AvailabilityWindows = dishes[idx].AvailabilityWindows
+				dish.ImageURL = dishes[idx].ImageURL
+				dish.ImageSrcset = dishes[idx].ImageSrcset
+				dish.ImageWebP = dishes[idx].ImageWebP
+				dish.SortOrder = dishes[idx].SortOrder
+				dish.CreatedAt = dishes[idx].CreatedAt
+				dishes[idx] = dish
+			case change.Dish != nil && change.EntityID == 0:
+				dish := *change.Dish
+				dish.RestaurantID = change.RestaurantID
+				dishes = append(dishes, dish)
+			}
+		}
+	}
+	return categories, dishes
+}
+
+func (s *MenuVersionService) Publish(ctx context.Context, restaurantID int) (*domain.MenuVersion, error) {
+	changes, err := s.repo.ListMenuDraft(ctx, restaurantID)
+	if err != nil {
+		return nil, err
+	}
+	if len(changes) == 0 {
+		return nil, ErrEmptyDraft
+	}
+	return s.repo.PublishMenuDraft(ctx, restaurantID)
+}
+
+func (s *MenuVersionService) Versions(ctx context.Context, restaurantID int) ([]domain.MenuVersion, error) {
+	return s.repo.ListMenuVersions(ctx, restaurantID)
+}
+
+func (s *MenuVersionService) Version(ctx context.Context, restaurantID, version int) (*domain.MenuVersion, error) {
+	return s.repo.GetMenuVersion(ctx, restaurantID, version)
+}
+
+// Rollback republishes an earlier version as the next version number, so
+// orders placed before the rollback keep pointing at the version they saw.
+func (s *MenuVersionService) Rollback(ctx context.Context, restaurantID, version int) (*domain.MenuVersion, error) {
+	return s.repo.RollbackMenu(ctx, restaurantID, version)
+}
+
+var _ MenuVersionServiceInterface = (*MenuVersionService)(nil)
@@ -0,0 +1,141 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"overcooked-simplified/dish-svc/internal/domain"
+)
+
+var ErrInvalidModifierGroup = errors.New("invalid modifier group")
+
+type ModifierService struct {
+	repo   ModifierRepository
+	dishes DishRepository
+}
+
+func NewModifierService(repo ModifierRepository, dishes DishRepository) *ModifierService {
+	return &ModifierService{repo: repo, dishes: dishes}
+}
+
+func (s *ModifierService) List(ctx context.Context, restaurantID, dishID int) ([]domain.ModifierGroup, error) {
+	if _, err := s.dishes.GetDish(ctx, restaurantID, dishID); err != nil {
+		return nil, err
+	}
+	groups, err := s.repo.ListModifierGroups(ctx, []int{dishID})
+	if err != nil {
+		return nil, err
+	}
+	if groups[dishID] == nil {
+		return []domain.ModifierGroup{}, nil
+	}
+	return groups[dishID], nil
+}
+
+func (s *ModifierService) Create(ctx context.Context, restaurantID int, group *domain.ModifierGroup) error {
+	if err := validateModifierGroup(group); err != nil {
+		return err
+	}
+	if _, err := s.dishes.GetDish(ctx, restaurantID, group.DishID); err != nil {
+		return err
+	}
+	return s.repo.CreateModifierGroup(ctx, group)
+}
+
+func (s *ModifierService) Update(ctx context.Context, restaurantID int, group *domain.ModifierGroup) error {
+	if err := validateModifierGroup(group); err != nil {
+		return err
+	}
+	if _, err := s.dishes.GetDish(ctx, restaurantID, group.DishID); err != nil {
+		return err
+	}
+	return s.repo.UpdateModifierGroup(ctx, group)
+}
+
+func (s *ModifierService) Delete(ctx context.Context, restaurantID, dishID, groupID int) (int64, error) {
+	if _, err := s.dishes.GetDish(ctx, restaurantID, dishID); err != nil {
+		return 0, err
+	}
+	return s.repo.DeleteModifierGroup(ctx, dishID, groupID)
+}
+
+var _ ModifierServiceInterface = (*ModifierService)(nil)
+
+func validateModifierGroup(group *domain.ModifierGroup) error {
+	switch {
+	case group.Name == "":
+		return fmt.Errorf("%w: name is required", ErrInvalidModifierGroup)
+	case len(group.Options) == 0:
+		return fmt.Errorf("%w: at least one option is required", ErrInvalidModifierGroup)
+	case group.MinSelect < 0 || group.MaxSelect < 1 || group.MinSelect > group.MaxSelect:
+		return fmt.Errorf("%w: need 0 <= min_select <= max_select and max_select >= 1", ErrInvalidModifierGroup)
+	case group.MinSelect > len(group.Options):
+		return fmt.Errorf("%w: min_select exceeds the number of options", ErrInvalidModifierGroup)
+	}
+	ids := make(map[int]bool, len(group.Options))
+	for _, option := range group.Options {
+		if option.Name == "" {
+			return fmt.Errorf("%w: option name is required", ErrInvalidModifierGroup)
+		}
+		if option.ID != 0 && ids[option.ID] {
+			return fmt.Errorf("%w: option %d is listed twice", ErrInvalidModifierGroup, option.ID)
+		}
+		ids[option.ID] = true
+	}
+	return nil
+}
+
+// selectOptions checks the options chosen for an order item against the
+// dish's modifier groups and returns them priced, ordered by group. The
+// string result is a validation message for the order item, empty if valid.
+func selectOptions(groups []domain.ModifierGroup, selected []domain.OrderItemOption) ([]domain.OrderItemOption, string) {
+	type groupOption struct {
+		group  int
+		option domain.ModifierOption
+	}
+	available := make(map[int]groupOption)
+	for gi, group := range groups {
+		for _, option := range group.Options {
+			available[option.ID] = groupOption{group: gi, option: option}
+		}
+	}
+
+	counts := make([]int, len(groups))
+	seen := make(map[int]bool, len(selected))
+	chosen := make([]domain.OrderItemOption, 0, len(selected))
+	chosenGroup := make(map[int]int, len(selected))
+	for _, sel := range selected {
+		match, ok := available[sel.OptionID]
+		if !ok {
+			return nil, fmt.Sprintf("option %d is not offered for this dish", sel.OptionID)
+		}
+		if seen[sel.OptionID] {
+			return nil, fmt.Sprintf("option %d selected more than once", sel.OptionID)
+		}
+		seen[sel.OptionID] = true
+		counts[match.group]++
+		chosenGroup[sel.OptionID] = match.group
+		chosen = append(chosen, domain.OrderItemOption{
+			OptionID:   match.option.ID,
+			GroupName:  groups[match.group].Name,
+			Name:       match.option.Name,
+			PriceDelta: match.option.PriceDelta,
+		})
+	}
+
+	for gi, group := range groups {
+		if counts[gi] < group.MinSelect {
+			return nil, fmt.Sprintf("%q requires at least %d option(s)", group.Name, group.MinSelect)
+		}
+		if counts[gi] > group.MaxSelect {
+			return nil, fmt.Sprintf("%q allows at most %d option(s)", group.Name, group.MaxSelect)
+		}
+	}
+
+	sort.SliceStable(chosen, func(i, j int) bool {
+		return chosenGroup[chosen[i].OptionID] < chosenGroup[chosen[j].OptionID]
+	})
+	return chosen, ""
+}
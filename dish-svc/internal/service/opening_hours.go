@@ -0,0 +1,172 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"overcooked-simplified/dish-svc/internal/domain"
+
+	"golang.org/x/text/language"
+)
+
+var (
+	ErrInvalidTimeZone     = errors.New("invalid time zone")
+	ErrInvalidLocale       = errors.New("invalid locale")
+	ErrInvalidOpeningHours = errors.New("invalid opening hours")
+)
+
+const (
+	// DefaultTimeZone is what restaurants created before time zones existed
+	// got, matching the servers that computed their days until then.
+	DefaultTimeZone = "UTC"
+	DefaultLocale   = "ru-RU"
+
+	dateLayout = "2006-01-02"
+)
+
+// RestaurantLocation loads the restaurant's time zone, falling back to UTC for
+// an empty or unknown one.
+func RestaurantLocation(rest domain.Restaurant) *time.Location {
+	if rest.TimeZone == "" {
+		return time.UTC
+	}
+	loc, err := time.LoadLocation(rest.TimeZone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// RestaurantOpenAt reports whether the restaurant is open at t, taken in its
+// own time zone. A restaurant without opening hours is always open.
+func RestaurantOpenAt(rest domain.Restaurant, t time.Time) bool {
+	hours := rest.OpeningHours
+	if hours == nil {
+		return true
+	}
+	t = t.In(RestaurantLocation(rest))
+	minute := t.Hour()*60 + t.Minute()
+
+	today, limited := dayWindows(hours, t)
+	if !limited {
+		return true
+	}
+	for _, window := range today {
+		start, end, ok := windowMinutes(window)
+		if !ok {
+			continue
+		}
+		if end > start && minute >= start && minute < end {
+			return true
+		}
+		if end <= start && minute >= start {
+			return true
+		}
+	}
+
+	// the part of yesterday's overnight windows after midnight
+	yesterday, limited := dayWindows(hours, t.AddDate(0, 0, -1))
+	if !limited {
+		return false
+	}
+	for _, window := range yesterday {
+		start, end, ok := windowMinutes(window)
+		if ok && end <= start && minute < end {
+			return true
+		}
+	}
+	return false
+}
+
+// dayWindows returns the windows that start on the day of t: its exception if
+// there is one, otherwise the weekly windows for its weekday. limited is false
+// when the day is not restricted at all.
+func dayWindows(hours *domain.OpeningHours, t time.Time) (windows []domain.AvailabilityWindow, limited bool) {
+	date := t.Format(dateLayout)
+	for _, exception := range hours.Exceptions {
+		if exception.Date == date {
+			return exception.Windows, true
+		}
+	}
+	if len(hours.Weekly) == 0 {
+		return nil, false
+	}
+	for _, window := range hours.Weekly {
+		if len(window.Weekdays) == 0 || containsWeekday(window.Weekdays, t.Weekday()) {
+			windows = append(windows, window)
+		}
+	}
+	return windows, true
+}
+
+func windowMinutes(window domain.AvailabilityWindow) (start, end int, ok bool) {
+	from, errFrom := time.Parse(clockLayout, window.From)
+	to, errTo := time.Parse(clockLayout, window.To)
+	if errFrom != nil || errTo != nil {
+		return 0, 0, false
+	}
+	return from.Hour()*60 + from.Minute(), to.Hour()*60 + to.Minute(), true
+}
+
+// normalizeRestaurantLocale checks the time zone, locale and opening hours
+// and fills in the defaults for empty ones.
+func normalizeRestaurantLocale(rest *domain.Restaurant) error {
+	rest.TimeZone = strings.TrimSpace(rest.TimeZone)
+	if rest.TimeZone == "" {
+		rest.TimeZone = DefaultTimeZone
+	}
+	// "Local" would make the zone depend on the server again
+	if rest.TimeZone == "Local" {
+		return fmt.Errorf("%w: %q", ErrInvalidTimeZone, rest.TimeZone)
+	}
+	if _, err := time.LoadLocation(rest.TimeZone); err != nil {
+		return fmt.Errorf("%w: %q", ErrInvalidTimeZone, rest.TimeZone)
+	}
+
+	rest.Locale = strings.TrimSpace(rest.Locale)
+	if rest.Locale == "" {
+		rest.Locale = DefaultLocale
+	}
+	tag, err := language.Parse(rest.Locale)
+	if err != nil {
+		return fmt.Errorf("%w: %q", ErrInvalidLocale, rest.Locale)
+	}
+	rest.Locale = tag.String()
+
+	return validateOpeningHours(rest.OpeningHours)
+}
+
+func validateOpeningHours(hours *domain.OpeningHours) error {
+	if hours == nil {
+		return nil
+	}
+	if err := validateWindows(hours.Weekly); err != nil {
+		return fmt.Errorf("%w: weekly: %v", ErrInvalidOpeningHours, err)
+	}
+	seen := make(map[string]bool, len(hours.Exceptions))
+	for _, exception := range hours.Exceptions {
+		if _, err := time.Parse(dateLayout, exception.Date); err != nil {
+			return fmt.Errorf("%w: bad date %q", ErrInvalidOpeningHours, exception.Date)
+		}
+		if seen[exception.Date] {
+			return fmt.Errorf("%w: %s listed twice", ErrInvalidOpeningHours, exception.Date)
+		}
+		seen[exception.Date] = true
+		if err := validateWindows(exception.Windows); err != nil {
+			return fmt.Errorf("%w: %s: %v", ErrInvalidOpeningHours, exception.Date, err)
+		}
+	}
+	return nil
+}
+
+// setOpenNow fills in OpenNow for restaurants that have opening hours.
+func setOpenNow(rest *domain.Restaurant, now time.Time) {
+	rest.OpenNow = nil
+	if rest.OpeningHours == nil {
+		return
+	}
+	open := RestaurantOpenAt(*rest, now)
+	rest.OpenNow = &open
+}
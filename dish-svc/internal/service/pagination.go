@@ -0,0 +1,142 @@
+package service
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"overcooked-simplified/dish-svc/internal/domain"
+)
+
+var ErrInvalidPage = errors.New("invalid page request")
+
+const (
+	defaultRestaurantPage = 100
+	maxRestaurantPage     = 500
+	defaultOrderPage      = 50
+	maxOrderPage          = 200
+
+	// newest first, as the listings were before they had pages
+	defaultListSort = "-created_at"
+)
+
+// restaurantSorts and orderSorts are the columns each listing sorts by,
+// with how the cursor value is read off the last row of a page.
+var restaurantSorts = map[string]func(domain.Restaurant) string{
+	"created_at": func(rest domain.Restaurant) string { return rest.CreatedAt.Format(time.RFC3339Nano) },
+	"name":       func(rest domain.Restaurant) string { return rest.Name },
+}
+
+var orderSorts = map[string]func(domain.Order) string{
+	"created_at":   func(order domain.Order) string { return order.CreatedAt.Format(time.RFC3339Nano) },
+	"total_amount": func(order domain.Order) string { return strconv.FormatFloat(order.TotalAmount, 'f', -1, 64) },
+}
+
+// cursorToken is what the opaque cursor carries. The sort is kept so a
+// cursor cannot be replayed against a different order.
+type cursorToken struct {
+	Sort string `json:"s"`
+	domain.PageCursor
+}
+
+// EncodeCursor makes the token clients pass back as ?cursor= for the next
+// page.
+func EncodeCursor(sort string, cursor domain.PageCursor) string {
+	data, _ := json.Marshal(cursorToken{Sort: sort, PageCursor: cursor})
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeCursor reads a token made by EncodeCursor for the same sort. An
+// empty token is the first page.
+func DecodeCursor(sort, token string) (*domain.PageCursor, error) {
+	if token == "" {
+		return nil, nil
+	}
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, fmt.Errorf("%w: bad cursor", ErrInvalidPage)
+	}
+	var decoded cursorToken
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return nil, fmt.Errorf("%w: bad cursor", ErrInvalidPage)
+	}
+	if decoded.Sort != sort {
+		return nil, fmt.Errorf("%w: cursor was made for sort %q", ErrInvalidPage, decoded.Sort)
+	}
+	// the value goes to Postgres as text, so make sure the cast will work
+	switch strings.TrimPrefix(sort, "-") {
+	case "created_at":
+		_, err = time.Parse(time.RFC3339Nano, decoded.Value)
+	case "total_amount":
+		_, err = strconv.ParseFloat(decoded.Value, 64)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: bad cursor", ErrInvalidPage)
+	}
+	return &decoded.PageCursor, nil
+}
+
+// pageLimit applies the listing's default to a zero limit and caps it.
+func pageLimit(limit, defaultLimit, maxLimit int) (int, error) {
+	switch {
+	case limit < 0:
+		return 0, fmt.Errorf("%w: limit must not be negative", ErrInvalidPage)
+	case limit == 0:
+		return defaultLimit, nil
+	case limit > maxLimit:
+		return maxLimit, nil
+	}
+	return limit, nil
+}
+
+// checkSort defaults an empty sort and reports whether sorts knows the
+// column.
+func checkSort(sort *string, known func(string) bool) error {
+	if *sort == "" {
+		*sort = defaultListSort
+	}
+	if !known(strings.TrimPrefix(*sort, "-")) {
+		return fmt.Errorf("%w: unknown sort %q", ErrInvalidPage, *sort)
+	}
+	return nil
+}
+
+func normalizeRestaurantFilter(filter *domain.RestaurantFilter) error {
+	if err := checkSort(&filter.Sort, func(column string) bool { return restaurantSorts[column] != nil }); err != nil {
+		return err
+	}
+	limit, err := pageLimit(filter.Limit, defaultRestaurantPage, maxRestaurantPage)
+	if err != nil {
+		return err
+	}
+	filter.Limit = limit
+	filter.Name = strings.TrimSpace(filter.Name)
+	filter.After, err = DecodeCursor(filter.Sort, filter.Cursor)
+	return err
+}
+
+func normalizeOrderFilter(filter *domain.OrderFilter) error {
+	if err := checkSort(&filter.Sort, func(column string) bool { return orderSorts[column] != nil }); err != nil {
+		return err
+	}
+	limit, err := pageLimit(filter.Limit, defaultOrderPage, maxOrderPage)
+	if err != nil {
+		return err
+	}
+	filter.Limit = limit
+	switch {
+	case filter.RestaurantID < 0:
+		return fmt.Errorf("%w: bad restaurant_id", ErrInvalidPage)
+	case filter.MinTotal != nil && *filter.MinTotal < 0:
+		return fmt.Errorf("%w: min_total must not be negative", ErrInvalidPage)
+	case filter.From != nil && filter.To != nil && !filter.From.Before(*filter.To):
+		return fmt.Errorf("%w: from must be before to", ErrInvalidPage)
+	}
+	filter.Status = strings.TrimSpace(filter.Status)
+	filter.After, err = DecodeCursor(filter.Sort, filter.Cursor)
+	return err
+}
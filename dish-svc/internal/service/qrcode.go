@@ -1,20 +1,82 @@
 package service
 
 import (
+	"errors"
 	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
 
 	"github.com/skip2/go-qrcode"
+
+	"overcooked-simplified/dish-svc/internal/domain"
 )
 
+var ErrInvalidBaseURL = errors.New("invalid public base URL")
+
 type QRGenerator interface {
-	Generate(orderID int) ([]byte, error)
+	Generate(content string) ([]byte, error)
+}
+
+// DefaultQRGenerator encodes QR codes as 256px PNG images.
+type DefaultQRGenerator struct{}
+
+func (DefaultQRGenerator) Generate(content string) ([]byte, error) {
+	return qrcode.Encode(content, qrcode.Medium, 256)
 }
 
-type DefaultQRGenerator struct {
+// ReviewLinks builds the links put into review QR codes. They are short,
+// BaseURL/r/<code>, so the QR code stays coarse enough for small receipts,
+// and redirect to the review page. A restaurant's own PublicBaseURL, such as
+// a custom domain, takes precedence over BaseURL.
+type ReviewLinks struct {
 	BaseURL string
 }
 
-func (g DefaultQRGenerator) Generate(orderID int) ([]byte, error) {
-	qrData := fmt.Sprintf("%s/review.html?check_id=%d", g.BaseURL, orderID)
-	return qrcode.Encode(qrData, qrcode.Medium, 256)
+func (l ReviewLinks) URL(rest *domain.Restaurant, orderID int) string {
+	return l.base(rest) + "/r/" + ShortCode(orderID)
+}
+
+// TableURL is the link in a table's static QR code.
+func (l ReviewLinks) TableURL(rest *domain.Restaurant, code string) string {
+	return l.base(rest) + "/t/" + code
+}
+
+func (l ReviewLinks) base(rest *domain.Restaurant) string {
+	if rest != nil && rest.PublicBaseURL != "" {
+		return rest.PublicBaseURL
+	}
+	return l.BaseURL
+}
+
+// ShortCode is the order ID in base 36.
+func ShortCode(orderID int) string {
+	return strconv.FormatInt(int64(orderID), 36)
+}
+
+func ParseShortCode(code string) (int, bool) {
+	id, err := strconv.ParseInt(strings.ToLower(code), 36, 32)
+	return int(id), err == nil && id > 0
+}
+
+// ReviewPath is where a short link redirects to, relative to the host the
+// link was opened on.
+func ReviewPath(orderID int) string {
+	return fmt.Sprintf("/review.html?check_id=%d", orderID)
+}
+
+// MenuPath is where a table link redirects to: the menu of the table's
+// restaurant, with the table remembered for the visit rating.
+func MenuPath(tableCode string) string {
+	return "/index.html?table=" + url.QueryEscape(tableCode)
+}
+
+// NormalizeBaseURL checks that raw is an absolute http(s) URL without query
+// or fragment and strips the trailing slash.
+func NormalizeBaseURL(raw string) (string, error) {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" || u.RawQuery != "" || u.Fragment != "" || u.User != nil {
+		return "", fmt.Errorf("%w: %q, expected http(s)://host[/path]", ErrInvalidBaseURL, raw)
+	}
+	return strings.TrimSuffix(u.String(), "/"), nil
 }
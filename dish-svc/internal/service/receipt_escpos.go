@@ -0,0 +1,180 @@
+package service
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding/charmap"
+)
+
+// ESC/POS commands used for receipts, as in the Epson command reference.
+var (
+	escposInit        = []byte{0x1b, '@'}
+	escposCodePage866 = []byte{0x1b, 't', 17}
+	escposFeedAndCut  = []byte{0x1b, 'd', 3, 0x1d, 'V', 66, 0}
+)
+
+const (
+	escposLeft   = 0
+	escposCenter = 1
+
+	escposNormalSize   = 0x00
+	escposDoubleHeight = 0x01
+	escposDoubleSize   = 0x11
+
+	// thermal heads print 8 dots per mm, CSS pixels are 96 per inch
+	escposDotsPerPx = 203.0 / 96
+)
+
+// escposPaper is the number of Font A columns and printable dots per line.
+var escposPaper = map[int]struct{ cols, dots int }{
+	58: {32, 384},
+	80: {48, 576},
+}
+
+var escposQRLevels = map[string]byte{"L": '0', "M": '1', "Q": '2', "H": '3'}
+
+// escposReplacements stands in for characters code page 866 does not have.
+var escposReplacements = strings.NewReplacer("×", "x", "—", "-", "–", "-", "«", `"`, "»", `"`, "“", `"`, "”", `"`)
+
+type escposWriter struct {
+	bytes.Buffer
+	cols int
+}
+
+func (p *escposWriter) cmd(b ...byte) {
+	p.Write(b)
+}
+
+func (p *escposWriter) style(align, size byte, bold bool) {
+	var emphasis byte
+	if bold {
+		emphasis = 1
+	}
+	p.cmd(0x1b, 'a', align, 0x1d, '!', size, 0x1b, 'E', emphasis)
+}
+
+// println writes s in code page 866; characters it cannot show become "?".
+func (p *escposWriter) println(s string) {
+	for _, r := range escposReplacements.Replace(plainText(s)) {
+		if b, ok := charmap.CodePage866.EncodeRune(r); ok {
+			p.WriteByte(b)
+		} else {
+			p.WriteByte('?')
+		}
+	}
+	p.WriteByte('\n')
+}
+
+// row prints left wrapped to the width the right column leaves free, with
+// right aligned to the edge on the first line.
+func (p *escposWriter) row(left, right string) {
+	right = plainText(right)
+	width := p.cols
+	if right != "" {
+		width -= utf8.RuneCountInString(right) + 1
+	}
+	for i, part := range wrapText(plainText(left), width) {
+		if i == 0 && right != "" {
+			part += strings.Repeat(" ", max(p.cols-utf8.RuneCountInString(part)-utf8.RuneCountInString(right), 1)) + right
+		}
+		p.println(part)
+	}
+}
+
+// nativeQR prints data with the printer's QR code command (GS ( k, model 2).
+func (p *escposWriter) nativeQR(data, level string, module int) {
+	p.cmd(0x1d, '(', 'k', 4, 0, '1', 'A', '2', 0)
+	p.cmd(0x1d, '(', 'k', 3, 0, '1', 'C', byte(module))
+	p.cmd(0x1d, '(', 'k', 3, 0, '1', 'E', escposQRLevels[level])
+	size := len(data) + 3
+	p.cmd(0x1d, '(', 'k', byte(size), byte(size>>8), '1', 'P', '0')
+	p.WriteString(data)
+	p.cmd(0x1d, '(', 'k', 3, 0, '1', 'Q', '0')
+	p.WriteByte('\n')
+}
+
+// rasterQR prints the QR code bitmap as a raster image (GS v 0), for printers
+// without the QR command.
+func (p *escposWriter) rasterQR(qr [][]bool, module int) {
+	dots := len(qr) * module
+	rowBytes := (dots + 7) / 8
+	p.cmd(0x1d, 'v', '0', 0, byte(rowBytes), byte(rowBytes>>8), byte(dots), byte(dots>>8))
+	line := make([]byte, rowBytes)
+	for _, row := range qr {
+		clear(line)
+		for x, dark := range row {
+			if !dark {
+				continue
+			}
+			for dx := 0; dx < module; dx++ {
+				dot := x*module + dx
+				line[dot/8] |= 0x80 >> (dot % 8)
+			}
+		}
+		for i := 0; i < module; i++ {
+			p.Write(line)
+		}
+	}
+	p.WriteByte('\n')
+}
+
+// renderReceiptESCPOS produces the byte stream for a thermal printer: text in
+// code page 866 for Cyrillic, and the QR code as the printer's QR command or
+// as a raster image. Colors and the logo do not apply to thermal paper.
+func renderReceiptESCPOS(w io.Writer, r *receipt) (string, error) {
+	paper := escposPaper[r.settings.PaperWidth]
+	if paper.cols == 0 {
+		paper = escposPaper[80]
+	}
+	p := &escposWriter{cols: paper.cols}
+	p.cmd(escposInit...)
+	p.cmd(escposCodePage866...)
+
+	for _, line := range r.lines {
+		switch line.Style {
+		case lineTitle:
+			p.style(escposCenter, escposDoubleSize, true)
+			for _, part := range wrapText(line.Left, paper.cols/2) {
+				p.println(part)
+			}
+		case lineRule:
+			p.style(escposLeft, escposNormalSize, false)
+			p.println(strings.Repeat("-", paper.cols))
+		case lineTotal:
+			p.style(escposLeft, escposDoubleHeight, true)
+			p.row(line.Left, line.Right)
+		default:
+			p.style(escposLeft, escposNormalSize, false)
+			p.row(line.Left, line.Right)
+		}
+	}
+
+	p.style(escposCenter, escposNormalSize, false)
+	p.WriteByte('\n')
+	modules := len(r.qr)
+	module := int(float64(r.settings.QRSize) * escposDotsPerPx / float64(modules))
+	module = max(min(module, 16, paper.dots/modules), 1)
+	if r.settings.PrinterQR == PrinterQRRaster {
+		p.rasterQR(r.qr, module)
+	} else {
+		p.nativeQR(r.reviewURL, r.settings.QRLevel, module)
+	}
+	for _, part := range wrapText(qrCaption, paper.cols) {
+		p.println(part)
+	}
+	if r.settings.FooterText != "" {
+		p.WriteByte('\n')
+		for _, paragraph := range strings.Split(r.settings.FooterText, "\n") {
+			for _, part := range wrapText(paragraph, paper.cols) {
+				p.println(part)
+			}
+		}
+	}
+	p.cmd(escposFeedAndCut...)
+
+	_, err := w.Write(p.Bytes())
+	return "application/octet-stream", err
+}
@@ -0,0 +1,482 @@
+package service
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/xml"
+	"fmt"
+	"html/template"
+	"image"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/go-pdf/fpdf"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/gofont/goregular"
+
+	"overcooked-simplified/dish-svc/internal/domain"
+)
+
+// receipt is everything a renderer needs: the text lines, the QR code as a
+// module bitmap including its quiet zone, and the optional logo.
+type receipt struct {
+	settings  *domain.ReceiptSettings
+	lines     []receiptLine
+	reviewURL string
+	qr        [][]bool
+	logo      image.Image
+	logoPNG   []byte
+}
+
+type lineStyle int
+
+const (
+	lineTitle lineStyle = iota
+	lineText
+	lineMuted
+	lineTotal
+	lineRule
+)
+
+// receiptLine is one row of the receipt, Right being an amount aligned to
+// the right edge.
+type receiptLine struct {
+	Style lineStyle
+	Left  string
+	Right string
+}
+
+const (
+	qrCaption     = "Отсканируйте, чтобы оставить отзыв"
+	textColor     = "#111111"
+	mutedColor    = "#6b7280"
+	logoShare     = 0.22
+	receiptWidth  = 320 // CSS pixels, about 80 mm paper
+	receiptMargin = 24
+	pointsPerPx   = 0.75
+)
+
+var receiptRenderers = map[string]func(io.Writer, *receipt) (string, error){
+	ReceiptHTML:   renderReceiptHTML,
+	ReceiptSVG:    renderReceiptSVG,
+	ReceiptPDF:    renderReceiptPDF,
+	ReceiptESCPOS: renderReceiptESCPOS,
+}
+
+// Class is the CSS class of the line in the HTML receipt.
+func (l receiptLine) Class() string {
+	return [...]string{"title", "row", "row muted", "row total", "rule"}[l.Style]
+}
+
+func receiptLines(order *domain.Order) []receiptLine {
+	lines := []receiptLine{
+		{Style: lineTitle, Left: order.RestaurantName},
+		{Style: lineMuted, Left: fmt.Sprintf("Чек № %d", order.ID), Right: order.CreatedAt.Format("02.01.2006 15:04")},
+	}
+	if order.TableLabel != "" {
+		lines = append(lines, receiptLine{Style: lineMuted, Left: "Стол " + order.TableLabel})
+	}
+	lines = append(lines, receiptLine{Style: lineRule})
+	for _, item := range order.Items {
+		lines = append(lines,
+			receiptLine{Style: lineText, Left: item.DishName, Right: formatMoney(item.Price * float64(item.Quantity))},
+			receiptLine{Style: lineMuted, Left: fmt.Sprintf("%d × %s", item.Quantity, formatMoney(item.Price))})
+		for _, option := range item.Options {
+			name := option.Name
+			if option.GroupName != "" {
+				name = option.GroupName + ": " + option.Name
+			}
+			lines = append(lines, receiptLine{Style: lineMuted, Left: "+ " + name})
+		}
+	}
+	return append(lines,
+		receiptLine{Style: lineRule},
+		receiptLine{Style: lineTotal, Left: "Итого", Right: formatMoney(order.TotalAmount)})
+}
+
+func formatMoney(amount float64) string {
+	return strconv.FormatFloat(amount, 'f', 2, 64) + " ₽"
+}
+
+// logoFrame places the logo in the middle of a QR code of the given side: a
+// plate in the background color with a margin of one module, and the logo
+// scaled into the plate keeping its aspect ratio. Both are x, y, w, h from
+// the QR code's corner.
+func (r *receipt) logoFrame(side float64) (plate, logo [4]float64) {
+	module := side / float64(len(r.qr))
+	box := side * logoShare
+	b := r.logo.Bounds()
+	w, h := box, box*float64(b.Dy())/float64(b.Dx())
+	if h > box {
+		w, h = box*float64(b.Dx())/float64(b.Dy()), box
+	}
+	plateSide := box + 2*module
+	plate = [4]float64{(side - plateSide) / 2, (side - plateSide) / 2, plateSide, plateSide}
+	logo = [4]float64{(side - w) / 2, (side - h) / 2, w, h}
+	return plate, logo
+}
+
+// qrRuns calls fn for every horizontal run of dark modules.
+func (r *receipt) qrRuns(fn func(row, col, length int)) {
+	for y, row := range r.qr {
+		for x := 0; x < len(row); {
+			if !row[x] {
+				x++
+				continue
+			}
+			start := x
+			for x < len(row) && row[x] {
+				x++
+			}
+			fn(y, start, x-start)
+		}
+	}
+}
+
+// qrSVG draws the QR code as an SVG element of the configured size.
+func (r *receipt) qrSVG(x, y float64) string {
+	side := float64(r.settings.QRSize)
+	var path strings.Builder
+	r.qrRuns(func(row, col, length int) {
+		fmt.Fprintf(&path, "M%d %dh%dv1h-%dz", col, row, length, length)
+	})
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg x="%s" y="%s" width="%s" height="%s" viewBox="0 0 %d %d" shape-rendering="crispEdges">`,
+		num(x), num(y), num(side), num(side), len(r.qr), len(r.qr))
+	fmt.Fprintf(&b, `<rect width="100%%" height="100%%" fill="%s"/><path d="%s" fill="%s"/>`,
+		r.settings.BackgroundColor, path.String(), r.settings.QRColor)
+	b.WriteString(`</svg>`)
+	if r.logo != nil {
+		plate, logo := r.logoFrame(side)
+		fmt.Fprintf(&b, `<rect x="%s" y="%s" width="%s" height="%s" fill="%s"/>`,
+			num(x+plate[0]), num(y+plate[1]), num(plate[2]), num(plate[3]), r.settings.BackgroundColor)
+		fmt.Fprintf(&b, `<image x="%s" y="%s" width="%s" height="%s" href="data:image/png;base64,%s"/>`,
+			num(x+logo[0]), num(y+logo[1]), num(logo[2]), num(logo[3]), base64.StdEncoding.EncodeToString(r.logoPNG))
+	}
+	return b.String()
+}
+
+func num(v float64) string {
+	return strconv.FormatFloat(math.Round(v*100)/100, 'f', -1, 64)
+}
+
+var receiptHTML = template.Must(template.New("receipt").Parse(`<!DOCTYPE html>
+<html lang="ru">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+@page { size: 80mm auto; margin: 4mm; }
+body { margin: 0; background: {{.Background}}; color: {{.Text}}; font: 13px/1.45 "DejaVu Sans", Arial, sans-serif; }
+.receipt { max-width: {{.Width}}px; margin: 0 auto; padding: 16px; box-sizing: border-box; }
+.row { display: flex; justify-content: space-between; gap: 12px; }
+.row span:last-child { white-space: nowrap; }
+.title { font-size: 18px; font-weight: bold; color: {{.Accent}}; text-align: center; }
+.muted { font-size: 12px; color: {{.Muted}}; }
+.total { font-size: 15px; font-weight: bold; color: {{.Accent}}; }
+hr { border: 0; border-top: 1px dashed {{.Accent}}; margin: 8px 0; }
+.qr { margin: 16px 0 0; text-align: center; }
+.qr svg { display: block; margin: 0 auto 6px; }
+.footer { margin-top: 12px; text-align: center; font-size: 12px; color: {{.Muted}}; white-space: pre-line; }
+</style>
+</head>
+<body>
+<main class="receipt">
+{{range .Lines}}{{if eq .Class "rule"}}<hr>
+{{else if eq .Class "title"}}<div class="title">{{.Left}}</div>
+{{else}}<div class="{{.Class}}"><span>{{.Left}}</span><span>{{.Right}}</span></div>
+{{end}}{{end}}<figure class="qr">{{.QR}}<figcaption class="muted"><a href="{{.ReviewURL}}">{{.Caption}}</a></figcaption></figure>
+{{with .Footer}}<p class="footer">{{.}}</p>
+{{end}}</main>
+</body>
+</html>
+`))
+
+func renderReceiptHTML(w io.Writer, r *receipt) (string, error) {
+	side := r.settings.QRSize
+	qr := fmt.Sprintf(`<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d">%s</svg>`,
+		side, side, side, side, r.qrSVG(0, 0))
+	err := receiptHTML.Execute(w, map[string]interface{}{
+		"Title":      r.lines[0].Left,
+		"Lines":      r.lines,
+		"Width":      max(receiptWidth, side+2*receiptMargin),
+		"Background": template.CSS(r.settings.BackgroundColor),
+		"Accent":     template.CSS(r.settings.AccentColor),
+		"Text":       template.CSS(textColor),
+		"Muted":      template.CSS(mutedColor),
+		"QR":         template.HTML(qr),
+		"ReviewURL":  r.reviewURL,
+		"Caption":    qrCaption,
+		"Footer":     r.settings.FooterText,
+	})
+	return "text/html; charset=utf-8", err
+}
+
+// svgFonts are the font size and line height of each line style.
+var svgFonts = map[lineStyle][2]float64{
+	lineTitle: {18, 28},
+	lineText:  {13, 20},
+	lineMuted: {12, 18},
+	lineTotal: {15, 24},
+	lineRule:  {0, 14},
+}
+
+// renderReceiptSVG lays the receipt out as one SVG image. SVG has no text
+// wrapping, so long names are wrapped by an estimate of the glyph width.
+func renderReceiptSVG(w io.Writer, r *receipt) (string, error) {
+	side := float64(r.settings.QRSize)
+	width := math.Max(receiptWidth, side+2*receiptMargin)
+	inner := width - 2*receiptMargin
+
+	var body strings.Builder
+	y := float64(receiptMargin)
+	text := func(x, size float64, anchor, color, weight, s string) {
+		fmt.Fprintf(&body, `<text x="%s" y="%s" font-size="%s" fill="%s"`, num(x), num(y), num(size), color)
+		if anchor != "" {
+			fmt.Fprintf(&body, ` text-anchor="%s"`, anchor)
+		}
+		if weight != "" {
+			fmt.Fprintf(&body, ` font-weight="%s"`, weight)
+		}
+		body.WriteString(">" + escapeXML(s) + "</text>")
+	}
+	for _, line := range r.lines {
+		font := svgFonts[line.Style]
+		if line.Style == lineRule {
+			fmt.Fprintf(&body, `<line x1="%s" y1="%s" x2="%s" y2="%s" stroke="%s" stroke-dasharray="4 3"/>`,
+				num(receiptMargin), num(y+font[1]/2), num(width-receiptMargin), num(y+font[1]/2), r.settings.AccentColor)
+			y += font[1]
+			continue
+		}
+		color, weight := textColor, ""
+		switch line.Style {
+		case lineTitle, lineTotal:
+			color, weight = r.settings.AccentColor, "bold"
+		case lineMuted:
+			color = mutedColor
+		}
+		chars := int(inner / (font[0] * 0.58))
+		if line.Right != "" {
+			chars -= utf8.RuneCountInString(line.Right) + 2
+		}
+		for i, part := range wrapText(line.Left, chars) {
+			y += font[1]
+			if line.Style == lineTitle {
+				text(width/2, font[0], "middle", color, weight, part)
+			} else {
+				text(receiptMargin, font[0], "", color, weight, part)
+			}
+			if i == 0 && line.Right != "" {
+				text(width-receiptMargin, font[0], "end", color, weight, line.Right)
+			}
+		}
+	}
+
+	y += 16
+	body.WriteString(r.qrSVG((width-side)/2, y))
+	y += side + 4
+	muted := svgFonts[lineMuted]
+	y += muted[1]
+	text(width/2, muted[0], "middle", mutedColor, "", qrCaption)
+	if r.settings.FooterText != "" {
+		y += 8
+		for _, paragraph := range strings.Split(r.settings.FooterText, "\n") {
+			for _, part := range wrapText(paragraph, int(inner/(muted[0]*0.58))) {
+				y += muted[1]
+				text(width/2, muted[0], "middle", mutedColor, "", part)
+			}
+		}
+	}
+	height := y + receiptMargin
+
+	fmt.Fprintf(w, `<svg xmlns="http://www.w3.org/2000/svg" width="%s" height="%s" viewBox="0 0 %s %s" font-family="DejaVu Sans, Arial, sans-serif">`,
+		num(width), num(height), num(width), num(height))
+	fmt.Fprintf(w, `<rect width="100%%" height="100%%" fill="%s"/>`, r.settings.BackgroundColor)
+	io.WriteString(w, body.String())
+	_, err := io.WriteString(w, "</svg>\n")
+	return "image/svg+xml", err
+}
+
+func escapeXML(s string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
+
+// wrapText breaks s into lines of at most width characters at spaces. Words
+// longer than width are split.
+func wrapText(s string, width int) []string {
+	width = max(width, 8)
+	var lines []string
+	line := ""
+	for _, word := range strings.Fields(s) {
+		for utf8.RuneCountInString(word) > width {
+			if line != "" {
+				lines = append(lines, line)
+				line = ""
+			}
+			runes := []rune(word)
+			lines = append(lines, string(runes[:width]))
+			word = string(runes[width:])
+		}
+		switch {
+		case line == "":
+			line = word
+		case utf8.RuneCountInString(line)+1+utf8.RuneCountInString(word) <= width:
+			line += " " + word
+		default:
+			lines = append(lines, line)
+			line = word
+		}
+	}
+	if line != "" || len(lines) == 0 {
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// pdfFonts are the font size and line height in points of each line style.
+var pdfFonts = map[lineStyle][2]float64{
+	lineTitle: {13.5, 21},
+	lineText:  {9.75, 15},
+	lineMuted: {9, 13.5},
+	lineTotal: {11.25, 18},
+	lineRule:  {0, 10.5},
+}
+
+// renderReceiptPDF produces a single page as long as the receipt, for roll
+// printers. Text uses the embedded Go fonts, which cover Cyrillic but have no
+// ruble sign.
+func renderReceiptPDF(w io.Writer, r *receipt) (string, error) {
+	side := float64(r.settings.QRSize) * pointsPerPx
+	margin := receiptMargin * pointsPerPx
+	width := math.Max(receiptWidth*pointsPerPx, side+2*margin)
+	inner := width - 2*margin
+
+	pdf := fpdf.NewCustom(&fpdf.InitType{UnitStr: "pt", Size: fpdf.SizeType{Wd: width, Ht: width}})
+	pdf.SetAutoPageBreak(false, 0)
+	pdf.SetTitle(r.lines[0].Left, true)
+	pdf.AddUTF8FontFromBytes("go", "", goregular.TTF)
+	pdf.AddUTF8FontFromBytes("go", "B", gobold.TTF)
+	if r.logo != nil {
+		pdf.RegisterImageOptionsReader("logo", fpdf.ImageOptions{ImageType: "PNG"}, bytes.NewReader(r.logoPNG))
+	}
+
+	// the first pass only measures, the page height is known afterwards
+	layout := func(draw bool) float64 {
+		y := margin
+		text := func(x float64, align string, s string) {
+			if !draw {
+				return
+			}
+			switch align {
+			case "C":
+				x -= pdf.GetStringWidth(s) / 2
+			case "R":
+				x -= pdf.GetStringWidth(s)
+			}
+			pdf.Text(x, y, s)
+		}
+		setFont := func(style lineStyle) {
+			fontStyle, color := "", textColor
+			switch style {
+			case lineTitle, lineTotal:
+				fontStyle, color = "B", r.settings.AccentColor
+			case lineMuted:
+				color = mutedColor
+			}
+			pdf.SetFont("go", fontStyle, pdfFonts[style][0])
+			pdf.SetTextColor(pdfColor(color))
+		}
+
+		for _, line := range r.lines {
+			font := pdfFonts[line.Style]
+			if line.Style == lineRule {
+				if draw {
+					pdf.SetDrawColor(pdfColor(r.settings.AccentColor))
+					pdf.SetLineWidth(0.75)
+					pdf.SetDashPattern([]float64{3, 2.25}, 0)
+					pdf.Line(margin, y+font[1]/2, width-margin, y+font[1]/2)
+				}
+				y += font[1]
+				continue
+			}
+			setFont(line.Style)
+			right := plainText(line.Right)
+			available := inner
+			if right != "" {
+				available -= pdf.GetStringWidth(right) + 9
+			}
+			for i, part := range pdf.SplitText(plainText(line.Left), available) {
+				y += font[1]
+				if line.Style == lineTitle {
+					text(width/2, "C", part)
+				} else {
+					text(margin, "L", part)
+				}
+				if i == 0 && right != "" {
+					text(width-margin, "R", right)
+				}
+			}
+		}
+
+		y += 12
+		if draw {
+			module := side / float64(len(r.qr))
+			x0 := (width - side) / 2
+			pdf.SetFillColor(pdfColor(r.settings.BackgroundColor))
+			pdf.Rect(x0, y, side, side, "F")
+			pdf.SetFillColor(pdfColor(r.settings.QRColor))
+			r.qrRuns(func(row, col, length int) {
+				pdf.Rect(x0+float64(col)*module, y+float64(row)*module, float64(length)*module, module, "F")
+			})
+			if r.logo != nil {
+				plate, logo := r.logoFrame(side)
+				pdf.SetFillColor(pdfColor(r.settings.BackgroundColor))
+				pdf.Rect(x0+plate[0], y+plate[1], plate[2], plate[3], "F")
+				pdf.ImageOptions("logo", x0+logo[0], y+logo[1], logo[2], logo[3], false, fpdf.ImageOptions{ImageType: "PNG"}, 0, r.reviewURL)
+			}
+			pdf.LinkString(x0, y, side, side, r.reviewURL)
+		}
+		y += side + 3
+		setFont(lineMuted)
+		muted := pdfFonts[lineMuted]
+		y += muted[1]
+		text(width/2, "C", qrCaption)
+		if r.settings.FooterText != "" {
+			y += 6
+			for _, paragraph := range strings.Split(r.settings.FooterText, "\n") {
+				for _, part := range pdf.SplitText(plainText(paragraph), inner) {
+					y += muted[1]
+					text(width/2, "C", part)
+				}
+			}
+		}
+		return y + margin
+	}
+
+	pdf.SetFont("go", "", pdfFonts[lineText][0])
+	height := layout(false)
+	pdf.AddPageFormat("P", fpdf.SizeType{Wd: width, Ht: height})
+	pdf.SetFillColor(pdfColor(r.settings.BackgroundColor))
+	pdf.Rect(0, 0, width, height, "F")
+	layout(true)
+	if err := pdf.Output(w); err != nil {
+		return "", err
+	}
+	return "application/pdf", nil
+}
+
+func pdfColor(color string) (int, int, int) {
+	r, g, b := parseHexColor(color)
+	return int(r), int(g), int(b)
+}
+
+// plainText spells out the ruble sign for fonts and code pages that do not
+// have it.
+func plainText(s string) string {
+	return strings.ReplaceAll(s, "₽", "руб.")
+}
@@ -0,0 +1,315 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"image"
+	"image/png"
+	"io"
+	"log"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/skip2/go-qrcode"
+
+	"overcooked-simplified/dish-svc/internal/domain"
+)
+
+var (
+	ErrInvalidReceiptSettings   = errors.New("invalid receipt settings")
+	ErrUnsupportedReceiptFormat = errors.New("unsupported receipt format")
+)
+
+// Receipt formats accepted by ReceiptService.Render.
+const (
+	ReceiptHTML   = "html"
+	ReceiptSVG    = "svg"
+	ReceiptPDF    = "pdf"
+	ReceiptESCPOS = "escpos"
+)
+
+// How ESC/POS receipts print the QR code: with the printer's own QR command,
+// or as a bitmap for printers that lack it.
+const (
+	PrinterQRNative = "native"
+	PrinterQRRaster = "raster"
+)
+
+const (
+	minQRSize        = 96
+	maxQRSize        = 512
+	maxFooterLength  = 200
+	maxLogoWidth     = 256
+	minColorContrast = 3
+)
+
+var qrLevels = map[string]qrcode.RecoveryLevel{
+	"L": qrcode.Low,
+	"M": qrcode.Medium,
+	"Q": qrcode.High,
+	"H": qrcode.Highest,
+}
+
+var hexColor = regexp.MustCompile(`^#[0-9a-f]{6}$`)
+
+// DefaultReceiptSettings is used until a restaurant saves its own.
+func DefaultReceiptSettings(restaurantID int) *domain.ReceiptSettings {
+	return &domain.ReceiptSettings{
+		RestaurantID:    restaurantID,
+		QRSize:          160,
+		QRLevel:         "M",
+		QRColor:         "#000000",
+		BackgroundColor: "#ffffff",
+		AccentColor:     "#111827",
+		PaperWidth:      80,
+		PrinterQR:       PrinterQRNative,
+	}
+}
+
+// ReceiptService renders printable receipts with the review QR code and keeps
+// the per-restaurant receipt settings. The logo is an uploaded image, read
+// back from files when a receipt is rendered.
+type ReceiptService struct {
+	repo        ReceiptRepository
+	orders      OrderRepository
+	restaurants RestaurantRepository
+	images      ImageStore
+	files       BlobStore
+	links       ReviewLinks
+}
+
+func NewReceiptService(repo ReceiptRepository, orders OrderRepository, restaurants RestaurantRepository, images ImageStore, files BlobStore, links ReviewLinks) *ReceiptService {
+	return &ReceiptService{repo: repo, orders: orders, restaurants: restaurants, images: images, files: files, links: links}
+}
+
+func (s *ReceiptService) Settings(ctx context.Context, restaurantID int) (*domain.ReceiptSettings, error) {
+	if _, err := s.restaurants.GetRestaurant(ctx, restaurantID); err != nil {
+		return nil, err
+	}
+	return s.settings(ctx, restaurantID)
+}
+
+func (s *ReceiptService) settings(ctx context.Context, restaurantID int) (*domain.ReceiptSettings, error) {
+	settings, err := s.repo.GetReceiptSettings(ctx, restaurantID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return DefaultReceiptSettings(restaurantID), nil
+	}
+	return settings, err
+}
+
+// UpdateSettings replaces the settings; empty fields take the defaults. The
+// logo can only be an uploaded file, set with UpdateLogo or taken from an
+// image of the restaurant or its dishes.
+func (s *ReceiptService) UpdateSettings(ctx context.Context, settings *domain.ReceiptSettings) error {
+	if _, err := s.restaurants.GetRestaurant(ctx, settings.RestaurantID); err != nil {
+		return err
+	}
+	current, err := s.settings(ctx, settings.RestaurantID)
+	if err != nil {
+		return err
+	}
+	normalizeReceiptSettings(settings)
+	if err := validateReceiptSettings(settings); err != nil {
+		return err
+	}
+	if settings.LogoURL != "" {
+		if s.files == nil {
+			return fmt.Errorf("%w: file storage is not configured", ErrInvalidReceiptSettings)
+		}
+		if _, ok := s.files.KeyFromURL(settings.LogoURL); !ok {
+			return fmt.Errorf("%w: logo_url must be an uploaded image", ErrInvalidReceiptSettings)
+		}
+	}
+	if err := s.repo.SaveReceiptSettings(ctx, settings); err != nil {
+		return err
+	}
+	if s.images != nil {
+		removeReplacedImage(ctx, s.images, s.restaurants.ImageInUse, current.LogoURL, settings.LogoURL)
+	}
+	return nil
+}
+
+// UpdateLogo stores an uploaded logo and puts it on the receipts. A logo
+// covers part of the QR code, so levels L and M are raised to H.
+func (s *ReceiptService) UpdateLogo(ctx context.Context, restaurantID int, upload io.Reader) (*domain.ReceiptSettings, error) {
+	if _, err := s.restaurants.GetRestaurant(ctx, restaurantID); err != nil {
+		return nil, err
+	}
+	current, err := s.settings(ctx, restaurantID)
+	if err != nil {
+		return nil, err
+	}
+	set, err := s.images.Save(upload)
+	if err != nil {
+		return nil, err
+	}
+	settings := *current
+	settings.LogoURL = set.URL
+	if settings.QRLevel == "L" || settings.QRLevel == "M" {
+		settings.QRLevel = "H"
+	}
+	if err := s.repo.SaveReceiptSettings(ctx, &settings); err != nil {
+		return nil, err
+	}
+	removeReplacedImage(ctx, s.images, s.restaurants.ImageInUse, current.LogoURL, settings.LogoURL)
+	return &settings, nil
+}
+
+// Render returns the receipt of an order in format together with its
+// content type.
+func (s *ReceiptService) Render(ctx context.Context, orderID int, format string) ([]byte, string, error) {
+	render, ok := receiptRenderers[format]
+	if !ok {
+		return nil, "", fmt.Errorf("%w: %q, expected pdf, svg, html or escpos", ErrUnsupportedReceiptFormat, format)
+	}
+	order, items, err := s.orders.GetOrder(ctx, orderID)
+	if err != nil {
+		return nil, "", err
+	}
+	order.Items = items
+	settings, err := s.settings(ctx, order.RestaurantID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	rest, err := s.orders.GetRestaurantWithDeleted(ctx, order.RestaurantID)
+	if err != nil {
+		return nil, "", err
+	}
+	reviewURL := s.links.URL(rest, order.ID)
+	code, err := qrcode.New(reviewURL, qrLevels[settings.QRLevel])
+	if err != nil {
+		return nil, "", err
+	}
+	r := &receipt{
+		settings:  settings,
+		lines:     receiptLines(order),
+		reviewURL: reviewURL,
+		qr:        code.Bitmap(),
+	}
+	if settings.LogoURL != "" && format != ReceiptESCPOS {
+		// a receipt without the logo is better than no receipt
+		if r.logo, r.logoPNG, err = s.loadLogo(settings.LogoURL); err != nil {
+			log.Printf("receipt logo %s: %v", settings.LogoURL, err)
+		}
+	}
+
+	var buf bytes.Buffer
+	contentType, err := render(&buf, r)
+	if err != nil {
+		return nil, "", err
+	}
+	return buf.Bytes(), contentType, nil
+}
+
+// loadLogo reads the logo from the store, scaled down for embedding and
+// encoded as PNG so transparency survives in every format.
+func (s *ReceiptService) loadLogo(logoURL string) (image.Image, []byte, error) {
+	if s.files == nil {
+		return nil, nil, errors.New("file storage is not configured")
+	}
+	key, ok := s.files.KeyFromURL(logoURL)
+	if !ok {
+		return nil, nil, errors.New("not an uploaded file")
+	}
+	body, err := s.files.Get(key)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer body.Close()
+	img, _, err := image.Decode(body)
+	if err != nil {
+		return nil, nil, err
+	}
+	img = resizeToWidth(img, maxLogoWidth)
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, nil, err
+	}
+	return img, buf.Bytes(), nil
+}
+
+var _ ReceiptServiceInterface = (*ReceiptService)(nil)
+
+func normalizeReceiptSettings(settings *domain.ReceiptSettings) {
+	defaults := DefaultReceiptSettings(settings.RestaurantID)
+	if settings.QRSize == 0 {
+		settings.QRSize = defaults.QRSize
+	}
+	settings.QRLevel = strings.ToUpper(strings.TrimSpace(settings.QRLevel))
+	if settings.QRLevel == "" {
+		settings.QRLevel = defaults.QRLevel
+	}
+	for _, c := range []struct {
+		value    *string
+		fallback string
+	}{
+		{&settings.QRColor, defaults.QRColor},
+		{&settings.BackgroundColor, defaults.BackgroundColor},
+		{&settings.AccentColor, defaults.AccentColor},
+	} {
+		*c.value = strings.ToLower(strings.TrimSpace(*c.value))
+		if *c.value == "" {
+			*c.value = c.fallback
+		}
+	}
+	if settings.PaperWidth == 0 {
+		settings.PaperWidth = defaults.PaperWidth
+	}
+	settings.PrinterQR = strings.ToLower(strings.TrimSpace(settings.PrinterQR))
+	if settings.PrinterQR == "" {
+		settings.PrinterQR = defaults.PrinterQR
+	}
+	settings.LogoURL = strings.TrimSpace(settings.LogoURL)
+	settings.FooterText = strings.TrimSpace(settings.FooterText)
+}
+
+func validateReceiptSettings(settings *domain.ReceiptSettings) error {
+	_, knownLevel := qrLevels[settings.QRLevel]
+	switch {
+	case settings.QRSize < minQRSize || settings.QRSize > maxQRSize:
+		return fmt.Errorf("%w: qr_size must be between %d and %d", ErrInvalidReceiptSettings, minQRSize, maxQRSize)
+	case !knownLevel:
+		return fmt.Errorf("%w: qr_level must be L, M, Q or H", ErrInvalidReceiptSettings)
+	case !hexColor.MatchString(settings.QRColor) || !hexColor.MatchString(settings.BackgroundColor) || !hexColor.MatchString(settings.AccentColor):
+		return fmt.Errorf("%w: colors must be #rrggbb", ErrInvalidReceiptSettings)
+	case settings.LogoURL != "" && (settings.QRLevel == "L" || settings.QRLevel == "M"):
+		return fmt.Errorf("%w: a logo needs qr_level Q or H", ErrInvalidReceiptSettings)
+	case settings.PaperWidth != 58 && settings.PaperWidth != 80:
+		return fmt.Errorf("%w: paper_width must be 58 or 80", ErrInvalidReceiptSettings)
+	case settings.PrinterQR != PrinterQRNative && settings.PrinterQR != PrinterQRRaster:
+		return fmt.Errorf("%w: printer_qr must be native or raster", ErrInvalidReceiptSettings)
+	case utf8.RuneCountInString(settings.FooterText) > maxFooterLength:
+		return fmt.Errorf("%w: footer_text is longer than %d characters", ErrInvalidReceiptSettings, maxFooterLength)
+	}
+	// scanners look for dark modules on a light background
+	qr, background := luminance(settings.QRColor), luminance(settings.BackgroundColor)
+	if qr >= background || (background+0.05)/(qr+0.05) < minColorContrast {
+		return fmt.Errorf("%w: qr_color must be clearly darker than background_color", ErrInvalidReceiptSettings)
+	}
+	return nil
+}
+
+// luminance is the WCAG relative luminance of a "#rrggbb" color.
+func luminance(color string) float64 {
+	r, g, b := parseHexColor(color)
+	channel := func(c uint8) float64 {
+		v := float64(c) / 255
+		if v <= 0.03928 {
+			return v / 12.92
+		}
+		return math.Pow((v+0.055)/1.055, 2.4)
+	}
+	return 0.2126*channel(r) + 0.7152*channel(g) + 0.0722*channel(b)
+}
+
+func parseHexColor(color string) (r, g, b uint8) {
+	v, _ := strconv.ParseUint(strings.TrimPrefix(color, "#"), 16, 32)
+	return uint8(v >> 16), uint8(v >> 8), uint8(v)
+}
@@ -0,0 +1,75 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"overcooked-simplified/dish-svc/internal/domain"
+)
+
+var ErrInvalidSearch = errors.New("invalid search query")
+
+const (
+	defaultSearchLimit = 20
+	maxSearchLimit     = 50
+	maxSearchLength    = 200
+)
+
+type SearchService struct {
+	repo SearchRepository
+}
+
+func NewSearchService(repo SearchRepository) *SearchService {
+	return &SearchService{repo: repo}
+}
+
+// Search checks the query and runs it; the limit applies to restaurants and
+// dishes separately.
+func (s *SearchService) Search(ctx context.Context, query domain.SearchQuery) (*domain.SearchResults, error) {
+	query.Text = strings.Join(strings.Fields(query.Text), " ")
+	switch {
+	case query.Text == "":
+		return nil, fmt.Errorf("%w: q is required", ErrInvalidSearch)
+	case utf8.RuneCountInString(query.Text) > maxSearchLength:
+		return nil, fmt.Errorf("%w: q is longer than %d characters", ErrInvalidSearch, maxSearchLength)
+	case query.MinPrice != nil && *query.MinPrice < 0, query.MaxPrice != nil && *query.MaxPrice < 0:
+		return nil, fmt.Errorf("%w: prices must not be negative", ErrInvalidSearch)
+	case query.MinPrice != nil && query.MaxPrice != nil && *query.MinPrice > *query.MaxPrice:
+		return nil, fmt.Errorf("%w: min_price is above max_price", ErrInvalidSearch)
+	case query.MinRating != nil && (*query.MinRating < 0 || *query.MinRating > 5):
+		return nil, fmt.Errorf("%w: min_rating must be between 0 and 5", ErrInvalidSearch)
+	case query.RestaurantID < 0:
+		return nil, fmt.Errorf("%w: bad restaurant_id", ErrInvalidSearch)
+	}
+
+	allergens, err := NormalizeAllergens(query.ExcludeAllergens)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSearch, err)
+	}
+	query.ExcludeAllergens = allergens
+
+	switch {
+	case query.Limit <= 0:
+		query.Limit = defaultSearchLimit
+	case query.Limit > maxSearchLimit:
+		query.Limit = maxSearchLimit
+	}
+
+	results, err := s.repo.Search(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	results.Query = query.Text
+	if results.Restaurants == nil {
+		results.Restaurants = []domain.RestaurantHit{}
+	}
+	if results.Dishes == nil {
+		results.Dishes = []domain.DishHit{}
+	}
+	return results, nil
+}
+
+var _ SearchServiceInterface = (*SearchService)(nil)
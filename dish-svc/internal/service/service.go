@@ -1,165 +1,738 @@
 package service
 
 import (
+	"context"
+	"database/sql"
 	"errors"
 	"fmt"
+	"io"
+	"log"
+	"math"
+	"strings"
+	"time"
 
 	"overcooked-simplified/dish-svc/internal/domain"
+
+	"golang.org/x/text/language"
+)
+
+var (
+	ErrInvalidOrder    = errors.New("invalid order payload")
+	ErrUnknownCategory = errors.New("unknown menu category")
 )
 
+// OrderValidationError lists every order item that failed validation, so the
+// client can fix the whole order in one round trip.
+type OrderValidationError struct {
+	Items []domain.OrderItemError
+}
+
+func (e *OrderValidationError) Error() string {
+	messages := make([]string, 0, len(e.Items))
+	for _, item := range e.Items {
+		messages = append(messages, fmt.Sprintf("item %d (dish %d): %s", item.Index, item.DishID, item.Message))
+	}
+	return "invalid order items: " + strings.Join(messages, "; ")
+}
+
 type RestaurantRepository interface {
-	CreateRestaurant(rest *domain.Restaurant) error
-	ListRestaurants() ([]domain.Restaurant, error)
-	GetRestaurant(id int) (*domain.Restaurant, error)
-	UpdateRestaurant(rest *domain.Restaurant) error
-	DeleteRestaurant(id int) (int64, error)
-	UpdateRestaurantImage(id int, imageURL string) error
+	CreateRestaurant(ctx context.Context, rest *domain.Restaurant) error
+	// ListRestaurants returns up to filter.Limit restaurants after
+	// filter.After in filter.Sort order.
+	ListRestaurants(ctx context.Context, filter domain.RestaurantFilter) ([]domain.Restaurant, error)
+	GetRestaurant(ctx context.Context, id int) (*domain.Restaurant, error)
+	UpdateRestaurant(ctx context.Context, rest *domain.Restaurant) error
+	DeleteRestaurant(ctx context.Context, id int) (int64, error)
+	UpdateRestaurantImage(ctx context.Context, id int, image domain.ImageSet) error
+	ImageInUse(ctx context.Context, imageURL string) (bool, error)
+	ListDeletedRestaurants(ctx context.Context) ([]domain.Restaurant, error)
+	GetRestaurantWithDeleted(ctx context.Context, id int) (*domain.Restaurant, error)
+	RestoreRestaurant(ctx context.Context, id int) (int64, error)
+	PurgeRestaurant(ctx context.Context, id int) (int64, error)
 }
 
 type DishRepository interface {
-	CreateDish(dish *domain.Dish) error
-	ListDishes(restaurantID int) ([]domain.Dish, error)
-	GetDish(restaurantID, dishID int) (*domain.Dish, error)
-	UpdateDish(dish *domain.Dish) error
-	DeleteDish(restaurantID, dishID int) (int64, error)
-	UpdateDishImage(restaurantID, dishID int, imageURL string) error
+	CreateDish(ctx context.Context, dish *domain.Dish) error
+	ListDishes(ctx context.Context, restaurantID int) ([]domain.Dish, error)
+	GetDish(ctx context.Context, restaurantID, dishID int) (*domain.Dish, error)
+	// GetRestaurant gives the time zone the dishes' availability windows are in.
+	GetRestaurant(ctx context.Context, id int) (*domain.Restaurant, error)
+	GetCategory(ctx context.Context, restaurantID, categoryID int) (*domain.MenuCategory, error)
+	UpdateDish(ctx context.Context, dish *domain.Dish) error
+	DeleteDish(ctx context.Context, restaurantID, dishID int) (int64, error)
+	UpdateDishImage(ctx context.Context, restaurantID, dishID int, image domain.ImageSet) error
+	ImageInUse(ctx context.Context, imageURL string) (bool, error)
+	UpdateDishAvailability(ctx context.Context, restaurantID, dishID int, stopListed bool, windows []domain.AvailabilityWindow) (int64, error)
+	ListPriceHistory(ctx context.Context, restaurantID, dishID int) ([]domain.PriceChange, error)
+	ListDeletedDishes(ctx context.Context, restaurantID int) ([]domain.Dish, error)
+	GetDishWithDeleted(ctx context.Context, restaurantID, dishID int) (*domain.Dish, error)
+	RestoreDish(ctx context.Context, restaurantID, dishID int) (int64, error)
+	PurgeDish(ctx context.Context, restaurantID, dishID int) (int64, error)
+}
+
+type CategoryRepository interface {
+	CreateCategory(ctx context.Context, cat *domain.MenuCategory) error
+	ListCategories(ctx context.Context, restaurantID int) ([]domain.MenuCategory, error)
+	UpdateCategory(ctx context.Context, cat *domain.MenuCategory) error
+	DeleteCategory(ctx context.Context, restaurantID, categoryID int) (int64, error)
+	ReorderCategories(ctx context.Context, restaurantID int, categoryIDs []int) error
+	ReorderDishes(ctx context.Context, restaurantID, categoryID int, dishIDs []int) error
+}
+
+// ModifierRepository returns modifier groups keyed by dish ID, with groups and
+// options in display order.
+type ModifierRepository interface {
+	ListModifierGroups(ctx context.Context, dishIDs []int) (map[int][]domain.ModifierGroup, error)
+	CreateModifierGroup(ctx context.Context, group *domain.ModifierGroup) error
+	UpdateModifierGroup(ctx context.Context, group *domain.ModifierGroup) error
+	DeleteModifierGroup(ctx context.Context, dishID, groupID int) (int64, error)
+}
+
+// MenuVersionRepository stores the menu draft and the published versions.
+// PublishMenuDraft and RollbackMenu change the live menu in one transaction.
+type MenuVersionRepository interface {
+	StageMenuChange(ctx context.Context, change *domain.MenuDraftChange) error
+	ListMenuDraft(ctx context.Context, restaurantID int) ([]domain.MenuDraftChange, error)
+	DeleteMenuDraftChange(ctx context.Context, restaurantID, changeID int) (int64, error)
+	DiscardMenuDraft(ctx context.Context, restaurantID int) (int64, error)
+	PublishMenuDraft(ctx context.Context, restaurantID int) (*domain.MenuVersion, error)
+	ListMenuVersions(ctx context.Context, restaurantID int) ([]domain.MenuVersion, error)
+	GetMenuVersion(ctx context.Context, restaurantID, version int) (*domain.MenuVersion, error)
+	RollbackMenu(ctx context.Context, restaurantID, version int) (*domain.MenuVersion, error)
+}
+
+type MenuImportRepository interface {
+	ImportMenu(ctx context.Context, restaurantID int, rows []domain.MenuRow) (*domain.MenuImportReport, error)
+}
+
+type ReceiptRepository interface {
+	GetReceiptSettings(ctx context.Context, restaurantID int) (*domain.ReceiptSettings, error)
+	SaveReceiptSettings(ctx context.Context, settings *domain.ReceiptSettings) error
+}
+
+// ImageStore processes uploaded images and removes them once nothing refers
+// to them anymore.
+type ImageStore interface {
+	Save(upload io.Reader) (*domain.ImageSet, error)
+	Remove(imageURL string) error
+}
+
+// BlobStore keeps uploaded files under slash-separated keys. Get returns an
+// error wrapping fs.ErrNotExist for missing keys. URL is the public address of
+// a key, KeyFromURL its inverse for URLs the store handed out, and SignedURL
+// an address that stops working after ttl.
+type BlobStore interface {
+	Put(key, contentType string, body io.Reader) error
+	Get(key string) (io.ReadCloser, error)
+	Exists(key string) (bool, error)
+	Delete(key string) error
+	List(prefix string) ([]string, error)
+	URL(key string) string
+	KeyFromURL(url string) (string, bool)
+	SignedURL(key string, ttl time.Duration) (string, error)
+}
+
+// BlobURLRepository rewrites stored file URLs after the files moved to
+// another BlobStore.
+type BlobURLRepository interface {
+	RewriteImageURLs(ctx context.Context, oldPrefix, newPrefix string) (int64, error)
+}
+
+type TableRepository interface {
+	CreateTable(ctx context.Context, table *domain.Table) error
+	ListTables(ctx context.Context, restaurantID int) ([]domain.Table, error)
+	GetTable(ctx context.Context, restaurantID, tableID int) (*domain.Table, error)
+	GetTableByCode(ctx context.Context, code string) (*domain.Table, error)
+	UpdateTable(ctx context.Context, table *domain.Table) error
+	DeleteTable(ctx context.Context, restaurantID, tableID int) (int64, error)
+}
+
+// TranslationRepository returns translations keyed by dish ID, ordered by
+// language.
+type TranslationRepository interface {
+	ListDishTranslations(ctx context.Context, dishIDs []int) (map[int][]domain.DishTranslation, error)
+	UpsertDishTranslation(ctx context.Context, tr *domain.DishTranslation) error
+	DeleteDishTranslation(ctx context.Context, dishID int, lang string) (int64, error)
+}
+
+// SearchRepository ranks restaurants and dishes for a checked query; the
+// limit applies to each list separately.
+type SearchRepository interface {
+	Search(ctx context.Context, query domain.SearchQuery) (*domain.SearchResults, error)
 }
 
 type OrderRepository interface {
-	CreateOrder(order *domain.Order) error
-	SaveQRCode(orderID int, qr []byte) error
-	GetOrder(orderID int) (*domain.Order, []domain.OrderItem, error)
-	ListOrders() ([]domain.Order, error)
-	GetQRCode(orderID int) ([]byte, error)
+	CreateOrder(ctx context.Context, order *domain.Order) error
+	GetDishesByIDs(ctx context.Context, dishIDs []int) (map[int]domain.Dish, error)
+	ListModifierGroups(ctx context.Context, dishIDs []int) (map[int][]domain.ModifierGroup, error)
+	SaveQRCode(ctx context.Context, orderID int, qr []byte) error
+	GetOrder(ctx context.Context, orderID int) (*domain.Order, []domain.OrderItem, error)
+	// ListOrders pages orders like ListRestaurants, with restaurant names.
+	ListOrders(ctx context.Context, filter domain.OrderFilter) ([]domain.Order, error)
+	GetQRCode(ctx context.Context, orderID int) ([]byte, error)
+	// ListOrderIDs groups order IDs by restaurant; restaurantID 0 lists all.
+	ListOrderIDs(ctx context.Context, restaurantID int) (map[int][]int, error)
+	GetRestaurantWithDeleted(ctx context.Context, id int) (*domain.Restaurant, error)
+	GetTable(ctx context.Context, restaurantID, tableID int) (*domain.Table, error)
 }
 
 type RestaurantServiceInterface interface {
-	Create(rest *domain.Restaurant) error
-	List() ([]domain.Restaurant, error)
-	Get(id int) (*domain.Restaurant, error)
-	Update(rest *domain.Restaurant) error
-	Delete(id int) (int64, error)
-	UpdateImage(id int, imageURL string) error
+	Create(ctx context.Context, rest *domain.Restaurant) error
+	// List returns a page of restaurants and the cursor of the next page,
+	// empty on the last one.
+	List(ctx context.Context, filter domain.RestaurantFilter) ([]domain.Restaurant, string, error)
+	Get(ctx context.Context, id int) (*domain.Restaurant, error)
+	Update(ctx context.Context, rest *domain.Restaurant) error
+	Delete(ctx context.Context, id int) (int64, error)
+	UpdateImage(ctx context.Context, id int, upload io.Reader) (*domain.ImageSet, error)
+	ListDeleted(ctx context.Context) ([]domain.Restaurant, error)
+	Restore(ctx context.Context, id int) (int64, error)
+	Purge(ctx context.Context, id int, confirm string) error
 }
 
 type DishServiceInterface interface {
-	Create(dish *domain.Dish) error
-	List(restaurantID int) ([]domain.Dish, error)
-	Get(restaurantID, dishID int) (*domain.Dish, error)
-	Update(dish *domain.Dish) error
-	Delete(restaurantID, dishID int) (int64, error)
-	UpdateImage(restaurantID, dishID int, imageURL string) error
+	Create(ctx context.Context, dish *domain.Dish) error
+	List(ctx context.Context, restaurantID int, filter domain.DishFilter) ([]domain.Dish, error)
+	Get(ctx context.Context, restaurantID, dishID int) (*domain.Dish, error)
+	Update(ctx context.Context, dish *domain.Dish) error
+	Delete(ctx context.Context, restaurantID, dishID int) (int64, error)
+	UpdateImage(ctx context.Context, restaurantID, dishID int, upload io.Reader) (*domain.ImageSet, error)
+	SetAvailability(ctx context.Context, restaurantID, dishID int, stopListed bool, windows []domain.AvailabilityWindow) (int64, error)
+	StopList(ctx context.Context, restaurantID int) ([]domain.Dish, error)
+	PriceHistory(ctx context.Context, restaurantID, dishID int) ([]domain.PriceChange, error)
+	ListDeleted(ctx context.Context, restaurantID int) ([]domain.Dish, error)
+	Restore(ctx context.Context, restaurantID, dishID int) (int64, error)
+	Purge(ctx context.Context, restaurantID, dishID int, confirm string) error
+}
+
+type CategoryServiceInterface interface {
+	Create(ctx context.Context, cat *domain.MenuCategory) error
+	List(ctx context.Context, restaurantID int) ([]domain.MenuCategory, error)
+	Update(ctx context.Context, cat *domain.MenuCategory) error
+	Delete(ctx context.Context, restaurantID, categoryID int) (int64, error)
+	Reorder(ctx context.Context, restaurantID int, categoryIDs []int) error
+	ReorderDishes(ctx context.Context, restaurantID, categoryID int, dishIDs []int) error
+	Menu(ctx context.Context, restaurantID int, filter domain.DishFilter) ([]domain.MenuSection, error)
+}
+
+type ModifierServiceInterface interface {
+	List(ctx context.Context, restaurantID, dishID int) ([]domain.ModifierGroup, error)
+	Create(ctx context.Context, restaurantID int, group *domain.ModifierGroup) error
+	Update(ctx context.Context, restaurantID int, group *domain.ModifierGroup) error
+	Delete(ctx context.Context, restaurantID, dishID, groupID int) (int64, error)
+}
+
+type MenuVersionServiceInterface interface {
+	StageDish(ctx context.Context, dish *domain.Dish) (*domain.MenuDraftChange, error)
+	StageDishDeletion(ctx context.Context, restaurantID, dishID int) (*domain.MenuDraftChange, error)
+	StageCategory(ctx context.Context, cat *domain.MenuCategory) (*domain.MenuDraftChange, error)
+	StageCategoryDeletion(ctx context.Context, restaurantID, categoryID int) (*domain.MenuDraftChange, error)
+	Draft(ctx context.Context, restaurantID int) ([]domain.MenuDraftChange, error)
+	DropChange(ctx context.Context, restaurantID, changeID int) (int64, error)
+	Discard(ctx context.Context, restaurantID int) (int64, error)
+	Preview(ctx context.Context, restaurantID int) ([]domain.MenuSection, error)
+	Publish(ctx context.Context, restaurantID int) (*domain.MenuVersion, error)
+	Versions(ctx context.Context, restaurantID int) ([]domain.MenuVersion, error)
+	Version(ctx context.Context, restaurantID, version int) (*domain.MenuVersion, error)
+	Rollback(ctx context.Context, restaurantID, version int) (*domain.MenuVersion, error)
+}
+
+type MenuImportServiceInterface interface {
+	Import(ctx context.Context, restaurantID int, format string, body io.Reader, dryRun bool) (*domain.MenuImportReport, error)
+	Export(ctx context.Context, restaurantID int) ([]domain.MenuRow, error)
 }
 
 type OrderServiceInterface interface {
-	Create(order *domain.Order) error
-	SaveQRCode(orderID int, qr []byte) error
-	Get(orderID int) (*domain.Order, error)
-	List() ([]domain.Order, error)
-	GetQRCode(orderID int) ([]byte, error)
+	Create(ctx context.Context, order *domain.Order) error
+	SaveQRCode(ctx context.Context, orderID int, qr []byte) error
+	Get(ctx context.Context, orderID int) (*domain.Order, error)
+	List(ctx context.Context, filter domain.OrderFilter) ([]domain.Order, string, error)
+	GetQRCode(ctx context.Context, orderID int) ([]byte, error)
 	QRLink(orderID int) string
+	RegenerateQRCodes(ctx context.Context, restaurantID int) (int, error)
+}
+
+type TableServiceInterface interface {
+	Create(ctx context.Context, table *domain.Table) error
+	List(ctx context.Context, restaurantID int) ([]domain.Table, error)
+	Get(ctx context.Context, restaurantID, tableID int) (*domain.Table, error)
+	ByCode(ctx context.Context, code string) (*domain.Table, error)
+	Update(ctx context.Context, table *domain.Table) error
+	Delete(ctx context.Context, restaurantID, tableID int) (int64, error)
+	QRCode(ctx context.Context, restaurantID, tableID int) ([]byte, error)
+}
+
+type TranslationServiceInterface interface {
+	List(ctx context.Context, restaurantID, dishID int) ([]domain.DishTranslation, error)
+	Put(ctx context.Context, restaurantID int, tr *domain.DishTranslation) error
+	Delete(ctx context.Context, restaurantID, dishID int, lang string) (int64, error)
+	Localize(ctx context.Context, restaurantID int, dishes []domain.Dish, requested []language.Tag) error
+	LocalizeMenu(ctx context.Context, restaurantID int, sections []domain.MenuSection, requested []language.Tag) error
+}
+
+type SearchServiceInterface interface {
+	Search(ctx context.Context, query domain.SearchQuery) (*domain.SearchResults, error)
+}
+
+type ReceiptServiceInterface interface {
+	Settings(ctx context.Context, restaurantID int) (*domain.ReceiptSettings, error)
+	UpdateSettings(ctx context.Context, settings *domain.ReceiptSettings) error
+	UpdateLogo(ctx context.Context, restaurantID int, upload io.Reader) (*domain.ReceiptSettings, error)
+	Render(ctx context.Context, orderID int, format string) ([]byte, string, error)
 }
 
 type RestaurantService struct {
-	repo RestaurantRepository
+	repo   RestaurantRepository
+	images ImageStore
 }
 
-func NewRestaurantService(repo RestaurantRepository) *RestaurantService {
-	return &RestaurantService{repo: repo}
+func NewRestaurantService(repo RestaurantRepository, images ImageStore) *RestaurantService {
+	return &RestaurantService{repo: repo, images: images}
 }
 
-func (s *RestaurantService) Create(rest *domain.Restaurant) error {
-	return s.repo.CreateRestaurant(rest)
+func (s *RestaurantService) Create(ctx context.Context, rest *domain.Restaurant) error {
+	if err := normalizePublicBaseURL(rest); err != nil {
+		return err
+	}
+	if err := normalizeRestaurantLocale(rest); err != nil {
+		return err
+	}
+	if err := s.repo.CreateRestaurant(ctx, rest); err != nil {
+		return err
+	}
+	setOpenNow(rest, time.Now())
+	return nil
 }
 
-func (s *RestaurantService) List() ([]domain.Restaurant, error) {
-	return s.repo.ListRestaurants()
+func (s *RestaurantService) List(ctx context.Context, filter domain.RestaurantFilter) ([]domain.Restaurant, string, error) {
+	if err := normalizeRestaurantFilter(&filter); err != nil {
+		return nil, "", err
+	}
+	limit := filter.Limit
+	// one row more than asked tells whether there is a next page
+	filter.Limit++
+	restaurants, err := s.repo.ListRestaurants(ctx, filter)
+	if err != nil {
+		return nil, "", err
+	}
+	next := ""
+	if len(restaurants) > limit {
+		restaurants = restaurants[:limit]
+		last := restaurants[limit-1]
+		next = EncodeCursor(filter.Sort, domain.PageCursor{Value: restaurantSorts[strings.TrimPrefix(filter.Sort, "-")](last), ID: last.ID})
+	}
+	now := time.Now()
+	for i := range restaurants {
+		setOpenNow(&restaurants[i], now)
+	}
+	return restaurants, next, nil
 }
 
-func (s *RestaurantService) Get(id int) (*domain.Restaurant, error) {
-	return s.repo.GetRestaurant(id)
+func (s *RestaurantService) Get(ctx context.Context, id int) (*domain.Restaurant, error) {
+	rest, err := s.repo.GetRestaurant(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	setOpenNow(rest, time.Now())
+	return rest, nil
 }
 
-func (s *RestaurantService) Update(rest *domain.Restaurant) error {
-	return s.repo.UpdateRestaurant(rest)
+func (s *RestaurantService) Update(ctx context.Context, rest *domain.Restaurant) error {
+	if err := normalizePublicBaseURL(rest); err != nil {
+		return err
+	}
+	if err := normalizeRestaurantLocale(rest); err != nil {
+		return err
+	}
+	if err := s.repo.UpdateRestaurant(ctx, rest); err != nil {
+		return err
+	}
+	setOpenNow(rest, time.Now())
+	return nil
 }
 
-func (s *RestaurantService) Delete(id int) (int64, error) {
-	return s.repo.DeleteRestaurant(id)
+// normalizePublicBaseURL checks the restaurant's own base URL for QR links;
+// an empty one means the configured default.
+func normalizePublicBaseURL(rest *domain.Restaurant) error {
+	if strings.TrimSpace(rest.PublicBaseURL) == "" {
+		rest.PublicBaseURL = ""
+		return nil
+	}
+	base, err := NormalizeBaseURL(rest.PublicBaseURL)
+	if err != nil {
+		return err
+	}
+	rest.PublicBaseURL = base
+	return nil
 }
 
-func (s *RestaurantService) UpdateImage(id int, imageURL string) error {
-	return s.repo.UpdateRestaurantImage(id, imageURL)
+func (s *RestaurantService) Delete(ctx context.Context, id int) (int64, error) {
+	return s.repo.DeleteRestaurant(ctx, id)
+}
+
+// UpdateImage stores the upload and removes the image it replaces unless
+// something else still shows it.
+func (s *RestaurantService) UpdateImage(ctx context.Context, id int, upload io.Reader) (*domain.ImageSet, error) {
+	rest, err := s.repo.GetRestaurant(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	set, err := s.images.Save(upload)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.UpdateRestaurantImage(ctx, id, *set); err != nil {
+		return nil, err
+	}
+	removeReplacedImage(ctx, s.images, s.repo.ImageInUse, rest.ImageURL, set.URL)
+	return set, nil
 }
 
 var _ RestaurantServiceInterface = (*RestaurantService)(nil)
 
 type DishService struct {
-	repo DishRepository
+	repo   DishRepository
+	images ImageStore
+}
+
+func NewDishService(repo DishRepository, images ImageStore) *DishService {
+	return &DishService{repo: repo, images: images}
+}
+
+func (s *DishService) Create(ctx context.Context, dish *domain.Dish) error {
+	if err := validateWindows(dish.AvailabilityWindows); err != nil {
+		return err
+	}
+	if err := normalizeDietary(dish); err != nil {
+		return err
+	}
+	if err := s.checkCategory(ctx, dish); err != nil {
+		return err
+	}
+	return s.repo.CreateDish(ctx, dish)
 }
 
-func NewDishService(repo DishRepository) *DishService {
-	return &DishService{repo: repo}
+// checkCategory rejects a category of another restaurant or one that does not
+// exist; storage would quietly leave the dish without a category instead.
+func (s *DishService) checkCategory(ctx context.Context, dish *domain.Dish) error {
+	if dish.CategoryID == nil {
+		return nil
+	}
+	_, err := s.repo.GetCategory(ctx, dish.RestaurantID, *dish.CategoryID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("%w %d in restaurant %d", ErrUnknownCategory, *dish.CategoryID, dish.RestaurantID)
+	}
+	return err
+}
+
+func (s *DishService) List(ctx context.Context, restaurantID int, filter domain.DishFilter) ([]domain.Dish, error) {
+	if err := inRestaurantZone(ctx, s.repo, restaurantID, &filter); err != nil {
+		return nil, err
+	}
+	dishes, err := s.repo.ListDishes(ctx, restaurantID)
+	if err != nil {
+		return nil, err
+	}
+	return FilterDishes(dishes, filter), nil
+}
+
+func (s *DishService) Get(ctx context.Context, restaurantID, dishID int) (*domain.Dish, error) {
+	return s.repo.GetDish(ctx, restaurantID, dishID)
+}
+
+func (s *DishService) Update(ctx context.Context, dish *domain.Dish) error {
+	if err := normalizeDietary(dish); err != nil {
+		return err
+	}
+	if err := s.checkCategory(ctx, dish); err != nil {
+		return err
+	}
+	return s.repo.UpdateDish(ctx, dish)
 }
 
-func (s *DishService) Create(dish *domain.Dish) error {
-	return s.repo.CreateDish(dish)
+func (s *DishService) Delete(ctx context.Context, restaurantID, dishID int) (int64, error) {
+	return s.repo.DeleteDish(ctx, restaurantID, dishID)
 }
 
-func (s *DishService) List(restaurantID int) ([]domain.Dish, error) {
-	return s.repo.ListDishes(restaurantID)
+func (s *DishService) UpdateImage(ctx context.Context, restaurantID, dishID int, upload io.Reader) (*domain.ImageSet, error) {
+	dish, err := s.repo.GetDish(ctx, restaurantID, dishID)
+	if err != nil {
+		return nil, err
+	}
+	set, err := s.images.Save(upload)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.UpdateDishImage(ctx, restaurantID, dishID, *set); err != nil {
+		return nil, err
+	}
+	removeReplacedImage(ctx, s.images, s.repo.ImageInUse, dish.ImageURL, set.URL)
+	return set, nil
 }
 
-func (s *DishService) Get(restaurantID, dishID int) (*domain.Dish, error) {
-	return s.repo.GetDish(restaurantID, dishID)
+// removeReplacedImage deletes an image that was just replaced. The same file
+// may be shared by identical uploads or kept by a published menu version, so
+// it is only removed once nothing refers to it. Failures are logged, the
+// upload itself already succeeded.
+func removeReplacedImage(ctx context.Context, images ImageStore, inUse func(context.Context, string) (bool, error), oldURL, newURL string) {
+	if oldURL == "" || oldURL == newURL {
+		return
+	}
+	used, err := inUse(ctx, oldURL)
+	if err == nil && !used {
+		err = images.Remove(oldURL)
+	}
+	if err != nil {
+		log.Printf("remove replaced image %s: %v", oldURL, err)
+	}
 }
 
-func (s *DishService) Update(dish *domain.Dish) error {
-	return s.repo.UpdateDish(dish)
+func (s *DishService) SetAvailability(ctx context.Context, restaurantID, dishID int, stopListed bool, windows []domain.AvailabilityWindow) (int64, error) {
+	if err := validateWindows(windows); err != nil {
+		return 0, err
+	}
+	if windows == nil {
+		windows = []domain.AvailabilityWindow{}
+	}
+	return s.repo.UpdateDishAvailability(ctx, restaurantID, dishID, stopListed, windows)
 }
 
-func (s *DishService) Delete(restaurantID, dishID int) (int64, error) {
-	return s.repo.DeleteDish(restaurantID, dishID)
+// StopList returns the dishes the kitchen has marked as temporarily unavailable.
+func (s *DishService) StopList(ctx context.Context, restaurantID int) ([]domain.Dish, error) {
+	dishes, err := s.repo.ListDishes(ctx, restaurantID)
+	if err != nil {
+		return nil, err
+	}
+	stopList := make([]domain.Dish, 0)
+	for _, dish := range dishes {
+		if dish.StopListed {
+			stopList = append(stopList, dish)
+		}
+	}
+	return stopList, nil
 }
 
-func (s *DishService) UpdateImage(restaurantID, dishID int, imageURL string) error {
-	return s.repo.UpdateDishImage(restaurantID, dishID, imageURL)
+// PriceHistory returns the recorded prices of a dish, newest first. An
+// unknown dish yields sql.ErrNoRows rather than an empty history.
+func (s *DishService) PriceHistory(ctx context.Context, restaurantID, dishID int) ([]domain.PriceChange, error) {
+	if _, err := s.repo.GetDish(ctx, restaurantID, dishID); err != nil {
+		return nil, err
+	}
+	return s.repo.ListPriceHistory(ctx, restaurantID, dishID)
 }
 
 var _ DishServiceInterface = (*DishService)(nil)
 
+type CategoryService struct {
+	repo   CategoryRepository
+	dishes DishRepository
+}
+
+func NewCategoryService(repo CategoryRepository, dishes DishRepository) *CategoryService {
+	return &CategoryService{repo: repo, dishes: dishes}
+}
+
+func (s *CategoryService) Create(ctx context.Context, cat *domain.MenuCategory) error {
+	return s.repo.CreateCategory(ctx, cat)
+}
+
+func (s *CategoryService) List(ctx context.Context, restaurantID int) ([]domain.MenuCategory, error) {
+	return s.repo.ListCategories(ctx, restaurantID)
+}
+
+func (s *CategoryService) Update(ctx context.Context, cat *domain.MenuCategory) error {
+	return s.repo.UpdateCategory(ctx, cat)
+}
+
+func (s *CategoryService) Delete(ctx context.Context, restaurantID, categoryID int) (int64, error) {
+	return s.repo.DeleteCategory(ctx, restaurantID, categoryID)
+}
+
+func (s *CategoryService) Reorder(ctx context.Context, restaurantID int, categoryIDs []int) error {
+	return s.repo.ReorderCategories(ctx, restaurantID, categoryIDs)
+}
+
+func (s *CategoryService) ReorderDishes(ctx context.Context, restaurantID, categoryID int, dishIDs []int) error {
+	return s.repo.ReorderDishes(ctx, restaurantID, categoryID, dishIDs)
+}
+
+// Menu groups the restaurant's dishes by visible category in category order.
+// Dishes of hidden categories are left out; dishes without a category go to a
+// trailing section with a nil Category.
+func (s *CategoryService) Menu(ctx context.Context, restaurantID int, filter domain.DishFilter) ([]domain.MenuSection, error) {
+	categories, err := s.repo.ListCategories(ctx, restaurantID)
+	if err != nil {
+		return nil, err
+	}
+	if err := inRestaurantZone(ctx, s.dishes, restaurantID, &filter); err != nil {
+		return nil, err
+	}
+	dishes, err := s.dishes.ListDishes(ctx, restaurantID)
+	if err != nil {
+		return nil, err
+	}
+	return groupMenu(categories, FilterDishes(dishes, filter)), nil
+}
+
+func groupMenu(categories []domain.MenuCategory, dishes []domain.Dish) []domain.MenuSection {
+	byCategory := make(map[int][]domain.Dish)
+	var uncategorized []domain.Dish
+	for _, dish := range dishes {
+		if dish.CategoryID == nil {
+			uncategorized = append(uncategorized, dish)
+			continue
+		}
+		byCategory[*dish.CategoryID] = append(byCategory[*dish.CategoryID], dish)
+	}
+
+	sections := make([]domain.MenuSection, 0, len(categories)+1)
+	for i := range categories {
+		if !categories[i].Visible {
+			continue
+		}
+		sectionDishes := byCategory[categories[i].ID]
+		if sectionDishes == nil {
+			sectionDishes = []domain.Dish{}
+		}
+		sections = append(sections, domain.MenuSection{Category: &categories[i], Dishes: sectionDishes})
+	}
+	if len(uncategorized) > 0 {
+		sections = append(sections, domain.MenuSection{Dishes: uncategorized})
+	}
+	return sections
+}
+
+var _ CategoryServiceInterface = (*CategoryService)(nil)
+
 type OrderService struct {
 	repo      OrderRepository
 	qrEncoder QRGenerator
+	links     ReviewLinks
 }
 
-func NewOrderService(repo OrderRepository, qr QRGenerator) *OrderService {
-	return &OrderService{repo: repo, qrEncoder: qr}
+func NewOrderService(repo OrderRepository, qr QRGenerator, links ReviewLinks) *OrderService {
+	return &OrderService{repo: repo, qrEncoder: qr, links: links}
 }
 
-func (s *OrderService) Create(order *domain.Order) error {
+func (s *OrderService) Create(ctx context.Context, order *domain.Order) error {
 	if order.RestaurantID <= 0 || len(order.Items) == 0 {
-		return errors.New("invalid order payload")
+		return ErrInvalidOrder
+	}
+	if order.TableID != nil {
+		_, err := s.repo.GetTable(ctx, order.RestaurantID, *order.TableID)
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("%w: table %d is not in restaurant %d", ErrInvalidOrder, *order.TableID, order.RestaurantID)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if err := s.priceOrder(ctx, order); err != nil {
+		return err
 	}
-	if err := s.repo.CreateOrder(order); err != nil {
+	if err := s.repo.CreateOrder(ctx, order); err != nil {
 		return err
 	}
+	ordersCreated.Inc()
 
 	if s.qrEncoder != nil {
-		if qr, err := s.qrEncoder.Generate(order.ID); err == nil {
-			_ = s.repo.SaveQRCode(order.ID, qr)
+		if qr, err := s.generateQRCode(ctx, order.RestaurantID, order.ID); err == nil {
+			_ = s.repo.SaveQRCode(ctx, order.ID, qr)
+		}
+	}
+
+	return nil
+}
+
+func (s *OrderService) generateQRCode(ctx context.Context, restaurantID, orderID int) ([]byte, error) {
+	rest, err := s.repo.GetRestaurantWithDeleted(ctx, restaurantID)
+	if err != nil {
+		return nil, err
+	}
+	return s.qrEncoder.Generate(s.links.URL(rest, orderID))
+}
+
+// priceOrder replaces client-supplied prices with the current dish and option
+// prices and recomputes the order total. Items are rejected when the dish is
+// unknown, belongs to another restaurant, is not available right now, the
+// option selection breaks the modifier group rules or the quantity is not
+// positive.
+func (s *OrderService) priceOrder(ctx context.Context, order *domain.Order) error {
+	dishIDs := make([]int, 0, len(order.Items))
+	for _, item := range order.Items {
+		dishIDs = append(dishIDs, item.DishID)
+	}
+	dishes, err := s.repo.GetDishesByIDs(ctx, dishIDs)
+	if err != nil {
+		return err
+	}
+	modifiers, err := s.repo.ListModifierGroups(ctx, dishIDs)
+	if err != nil {
+		return err
+	}
+
+	rest, err := s.repo.GetRestaurantWithDeleted(ctx, order.RestaurantID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("%w: restaurant %d not found", ErrInvalidOrder, order.RestaurantID)
+	}
+	if err != nil {
+		return err
+	}
+	// availability windows are set in the restaurant's local time
+	now := time.Now().In(RestaurantLocation(*rest))
+	var itemErrors []domain.OrderItemError
+	var total float64
+	for i := range order.Items {
+		item := &order.Items[i]
+		if item.Quantity <= 0 {
+			itemErrors = append(itemErrors, domain.OrderItemError{Index: i, DishID: item.DishID, Message: "quantity must be positive"})
+			continue
+		}
+		dish, ok := dishes[item.DishID]
+		if !ok {
+			itemErrors = append(itemErrors, domain.OrderItemError{Index: i, DishID: item.DishID, Message: "dish not found"})
+			continue
+		}
+		if dish.RestaurantID != order.RestaurantID {
+			itemErrors = append(itemErrors, domain.OrderItemError{Index: i, DishID: item.DishID, Message: "dish belongs to another restaurant"})
+			continue
+		}
+		if !DishAvailableAt(dish, now) {
+			itemErrors = append(itemErrors, domain.OrderItemError{Index: i, DishID: item.DishID, Message: "dish is not available"})
+			continue
 		}
+		options, problem := selectOptions(modifiers[item.DishID], item.Options)
+		if problem != "" {
+			itemErrors = append(itemErrors, domain.OrderItemError{Index: i, DishID: item.DishID, Message: problem})
+			continue
+		}
+		unitPrice := dish.Price
+		for _, option := range options {
+			unitPrice += option.PriceDelta
+		}
+		item.DishName = dish.Name
+		item.Options = options
+		item.Price = roundMoney(unitPrice)
+		total += item.Price * float64(item.Quantity)
+	}
+	if len(itemErrors) > 0 {
+		return &OrderValidationError{Items: itemErrors}
 	}
 
+	order.TotalAmount = roundMoney(total)
 	return nil
 }
 
-func (s *OrderService) SaveQRCode(orderID int, qr []byte) error {
-	return s.repo.SaveQRCode(orderID, qr)
+func roundMoney(amount float64) float64 {
+	return math.Round(amount*100) / 100
 }
 
-func (s *OrderService) Get(orderID int) (*domain.Order, error) {
-	order, items, err := s.repo.GetOrder(orderID)
+func (s *OrderService) SaveQRCode(ctx context.Context, orderID int, qr []byte) error {
+	return s.repo.SaveQRCode(ctx, orderID, qr)
+}
+
+func (s *OrderService) Get(ctx context.Context, orderID int) (*domain.Order, error) {
+	order, items, err := s.repo.GetOrder(ctx, orderID)
 	if err != nil {
 		return nil, err
 	}
@@ -167,24 +740,79 @@ func (s *OrderService) Get(orderID int) (*domain.Order, error) {
 	return order, nil
 }
 
-func (s *OrderService) List() ([]domain.Order, error) {
-	return s.repo.ListOrders()
+func (s *OrderService) List(ctx context.Context, filter domain.OrderFilter) ([]domain.Order, string, error) {
+	if err := normalizeOrderFilter(&filter); err != nil {
+		return nil, "", err
+	}
+	limit := filter.Limit
+	filter.Limit++
+	orders, err := s.repo.ListOrders(ctx, filter)
+	if err != nil {
+		return nil, "", err
+	}
+	next := ""
+	if len(orders) > limit {
+		orders = orders[:limit]
+		last := orders[limit-1]
+		next = EncodeCursor(filter.Sort, domain.PageCursor{Value: orderSorts[strings.TrimPrefix(filter.Sort, "-")](last), ID: last.ID})
+	}
+	return orders, next, nil
 }
 
-func (s *OrderService) GetQRCode(orderID int) ([]byte, error) {
-	qr, err := s.repo.GetQRCode(orderID)
+func (s *OrderService) GetQRCode(ctx context.Context, orderID int) ([]byte, error) {
+	qr, err := s.repo.GetQRCode(ctx, orderID)
 	if err != nil {
 		return nil, err
 	}
 	if len(qr) == 0 && s.qrEncoder != nil {
-		if regenerated, err := s.qrEncoder.Generate(orderID); err == nil {
-			_ = s.repo.SaveQRCode(orderID, regenerated)
+		order, _, err := s.repo.GetOrder(ctx, orderID)
+		if err != nil {
+			return nil, err
+		}
+		if regenerated, err := s.generateQRCode(ctx, order.RestaurantID, orderID); err == nil {
+			_ = s.repo.SaveQRCode(ctx, orderID, regenerated)
 			return regenerated, nil
 		}
 	}
 	return qr, nil
 }
 
+// RegenerateQRCodes rewrites the stored QR codes of a restaurant's orders,
+// or of all orders when restaurantID is 0, after the public base URL has
+// changed. It returns how many were rewritten.
+func (s *OrderService) RegenerateQRCodes(ctx context.Context, restaurantID int) (int, error) {
+	if s.qrEncoder == nil {
+		return 0, nil
+	}
+	if restaurantID != 0 {
+		if _, err := s.repo.GetRestaurantWithDeleted(ctx, restaurantID); err != nil {
+			return 0, err
+		}
+	}
+	orderIDs, err := s.repo.ListOrderIDs(ctx, restaurantID)
+	if err != nil {
+		return 0, err
+	}
+	regenerated := 0
+	for rid, ids := range orderIDs {
+		rest, err := s.repo.GetRestaurantWithDeleted(ctx, rid)
+		if err != nil {
+			return regenerated, err
+		}
+		for _, id := range ids {
+			qr, err := s.qrEncoder.Generate(s.links.URL(rest, id))
+			if err != nil {
+				return regenerated, err
+			}
+			if err := s.repo.SaveQRCode(ctx, id, qr); err != nil {
+				return regenerated, err
+			}
+			regenerated++
+		}
+	}
+	return regenerated, nil
+}
+
 func (s *OrderService) QRLink(orderID int) string {
 	return fmt.Sprintf("/api/orders/%d/qrcode", orderID)
 }
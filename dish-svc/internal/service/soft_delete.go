@@ -0,0 +1,81 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"overcooked-simplified/dish-svc/internal/domain"
+)
+
+var (
+	ErrNotDeleted        = errors.New("not deleted")
+	ErrPurgeNotConfirmed = errors.New("purge not confirmed")
+)
+
+// Deleting a restaurant or dish only hides it, so its orders and reviews stay
+// available to analytics. Purging removes it together with that history and
+// is only allowed for an already deleted row whose name is passed as confirm.
+
+func (s *RestaurantService) ListDeleted(ctx context.Context) ([]domain.Restaurant, error) {
+	return s.repo.ListDeletedRestaurants(ctx)
+}
+
+func (s *RestaurantService) Restore(ctx context.Context, id int) (int64, error) {
+	return s.repo.RestoreRestaurant(ctx, id)
+}
+
+func (s *RestaurantService) Purge(ctx context.Context, id int, confirm string) error {
+	rest, err := s.repo.GetRestaurantWithDeleted(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := checkPurge(rest.DeletedAt != nil, rest.Name, confirm); err != nil {
+		return err
+	}
+	rows, err := s.repo.PurgeRestaurant(ctx, id)
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		// restored between the check and the purge
+		return ErrNotDeleted
+	}
+	return nil
+}
+
+func (s *DishService) ListDeleted(ctx context.Context, restaurantID int) ([]domain.Dish, error) {
+	return s.repo.ListDeletedDishes(ctx, restaurantID)
+}
+
+func (s *DishService) Restore(ctx context.Context, restaurantID, dishID int) (int64, error) {
+	return s.repo.RestoreDish(ctx, restaurantID, dishID)
+}
+
+func (s *DishService) Purge(ctx context.Context, restaurantID, dishID int, confirm string) error {
+	dish, err := s.repo.GetDishWithDeleted(ctx, restaurantID, dishID)
+	if err != nil {
+		return err
+	}
+	if err := checkPurge(dish.DeletedAt != nil, dish.Name, confirm); err != nil {
+		return err
+	}
+	rows, err := s.repo.PurgeDish(ctx, restaurantID, dishID)
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ErrNotDeleted
+	}
+	return nil
+}
+
+func checkPurge(deleted bool, name, confirm string) error {
+	if !deleted {
+		return fmt.Errorf("%w: delete it before purging", ErrNotDeleted)
+	}
+	if confirm != name {
+		return fmt.Errorf("%w: confirm must be %q", ErrPurgeNotConfirmed, name)
+	}
+	return nil
+}
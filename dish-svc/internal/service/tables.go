@@ -0,0 +1,117 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base32"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"overcooked-simplified/dish-svc/internal/domain"
+)
+
+var ErrInvalidTable = errors.New("invalid table")
+
+const maxTableLabelLength = 64
+
+// tableCodeEncoding spells table codes in lower case without padding; eight
+// characters carry 40 random bits, enough that codes cannot be guessed.
+var tableCodeEncoding = base32.NewEncoding("abcdefghijklmnopqrstuvwxyz234567").WithPadding(base32.NoPadding)
+
+// TableService keeps the tables of a restaurant and draws their static QR
+// codes. The codes are random and never change, so a printed QR code stays
+// valid when a table is renamed or moved to another section.
+type TableService struct {
+	repo        TableRepository
+	restaurants RestaurantRepository
+	qr          QRGenerator
+	links       ReviewLinks
+}
+
+func NewTableService(repo TableRepository, restaurants RestaurantRepository, qr QRGenerator, links ReviewLinks) *TableService {
+	return &TableService{repo: repo, restaurants: restaurants, qr: qr, links: links}
+}
+
+func (s *TableService) Create(ctx context.Context, table *domain.Table) error {
+	if err := normalizeTable(table); err != nil {
+		return err
+	}
+	if _, err := s.restaurants.GetRestaurant(ctx, table.RestaurantID); err != nil {
+		return err
+	}
+	code, err := newTableCode()
+	if err != nil {
+		return err
+	}
+	table.Code = code
+	return s.repo.CreateTable(ctx, table)
+}
+
+func (s *TableService) List(ctx context.Context, restaurantID int) ([]domain.Table, error) {
+	if _, err := s.restaurants.GetRestaurant(ctx, restaurantID); err != nil {
+		return nil, err
+	}
+	tables, err := s.repo.ListTables(ctx, restaurantID)
+	if tables == nil {
+		tables = []domain.Table{}
+	}
+	return tables, err
+}
+
+func (s *TableService) Get(ctx context.Context, restaurantID, tableID int) (*domain.Table, error) {
+	return s.repo.GetTable(ctx, restaurantID, tableID)
+}
+
+// ByCode resolves a scanned table QR code.
+func (s *TableService) ByCode(ctx context.Context, code string) (*domain.Table, error) {
+	return s.repo.GetTableByCode(ctx, strings.ToLower(code))
+}
+
+func (s *TableService) Update(ctx context.Context, table *domain.Table) error {
+	if err := normalizeTable(table); err != nil {
+		return err
+	}
+	return s.repo.UpdateTable(ctx, table)
+}
+
+func (s *TableService) Delete(ctx context.Context, restaurantID, tableID int) (int64, error) {
+	return s.repo.DeleteTable(ctx, restaurantID, tableID)
+}
+
+// QRCode draws the table's QR code as PNG. It is not stored: the link only
+// depends on the code and the restaurant's base URL.
+func (s *TableService) QRCode(ctx context.Context, restaurantID, tableID int) ([]byte, error) {
+	table, err := s.repo.GetTable(ctx, restaurantID, tableID)
+	if err != nil {
+		return nil, err
+	}
+	rest, err := s.restaurants.GetRestaurant(ctx, restaurantID)
+	if err != nil {
+		return nil, err
+	}
+	return s.qr.Generate(s.links.TableURL(rest, table.Code))
+}
+
+var _ TableServiceInterface = (*TableService)(nil)
+
+func normalizeTable(table *domain.Table) error {
+	table.Label = strings.TrimSpace(table.Label)
+	table.Section = strings.TrimSpace(table.Section)
+	switch {
+	case table.Label == "":
+		return fmt.Errorf("%w: label is required", ErrInvalidTable)
+	case utf8.RuneCountInString(table.Label) > maxTableLabelLength || utf8.RuneCountInString(table.Section) > maxTableLabelLength:
+		return fmt.Errorf("%w: label and section are limited to %d characters", ErrInvalidTable, maxTableLabelLength)
+	}
+	return nil
+}
+
+func newTableCode() (string, error) {
+	b := make([]byte, 5)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return tableCodeEncoding.EncodeToString(b), nil
+}
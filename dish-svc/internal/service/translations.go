@@ -0,0 +1,131 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"overcooked-simplified/dish-svc/internal/domain"
+	"overcooked-simplified/i18n"
+
+	"golang.org/x/text/language"
+)
+
+var ErrInvalidTranslation = errors.New("invalid translation")
+
+type TranslationService struct {
+	repo        TranslationRepository
+	dishes      DishRepository
+	restaurants RestaurantRepository
+}
+
+func NewTranslationService(repo TranslationRepository, dishes DishRepository, restaurants RestaurantRepository) *TranslationService {
+	return &TranslationService{repo: repo, dishes: dishes, restaurants: restaurants}
+}
+
+func (s *TranslationService) List(ctx context.Context, restaurantID, dishID int) ([]domain.DishTranslation, error) {
+	if _, err := s.dishes.GetDish(ctx, restaurantID, dishID); err != nil {
+		return nil, err
+	}
+	translations, err := s.repo.ListDishTranslations(ctx, []int{dishID})
+	if err != nil {
+		return nil, err
+	}
+	if translations[dishID] == nil {
+		return []domain.DishTranslation{}, nil
+	}
+	return translations[dishID], nil
+}
+
+// Put creates or replaces the translation of the dish into tr.Lang, which is
+// stored in its canonical form ("EN-us" becomes "en-US").
+func (s *TranslationService) Put(ctx context.Context, restaurantID int, tr *domain.DishTranslation) error {
+	tag, err := language.Parse(tr.Lang)
+	if err != nil {
+		return fmt.Errorf("%w: unknown language %q", ErrInvalidTranslation, tr.Lang)
+	}
+	tr.Lang = tag.String()
+	tr.Name = strings.TrimSpace(tr.Name)
+	if tr.Name == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidTranslation)
+	}
+	if _, err := s.dishes.GetDish(ctx, restaurantID, tr.DishID); err != nil {
+		return err
+	}
+	return s.repo.UpsertDishTranslation(ctx, tr)
+}
+
+func (s *TranslationService) Delete(ctx context.Context, restaurantID, dishID int, lang string) (int64, error) {
+	tag, err := language.Parse(lang)
+	if err != nil {
+		return 0, fmt.Errorf("%w: unknown language %q", ErrInvalidTranslation, lang)
+	}
+	if _, err := s.dishes.GetDish(ctx, restaurantID, dishID); err != nil {
+		return 0, err
+	}
+	return s.repo.DeleteDishTranslation(ctx, dishID, tag.String())
+}
+
+// Localize replaces names and descriptions of the restaurant's dishes with
+// the translations that fit the requested languages best. Dishes keep their
+// own text when the restaurant's locale fits as well or nothing fits.
+func (s *TranslationService) Localize(ctx context.Context, restaurantID int, dishes []domain.Dish, requested []language.Tag) error {
+	if len(requested) == 0 || len(dishes) == 0 {
+		return nil
+	}
+	rest, err := s.restaurants.GetRestaurant(ctx, restaurantID)
+	if err != nil {
+		return err
+	}
+	dishIDs := make([]int, len(dishes))
+	for i, dish := range dishes {
+		dishIDs[i] = dish.ID
+	}
+	translations, err := s.repo.ListDishTranslations(ctx, dishIDs)
+	if err != nil {
+		return err
+	}
+
+	for i := range dishes {
+		available := translations[dishes[i].ID]
+		langs := make([]string, len(available))
+		for j, tr := range available {
+			langs[j] = tr.Lang
+		}
+		lang := i18n.Pick(requested, rest.Locale, langs)
+		if lang == "" {
+			continue
+		}
+		for _, tr := range available {
+			if tr.Lang != lang {
+				continue
+			}
+			dishes[i].Name = tr.Name
+			// an untranslated description is better than none
+			if tr.Description != "" {
+				dishes[i].Description = tr.Description
+			}
+			dishes[i].Lang = lang
+		}
+	}
+	return nil
+}
+
+// LocalizeMenu localizes the dishes of every section of a grouped menu.
+func (s *TranslationService) LocalizeMenu(ctx context.Context, restaurantID int, sections []domain.MenuSection, requested []language.Tag) error {
+	var dishes []domain.Dish
+	for _, section := range sections {
+		dishes = append(dishes, section.Dishes...)
+	}
+	if err := s.Localize(ctx, restaurantID, dishes, requested); err != nil {
+		return err
+	}
+	for i := range sections {
+		n := copy(sections[i].Dishes, dishes)
+		dishes = dishes[n:]
+	}
+	return nil
+}
+
+var _ TranslationServiceInterface = (*TranslationService)(nil)
@@ -0,0 +1,210 @@
+package storage
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrInvalidBlobKey = errors.New("invalid blob key")
+
+// FileBlobStore keeps blobs as files under Dir. Files are public under
+// BaseURL; signed URLs point at SignedBaseURL, which is served by ServeHTTP.
+type FileBlobStore struct {
+	Dir           string
+	BaseURL       string
+	SignedBaseURL string
+	signingKey    []byte
+}
+
+// NewFileBlobStore returns a store under dir. Without a signing key SignedURL
+// fails and ServeHTTP rejects every request.
+func NewFileBlobStore(dir, baseURL, signedBaseURL string, signingKey []byte) *FileBlobStore {
+	return &FileBlobStore{
+		Dir:           dir,
+		BaseURL:       strings.TrimSuffix(baseURL, "/"),
+		SignedBaseURL: strings.TrimSuffix(signedBaseURL, "/"),
+		signingKey:    signingKey,
+	}
+}
+
+// cleanBlobKey rejects keys that would leave the store: absolute paths, ".."
+// segments and hidden files, which are used for partial writes.
+func cleanBlobKey(key string) (string, error) {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") || path.Clean(key) != key {
+		return "", fmt.Errorf("%w: %q", ErrInvalidBlobKey, key)
+	}
+	for _, segment := range strings.Split(key, "/") {
+		if segment == ".." || strings.HasPrefix(segment, ".") {
+			return "", fmt.Errorf("%w: %q", ErrInvalidBlobKey, key)
+		}
+	}
+	return key, nil
+}
+
+func (s *FileBlobStore) path(key string) (string, error) {
+	key, err := cleanBlobKey(key)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(s.Dir, filepath.FromSlash(key)), nil
+}
+
+// Put writes through a temporary file so readers never see a partial blob.
+func (s *FileBlobStore) Put(key, contentType string, body io.Reader) error {
+	target, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(target), ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := io.Copy(tmp, body); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), target)
+}
+
+func (s *FileBlobStore) Get(key string) (io.ReadCloser, error) {
+	target, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+	return os.Open(target)
+}
+
+func (s *FileBlobStore) Exists(key string) (bool, error) {
+	target, err := s.path(key)
+	if err != nil {
+		return false, err
+	}
+	_, err = os.Stat(target)
+	if errors.Is(err, fs.ErrNotExist) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (s *FileBlobStore) Delete(key string) error {
+	target, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(target); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// List returns the keys starting with prefix, skipping temporary files.
+func (s *FileBlobStore) List(prefix string) ([]string, error) {
+	keys := []string{}
+	err := filepath.WalkDir(s.Dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) && p == s.Dir {
+				return filepath.SkipDir
+			}
+			return err
+		}
+		if d.IsDir() || strings.HasPrefix(d.Name(), ".") {
+			return nil
+		}
+		rel, err := filepath.Rel(s.Dir, p)
+		if err != nil {
+			return err
+		}
+		if key := filepath.ToSlash(rel); strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+		return nil
+	})
+	return keys, err
+}
+
+func (s *FileBlobStore) URL(key string) string {
+	return s.BaseURL + "/" + key
+}
+
+func (s *FileBlobStore) KeyFromURL(rawURL string) (string, bool) {
+	return keyFromURL(s.BaseURL, rawURL)
+}
+
+// keyFromURL strips base and any query string from a URL handed out by a
+// store. Anything that is not a valid key under base yields false.
+func keyFromURL(base, rawURL string) (string, bool) {
+	rest, ok := strings.CutPrefix(rawURL, base+"/")
+	if !ok {
+		return "", false
+	}
+	rest, _, _ = strings.Cut(rest, "?")
+	key, err := cleanBlobKey(rest)
+	return key, err == nil
+}
+
+// SignedURL returns SignedBaseURL/key with an expiry and an HMAC of both.
+func (s *FileBlobStore) SignedURL(key string, ttl time.Duration) (string, error) {
+	if len(s.signingKey) == 0 {
+		return "", errors.New("signed URLs need BLOB_SIGNING_KEY")
+	}
+	if _, err := cleanBlobKey(key); err != nil {
+		return "", err
+	}
+	expires := strconv.FormatInt(time.Now().Add(ttl).Unix(), 10)
+	query := url.Values{"expires": {expires}, "signature": {s.signature(key, expires)}}
+	return s.SignedBaseURL + "/" + key + "?" + query.Encode(), nil
+}
+
+func (s *FileBlobStore) signature(key, expires string) string {
+	mac := hmac.New(sha256.New, s.signingKey)
+	mac.Write([]byte(key + "\n" + expires))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// ServeHTTP serves a blob by a URL from SignedURL, with the signed base
+// already stripped from the path.
+func (s *FileBlobStore) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	key := strings.TrimPrefix(r.URL.Path, "/")
+	expires := r.URL.Query().Get("expires")
+	signature := r.URL.Query().Get("signature")
+	unix, err := strconv.ParseInt(expires, 10, 64)
+	if len(s.signingKey) == 0 || err != nil ||
+		!hmac.Equal([]byte(signature), []byte(s.signature(key, expires))) {
+		http.Error(w, "Invalid signature", http.StatusForbidden)
+		return
+	}
+	if time.Now().Unix() > unix {
+		http.Error(w, "Link expired", http.StatusForbidden)
+		return
+	}
+	target, err := s.path(key)
+	if err != nil {
+		http.Error(w, "File not found", http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Cache-Control", "private, max-age="+strconv.FormatInt(max(unix-time.Now().Unix(), 0), 10))
+	http.ServeFile(w, r, target)
+}
@@ -0,0 +1,117 @@
+package storage
+
+import (
+	"context"
+	"strings"
+
+	"overcooked-simplified/dish-svc/internal/domain"
+)
+
+// ImportMenu upserts validated rows by SKU in one transaction, creating
+// missing categories by name, and records the result as a new menu version.
+// Dishes that are not in the import are left alone.
+func (r *PostgresRepository) ImportMenu(ctx context.Context, restaurantID int, rows []domain.MenuRow) (*domain.MenuImportReport, error) {
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	current, err := lockMenuVersion(ctx, tx, restaurantID)
+	if err != nil {
+		return nil, err
+	}
+
+	categories, err := listCategories(ctx, tx, restaurantID)
+	if err != nil {
+		return nil, err
+	}
+	categoryIDs := make(map[string]int, len(categories))
+	for _, cat := range categories {
+		key := strings.ToLower(cat.Name)
+		if _, ok := categoryIDs[key]; !ok {
+			categoryIDs[key] = cat.ID
+		}
+	}
+
+	existing := make(map[string]int)
+	deleted := make(map[int]bool)
+	skuRows, err := tx.QueryContext(ctx, "SELECT id, external_sku, deleted_at IS NOT NULL FROM dishes WHERE restaurant_id = $1 AND external_sku IS NOT NULL FOR UPDATE", restaurantID)
+	if err != nil {
+		return nil, err
+	}
+	for skuRows.Next() {
+		var id int
+		var sku string
+		var isDeleted bool
+		if err := skuRows.Scan(&id, &sku, &isDeleted); err != nil {
+			skuRows.Close()
+			return nil, err
+		}
+		existing[sku] = id
+		deleted[id] = isDeleted
+	}
+	skuRows.Close()
+	if err := skuRows.Err(); err != nil {
+		return nil, err
+	}
+
+	report := &domain.MenuImportReport{Rows: len(rows), Errors: []domain.MenuRowError{}}
+	for _, row := range rows {
+		dish := domain.Dish{
+			RestaurantID: restaurantID,
+			SKU:          row.SKU,
+			Name:         row.Name,
+			Description:  row.Description,
+			Price:        row.Price,
+			Allergens:    row.Allergens,
+			DietaryTags:  row.DietaryTags,
+			Nutrition:    row.Nutrition,
+		}
+		if row.Category != "" {
+			key := strings.ToLower(row.Category)
+			id, ok := categoryIDs[key]
+			if !ok {
+				cat := domain.MenuCategory{RestaurantID: restaurantID, Name: row.Category, Visible: true}
+				if err := insertCategory(ctx, tx, &cat); err != nil {
+					return nil, err
+				}
+				id = cat.ID
+				categoryIDs[key] = id
+				report.CategoriesCreated++
+			}
+			dish.CategoryID = &id
+		}
+
+		if id, ok := existing[row.SKU]; ok {
+			dish.ID = id
+			if deleted[id] {
+				// a soft-deleted dish keeps its SKU; importing it brings it back
+				if _, err := tx.ExecContext(ctx, "UPDATE dishes SET deleted_at = NULL WHERE id = $1", id); err != nil {
+					return nil, err
+				}
+			}
+			if err := updateDish(ctx, tx, &dish); err != nil {
+				return nil, err
+			}
+			if deleted[id] {
+				report.Created++
+			} else {
+				report.Updated++
+			}
+			continue
+		}
+		if err := insertDish(ctx, tx, &dish); err != nil {
+			return nil, err
+		}
+		existing[row.SKU] = dish.ID
+		report.Created++
+	}
+
+	version, err := saveMenuVersion(ctx, tx, restaurantID, current+1, nil)
+	if err != nil {
+		return nil, err
+	}
+	report.MenuVersion = version.Version
+	return report, tx.Commit()
+}
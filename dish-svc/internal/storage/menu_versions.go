@@ -0,0 +1,419 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"overcooked-simplified/dish-svc/internal/domain"
+
+	"github.com/lib/pq"
+)
+
+// menuSnapshot is the JSON stored in menu_versions.snapshot.
+type menuSnapshot struct {
+	Categories []domain.MenuCategory `json:"categories"`
+	Dishes     []domain.Dish         `json:"dishes"`
+}
+
+// StageMenuChange adds a change to the restaurant's draft. A change to an
+// existing dish or category replaces any earlier staged change to it.
+func (r *PostgresRepository) StageMenuChange(ctx context.Context, change *domain.MenuDraftChange) error {
+	var payload []byte
+	var err error
+	switch {
+	case change.Dish != nil:
+		payload, err = json.Marshal(change.Dish)
+	case change.Category != nil:
+		payload, err = json.Marshal(change.Category)
+	}
+	if err != nil {
+		return err
+	}
+
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if change.EntityID != 0 {
+		if _, err := tx.ExecContext(ctx,
+			"DELETE FROM menu_drafts WHERE restaurant_id = $1 AND entity = $2 AND entity_id = $3",
+			change.RestaurantID, change.Entity, change.EntityID); err != nil {
+			return err
+		}
+	}
+	if err := tx.QueryRowContext(ctx, `
+		INSERT INTO menu_drafts (restaurant_id, entity, action, entity_id, payload)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at`,
+		change.RestaurantID, change.Entity, change.Action, change.EntityID, payload).
+		Scan(&change.ID, &change.CreatedAt); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *PostgresRepository) ListMenuDraft(ctx context.Context, restaurantID int) ([]domain.MenuDraftChange, error) {
+	return listMenuDraft(ctx, r.DB, restaurantID)
+}
+
+func listMenuDraft(ctx context.Context, q execer, restaurantID int) ([]domain.MenuDraftChange, error) {
+	rows, err := q.QueryContext(ctx, `
+		SELECT id, restaurant_id, entity, action, entity_id, payload, created_at
+		FROM menu_drafts
+		WHERE restaurant_id = $1
+		ORDER BY id`, restaurantID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	changes := []domain.MenuDraftChange{}
+	for rows.Next() {
+		var change domain.MenuDraftChange
+		var payload []byte
+		if err := rows.Scan(&change.ID, &change.RestaurantID, &change.Entity, &change.Action,
+			&change.EntityID, &payload, &change.CreatedAt); err != nil {
+			return nil, err
+		}
+		if payload != nil {
+			switch change.Entity {
+			case domain.DraftEntityDish:
+				change.Dish = &domain.Dish{}
+				err = json.Unmarshal(payload, change.Dish)
+			case domain.DraftEntityCategory:
+				change.Category = &domain.MenuCategory{}
+				err = json.Unmarshal(payload, change.Category)
+			}
+			if err != nil {
+				return nil, fmt.Errorf("draft change %d: %w", change.ID, err)
+			}
+		}
+		changes = append(changes, change)
+	}
+	return changes, rows.Err()
+}
+
+func (r *PostgresRepository) DeleteMenuDraftChange(ctx context.Context, restaurantID, changeID int) (int64, error) {
+	result, err := r.DB.ExecContext(ctx, "DELETE FROM menu_drafts WHERE id = $1 AND restaurant_id = $2", changeID, restaurantID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+func (r *PostgresRepository) DiscardMenuDraft(ctx context.Context, restaurantID int) (int64, error) {
+	result, err := r.DB.ExecContext(ctx, "DELETE FROM menu_drafts WHERE restaurant_id = $1", restaurantID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// PublishMenuDraft applies every staged change, snapshots the resulting menu
+// as the next version and clears the draft, all in one transaction. A change
+// to a dish or category that no longer exists aborts the publish with an
+// error wrapping sql.ErrNoRows.
+func (r *PostgresRepository) PublishMenuDraft(ctx context.Context, restaurantID int) (*domain.MenuVersion, error) {
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	current, err := lockMenuVersion(ctx, tx, restaurantID)
+	if err != nil {
+		return nil, err
+	}
+	changes, err := listMenuDraft(ctx, tx, restaurantID)
+	if err != nil {
+		return nil, err
+	}
+	for _, change := range changes {
+		if err := applyMenuChange(ctx, tx, change); err != nil {
+			return nil, fmt.Errorf("draft change %d: %w", change.ID, err)
+		}
+	}
+	if _, err := tx.ExecContext(ctx, "DELETE FROM menu_drafts WHERE restaurant_id = $1", restaurantID); err != nil {
+		return nil, err
+	}
+
+	version, err := saveMenuVersion(ctx, tx, restaurantID, current+1, nil)
+	if err != nil {
+		return nil, err
+	}
+	return version, tx.Commit()
+}
+
+func applyMenuChange(ctx context.Context, tx *sql.Tx, change domain.MenuDraftChange) error {
+	switch {
+	case change.Entity == domain.DraftEntityCategory && change.Action == domain.DraftActionDelete:
+		_, err := tx.ExecContext(ctx, "DELETE FROM menu_categories WHERE id = $1 AND restaurant_id = $2", change.EntityID, change.RestaurantID)
+		return err
+	case change.Entity == domain.DraftEntityDish && change.Action == domain.DraftActionDelete:
+		_, err := tx.ExecContext(ctx, "UPDATE dishes SET deleted_at = CURRENT_TIMESTAMP WHERE id = $1 AND restaurant_id = $2 AND deleted_at IS NULL",
+			change.EntityID, change.RestaurantID)
+		return err
+	case change.Category != nil:
+		cat := *change.Category
+		cat.ID = change.EntityID
+		cat.RestaurantID = change.RestaurantID
+		if cat.ID == 0 {
+			return insertCategory(ctx, tx, &cat)
+		}
+		if err := updateCategory(ctx, tx, &cat); err != nil {
+			return fmt.Errorf("category %d: %w", cat.ID, err)
+		}
+		return nil
+	case change.Dish != nil:
+		dish := *change.Dish
+		dish.ID = change.EntityID
+		dish.RestaurantID = change.RestaurantID
+		if dish.ID == 0 {
+			return insertDish(ctx, tx, &dish)
+		}
+		if err := updateDish(ctx, tx, &dish); err != nil {
+			return fmt.Errorf("dish %d: %w", dish.ID, err)
+		}
+		return nil
+	}
+	return fmt.Errorf("unsupported change %s/%s", change.Entity, change.Action)
+}
+
+// RollbackMenu restores the categories and dishes of an earlier version and
+// publishes the result as a new version. Stop-list state and modifier groups
+// are not part of a version and are left as they are; dishes missing from the
+// snapshot are soft-deleted and dishes in it are restored.
+func (r *PostgresRepository) RollbackMenu(ctx context.Context, restaurantID, version int) (*domain.MenuVersion, error) {
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	current, err := lockMenuVersion(ctx, tx, restaurantID)
+	if err != nil {
+		return nil, err
+	}
+	var raw []byte
+	if err := tx.QueryRowContext(ctx,
+		"SELECT snapshot FROM menu_versions WHERE restaurant_id = $1 AND version = $2",
+		restaurantID, version).Scan(&raw); err != nil {
+		return nil, err
+	}
+	var snapshot menuSnapshot
+	if err := json.Unmarshal(raw, &snapshot); err != nil {
+		return nil, err
+	}
+
+	categoryIDs := make([]int64, 0, len(snapshot.Categories))
+	for _, cat := range snapshot.Categories {
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO menu_categories (id, restaurant_id, name, sort_order, visible, created_at)
+			VALUES ($1, $2, $3, $4, $5, $6)
+			ON CONFLICT (id) DO UPDATE SET name = EXCLUDED.name, sort_order = EXCLUDED.sort_order, visible = EXCLUDED.visible
+			WHERE menu_categories.restaurant_id = EXCLUDED.restaurant_id`,
+			cat.ID, restaurantID, cat.Name, cat.SortOrder, cat.Visible, cat.CreatedAt); err != nil {
+			return nil, err
+		}
+		categoryIDs = append(categoryIDs, int64(cat.ID))
+	}
+	if _, err := tx.ExecContext(ctx, "DELETE FROM menu_categories WHERE restaurant_id = $1 AND NOT (id = ANY($2))",
+		restaurantID, pq.Array(categoryIDs)); err != nil {
+		return nil, err
+	}
+
+	dishIDs := make([]int64, 0, len(snapshot.Dishes))
+	skus := make([]string, 0, len(snapshot.Dishes))
+	for i, dish := range snapshot.Dishes {
+		if dish.SKU == "" {
+			// snapshots taken before dishes had SKUs
+			snapshot.Dishes[i].SKU = fmt.Sprintf("dish-%d", dish.ID)
+		}
+		dishIDs = append(dishIDs, int64(dish.ID))
+		skus = append(skus, snapshot.Dishes[i].SKU)
+	}
+	if err := freeSnapshotSKUs(ctx, tx, restaurantID, dishIDs, skus); err != nil {
+		return nil, err
+	}
+	for _, dish := range snapshot.Dishes {
+		if err := restoreDish(ctx, tx, restaurantID, dish); err != nil {
+			return nil, fmt.Errorf("dish %d: %w", dish.ID, err)
+		}
+	}
+
+	restored, err := saveMenuVersion(ctx, tx, restaurantID, current+1, &version)
+	if err != nil {
+		return nil, err
+	}
+	return restored, tx.Commit()
+}
+
+// freeSnapshotSKUs soft-deletes the dishes missing from the snapshot and
+// makes way for the snapshot's SKUs before any dish is restored: a dish added
+// after the snapshot may have taken the SKU of one that is coming back, and
+// dishes in the snapshot may have swapped SKUs since. Dishes left out keep
+// their SKU with a ~<id> suffix so they can still be found and restored.
+func freeSnapshotSKUs(ctx context.Context, tx *sql.Tx, restaurantID int, dishIDs []int64, skus []string) error {
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE dishes SET deleted_at = CURRENT_TIMESTAMP
+		WHERE restaurant_id = $1 AND deleted_at IS NULL AND NOT (id = ANY($2))`,
+		restaurantID, pq.Array(dishIDs)); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE dishes SET external_sku = LEFT(external_sku, 48) || '~' || id
+		WHERE restaurant_id = $1 AND NOT (id = ANY($2)) AND external_sku = ANY($3)`,
+		restaurantID, pq.Array(dishIDs), pq.Array(skus)); err != nil {
+		return err
+	}
+	// restoreDish sets them again
+	_, err := tx.ExecContext(ctx, "UPDATE dishes SET external_sku = NULL WHERE restaurant_id = $1 AND id = ANY($2)",
+		restaurantID, pq.Array(dishIDs))
+	return err
+}
+
+func restoreDish(ctx context.Context, tx *sql.Tx, restaurantID int, dish domain.Dish) error {
+	if dish.AvailabilityWindows == nil {
+		dish.AvailabilityWindows = []domain.AvailabilityWindow{}
+	}
+	windows, err := json.Marshal(dish.AvailabilityWindows)
+	if err != nil {
+		return err
+	}
+	nutrition, err := encodeNutrition(dish.Nutrition)
+	if err != nil {
+		return err
+	}
+
+	var oldPrice sql.NullFloat64
+	if err := tx.QueryRowContext(ctx, "SELECT price FROM dishes WHERE id = $1 AND restaurant_id = $2 FOR UPDATE",
+		dish.ID, restaurantID).Scan(&oldPrice); err != nil && err != sql.ErrNoRows {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO dishes (id, restaurant_id, category_id, name, description, price, image_url, sort_order,
+			availability_windows, allergens, dietary_tags, nutrition, created_at, external_sku,
+			image_srcset, image_webp_srcset)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)
+		ON CONFLICT (id) DO UPDATE SET
+			category_id = EXCLUDED.category_id, external_sku = EXCLUDED.external_sku,
+			name = EXCLUDED.name, description = EXCLUDED.description,
+			price = EXCLUDED.price, image_url = EXCLUDED.image_url,
+			image_srcset = EXCLUDED.image_srcset, image_webp_srcset = EXCLUDED.image_webp_srcset, sort_order = EXCLUDED.sort_order,
+			availability_windows = EXCLUDED.availability_windows, allergens = EXCLUDED.allergens,
+			dietary_tags = EXCLUDED.dietary_tags, nutrition = EXCLUDED.nutrition, deleted_at = NULL
+		WHERE dishes.restaurant_id = EXCLUDED.restaurant_id`,
+		dish.ID, restaurantID, dish.CategoryID, dish.Name, dish.Description, dish.Price, dish.ImageURL, dish.SortOrder,
+		windows, pq.Array(nonNilStrings(dish.Allergens)), pq.Array(nonNilStrings(dish.DietaryTags)), nutrition, dish.CreatedAt, dish.SKU,
+		dish.ImageSrcset, dish.ImageWebP); err != nil {
+		return err
+	}
+	if !oldPrice.Valid || oldPrice.Float64 != dish.Price {
+		return recordPrice(ctx, tx, dish.ID, dish.Price)
+	}
+	return nil
+}
+
+// lockMenuVersion returns the published version of the restaurant's menu and
+// locks the restaurant row so concurrent publishes are serialized.
+func lockMenuVersion(ctx context.Context, tx *sql.Tx, restaurantID int) (int, error) {
+	var current int
+	err := tx.QueryRowContext(ctx, "SELECT menu_version FROM restaurants WHERE id = $1 FOR UPDATE", restaurantID).Scan(&current)
+	if err != nil {
+		return 0, fmt.Errorf("restaurant %d: %w", restaurantID, err)
+	}
+	return current, nil
+}
+
+func saveMenuVersion(ctx context.Context, tx *sql.Tx, restaurantID, version int, rolledBackFrom *int) (*domain.MenuVersion, error) {
+	categories, err := listCategories(ctx, tx, restaurantID)
+	if err != nil {
+		return nil, err
+	}
+	dishes, err := listDishes(ctx, tx, restaurantID)
+	if err != nil {
+		return nil, err
+	}
+	snapshot, err := json.Marshal(menuSnapshot{Categories: categories, Dishes: dishes})
+	if err != nil {
+		return nil, err
+	}
+
+	saved := &domain.MenuVersion{
+		RestaurantID:   restaurantID,
+		Version:        version,
+		RolledBackFrom: rolledBackFrom,
+		Current:        true,
+		Categories:     categories,
+		Dishes:         dishes,
+	}
+	if err := tx.QueryRowContext(ctx, `
+		INSERT INTO menu_versions (restaurant_id, version, rolled_back_from, snapshot)
+		VALUES ($1, $2, $3, $4)
+		RETURNING published_at`,
+		restaurantID, version, rolledBackFrom, snapshot).Scan(&saved.PublishedAt); err != nil {
+		return nil, err
+	}
+	if _, err := tx.ExecContext(ctx, "UPDATE restaurants SET menu_version = $1 WHERE id = $2", version, restaurantID); err != nil {
+		return nil, err
+	}
+	return saved, nil
+}
+
+func (r *PostgresRepository) ListMenuVersions(ctx context.Context, restaurantID int) ([]domain.MenuVersion, error) {
+	rows, err := r.DB.QueryContext(ctx, `
+		SELECT v.restaurant_id, v.version, v.rolled_back_from, v.version = rest.menu_version, v.published_at
+		FROM menu_versions v
+		JOIN restaurants rest ON rest.id = v.restaurant_id
+		WHERE v.restaurant_id = $1
+		ORDER BY v.version DESC`, restaurantID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	versions := []domain.MenuVersion{}
+	for rows.Next() {
+		var v domain.MenuVersion
+		var rolledBackFrom sql.NullInt64
+		if err := rows.Scan(&v.RestaurantID, &v.Version, &rolledBackFrom, &v.Current, &v.PublishedAt); err != nil {
+			return nil, err
+		}
+		if rolledBackFrom.Valid {
+			from := int(rolledBackFrom.Int64)
+			v.RolledBackFrom = &from
+		}
+		versions = append(versions, v)
+	}
+	return versions, rows.Err()
+}
+
+func (r *PostgresRepository) GetMenuVersion(ctx context.Context, restaurantID, version int) (*domain.MenuVersion, error) {
+	v := domain.MenuVersion{}
+	var rolledBackFrom sql.NullInt64
+	var raw []byte
+	if err := r.DB.QueryRowContext(ctx, `
+		SELECT v.restaurant_id, v.version, v.rolled_back_from, v.version = rest.menu_version, v.published_at, v.snapshot
+		FROM menu_versions v
+		JOIN restaurants rest ON rest.id = v.restaurant_id
+		WHERE v.restaurant_id = $1 AND v.version = $2`, restaurantID, version).
+		Scan(&v.RestaurantID, &v.Version, &rolledBackFrom, &v.Current, &v.PublishedAt, &raw); err != nil {
+		return nil, err
+	}
+	if rolledBackFrom.Valid {
+		from := int(rolledBackFrom.Int64)
+		v.RolledBackFrom = &from
+	}
+	var snapshot menuSnapshot
+	if err := json.Unmarshal(raw, &snapshot); err != nil {
+		return nil, err
+	}
+	v.Categories = snapshot.Categories
+	v.Dishes = snapshot.Dishes
+	return &v, nil
+}
@@ -0,0 +1,151 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+
+	"overcooked-simplified/dish-svc/internal/domain"
+
+	"github.com/lib/pq"
+)
+
+func (r *PostgresRepository) ListModifierGroups(ctx context.Context, dishIDs []int) (map[int][]domain.ModifierGroup, error) {
+	rows, err := r.DB.QueryContext(ctx, `
+		SELECT g.id, g.dish_id, g.name, g.min_select, g.max_select, g.sort_order,
+		       o.id, o.name, o.price_delta, o.sort_order
+		FROM modifier_groups g
+		JOIN modifier_options o ON o.group_id = g.id
+		WHERE g.dish_id = ANY($1)
+		ORDER BY g.dish_id, g.sort_order, g.id, o.sort_order, o.id`, pq.Array(dishIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	groups := make(map[int][]domain.ModifierGroup)
+	for rows.Next() {
+		var group domain.ModifierGroup
+		var option domain.ModifierOption
+		if err := rows.Scan(&group.ID, &group.DishID, &group.Name, &group.MinSelect, &group.MaxSelect, &group.SortOrder,
+			&option.ID, &option.Name, &option.PriceDelta, &option.SortOrder); err != nil {
+			return nil, err
+		}
+		option.GroupID = group.ID
+
+		dishGroups := groups[group.DishID]
+		if n := len(dishGroups); n > 0 && dishGroups[n-1].ID == group.ID {
+			dishGroups[n-1].Options = append(dishGroups[n-1].Options, option)
+		} else {
+			group.Options = []domain.ModifierOption{option}
+			dishGroups = append(dishGroups, group)
+		}
+		groups[group.DishID] = dishGroups
+	}
+	return groups, rows.Err()
+}
+
+func (r *PostgresRepository) CreateModifierGroup(ctx context.Context, group *domain.ModifierGroup) error {
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := tx.QueryRowContext(ctx, `
+		INSERT INTO modifier_groups (dish_id, name, min_select, max_select, sort_order)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id`,
+		group.DishID, group.Name, group.MinSelect, group.MaxSelect, group.SortOrder).Scan(&group.ID); err != nil {
+		return err
+	}
+	if err := insertModifierOptions(ctx, tx, group); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// UpdateModifierGroup updates the group's options in place by ID, adds the
+// ones without an ID and deletes the ones left out. Options keep their IDs so
+// order_item_options (and the per-variant ratings built on them) still point
+// at them; orders keep a copy of the option name and price, so old receipts
+// are not affected by renames.
+func (r *PostgresRepository) UpdateModifierGroup(ctx context.Context, group *domain.ModifierGroup) error {
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, `
+		UPDATE modifier_groups SET name=$1, min_select=$2, max_select=$3, sort_order=$4
+		WHERE id=$5 AND dish_id=$6`,
+		group.Name, group.MinSelect, group.MaxSelect, group.SortOrder, group.ID, group.DishID)
+	if err != nil {
+		return err
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return sql.ErrNoRows
+	}
+
+	keep := make([]int64, 0, len(group.Options))
+	for _, option := range group.Options {
+		if option.ID != 0 {
+			keep = append(keep, int64(option.ID))
+		}
+	}
+	if _, err := tx.ExecContext(ctx, "DELETE FROM modifier_options WHERE group_id=$1 AND NOT (id = ANY($2))",
+		group.ID, pq.Array(keep)); err != nil {
+		return err
+	}
+	if err := upsertModifierOptions(ctx, tx, group); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *PostgresRepository) DeleteModifierGroup(ctx context.Context, dishID, groupID int) (int64, error) {
+	result, err := r.DB.ExecContext(ctx, "DELETE FROM modifier_groups WHERE id=$1 AND dish_id=$2", groupID, dishID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+func insertModifierOptions(ctx context.Context, tx *sql.Tx, group *domain.ModifierGroup) error {
+	for i := range group.Options {
+		group.Options[i].ID = 0
+	}
+	return upsertModifierOptions(ctx, tx, group)
+}
+
+// upsertModifierOptions updates the options that already belong to the group
+// and inserts the rest. An ID from another group is treated as a new option.
+func upsertModifierOptions(ctx context.Context, tx *sql.Tx, group *domain.ModifierGroup) error {
+	for i := range group.Options {
+		option := &group.Options[i]
+		option.GroupID = group.ID
+		if option.SortOrder == 0 {
+			option.SortOrder = i
+		}
+		if option.ID != 0 {
+			result, err := tx.ExecContext(ctx, `
+				UPDATE modifier_options SET name=$1, price_delta=$2, sort_order=$3
+				WHERE id=$4 AND group_id=$5`,
+				option.Name, option.PriceDelta, option.SortOrder, option.ID, group.ID)
+			if err != nil {
+				return err
+			}
+			if affected, _ := result.RowsAffected(); affected > 0 {
+				continue
+			}
+		}
+		if err := tx.QueryRowContext(ctx, `
+			INSERT INTO modifier_options (group_id, name, price_delta, sort_order)
+			VALUES ($1, $2, $3, $4)
+			RETURNING id`,
+			group.ID, option.Name, option.PriceDelta, option.SortOrder).Scan(&option.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,41 @@
+package storage
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"overcooked-simplified/dish-svc/internal/domain"
+)
+
+// sortColumn is a column a listing can be sorted by, with the type the text
+// value of a cursor is cast to.
+type sortColumn struct {
+	expr string
+	cast string
+}
+
+// keyset turns a sort ("column" or "-column") into the ORDER BY clause, with
+// idColumn breaking ties, and, when after is set, the condition that starts
+// the page right after it. Arguments are appended to args.
+func keyset(columns map[string]sortColumn, idColumn, sort string, after *domain.PageCursor, args []interface{}) (string, string, []interface{}, error) {
+	column, ok := columns[strings.TrimPrefix(sort, "-")]
+	if !ok {
+		return "", "", nil, fmt.Errorf("unknown sort %q", sort)
+	}
+	direction, compare := "ASC", ">"
+	if strings.HasPrefix(sort, "-") {
+		direction, compare = "DESC", "<"
+	}
+	orderBy := column.expr + " " + direction + ", " + idColumn + " " + direction
+	if after == nil {
+		return "", orderBy, args, nil
+	}
+	args = append(args, after.Value, after.ID)
+	condition := fmt.Sprintf("(%s, %s) %s ($%d::%s, $%d)", column.expr, idColumn, compare, len(args)-1, column.cast, len(args))
+	return condition, orderBy, args, nil
+}
+
+func placeholder(args []interface{}) string {
+	return "$" + strconv.Itoa(len(args))
+}
@@ -1,12 +1,89 @@
 package storage
 
 import (
+	"context"
 	"database/sql"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"strings"
 
 	"overcooked-simplified/dish-svc/internal/domain"
+
+	"github.com/lib/pq"
 )
 
+const dishColumns = "id, restaurant_id, category_id, COALESCE(external_sku, ''), name, COALESCE(description, ''), price, COALESCE(image_url, ''), COALESCE(image_srcset, ''), COALESCE(image_webp_srcset, ''), sort_order, stop_listed, availability_windows, allergens, dietary_tags, nutrition, created_at, deleted_at"
+
+// liveDish restricts a dishes query to dishes that are not soft-deleted and
+// belong to a restaurant that is not soft-deleted either.
+const liveDish = "deleted_at IS NULL AND restaurant_id IN (SELECT id FROM restaurants WHERE deleted_at IS NULL)"
+
+const restaurantColumns = "id, name, COALESCE(address, ''), COALESCE(description, ''), COALESCE(image_url, ''), COALESCE(image_srcset, ''), COALESCE(image_webp_srcset, ''), COALESCE(public_base_url, ''), time_zone, locale, opening_hours, created_at, deleted_at"
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanDish(row rowScanner) (domain.Dish, error) {
+	var dish domain.Dish
+	var categoryID sql.NullInt64
+	var windows, nutrition []byte
+	var allergens, tags pq.StringArray
+	if err := row.Scan(&dish.ID, &dish.RestaurantID, &categoryID, &dish.SKU, &dish.Name, &dish.Description, &dish.Price, &dish.ImageURL, &dish.ImageSrcset, &dish.ImageWebP, &dish.SortOrder, &dish.StopListed, &windows, &allergens, &tags, &nutrition, &dish.CreatedAt, &dish.DeletedAt); err != nil {
+		return dish, err
+	}
+	dish.Allergens = []string(allergens)
+	dish.DietaryTags = []string(tags)
+	if nutrition != nil {
+		if err := json.Unmarshal(nutrition, &dish.Nutrition); err != nil {
+			return dish, fmt.Errorf("decode nutrition of dish %d: %w", dish.ID, err)
+		}
+	}
+	if categoryID.Valid {
+		id := int(categoryID.Int64)
+		dish.CategoryID = &id
+	}
+	if err := json.Unmarshal(windows, &dish.AvailabilityWindows); err != nil {
+		return dish, fmt.Errorf("decode availability windows of dish %d: %w", dish.ID, err)
+	}
+	return dish, nil
+}
+
+func scanRestaurant(row rowScanner) (domain.Restaurant, error) {
+	var rest domain.Restaurant
+	var hours []byte
+	if err := row.Scan(&rest.ID, &rest.Name, &rest.Address, &rest.Description, &rest.ImageURL, &rest.ImageSrcset, &rest.ImageWebP, &rest.PublicBaseURL, &rest.TimeZone, &rest.Locale, &hours, &rest.CreatedAt, &rest.DeletedAt); err != nil {
+		return rest, err
+	}
+	if hours != nil {
+		if err := json.Unmarshal(hours, &rest.OpeningHours); err != nil {
+			return rest, fmt.Errorf("decode opening hours of restaurant %d: %w", rest.ID, err)
+		}
+	}
+	return rest, nil
+}
+
+// openingHoursJSON encodes the hours for a JSONB column; nil stays NULL.
+func openingHoursJSON(hours *domain.OpeningHours) (interface{}, error) {
+	if hours == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(hours)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+// execer is satisfied by both *sql.DB and *sql.Tx, so statement helpers can
+// run on their own or as part of a larger transaction.
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
 type PostgresRepository struct {
 	DB *sql.DB
 }
@@ -15,18 +92,72 @@ func NewPostgresRepository(db *sql.DB) *PostgresRepository {
 	return &PostgresRepository{DB: db}
 }
 
-func (r *PostgresRepository) CreateRestaurant(rest *domain.Restaurant) error {
-	return r.DB.QueryRow(
-		"INSERT INTO restaurants (name, address, description) VALUES ($1, $2, $3) RETURNING id, created_at",
-		rest.Name, rest.Address, rest.Description,
+func (r *PostgresRepository) CreateRestaurant(ctx context.Context, rest *domain.Restaurant) error {
+	hours, err := openingHoursJSON(rest.OpeningHours)
+	if err != nil {
+		return err
+	}
+	return r.DB.QueryRowContext(ctx,
+		"INSERT INTO restaurants (name, address, description, public_base_url, time_zone, locale, opening_hours) VALUES ($1, $2, $3, NULLIF($4, ''), $5, $6, $7) RETURNING id, created_at",
+		rest.Name, rest.Address, rest.Description, rest.PublicBaseURL, rest.TimeZone, rest.Locale, hours,
 	).Scan(&rest.ID, &rest.CreatedAt)
 }
 
-func (r *PostgresRepository) ListRestaurants() ([]domain.Restaurant, error) {
-	rows, err := r.DB.Query(`
-        SELECT id, name, COALESCE(address, ''), COALESCE(description, ''), COALESCE(image_url, ''), created_at
-        FROM restaurants
-        ORDER BY created_at DESC`)
+var restaurantSortColumns = map[string]sortColumn{
+	"created_at": {expr: "created_at", cast: "timestamp"},
+	"name":       {expr: "name", cast: "text"},
+}
+
+// likeEscaper keeps a name filter from being read as a LIKE pattern.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+func (r *PostgresRepository) ListRestaurants(ctx context.Context, filter domain.RestaurantFilter) ([]domain.Restaurant, error) {
+	where := []string{"deleted_at IS NULL"}
+	var args []interface{}
+	if filter.Name != "" {
+		args = append(args, likeEscaper.Replace(filter.Name))
+		where = append(where, "name ILIKE '%' || "+placeholder(args)+" || '%'")
+	}
+	if filter.HasImage != nil {
+		if *filter.HasImage {
+			where = append(where, "COALESCE(image_url, '') <> ''")
+		} else {
+			where = append(where, "COALESCE(image_url, '') = ''")
+		}
+	}
+	after, orderBy, args, err := keyset(restaurantSortColumns, "id", filter.Sort, filter.After, args)
+	if err != nil {
+		return nil, err
+	}
+	if after != "" {
+		where = append(where, after)
+	}
+	args = append(args, filter.Limit)
+
+	rows, err := r.DB.QueryContext(ctx, "SELECT "+restaurantColumns+" FROM restaurants WHERE "+strings.Join(where, " AND ")+
+		" ORDER BY "+orderBy+" LIMIT "+placeholder(args), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var restaurants []domain.Restaurant
+	for rows.Next() {
+		rest, err := scanRestaurant(rows)
+		if err != nil {
+			return nil, err
+		}
+		restaurants = append(restaurants, rest)
+	}
+	return restaurants, rows.Err()
+}
+
+func (r *PostgresRepository) ListDeletedRestaurants(ctx context.Context) ([]domain.Restaurant, error) {
+	return r.listRestaurants(ctx, "deleted_at IS NOT NULL", "deleted_at DESC")
+}
+
+func (r *PostgresRepository) listRestaurants(ctx context.Context, where, orderBy string) ([]domain.Restaurant, error) {
+	rows, err := r.DB.QueryContext(ctx, "SELECT "+restaurantColumns+" FROM restaurants WHERE "+where+" ORDER BY "+orderBy)
 	if err != nil {
 		return nil, err
 	}
@@ -34,8 +165,8 @@ func (r *PostgresRepository) ListRestaurants() ([]domain.Restaurant, error) {
 
 	var restaurants []domain.Restaurant
 	for rows.Next() {
-		var rest domain.Restaurant
-		if err := rows.Scan(&rest.ID, &rest.Name, &rest.Address, &rest.Description, &rest.ImageURL, &rest.CreatedAt); err != nil {
+		rest, err := scanRestaurant(rows)
+		if err != nil {
 			continue
 		}
 		restaurants = append(restaurants, rest)
@@ -44,53 +175,211 @@ func (r *PostgresRepository) ListRestaurants() ([]domain.Restaurant, error) {
 	return restaurants, nil
 }
 
-func (r *PostgresRepository) GetRestaurant(id int) (*domain.Restaurant, error) {
-	var rest domain.Restaurant
-	err := r.DB.QueryRow(`
-		SELECT id, name, COALESCE(address, ''), COALESCE(description, ''), COALESCE(image_url, ''), created_at
-		FROM restaurants
-		WHERE id = $1`, id).
-		Scan(&rest.ID, &rest.Name, &rest.Address, &rest.Description, &rest.ImageURL, &rest.CreatedAt)
+func (r *PostgresRepository) GetRestaurant(ctx context.Context, id int) (*domain.Restaurant, error) {
+	rest, err := scanRestaurant(r.DB.QueryRowContext(ctx,
+		"SELECT "+restaurantColumns+" FROM restaurants WHERE id = $1 AND deleted_at IS NULL", id))
+	if err != nil {
+		return nil, err
+	}
+	return &rest, nil
+}
 
+// GetRestaurantWithDeleted also returns a soft-deleted restaurant, with
+// DeletedAt set.
+func (r *PostgresRepository) GetRestaurantWithDeleted(ctx context.Context, id int) (*domain.Restaurant, error) {
+	rest, err := scanRestaurant(r.DB.QueryRowContext(ctx, "SELECT "+restaurantColumns+" FROM restaurants WHERE id = $1", id))
 	if err != nil {
 		return nil, err
 	}
 	return &rest, nil
 }
 
-func (r *PostgresRepository) UpdateRestaurant(rest *domain.Restaurant) error {
-	return r.DB.QueryRow(
-		"UPDATE restaurants SET name=$1, address=$2, description=$3 WHERE id=$4 RETURNING id, name, address, description, COALESCE(image_url, ''), created_at",
-		rest.Name, rest.Address, rest.Description, rest.ID).
-		Scan(&rest.ID, &rest.Name, &rest.Address, &rest.Description, &rest.ImageURL, &rest.CreatedAt)
+func (r *PostgresRepository) UpdateRestaurant(ctx context.Context, rest *domain.Restaurant) error {
+	hours, err := openingHoursJSON(rest.OpeningHours)
+	if err != nil {
+		return err
+	}
+	updated, err := scanRestaurant(r.DB.QueryRowContext(ctx,
+		"UPDATE restaurants SET name=$1, address=$2, description=$3, public_base_url=NULLIF($4, ''), time_zone=$5, locale=$6, opening_hours=$7 WHERE id=$8 AND deleted_at IS NULL RETURNING "+restaurantColumns,
+		rest.Name, rest.Address, rest.Description, rest.PublicBaseURL, rest.TimeZone, rest.Locale, hours, rest.ID))
+	if err != nil {
+		return err
+	}
+	*rest = updated
+	return nil
 }
 
-func (r *PostgresRepository) DeleteRestaurant(id int) (int64, error) {
-	result, err := r.DB.Exec("DELETE FROM restaurants WHERE id=$1", id)
+// DeleteRestaurant only marks the restaurant as deleted, so its orders and
+// reviews stay in place for analytics. PurgeRestaurant removes it for good.
+func (r *PostgresRepository) DeleteRestaurant(ctx context.Context, id int) (int64, error) {
+	result, err := r.DB.ExecContext(ctx, "UPDATE restaurants SET deleted_at = CURRENT_TIMESTAMP WHERE id=$1 AND deleted_at IS NULL", id)
 	if err != nil {
 		return 0, err
 	}
 	return result.RowsAffected()
 }
 
-func (r *PostgresRepository) UpdateRestaurantImage(id int, imageURL string) error {
-	_, err := r.DB.Exec("UPDATE restaurants SET image_url=$1 WHERE id=$2", imageURL, id)
+func (r *PostgresRepository) RestoreRestaurant(ctx context.Context, id int) (int64, error) {
+	result, err := r.DB.ExecContext(ctx, "UPDATE restaurants SET deleted_at = NULL WHERE id=$1 AND deleted_at IS NOT NULL", id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// PurgeRestaurant hard-deletes a soft-deleted restaurant together with
+// everything that cascades from it: dishes, orders and reviews.
+func (r *PostgresRepository) PurgeRestaurant(ctx context.Context, id int) (int64, error) {
+	result, err := r.DB.ExecContext(ctx, "DELETE FROM restaurants WHERE id=$1 AND deleted_at IS NOT NULL", id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+func (r *PostgresRepository) UpdateRestaurantImage(ctx context.Context, id int, image domain.ImageSet) error {
+	_, err := r.DB.ExecContext(ctx, "UPDATE restaurants SET image_url=$1, image_srcset=$2, image_webp_srcset=$3 WHERE id=$4 AND deleted_at IS NULL",
+		image.URL, image.Srcset, image.WebPSrcset, id)
 	return err
 }
 
-func (r *PostgresRepository) CreateDish(dish *domain.Dish) error {
-	return r.DB.QueryRow(
-		"INSERT INTO dishes (restaurant_id, name, description, price, image_url) VALUES ($1, $2, $3, $4, $5) RETURNING id, created_at",
-		dish.RestaurantID, dish.Name, dish.Description, dish.Price, dish.ImageURL).
-		Scan(&dish.ID, &dish.CreatedAt)
+// ImageInUse reports whether a restaurant or dish, deleted ones included, or
+// a saved menu version still refers to the image.
+func (r *PostgresRepository) ImageInUse(ctx context.Context, imageURL string) (bool, error) {
+	var used bool
+	err := r.DB.QueryRowContext(ctx, `
+		SELECT EXISTS (SELECT 1 FROM restaurants WHERE image_url = $1)
+			OR EXISTS (SELECT 1 FROM dishes WHERE image_url = $1)
+			OR EXISTS (SELECT 1 FROM receipt_settings WHERE logo_url = $1)
+			OR EXISTS (SELECT 1 FROM menu_versions
+				WHERE jsonb_path_exists(snapshot, '$.dishes[*] ? (@.image_url == $url)', jsonb_build_object('url', $1::text)))`,
+		imageURL).Scan(&used)
+	return used, err
+}
+
+// RewriteImageURLs replaces oldPrefix with newPrefix in every stored image
+// URL and srcset, including saved menu versions, in one transaction.
+func (r *PostgresRepository) RewriteImageURLs(ctx context.Context, oldPrefix, newPrefix string) (int64, error) {
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	var total int64
+	for _, query := range []string{
+		`UPDATE restaurants SET image_url = $2 || substr(image_url, length($1) + 1),
+			image_srcset = replace(image_srcset, $1, $2), image_webp_srcset = replace(image_webp_srcset, $1, $2)
+		WHERE starts_with(image_url, $1)`,
+		`UPDATE dishes SET image_url = $2 || substr(image_url, length($1) + 1),
+			image_srcset = replace(image_srcset, $1, $2), image_webp_srcset = replace(image_webp_srcset, $1, $2)
+		WHERE starts_with(image_url, $1)`,
+		`UPDATE receipt_settings SET logo_url = $2 || substr(logo_url, length($1) + 1)
+		WHERE starts_with(logo_url, $1)`,
+		// URLs contain nothing JSON escapes; they start a string or follow
+		// ", " inside a srcset
+		`UPDATE menu_versions
+		SET snapshot = replace(replace(snapshot::text, '"' || $1, '"' || $2), ', ' || $1, ', ' || $2)::jsonb
+		WHERE strpos(snapshot::text, '"' || $1) > 0`,
+	} {
+		result, err := tx.ExecContext(ctx, query, oldPrefix, newPrefix)
+		if err != nil {
+			return 0, err
+		}
+		rows, err := result.RowsAffected()
+		if err != nil {
+			return 0, err
+		}
+		total += rows
+	}
+	return total, tx.Commit()
 }
 
-func (r *PostgresRepository) ListDishes(restaurantID int) ([]domain.Dish, error) {
-	rows, err := r.DB.Query(`
-		SELECT id, restaurant_id, name, description, price, COALESCE(image_url, ''), created_at
+func (r *PostgresRepository) CreateDish(ctx context.Context, dish *domain.Dish) error {
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := insertDish(ctx, tx, dish); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func insertDish(ctx context.Context, tx *sql.Tx, dish *domain.Dish) error {
+	if dish.AvailabilityWindows == nil {
+		dish.AvailabilityWindows = []domain.AvailabilityWindow{}
+	}
+	windows, err := json.Marshal(dish.AvailabilityWindows)
+	if err != nil {
+		return err
+	}
+	nutrition, err := encodeNutrition(dish.Nutrition)
+	if err != nil {
+		return err
+	}
+
+	if err := tx.QueryRowContext(ctx, `
+		INSERT INTO dishes (restaurant_id, category_id, name, description, price, image_url, stop_listed, availability_windows, allergens, dietary_tags, nutrition, external_sku)
+		VALUES ($1, (SELECT id FROM menu_categories WHERE id = $2 AND restaurant_id = $1), $3, $4, $5, $6, $7, $8, $9, $10, $11, NULLIF($12, ''))
+		RETURNING id, category_id, sort_order, created_at`,
+		dish.RestaurantID, dish.CategoryID, dish.Name, dish.Description, dish.Price, dish.ImageURL, dish.StopListed, windows,
+		pq.Array(nonNilStrings(dish.Allergens)), pq.Array(nonNilStrings(dish.DietaryTags)), nutrition, dish.SKU).
+		Scan(&dish.ID, &dish.CategoryID, &dish.SortOrder, &dish.CreatedAt); err != nil {
+		return err
+	}
+	if dish.SKU == "" {
+		// every dish gets a SKU so menu exports can be imported back
+		if err := tx.QueryRowContext(ctx, "UPDATE dishes SET external_sku = 'dish-' || id WHERE id = $1 RETURNING external_sku", dish.ID).
+			Scan(&dish.SKU); err != nil {
+			return err
+		}
+	}
+	return recordPrice(ctx, tx, dish.ID, dish.Price)
+}
+
+// recordPrice appends the dish price to dish_price_history. Every statement
+// that changes dishes.price must call it in the same transaction.
+func recordPrice(ctx context.Context, tx *sql.Tx, dishID int, price float64) error {
+	_, err := tx.ExecContext(ctx, "INSERT INTO dish_price_history (dish_id, price) VALUES ($1, $2)", dishID, price)
+	return err
+}
+
+func (r *PostgresRepository) ListPriceHistory(ctx context.Context, restaurantID, dishID int) ([]domain.PriceChange, error) {
+	rows, err := r.DB.QueryContext(ctx, `
+		SELECT h.dish_id, h.price, h.changed_at
+		FROM dish_price_history h
+		JOIN dishes d ON d.id = h.dish_id
+		WHERE h.dish_id = $1 AND d.restaurant_id = $2
+		ORDER BY h.changed_at DESC, h.id DESC`, dishID, restaurantID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	history := []domain.PriceChange{}
+	for rows.Next() {
+		var change domain.PriceChange
+		if err := rows.Scan(&change.DishID, &change.Price, &change.ChangedAt); err != nil {
+			return nil, err
+		}
+		history = append(history, change)
+	}
+	return history, rows.Err()
+}
+
+func (r *PostgresRepository) ListDishes(ctx context.Context, restaurantID int) ([]domain.Dish, error) {
+	return listDishes(ctx, r.DB, restaurantID)
+}
+
+func listDishes(ctx context.Context, q execer, restaurantID int) ([]domain.Dish, error) {
+	rows, err := q.QueryContext(ctx, `
+		SELECT `+dishColumns+`
 		FROM dishes
-		WHERE restaurant_id = $1
-		ORDER BY created_at DESC`, restaurantID)
+		WHERE restaurant_id = $1 AND `+liveDish+`
+		ORDER BY sort_order, created_at DESC`, restaurantID)
 	if err != nil {
 		return nil, err
 	}
@@ -98,8 +387,8 @@ func (r *PostgresRepository) ListDishes(restaurantID int) ([]domain.Dish, error)
 
 	var dishes []domain.Dish
 	for rows.Next() {
-		var dish domain.Dish
-		if err := rows.Scan(&dish.ID, &dish.RestaurantID, &dish.Name, &dish.Description, &dish.Price, &dish.ImageURL, &dish.CreatedAt); err != nil {
+		dish, err := scanDish(rows)
+		if err != nil {
 			continue
 		}
 		dishes = append(dishes, dish)
@@ -107,91 +396,372 @@ func (r *PostgresRepository) ListDishes(restaurantID int) ([]domain.Dish, error)
 	return dishes, nil
 }
 
-func (r *PostgresRepository) GetDish(restaurantID, dishID int) (*domain.Dish, error) {
-	var dish domain.Dish
-	err := r.DB.QueryRow(
-		"SELECT id, restaurant_id, name, description, price, COALESCE(image_url, ''), created_at FROM dishes WHERE id = $1 AND restaurant_id = $2",
-		dishID, restaurantID).
-		Scan(&dish.ID, &dish.RestaurantID, &dish.Name, &dish.Description, &dish.Price, &dish.ImageURL, &dish.CreatedAt)
+func (r *PostgresRepository) GetDish(ctx context.Context, restaurantID, dishID int) (*domain.Dish, error) {
+	dish, err := scanDish(r.DB.QueryRowContext(ctx,
+		"SELECT "+dishColumns+" FROM dishes WHERE id = $1 AND restaurant_id = $2 AND "+liveDish,
+		dishID, restaurantID))
+	if err != nil {
+		return nil, err
+	}
+	return &dish, nil
+}
+
+// GetDishWithDeleted also returns a soft-deleted dish, with DeletedAt set.
+func (r *PostgresRepository) GetDishWithDeleted(ctx context.Context, restaurantID, dishID int) (*domain.Dish, error) {
+	dish, err := scanDish(r.DB.QueryRowContext(ctx,
+		"SELECT "+dishColumns+" FROM dishes WHERE id = $1 AND restaurant_id = $2",
+		dishID, restaurantID))
 	if err != nil {
 		return nil, err
 	}
 	return &dish, nil
 }
 
-func (r *PostgresRepository) UpdateDish(dish *domain.Dish) error {
-	_, err := r.DB.Exec(`
+func (r *PostgresRepository) ListDeletedDishes(ctx context.Context, restaurantID int) ([]domain.Dish, error) {
+	rows, err := r.DB.QueryContext(ctx, `
+		SELECT `+dishColumns+`
+		FROM dishes
+		WHERE restaurant_id = $1 AND deleted_at IS NOT NULL
+		ORDER BY deleted_at DESC`, restaurantID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	dishes := []domain.Dish{}
+	for rows.Next() {
+		dish, err := scanDish(rows)
+		if err != nil {
+			return nil, err
+		}
+		dishes = append(dishes, dish)
+	}
+	return dishes, rows.Err()
+}
+
+func (r *PostgresRepository) UpdateDish(ctx context.Context, dish *domain.Dish) error {
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := updateDish(ctx, tx, dish); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			// nothing to update, same as the plain UPDATE matching no rows
+			return nil
+		}
+		return err
+	}
+	return tx.Commit()
+}
+
+// updateDish returns sql.ErrNoRows when the dish does not belong to the
+// restaurant.
+func updateDish(ctx context.Context, tx *sql.Tx, dish *domain.Dish) error {
+	nutrition, err := encodeNutrition(dish.Nutrition)
+	if err != nil {
+		return err
+	}
+
+	var oldPrice float64
+	if err := tx.QueryRowContext(ctx, "SELECT price FROM dishes WHERE id=$1 AND restaurant_id=$2 AND deleted_at IS NULL FOR UPDATE", dish.ID, dish.RestaurantID).Scan(&oldPrice); err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, `
 		UPDATE dishes
-		SET name=$1, description=$2, price=$3
-		WHERE id=$4 AND restaurant_id=$5`,
-		dish.Name, dish.Description, dish.Price, dish.ID, dish.RestaurantID)
-	return err
+		SET name=$1, description=$2, price=$3,
+			category_id=(SELECT id FROM menu_categories WHERE id = $4 AND restaurant_id = $6),
+			allergens=$7, dietary_tags=$8, nutrition=$9, external_sku=COALESCE(NULLIF($10, ''), external_sku)
+		WHERE id=$5 AND restaurant_id=$6`,
+		dish.Name, dish.Description, dish.Price, dish.CategoryID, dish.ID, dish.RestaurantID,
+		pq.Array(nonNilStrings(dish.Allergens)), pq.Array(nonNilStrings(dish.DietaryTags)), nutrition, dish.SKU); err != nil {
+		return err
+	}
+	if oldPrice != dish.Price {
+		return recordPrice(ctx, tx, dish.ID, dish.Price)
+	}
+	return nil
 }
 
-func (r *PostgresRepository) DeleteDish(restaurantID, dishID int) (int64, error) {
-	result, err := r.DB.Exec("DELETE FROM dishes WHERE id=$1 AND restaurant_id=$2", dishID, restaurantID)
+func encodeNutrition(facts *domain.NutritionFacts) ([]byte, error) {
+	if facts == nil {
+		return nil, nil
+	}
+	return json.Marshal(facts)
+}
+
+func nonNilStrings(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
+
+// DeleteDish only marks the dish as deleted; past order items and reviews
+// keep pointing at it.
+func (r *PostgresRepository) DeleteDish(ctx context.Context, restaurantID, dishID int) (int64, error) {
+	result, err := r.DB.ExecContext(ctx, "UPDATE dishes SET deleted_at = CURRENT_TIMESTAMP WHERE id=$1 AND restaurant_id=$2 AND deleted_at IS NULL", dishID, restaurantID)
 	if err != nil {
 		return 0, err
 	}
 	return result.RowsAffected()
 }
 
-func (r *PostgresRepository) UpdateDishImage(restaurantID, dishID int, imageURL string) error {
-	_, err := r.DB.Exec("UPDATE dishes SET image_url = $1 WHERE id = $2 AND restaurant_id = $3",
-		imageURL, dishID, restaurantID)
+func (r *PostgresRepository) RestoreDish(ctx context.Context, restaurantID, dishID int) (int64, error) {
+	result, err := r.DB.ExecContext(ctx, "UPDATE dishes SET deleted_at = NULL WHERE id=$1 AND restaurant_id=$2 AND deleted_at IS NOT NULL", dishID, restaurantID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// PurgeDish hard-deletes a soft-deleted dish together with its order items
+// and reviews.
+func (r *PostgresRepository) PurgeDish(ctx context.Context, restaurantID, dishID int) (int64, error) {
+	result, err := r.DB.ExecContext(ctx, "DELETE FROM dishes WHERE id=$1 AND restaurant_id=$2 AND deleted_at IS NOT NULL", dishID, restaurantID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+func (r *PostgresRepository) UpdateDishImage(ctx context.Context, restaurantID, dishID int, image domain.ImageSet) error {
+	_, err := r.DB.ExecContext(ctx, `
+		UPDATE dishes SET image_url = $1, image_srcset = $2, image_webp_srcset = $3
+		WHERE id = $4 AND restaurant_id = $5 AND deleted_at IS NULL`,
+		image.URL, image.Srcset, image.WebPSrcset, dishID, restaurantID)
 	return err
 }
 
-func (r *PostgresRepository) CreateOrder(order *domain.Order) error {
-	tx, err := r.DB.Begin()
+func (r *PostgresRepository) UpdateDishAvailability(ctx context.Context, restaurantID, dishID int, stopListed bool, windows []domain.AvailabilityWindow) (int64, error) {
+	encoded, err := json.Marshal(windows)
+	if err != nil {
+		return 0, err
+	}
+	result, err := r.DB.ExecContext(ctx, `
+		UPDATE dishes SET stop_listed = $1, availability_windows = $2
+		WHERE id = $3 AND restaurant_id = $4 AND deleted_at IS NULL`,
+		stopListed, encoded, dishID, restaurantID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+func (r *PostgresRepository) CreateCategory(ctx context.Context, cat *domain.MenuCategory) error {
+	return insertCategory(ctx, r.DB, cat)
+}
+
+func insertCategory(ctx context.Context, q execer, cat *domain.MenuCategory) error {
+	return q.QueryRowContext(ctx, `
+		INSERT INTO menu_categories (restaurant_id, name, sort_order, visible)
+		VALUES ($1, $2, COALESCE((SELECT MAX(sort_order) + 1 FROM menu_categories WHERE restaurant_id = $1), 0), $3)
+		RETURNING id, sort_order, created_at`,
+		cat.RestaurantID, cat.Name, cat.Visible).
+		Scan(&cat.ID, &cat.SortOrder, &cat.CreatedAt)
+}
+
+func (r *PostgresRepository) ListCategories(ctx context.Context, restaurantID int) ([]domain.MenuCategory, error) {
+	return listCategories(ctx, r.DB, restaurantID)
+}
+
+func listCategories(ctx context.Context, q execer, restaurantID int) ([]domain.MenuCategory, error) {
+	rows, err := q.QueryContext(ctx, `
+		SELECT id, restaurant_id, name, sort_order, visible, created_at
+		FROM menu_categories
+		WHERE restaurant_id = $1
+		ORDER BY sort_order, id`, restaurantID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var categories []domain.MenuCategory
+	for rows.Next() {
+		var cat domain.MenuCategory
+		if err := rows.Scan(&cat.ID, &cat.RestaurantID, &cat.Name, &cat.SortOrder, &cat.Visible, &cat.CreatedAt); err != nil {
+			continue
+		}
+		categories = append(categories, cat)
+	}
+	return categories, nil
+}
+
+func (r *PostgresRepository) GetCategory(ctx context.Context, restaurantID, categoryID int) (*domain.MenuCategory, error) {
+	var cat domain.MenuCategory
+	err := r.DB.QueryRowContext(ctx, `
+		SELECT id, restaurant_id, name, sort_order, visible, created_at
+		FROM menu_categories
+		WHERE id = $1 AND restaurant_id = $2`, categoryID, restaurantID).
+		Scan(&cat.ID, &cat.RestaurantID, &cat.Name, &cat.SortOrder, &cat.Visible, &cat.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &cat, nil
+}
+
+func (r *PostgresRepository) UpdateCategory(ctx context.Context, cat *domain.MenuCategory) error {
+	return updateCategory(ctx, r.DB, cat)
+}
+
+func updateCategory(ctx context.Context, q execer, cat *domain.MenuCategory) error {
+	return q.QueryRowContext(ctx, `
+		UPDATE menu_categories SET name=$1, visible=$2
+		WHERE id=$3 AND restaurant_id=$4
+		RETURNING sort_order, created_at`,
+		cat.Name, cat.Visible, cat.ID, cat.RestaurantID).
+		Scan(&cat.SortOrder, &cat.CreatedAt)
+}
+
+func (r *PostgresRepository) DeleteCategory(ctx context.Context, restaurantID, categoryID int) (int64, error) {
+	result, err := r.DB.ExecContext(ctx, "DELETE FROM menu_categories WHERE id=$1 AND restaurant_id=$2", categoryID, restaurantID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+func (r *PostgresRepository) ReorderCategories(ctx context.Context, restaurantID int, categoryIDs []int) error {
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for position, id := range categoryIDs {
+		result, err := tx.ExecContext(ctx, "UPDATE menu_categories SET sort_order=$1 WHERE id=$2 AND restaurant_id=$3", position, id, restaurantID)
+		if err != nil {
+			return err
+		}
+		if affected, _ := result.RowsAffected(); affected == 0 {
+			return fmt.Errorf("category %d: %w", id, sql.ErrNoRows)
+		}
+	}
+	return tx.Commit()
+}
+
+// ReorderDishes sets the order of dishes inside a category. Dishes listed here
+// are moved into the category if they belonged to another one.
+func (r *PostgresRepository) ReorderDishes(ctx context.Context, restaurantID, categoryID int, dishIDs []int) error {
+	tx, err := r.DB.BeginTx(ctx, nil)
 	if err != nil {
 		return err
 	}
 	defer tx.Rollback()
 
-	if err := tx.QueryRow(`
-		INSERT INTO orders (restaurant_id, total_amount, status, qr_code)
-		VALUES ($1, $2, 'completed', NULL)
-		RETURNING id, created_at
-	`, order.RestaurantID, order.TotalAmount).Scan(&order.ID, &order.CreatedAt); err != nil {
+	var exists bool
+	if err := tx.QueryRowContext(ctx, "SELECT EXISTS(SELECT 1 FROM menu_categories WHERE id=$1 AND restaurant_id=$2)", categoryID, restaurantID).Scan(&exists); err != nil {
 		return err
 	}
+	if !exists {
+		return fmt.Errorf("category %d: %w", categoryID, sql.ErrNoRows)
+	}
 
-	for _, item := range order.Items {
-		if _, err := tx.Exec(`
+	for position, id := range dishIDs {
+		result, err := tx.ExecContext(ctx, "UPDATE dishes SET sort_order=$1, category_id=$2 WHERE id=$3 AND restaurant_id=$4", position, categoryID, id, restaurantID)
+		if err != nil {
+			return err
+		}
+		if affected, _ := result.RowsAffected(); affected == 0 {
+			return fmt.Errorf("dish %d: %w", id, sql.ErrNoRows)
+		}
+	}
+	return tx.Commit()
+}
+
+func (r *PostgresRepository) CreateOrder(ctx context.Context, order *domain.Order) error {
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := tx.QueryRowContext(ctx, `
+		INSERT INTO orders (restaurant_id, table_id, total_amount, status, qr_code, menu_version)
+		VALUES ($1, $2, $3, 'completed', NULL, COALESCE((SELECT menu_version FROM restaurants WHERE id = $1), 0))
+		RETURNING id, menu_version, created_at
+	`, order.RestaurantID, order.TableID, order.TotalAmount).Scan(&order.ID, &order.MenuVersion, &order.CreatedAt); err != nil {
+		return err
+	}
+
+	for i := range order.Items {
+		item := &order.Items[i]
+		if err := tx.QueryRowContext(ctx, `
 			INSERT INTO order_items (order_id, dish_id, quantity, price)
 			VALUES ($1, $2, $3, $4)
-		`, order.ID, item.DishID, item.Quantity, item.Price); err != nil {
+			RETURNING id
+		`, order.ID, item.DishID, item.Quantity, item.Price).Scan(&item.ID); err != nil {
 			return err
 		}
+		for _, option := range item.Options {
+			if _, err := tx.ExecContext(ctx, `
+				INSERT INTO order_item_options (order_item_id, option_id, group_name, name, price_delta)
+				VALUES ($1, $2, $3, $4, $5)
+			`, item.ID, option.OptionID, option.GroupName, option.Name, option.PriceDelta); err != nil {
+				return err
+			}
+		}
 	}
 
 	return tx.Commit()
 }
 
-func (r *PostgresRepository) SaveQRCode(orderID int, qr []byte) error {
-	_, err := r.DB.Exec(`UPDATE orders SET qr_code = $1 WHERE id = $2`, qr, orderID)
+func (r *PostgresRepository) GetDishesByIDs(ctx context.Context, dishIDs []int) (map[int]domain.Dish, error) {
+	rows, err := r.DB.QueryContext(ctx, `
+		SELECT `+dishColumns+`
+		FROM dishes
+		WHERE id = ANY($1) AND `+liveDish, pq.Array(dishIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	dishes := make(map[int]domain.Dish, len(dishIDs))
+	for rows.Next() {
+		dish, err := scanDish(rows)
+		if err != nil {
+			return nil, err
+		}
+		dishes[dish.ID] = dish
+	}
+	return dishes, rows.Err()
+}
+
+func (r *PostgresRepository) SaveQRCode(ctx context.Context, orderID int, qr []byte) error {
+	_, err := r.DB.ExecContext(ctx, `UPDATE orders SET qr_code = $1 WHERE id = $2`, qr, orderID)
 	return err
 }
 
-func (r *PostgresRepository) GetOrder(orderID int) (*domain.Order, []domain.OrderItem, error) {
+// selectOrders is the head of every order query, so GetOrder and ListOrders
+// fill an order the same way; soft-deleted restaurants keep their name on
+// old orders.
+const selectOrders = `
+		SELECT o.id, o.restaurant_id, COALESCE(r.name, ''), o.table_id, COALESCE(t.label, ''), COALESCE(o.total_amount, 0),
+		       o.status, o.menu_version, o.created_at
+		FROM orders o
+		LEFT JOIN restaurants r ON r.id = o.restaurant_id
+		LEFT JOIN tables t ON t.id = o.table_id`
+
+func scanOrder(row interface{ Scan(...any) error }, order *domain.Order) error {
+	return row.Scan(&order.ID, &order.RestaurantID, &order.RestaurantName, &order.TableID, &order.TableLabel,
+		&order.TotalAmount, &order.Status, &order.MenuVersion, &order.CreatedAt)
+}
+
+func (r *PostgresRepository) GetOrder(ctx context.Context, orderID int) (*domain.Order, []domain.OrderItem, error) {
 	var order domain.Order
-	if err := r.DB.QueryRow(`
-		SELECT id, restaurant_id, total_amount, status, created_at
-		FROM orders WHERE id = $1
-	`, orderID).Scan(&order.ID, &order.RestaurantID, &order.TotalAmount, &order.Status, &order.CreatedAt); err != nil {
+	if err := scanOrder(r.DB.QueryRowContext(ctx, selectOrders+`
+		WHERE o.id = $1`, orderID), &order); err != nil {
 		return nil, nil, err
 	}
 
-	var restaurantName string
-	r.DB.QueryRow("SELECT name FROM restaurants WHERE id = $1", order.RestaurantID).Scan(&restaurantName)
-	order.RestaurantName = restaurantName
-
-	rows, err := r.DB.Query(`
-		SELECT oi.dish_id, d.name, oi.quantity, oi.price
+	rows, err := r.DB.QueryContext(ctx, `
+		SELECT oi.id, oi.dish_id, d.name, oi.quantity, oi.price
 		FROM order_items oi
 		JOIN dishes d ON oi.dish_id = d.id
 		WHERE oi.order_id = $1
+		ORDER BY oi.id
 	`, orderID)
 	if err != nil {
 		return &order, nil, err
@@ -199,23 +769,85 @@ func (r *PostgresRepository) GetOrder(orderID int) (*domain.Order, []domain.Orde
 	defer rows.Close()
 
 	var items []domain.OrderItem
+	itemIndex := make(map[int]int)
 	for rows.Next() {
 		var item domain.OrderItem
-		if err := rows.Scan(&item.DishID, &item.DishName, &item.Quantity, &item.Price); err != nil {
+		if err := rows.Scan(&item.ID, &item.DishID, &item.DishName, &item.Quantity, &item.Price); err != nil {
 			continue
 		}
+		itemIndex[item.ID] = len(items)
 		items = append(items, item)
 	}
 
+	optionRows, err := r.DB.QueryContext(ctx, `
+		SELECT oio.order_item_id, COALESCE(oio.option_id, 0), oio.group_name, oio.name, oio.price_delta
+		FROM order_item_options oio
+		JOIN order_items oi ON oi.id = oio.order_item_id
+		WHERE oi.order_id = $1
+		ORDER BY oio.id
+	`, orderID)
+	if err != nil {
+		return &order, items, err
+	}
+	defer optionRows.Close()
+
+	for optionRows.Next() {
+		var itemID int
+		var option domain.OrderItemOption
+		if err := optionRows.Scan(&itemID, &option.OptionID, &option.GroupName, &option.Name, &option.PriceDelta); err != nil {
+			continue
+		}
+		if idx, ok := itemIndex[itemID]; ok {
+			items[idx].Options = append(items[idx].Options, option)
+		}
+	}
+
 	return &order, items, nil
 }
 
-func (r *PostgresRepository) ListOrders() ([]domain.Order, error) {
-	rows, err := r.DB.Query(`
-		SELECT id, restaurant_id, total_amount, status, created_at
-		FROM orders
-		ORDER BY created_at DESC
-	`)
+var orderSortColumns = map[string]sortColumn{
+	"created_at":   {expr: "o.created_at", cast: "timestamp"},
+	"total_amount": {expr: "COALESCE(o.total_amount, 0)", cast: "numeric"},
+}
+
+func (r *PostgresRepository) ListOrders(ctx context.Context, filter domain.OrderFilter) ([]domain.Order, error) {
+	var where []string
+	var args []interface{}
+	add := func(condition string, arg interface{}) {
+		args = append(args, arg)
+		where = append(where, strings.ReplaceAll(condition, "$?", placeholder(args)))
+	}
+	if filter.RestaurantID > 0 {
+		add("o.restaurant_id = $?", filter.RestaurantID)
+	}
+	if filter.Status != "" {
+		add("o.status = $?", filter.Status)
+	}
+	if filter.From != nil {
+		add("o.created_at >= $?", filter.From.UTC())
+	}
+	if filter.To != nil {
+		add("o.created_at < $?", filter.To.UTC())
+	}
+	if filter.MinTotal != nil {
+		add("COALESCE(o.total_amount, 0) >= $?", *filter.MinTotal)
+	}
+	after, orderBy, args, err := keyset(orderSortColumns, "o.id", filter.Sort, filter.After, args)
+	if err != nil {
+		return nil, err
+	}
+	if after != "" {
+		where = append(where, after)
+	}
+	if len(where) == 0 {
+		where = append(where, "TRUE")
+	}
+	args = append(args, filter.Limit)
+
+	rows, err := r.DB.QueryContext(ctx, selectOrders+`
+		WHERE `+strings.Join(where, " AND ")+`
+		ORDER BY `+orderBy+`
+		LIMIT `+placeholder(args), args...)
 	if err != nil {
 		return nil, err
 	}
@@ -224,36 +856,36 @@ func (r *PostgresRepository) ListOrders() ([]domain.Order, error) {
 	var orders []domain.Order
 	for rows.Next() {
 		var order domain.Order
-		if err := rows.Scan(&order.ID, &order.RestaurantID, &order.TotalAmount, &order.Status, &order.CreatedAt); err != nil {
-			continue
+		if err := scanOrder(rows, &order); err != nil {
+			return nil, err
 		}
-
-		var restaurantName string
-		r.DB.QueryRow("SELECT name FROM restaurants WHERE id = $1", order.RestaurantID).Scan(&restaurantName)
-		order.RestaurantName = restaurantName
-
 		orders = append(orders, order)
 	}
-	return orders, nil
+	return orders, rows.Err()
 }
 
-func (r *PostgresRepository) GetQRCode(orderID int) ([]byte, error) {
+func (r *PostgresRepository) GetQRCode(ctx context.Context, orderID int) ([]byte, error) {
 	var qrCode []byte
-	if err := r.DB.QueryRow("SELECT qr_code FROM orders WHERE id = $1", orderID).Scan(&qrCode); err != nil {
+	if err := r.DB.QueryRowContext(ctx, "SELECT qr_code FROM orders WHERE id = $1", orderID).Scan(&qrCode); err != nil {
 		return nil, err
 	}
 	return qrCode, nil
 }
 
-func (r *PostgresRepository) EnsureSchema() error {
-	statements := []string{
-		"ALTER TABLE IF EXISTS restaurants ADD COLUMN IF NOT EXISTS description TEXT",
-		"ALTER TABLE IF EXISTS orders ADD COLUMN IF NOT EXISTS qr_code BYTEA",
+func (r *PostgresRepository) ListOrderIDs(ctx context.Context, restaurantID int) (map[int][]int, error) {
+	rows, err := r.DB.QueryContext(ctx, "SELECT id, restaurant_id FROM orders WHERE $1 = 0 OR restaurant_id = $1 ORDER BY id", restaurantID)
+	if err != nil {
+		return nil, err
 	}
-	for _, stmt := range statements {
-		if _, err := r.DB.Exec(stmt); err != nil {
-			return fmt.Errorf("ensure schema `%s`: %w", stmt, err)
+	defer rows.Close()
+
+	orderIDs := make(map[int][]int)
+	for rows.Next() {
+		var id, rid int
+		if err := rows.Scan(&id, &rid); err != nil {
+			return nil, err
 		}
+		orderIDs[rid] = append(orderIDs[rid], id)
 	}
-	return nil
+	return orderIDs, rows.Err()
 }
@@ -0,0 +1,37 @@
+package storage
+
+import (
+	"context"
+	"overcooked-simplified/dish-svc/internal/domain"
+)
+
+// GetReceiptSettings returns sql.ErrNoRows when the restaurant has not
+// customised its receipts yet.
+func (r *PostgresRepository) GetReceiptSettings(ctx context.Context, restaurantID int) (*domain.ReceiptSettings, error) {
+	settings := domain.ReceiptSettings{RestaurantID: restaurantID}
+	err := r.DB.QueryRowContext(ctx, `
+		SELECT qr_size, qr_level, qr_color, background_color, accent_color, COALESCE(logo_url, ''), COALESCE(footer_text, ''),
+		       paper_width, printer_qr
+		FROM receipt_settings WHERE restaurant_id = $1`, restaurantID).
+		Scan(&settings.QRSize, &settings.QRLevel, &settings.QRColor, &settings.BackgroundColor,
+			&settings.AccentColor, &settings.LogoURL, &settings.FooterText, &settings.PaperWidth, &settings.PrinterQR)
+	if err != nil {
+		return nil, err
+	}
+	return &settings, nil
+}
+
+func (r *PostgresRepository) SaveReceiptSettings(ctx context.Context, settings *domain.ReceiptSettings) error {
+	_, err := r.DB.ExecContext(ctx, `
+		INSERT INTO receipt_settings (restaurant_id, qr_size, qr_level, qr_color, background_color, accent_color,
+			logo_url, footer_text, paper_width, printer_qr)
+		VALUES ($1, $2, $3, $4, $5, $6, NULLIF($7, ''), NULLIF($8, ''), $9, $10)
+		ON CONFLICT (restaurant_id) DO UPDATE SET
+			qr_size = EXCLUDED.qr_size, qr_level = EXCLUDED.qr_level, qr_color = EXCLUDED.qr_color,
+			background_color = EXCLUDED.background_color, accent_color = EXCLUDED.accent_color,
+			logo_url = EXCLUDED.logo_url, footer_text = EXCLUDED.footer_text,
+			paper_width = EXCLUDED.paper_width, printer_qr = EXCLUDED.printer_qr`,
+		settings.RestaurantID, settings.QRSize, settings.QRLevel, settings.QRColor, settings.BackgroundColor,
+		settings.AccentColor, settings.LogoURL, settings.FooterText, settings.PaperWidth, settings.PrinterQR)
+	return err
+}
@@ -0,0 +1,151 @@
+package storage
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"overcooked-simplified/dish-svc/internal/domain"
+
+	"github.com/lib/pq"
+)
+
+// searchQuery matches the text both as Russian and as English words, so
+// "пельмени" finds "пельменей" and "dumpling" finds "Dumplings".
+const searchQuery = "websearch_to_tsquery('russian', $1) || websearch_to_tsquery('english', $1)"
+
+// Search ranks restaurants and dishes by full-text rank plus trigram
+// similarity of the name, which is what catches typos like "пелмени".
+// Dish filters narrow the dishes, and the restaurants to those that still have
+// a matching dish. Stop-listed dishes are left out.
+func (r *PostgresRepository) Search(ctx context.Context, query domain.SearchQuery) (*domain.SearchResults, error) {
+	restaurants, err := r.searchRestaurants(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	dishes, err := r.searchDishes(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.SearchResults{Restaurants: restaurants, Dishes: dishes}, nil
+}
+
+func (r *PostgresRepository) searchRestaurants(ctx context.Context, query domain.SearchQuery) ([]domain.RestaurantHit, error) {
+	args := []interface{}{query.Text}
+	where := []string{
+		"r.deleted_at IS NULL",
+		"(r.search_vector @@ q.ts OR r.name % $1 OR $1 <% r.name)",
+	}
+	if query.RestaurantID > 0 {
+		args = append(args, query.RestaurantID)
+		where = append(where, "r.id = $"+strconv.Itoa(len(args)))
+	}
+	if dishFilters, filterArgs := dishFilterSQL("d", query, len(args)); len(dishFilters) > 0 {
+		args = append(args, filterArgs...)
+		where = append(where, "EXISTS (SELECT 1 FROM dishes d WHERE d.restaurant_id = r.id AND d.deleted_at IS NULL AND NOT d.stop_listed AND "+
+			strings.Join(dishFilters, " AND ")+")")
+	}
+	args = append(args, query.Limit)
+
+	rows, err := r.DB.QueryContext(ctx, `
+		SELECT r.id, r.name, COALESCE(r.address, ''), COALESCE(r.description, ''), COALESCE(r.image_url, ''),
+		       ts_rank(r.search_vector, q.ts) + GREATEST(similarity(r.name, $1), word_similarity($1, r.name)) AS score
+		FROM restaurants r
+		CROSS JOIN (SELECT `+searchQuery+` AS ts) q
+		WHERE `+strings.Join(where, " AND ")+`
+		ORDER BY score DESC, r.id
+		LIMIT $`+strconv.Itoa(len(args)), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var hits []domain.RestaurantHit
+	for rows.Next() {
+		var hit domain.RestaurantHit
+		if err := rows.Scan(&hit.ID, &hit.Name, &hit.Address, &hit.Description, &hit.ImageURL, &hit.Score); err != nil {
+			return nil, err
+		}
+		hits = append(hits, hit)
+	}
+	return hits, rows.Err()
+}
+
+func (r *PostgresRepository) searchDishes(ctx context.Context, query domain.SearchQuery) ([]domain.DishHit, error) {
+	args := []interface{}{query.Text}
+	where := []string{
+		"d.deleted_at IS NULL",
+		"r.deleted_at IS NULL",
+		"NOT d.stop_listed",
+		"(d.search_vector @@ q.ts OR d.name % $1 OR $1 <% d.name OR tr.similarity >= 0.6)",
+	}
+	if query.RestaurantID > 0 {
+		args = append(args, query.RestaurantID)
+		where = append(where, "d.restaurant_id = $"+strconv.Itoa(len(args)))
+	}
+	dishFilters, filterArgs := dishFilterSQL("d", query, len(args))
+	args = append(args, filterArgs...)
+	where = append(where, dishFilters...)
+	args = append(args, query.Limit)
+
+	// translated names count for the typo match too, so tourists find dishes
+	// by the names they see on the menu
+	rows, err := r.DB.QueryContext(ctx, `
+		SELECT d.id, d.restaurant_id, r.name, d.name, COALESCE(d.description, ''), d.price,
+		       COALESCE(d.avg_rating, 0), d.allergens, COALESCE(d.image_url, ''),
+		       ts_rank(d.search_vector, q.ts)
+		         + GREATEST(similarity(d.name, $1), word_similarity($1, d.name), COALESCE(tr.similarity, 0)) AS score
+		FROM dishes d
+		JOIN restaurants r ON r.id = d.restaurant_id
+		CROSS JOIN (SELECT `+searchQuery+` AS ts) q
+		LEFT JOIN LATERAL (
+			SELECT MAX(word_similarity($1, t.name)) AS similarity
+			FROM dish_translations t
+			WHERE t.dish_id = d.id
+		) tr ON TRUE
+		WHERE `+strings.Join(where, " AND ")+`
+		ORDER BY score DESC, d.id
+		LIMIT $`+strconv.Itoa(len(args)), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var hits []domain.DishHit
+	for rows.Next() {
+		var hit domain.DishHit
+		var allergens pq.StringArray
+		if err := rows.Scan(&hit.ID, &hit.RestaurantID, &hit.RestaurantName, &hit.Name, &hit.Description, &hit.Price,
+			&hit.AvgRating, &allergens, &hit.ImageURL, &hit.Score); err != nil {
+			return nil, err
+		}
+		hit.Allergens = []string(allergens)
+		hits = append(hits, hit)
+	}
+	return hits, rows.Err()
+}
+
+// dishFilterSQL turns the dish filters of the query into conditions on the
+// dishes table aliased as alias. Placeholders continue after the argCount
+// arguments the caller already has.
+func dishFilterSQL(alias string, query domain.SearchQuery, argCount int) ([]string, []interface{}) {
+	var conditions []string
+	var args []interface{}
+	add := func(condition string, arg interface{}) {
+		args = append(args, arg)
+		conditions = append(conditions, strings.ReplaceAll(condition, "$?", "$"+strconv.Itoa(argCount+len(args))))
+	}
+	if query.MinPrice != nil {
+		add(alias+".price >= $?", *query.MinPrice)
+	}
+	if query.MaxPrice != nil {
+		add(alias+".price <= $?", *query.MaxPrice)
+	}
+	if query.MinRating != nil {
+		add("COALESCE("+alias+".avg_rating, 0) >= $?", *query.MinRating)
+	}
+	if len(query.ExcludeAllergens) > 0 {
+		add("NOT "+alias+".allergens && $?::text[]", pq.Array(query.ExcludeAllergens))
+	}
+	return conditions, args
+}
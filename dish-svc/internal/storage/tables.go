@@ -0,0 +1,82 @@
+package storage
+
+import (
+	"context"
+	"overcooked-simplified/dish-svc/internal/domain"
+)
+
+const tableColumns = "id, restaurant_id, label, section, code, created_at"
+
+func scanTable(row rowScanner) (*domain.Table, error) {
+	var table domain.Table
+	if err := row.Scan(&table.ID, &table.RestaurantID, &table.Label, &table.Section, &table.Code, &table.CreatedAt); err != nil {
+		return nil, err
+	}
+	return &table, nil
+}
+
+func (r *PostgresRepository) CreateTable(ctx context.Context, table *domain.Table) error {
+	return r.DB.QueryRowContext(ctx, `
+		INSERT INTO tables (restaurant_id, label, section, code)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, created_at`,
+		table.RestaurantID, table.Label, table.Section, table.Code).
+		Scan(&table.ID, &table.CreatedAt)
+}
+
+func (r *PostgresRepository) ListTables(ctx context.Context, restaurantID int) ([]domain.Table, error) {
+	rows, err := r.DB.QueryContext(ctx, "SELECT "+tableColumns+" FROM tables WHERE restaurant_id = $1 ORDER BY section, label, id", restaurantID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tables []domain.Table
+	for rows.Next() {
+		table, err := scanTable(rows)
+		if err != nil {
+			return nil, err
+		}
+		tables = append(tables, *table)
+	}
+	return tables, rows.Err()
+}
+
+func (r *PostgresRepository) GetTable(ctx context.Context, restaurantID, tableID int) (*domain.Table, error) {
+	return scanTable(r.DB.QueryRowContext(ctx, "SELECT "+tableColumns+" FROM tables WHERE id = $1 AND restaurant_id = $2", tableID, restaurantID))
+}
+
+// GetTableByCode finds the table of a scanned QR code together with the name
+// of its restaurant; tables of deleted restaurants are not found.
+func (r *PostgresRepository) GetTableByCode(ctx context.Context, code string) (*domain.Table, error) {
+	var table domain.Table
+	err := r.DB.QueryRowContext(ctx, `
+		SELECT t.id, t.restaurant_id, rs.name, t.label, t.section, t.code, t.created_at
+		FROM tables t
+		JOIN restaurants rs ON rs.id = t.restaurant_id
+		WHERE t.code = $1 AND rs.deleted_at IS NULL`, code).
+		Scan(&table.ID, &table.RestaurantID, &table.RestaurantName, &table.Label, &table.Section, &table.Code, &table.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &table, nil
+}
+
+func (r *PostgresRepository) UpdateTable(ctx context.Context, table *domain.Table) error {
+	return r.DB.QueryRowContext(ctx, `
+		UPDATE tables SET label = $1, section = $2
+		WHERE id = $3 AND restaurant_id = $4
+		RETURNING code, created_at`,
+		table.Label, table.Section, table.ID, table.RestaurantID).
+		Scan(&table.Code, &table.CreatedAt)
+}
+
+// DeleteTable keeps the orders and visit ratings of the table; they lose the
+// reference to it.
+func (r *PostgresRepository) DeleteTable(ctx context.Context, restaurantID, tableID int) (int64, error) {
+	result, err := r.DB.ExecContext(ctx, "DELETE FROM tables WHERE id = $1 AND restaurant_id = $2", tableID, restaurantID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
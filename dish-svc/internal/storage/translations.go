@@ -0,0 +1,47 @@
+package storage
+
+import (
+	"context"
+	"overcooked-simplified/dish-svc/internal/domain"
+
+	"github.com/lib/pq"
+)
+
+func (r *PostgresRepository) ListDishTranslations(ctx context.Context, dishIDs []int) (map[int][]domain.DishTranslation, error) {
+	rows, err := r.DB.QueryContext(ctx, `
+		SELECT dish_id, lang, name, description
+		FROM dish_translations
+		WHERE dish_id = ANY($1)
+		ORDER BY dish_id, lang`, pq.Array(dishIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	translations := make(map[int][]domain.DishTranslation)
+	for rows.Next() {
+		var tr domain.DishTranslation
+		if err := rows.Scan(&tr.DishID, &tr.Lang, &tr.Name, &tr.Description); err != nil {
+			return nil, err
+		}
+		translations[tr.DishID] = append(translations[tr.DishID], tr)
+	}
+	return translations, rows.Err()
+}
+
+func (r *PostgresRepository) UpsertDishTranslation(ctx context.Context, tr *domain.DishTranslation) error {
+	_, err := r.DB.ExecContext(ctx, `
+		INSERT INTO dish_translations (dish_id, lang, name, description)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (dish_id, lang) DO UPDATE SET name = EXCLUDED.name, description = EXCLUDED.description`,
+		tr.DishID, tr.Lang, tr.Name, tr.Description)
+	return err
+}
+
+func (r *PostgresRepository) DeleteDishTranslation(ctx context.Context, dishID int, lang string) (int64, error) {
+	result, err := r.DB.ExecContext(ctx, "DELETE FROM dish_translations WHERE dish_id = $1 AND lang = $2", dishID, lang)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
@@ -2,6 +2,7 @@ package tests
 
 import (
 	"bytes"
+	"database/sql"
 	"errors"
 	"net/http"
 	"net/http/httptest"
@@ -9,7 +10,9 @@ import (
 	"overcooked-simplified/dish-svc/internal/domain"
 	"overcooked-simplified/dish-svc/internal/mocks"
 	"overcooked-simplified/dish-svc/internal/service"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/gorilla/mux"
 	"github.com/stretchr/testify/assert"
@@ -27,7 +30,7 @@ func TestCreateRestaurantHandler(t *testing.T) {
 			name: "valid request",
 			body: `{"name":"Test","address":"Addr","description":"Desc"}`,
 			setupMock: func(m *mocks.RestaurantRepository) {
-				m.On("CreateRestaurant", mock.AnythingOfType("*domain.Restaurant")).Return(nil).Once()
+				m.On("CreateRestaurant", mock.Anything, mock.AnythingOfType("*domain.Restaurant")).Return(nil).Once()
 			},
 			wantCode: http.StatusOK,
 		},
@@ -37,11 +40,17 @@ func TestCreateRestaurantHandler(t *testing.T) {
 			setupMock: func(m *mocks.RestaurantRepository) {},
 			wantCode:  http.StatusBadRequest,
 		},
+		{
+			name:      "invalid public base URL",
+			body:      `{"name":"Test","public_base_url":"plov.example"}`,
+			setupMock: func(m *mocks.RestaurantRepository) {},
+			wantCode:  http.StatusBadRequest,
+		},
 		{
 			name: "database error",
 			body: `{"name":"Test"}`,
 			setupMock: func(m *mocks.RestaurantRepository) {
-				m.On("CreateRestaurant", mock.AnythingOfType("*domain.Restaurant")).Return(errors.New("db error")).Once()
+				m.On("CreateRestaurant", mock.Anything, mock.AnythingOfType("*domain.Restaurant")).Return(errors.New("db error")).Once()
 			},
 			wantCode: http.StatusInternalServerError,
 		},
@@ -50,8 +59,8 @@ func TestCreateRestaurantHandler(t *testing.T) {
 	for _, testCase := range tests {
 		t.Run(testCase.name, func(t *testing.T) {
 			mockRepo := new(mocks.RestaurantRepository)
-			restService := service.NewRestaurantService(mockRepo)
-			handler := httpapi.NewHandler(restService, nil, nil)
+			restService := service.NewRestaurantService(mockRepo, nil)
+			handler := httpapi.NewHandler(httpapi.Deps{Restaurants: restService})
 
 			testCase.setupMock(mockRepo)
 
@@ -94,13 +103,13 @@ func TestGetRestaurantHandler(t *testing.T) {
 	for _, testCase := range tests {
 		t.Run(testCase.name, func(t *testing.T) {
 			mockRepo := new(mocks.RestaurantRepository)
-			restService := service.NewRestaurantService(mockRepo)
-			handler := httpapi.NewHandler(restService, nil, nil)
+			restService := service.NewRestaurantService(mockRepo, nil)
+			handler := httpapi.NewHandler(httpapi.Deps{Restaurants: restService})
 
 			if testCase.mockError != nil {
-				mockRepo.On("GetRestaurant", mock.Anything).Return(nil, testCase.mockError).Once()
+				mockRepo.On("GetRestaurant", mock.Anything, mock.Anything).Return(nil, testCase.mockError).Once()
 			} else {
-				mockRepo.On("GetRestaurant", mock.Anything).Return(testCase.mockRest, nil).Once()
+				mockRepo.On("GetRestaurant", mock.Anything, mock.Anything).Return(testCase.mockRest, nil).Once()
 			}
 
 			req := httptest.NewRequest("GET", "/api/restaurants/"+testCase.id, nil)
@@ -115,3 +124,142 @@ func TestGetRestaurantHandler(t *testing.T) {
 		})
 	}
 }
+
+func TestGetRestaurantDishesHandler_DietaryFilters(t *testing.T) {
+	tests := []struct {
+		name      string
+		query     string
+		wantCode  int
+		wantDish  string
+		setupMock bool
+	}{
+		{
+			name:      "exclude allergens",
+			query:     "?exclude_allergens=milk",
+			wantCode:  http.StatusOK,
+			wantDish:  "Плов",
+			setupMock: true,
+		},
+		{
+			name:     "unknown allergen",
+			query:    "?exclude_allergens=pineapple",
+			wantCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			mockRepo := new(mocks.DishRepository)
+			dishService := service.NewDishService(mockRepo, nil)
+			handler := httpapi.NewHandler(httpapi.Deps{Dishes: dishService})
+
+			if testCase.setupMock {
+				mockRepo.On("ListDishes", mock.Anything, 1).Return([]domain.Dish{
+					{ID: 1, Name: "Хачапури", Allergens: []string{"gluten", "milk"}},
+					{ID: 2, Name: "Плов"},
+				}, nil).Once()
+			}
+
+			req := httptest.NewRequest("GET", "/api/restaurants/1/dishes"+testCase.query, nil)
+			w := httptest.NewRecorder()
+
+			r := mux.NewRouter()
+			handler.RegisterRoutes(r)
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, testCase.wantCode, w.Code)
+			if testCase.wantDish != "" {
+				assert.Contains(t, w.Body.String(), testCase.wantDish)
+				assert.NotContains(t, w.Body.String(), "Хачапури")
+			}
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestGetDishPricesHandler(t *testing.T) {
+	tests := []struct {
+		name     string
+		dishErr  error
+		wantCode int
+	}{
+		{name: "history", wantCode: http.StatusOK},
+		{name: "unknown dish", dishErr: sql.ErrNoRows, wantCode: http.StatusNotFound},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			mockRepo := new(mocks.DishRepository)
+			handler := httpapi.NewHandler(httpapi.Deps{Dishes: service.NewDishService(mockRepo, nil)})
+
+			if testCase.dishErr != nil {
+				mockRepo.On("GetDish", mock.Anything, 1, 7).Return(nil, testCase.dishErr).Once()
+			} else {
+				changedAt := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+				mockRepo.On("GetDish", mock.Anything, 1, 7).Return(&domain.Dish{ID: 7, RestaurantID: 1, Price: 390}, nil).Once()
+				mockRepo.On("ListPriceHistory", mock.Anything, 1, 7).Return([]domain.PriceChange{
+					{DishID: 7, Price: 390, ChangedAt: changedAt},
+					{DishID: 7, Price: 350, ChangedAt: changedAt.AddDate(0, -1, 0)},
+				}, nil).Once()
+			}
+
+			req := httptest.NewRequest("GET", "/api/restaurants/1/dishes/7/prices", nil)
+			w := httptest.NewRecorder()
+
+			r := mux.NewRouter()
+			handler.RegisterRoutes(r)
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, testCase.wantCode, w.Code)
+			if testCase.wantCode == http.StatusOK {
+				assert.Contains(t, w.Body.String(), `"price":390`)
+				assert.Contains(t, w.Body.String(), `"price":350`)
+			}
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUpdateDishHandler_Category(t *testing.T) {
+	tests := []struct {
+		name         string
+		body         string
+		wantCode     int
+		wantCategory int
+	}{
+		{name: "category not sent is kept", body: `{"name":"Плов","price":420}`, wantCode: http.StatusOK, wantCategory: 3},
+		{name: "category of another restaurant", body: `{"name":"Плов","price":420,"category_id":8}`, wantCode: http.StatusBadRequest},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			mockRepo := new(mocks.DishRepository)
+			handler := httpapi.NewHandler(httpapi.Deps{Dishes: service.NewDishService(mockRepo, nil)})
+
+			category := 3
+			mockRepo.On("GetDish", mock.Anything, 1, 7).Return(&domain.Dish{ID: 7, RestaurantID: 1, Name: "Плов", Price: 390, CategoryID: &category}, nil)
+			mockRepo.On("GetCategory", mock.Anything, 1, 3).Return(&domain.MenuCategory{ID: 3, RestaurantID: 1}, nil)
+			mockRepo.On("GetCategory", mock.Anything, 1, 8).Return(nil, sql.ErrNoRows)
+			var updated *domain.Dish
+			mockRepo.On("UpdateDish", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+				updated = args.Get(1).(*domain.Dish)
+			}).Return(nil)
+
+			req := httptest.NewRequest("PUT", "/api/restaurants/1/dishes/7", strings.NewReader(testCase.body))
+			w := httptest.NewRecorder()
+
+			r := mux.NewRouter()
+			handler.RegisterRoutes(r)
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, testCase.wantCode, w.Code)
+			if testCase.wantCode != http.StatusOK {
+				mockRepo.AssertNotCalled(t, "UpdateDish", mock.Anything)
+				return
+			}
+			if assert.NotNil(t, updated) && assert.NotNil(t, updated.CategoryID) {
+				assert.Equal(t, testCase.wantCategory, *updated.CategoryID)
+			}
+		})
+	}
+}
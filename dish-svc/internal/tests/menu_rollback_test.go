@@ -0,0 +1,78 @@
+package tests
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"testing"
+
+	"overcooked-simplified/database"
+	"overcooked-simplified/dish-svc/internal/domain"
+	"overcooked-simplified/dish-svc/internal/storage"
+
+	_ "github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// openTestDB connects to the database in TEST_POSTGRES_DSN and migrates it.
+// Tests that need Postgres are skipped without one.
+func openTestDB(t *testing.T) *sql.DB {
+	dsn := os.Getenv("TEST_POSTGRES_DSN")
+	if dsn == "" {
+		t.Skip("TEST_POSTGRES_DSN is not set")
+	}
+	db, err := sql.Open("postgres", dsn)
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	_, err = database.NewMigrator(db).Up(context.Background())
+	require.NoError(t, err)
+	return db
+}
+
+func TestRollbackMenu_SKUReusedByNewerDish(t *testing.T) {
+	db := openTestDB(t)
+	repo := storage.NewPostgresRepository(db)
+
+	rest := &domain.Restaurant{Name: "Rollback", Address: "Test", TimeZone: "UTC", Locale: "en"}
+	require.NoError(t, repo.CreateRestaurant(context.Background(), rest))
+	t.Cleanup(func() {
+		repo.DeleteRestaurant(context.Background(), rest.ID)
+		repo.PurgeRestaurant(context.Background(), rest.ID)
+	})
+
+	soup := &domain.Dish{RestaurantID: rest.ID, SKU: "soup", Name: "Soup", Price: 300}
+	salad := &domain.Dish{RestaurantID: rest.ID, SKU: "salad", Name: "Salad", Price: 250}
+	require.NoError(t, repo.CreateDish(context.Background(), soup))
+	require.NoError(t, repo.CreateDish(context.Background(), salad))
+	published, err := repo.PublishMenuDraft(context.Background(), rest.ID)
+	require.NoError(t, err)
+
+	// the soup is replaced by a new dish under the same SKU, and the salad
+	// gets a new SKU
+	_, err = repo.DeleteDish(context.Background(), rest.ID, soup.ID)
+	require.NoError(t, err)
+	newSoup := &domain.Dish{RestaurantID: rest.ID, SKU: "soup", Name: "New soup", Price: 350}
+	require.NoError(t, repo.CreateDish(context.Background(), newSoup))
+	salad.SKU = "salad-2"
+	require.NoError(t, repo.UpdateDish(context.Background(), salad))
+
+	_, err = repo.RollbackMenu(context.Background(), rest.ID, published.Version)
+	require.NoError(t, err)
+
+	dishes, err := repo.ListDishes(context.Background(), rest.ID)
+	require.NoError(t, err)
+	skus := map[int]string{}
+	for _, dish := range dishes {
+		skus[dish.ID] = dish.SKU
+	}
+	assert.Equal(t, map[int]string{soup.ID: "soup", salad.ID: "salad"}, skus)
+
+	var sku string
+	var deleted bool
+	require.NoError(t, db.QueryRow("SELECT external_sku, deleted_at IS NOT NULL FROM dishes WHERE id = $1", newSoup.ID).
+		Scan(&sku, &deleted))
+	assert.True(t, deleted, "dishes added after the version are soft-deleted")
+	assert.Equal(t, fmt.Sprintf("soup~%d", newSoup.ID), sku, "and give up their SKU")
+}
@@ -0,0 +1,51 @@
+package tests
+
+import (
+	"context"
+	"testing"
+
+	"overcooked-simplified/dish-svc/internal/domain"
+	"overcooked-simplified/dish-svc/internal/storage"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpdateModifierGroup_KeepsOptionIDs(t *testing.T) {
+	db := openTestDB(t)
+	repo := storage.NewPostgresRepository(db)
+
+	rest := &domain.Restaurant{Name: "Modifiers", Address: "Test", TimeZone: "UTC", Locale: "en"}
+	require.NoError(t, repo.CreateRestaurant(context.Background(), rest))
+	t.Cleanup(func() {
+		repo.DeleteRestaurant(context.Background(), rest.ID)
+		repo.PurgeRestaurant(context.Background(), rest.ID)
+	})
+	dish := &domain.Dish{RestaurantID: rest.ID, Name: "Coffee", Price: 200}
+	require.NoError(t, repo.CreateDish(context.Background(), dish))
+
+	group := &domain.ModifierGroup{DishID: dish.ID, Name: "Size", MinSelect: 1, MaxSelect: 1, Options: []domain.ModifierOption{
+		{Name: "Small"},
+		{Name: "Medium", PriceDelta: 40},
+		{Name: "Large", PriceDelta: 80},
+	}}
+	require.NoError(t, repo.CreateModifierGroup(context.Background(), group))
+	small, medium := group.Options[0].ID, group.Options[1].ID
+
+	// rename medium, drop large, add extra large
+	group.Options = []domain.ModifierOption{
+		{ID: small, Name: "Small"},
+		{ID: medium, Name: "Regular", PriceDelta: 50},
+		{Name: "Extra large", PriceDelta: 120},
+	}
+	require.NoError(t, repo.UpdateModifierGroup(context.Background(), group))
+
+	groups, err := repo.ListModifierGroups(context.Background(), []int{dish.ID})
+	require.NoError(t, err)
+	require.Len(t, groups[dish.ID], 1)
+	options := map[int]string{}
+	for _, option := range groups[dish.ID][0].Options {
+		options[option.ID] = option.Name
+	}
+	assert.Equal(t, map[int]string{small: "Small", medium: "Regular", group.Options[2].ID: "Extra large"}, options)
+}
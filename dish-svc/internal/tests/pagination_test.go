@@ -0,0 +1,164 @@
+package tests
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	httpapi "overcooked-simplified/dish-svc/internal/api/http"
+	"overcooked-simplified/dish-svc/internal/domain"
+	"overcooked-simplified/dish-svc/internal/mocks"
+	"overcooked-simplified/dish-svc/internal/service"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestOrderService_ListPages(t *testing.T) {
+	created := time.Date(2024, 5, 1, 12, 30, 0, 123456000, time.UTC)
+	repo := new(mocks.OrderRepository)
+	svc := service.NewOrderService(repo, nil, service.ReviewLinks{})
+
+	// a page of two asks for three rows to see whether more follow
+	repo.On("ListOrders", mock.Anything, domain.OrderFilter{RestaurantID: 7, Sort: "-created_at", Limit: 3}).Return([]domain.Order{
+		{ID: 9, CreatedAt: created.Add(time.Hour)},
+		{ID: 8, CreatedAt: created},
+		{ID: 5, CreatedAt: created},
+	}, nil).Once()
+
+	orders, next, err := svc.List(context.Background(), domain.OrderFilter{RestaurantID: 7, Limit: 2})
+
+	assert.NoError(t, err)
+	assert.Len(t, orders, 2)
+	assert.NotEmpty(t, next)
+
+	after, err := service.DecodeCursor("-created_at", next)
+	assert.NoError(t, err)
+	assert.Equal(t, &domain.PageCursor{Value: created.Format(time.RFC3339Nano), ID: 8}, after)
+
+	repo.On("ListOrders", mock.Anything, domain.OrderFilter{RestaurantID: 7, Sort: "-created_at", Limit: 3, Cursor: next, After: after}).
+		Return([]domain.Order{{ID: 5, CreatedAt: created}}, nil).Once()
+
+	orders, next, err = svc.List(context.Background(), domain.OrderFilter{RestaurantID: 7, Limit: 2, Cursor: next})
+
+	assert.NoError(t, err)
+	assert.Len(t, orders, 1)
+	assert.Empty(t, next)
+	repo.AssertExpectations(t)
+}
+
+func TestOrderService_ListRejectsBadPages(t *testing.T) {
+	minTotal := -1.0
+	from := time.Date(2024, 5, 2, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, -1)
+	tests := []struct {
+		name   string
+		filter domain.OrderFilter
+	}{
+		{name: "unknown sort", filter: domain.OrderFilter{Sort: "qr_code"}},
+		{name: "negative limit", filter: domain.OrderFilter{Limit: -1}},
+		{name: "negative min total", filter: domain.OrderFilter{MinTotal: &minTotal}},
+		{name: "empty date range", filter: domain.OrderFilter{From: &from, To: &to}},
+		{name: "garbage cursor", filter: domain.OrderFilter{Cursor: "not a cursor"}},
+		{name: "cursor of another sort", filter: domain.OrderFilter{Sort: "total_amount",
+			Cursor: service.EncodeCursor("-created_at", domain.PageCursor{Value: from.Format(time.RFC3339Nano), ID: 1})}},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			repo := new(mocks.OrderRepository)
+			svc := service.NewOrderService(repo, nil, service.ReviewLinks{})
+
+			_, _, err := svc.List(context.Background(), testCase.filter)
+
+			assert.ErrorIs(t, err, service.ErrInvalidPage)
+			repo.AssertNotCalled(t, "ListOrders", mock.Anything)
+		})
+	}
+}
+
+func TestRestaurantService_ListCapsLimit(t *testing.T) {
+	repo := new(mocks.RestaurantRepository)
+	svc := service.NewRestaurantService(repo, nil)
+	repo.On("ListRestaurants", mock.Anything, domain.RestaurantFilter{Name: "чай", Sort: "name", Limit: 501}).
+		Return([]domain.Restaurant{{ID: 1, Name: "Чайхона"}}, nil).Once()
+
+	restaurants, next, err := svc.List(context.Background(), domain.RestaurantFilter{Name: " чай ", Sort: "name", Limit: 10000})
+
+	assert.NoError(t, err)
+	assert.Len(t, restaurants, 1)
+	assert.Empty(t, next)
+	repo.AssertExpectations(t)
+}
+
+func TestGetOrdersHandler_Filters(t *testing.T) {
+	tests := []struct {
+		name       string
+		url        string
+		setupMock  func(*mocks.OrderServiceInterface)
+		wantStatus int
+		wantCursor string
+	}{
+		{
+			name: "filters and next page",
+			url:  "/api/orders?restaurant_id=7&status=pending&from=2024-05-01&to=2024-05-31&min_total=500&sort=-total_amount&limit=20&cursor=abc",
+			setupMock: func(svc *mocks.OrderServiceInterface) {
+				from := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+				to := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+				minTotal := 500.0
+				svc.On("List", mock.Anything, domain.OrderFilter{RestaurantID: 7, Status: "pending", From: &from, To: &to, MinTotal: &minTotal,
+					Sort: "-total_amount", Limit: 20, Cursor: "abc"}).
+					Return([]domain.Order{{ID: 1, RestaurantName: "Чайхона"}}, "next-page", nil)
+			},
+			wantStatus: http.StatusOK,
+			wantCursor: "next-page",
+		},
+		{name: "bad date", url: "/api/orders?from=yesterday", wantStatus: http.StatusBadRequest},
+		{
+			name: "bad page",
+			url:  "/api/orders?sort=qr_code",
+			setupMock: func(svc *mocks.OrderServiceInterface) {
+				svc.On("List", mock.Anything, mock.Anything).Return(nil, "", service.ErrInvalidPage)
+			},
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			svc := new(mocks.OrderServiceInterface)
+			if testCase.setupMock != nil {
+				testCase.setupMock(svc)
+			}
+			handler := httpapi.NewHandler(httpapi.Deps{Orders: svc})
+			r := mux.NewRouter()
+			handler.RegisterRoutes(r)
+
+			rr := httptest.NewRecorder()
+			r.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, testCase.url, nil))
+
+			assert.Equal(t, testCase.wantStatus, rr.Code)
+			assert.Equal(t, testCase.wantCursor, rr.Header().Get("X-Next-Cursor"))
+			svc.AssertExpectations(t)
+		})
+	}
+}
+
+func TestGetRestaurantsHandler_EmptyPage(t *testing.T) {
+	svc := new(mocks.RestaurantServiceInterface)
+	hasImage := true
+	svc.On("List", mock.Anything, domain.RestaurantFilter{HasImage: &hasImage}).Return(nil, "", nil)
+	handler := httpapi.NewHandler(httpapi.Deps{Restaurants: svc})
+	r := mux.NewRouter()
+	handler.RegisterRoutes(r)
+
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/restaurants?has_image=true", nil))
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, "[]", rr.Body.String())
+	assert.Empty(t, rr.Header().Get("X-Next-Cursor"))
+}
@@ -0,0 +1,186 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/xml"
+	"flag"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"os"
+	"overcooked-simplified/dish-svc/internal/domain"
+	"overcooked-simplified/dish-svc/internal/mocks"
+	"overcooked-simplified/dish-svc/internal/service"
+	"overcooked-simplified/dish-svc/internal/storage"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func testOrder() (*domain.Order, []domain.OrderItem) {
+	order := &domain.Order{
+		ID: 42, RestaurantID: 7, RestaurantName: `Чайхана "Самарканд" & Co`, TotalAmount: 730,
+		CreatedAt: time.Date(2026, 3, 1, 12, 30, 0, 0, time.UTC),
+	}
+	items := []domain.OrderItem{
+		{DishID: 1, DishName: "Плов", Quantity: 2, Price: 290,
+			Options: []domain.OrderItemOption{{GroupName: "Размер", Name: "Большой", PriceDelta: 40}}},
+		{DishID: 2, DishName: "Чай <зелёный>", Quantity: 1, Price: 150},
+	}
+	return order, items
+}
+
+func TestReceiptService_Render(t *testing.T) {
+	files := storage.NewFileBlobStore(t.TempDir(), "/uploads", "", nil)
+	logo := image.NewNRGBA(image.Rect(0, 0, 40, 20))
+	for x := 0; x < 40; x++ {
+		logo.Set(x, 10, color.NRGBA{R: 200, A: 255})
+	}
+	var logoPNG bytes.Buffer
+	require.NoError(t, png.Encode(&logoPNG, logo))
+	require.NoError(t, files.Put("logo.png", "image/png", &logoPNG))
+
+	order, items := testOrder()
+	orders := new(mocks.OrderRepository)
+	orders.On("GetOrder", mock.Anything, 42).Return(order, items, nil)
+	orders.On("GetOrder", mock.Anything, 404).Return(nil, nil, sql.ErrNoRows)
+	orders.On("GetRestaurantWithDeleted", mock.Anything, 7).Return(&domain.Restaurant{ID: 7, PublicBaseURL: "https://samarkand.example"}, nil)
+	repo := new(mocks.ReceiptRepository)
+	settings := service.DefaultReceiptSettings(7)
+	settings.QRLevel, settings.QRColor, settings.LogoURL, settings.FooterText = "H", "#1e3a8a", files.URL("logo.png"), "Спасибо!"
+	repo.On("GetReceiptSettings", mock.Anything, 7).Return(settings, nil)
+	svc := service.NewReceiptService(repo, orders, nil, nil, files, service.ReviewLinks{BaseURL: "https://cafe.example"})
+
+	html, contentType, err := svc.Render(context.Background(), 42, service.ReceiptHTML)
+	require.NoError(t, err)
+	assert.Equal(t, "text/html; charset=utf-8", contentType)
+	assert.Contains(t, string(html), "Чайхана &#34;Самарканд&#34; &amp; Co")
+	assert.Contains(t, string(html), "Чай &lt;зелёный&gt;")
+	assert.Contains(t, string(html), "580.00 ₽")
+	assert.Contains(t, string(html), "Размер: Большой")
+	assert.Contains(t, string(html), "https://samarkand.example/r/16")
+	assert.Contains(t, string(html), `fill="#1e3a8a"`)
+	assert.Contains(t, string(html), "data:image/png;base64,")
+
+	svg, contentType, err := svc.Render(context.Background(), 42, service.ReceiptSVG)
+	require.NoError(t, err)
+	assert.Equal(t, "image/svg+xml", contentType)
+	decoder := xml.NewDecoder(bytes.NewReader(svg))
+	for {
+		_, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err, "receipt SVG must be well-formed")
+	}
+	assert.Contains(t, string(svg), "730.00 ₽")
+
+	pdf, contentType, err := svc.Render(context.Background(), 42, service.ReceiptPDF)
+	require.NoError(t, err)
+	assert.Equal(t, "application/pdf", contentType)
+	assert.True(t, bytes.HasPrefix(pdf, []byte("%PDF-")))
+	assert.True(t, bytes.Contains(pdf, []byte("/Subtype /Image")), "logo is embedded")
+
+	_, _, err = svc.Render(context.Background(), 42, "docx")
+	assert.ErrorIs(t, err, service.ErrUnsupportedReceiptFormat)
+	_, _, err = svc.Render(context.Background(), 404, service.ReceiptPDF)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func TestReceiptService_UpdateSettings(t *testing.T) {
+	files := storage.NewFileBlobStore(t.TempDir(), "/uploads", "", nil)
+	tests := []struct {
+		name     string
+		settings domain.ReceiptSettings
+		wantErr  string
+	}{
+		{name: "defaults", settings: domain.ReceiptSettings{}},
+		{name: "size too small", settings: domain.ReceiptSettings{QRSize: 10}, wantErr: "qr_size"},
+		{name: "unknown level", settings: domain.ReceiptSettings{QRLevel: "X"}, wantErr: "qr_level"},
+		{name: "bad color", settings: domain.ReceiptSettings{AccentColor: "red"}, wantErr: "#rrggbb"},
+		{name: "light modules", settings: domain.ReceiptSettings{QRColor: "#ffffff", BackgroundColor: "#000000"}, wantErr: "darker"},
+		{name: "low contrast", settings: domain.ReceiptSettings{QRColor: "#999999", BackgroundColor: "#bbbbbb"}, wantErr: "darker"},
+		{name: "logo needs high level", settings: domain.ReceiptSettings{QRLevel: "m", LogoURL: "/uploads/logo.png"}, wantErr: "Q or H"},
+		{name: "logo from elsewhere", settings: domain.ReceiptSettings{QRLevel: "H", LogoURL: "https://evil.example/logo.png"}, wantErr: "uploaded image"},
+		{name: "logo", settings: domain.ReceiptSettings{QRLevel: "q", LogoURL: "/uploads/logo.png", QRColor: "#1E3A8A"}},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			restaurants := new(mocks.RestaurantRepository)
+			restaurants.On("GetRestaurant", mock.Anything, 7).Return(&domain.Restaurant{ID: 7}, nil)
+			repo := new(mocks.ReceiptRepository)
+			repo.On("GetReceiptSettings", mock.Anything, 7).Return(nil, sql.ErrNoRows)
+			svc := service.NewReceiptService(repo, nil, restaurants, nil, files, service.ReviewLinks{})
+
+			settings := testCase.settings
+			settings.RestaurantID = 7
+			if testCase.wantErr == "" {
+				repo.On("SaveReceiptSettings", mock.Anything, &settings).Return(nil).Once()
+			}
+			err := svc.UpdateSettings(context.Background(), &settings)
+
+			if testCase.wantErr != "" {
+				assert.ErrorIs(t, err, service.ErrInvalidReceiptSettings)
+				assert.True(t, strings.Contains(err.Error(), testCase.wantErr), err.Error())
+				repo.AssertNotCalled(t, "SaveReceiptSettings")
+				return
+			}
+			assert.NoError(t, err)
+			assert.Regexp(t, `^[LMQH]$`, settings.QRLevel)
+			assert.Regexp(t, `^#[0-9a-f]{6}$`, settings.QRColor)
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+var updateGolden = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+// TestReceiptService_RenderESCPOS compares the printer byte stream with
+// golden files; run with -update after an intended change and check the
+// result on a printer.
+func TestReceiptService_RenderESCPOS(t *testing.T) {
+	tests := []struct {
+		golden     string
+		paperWidth int
+		printerQR  string
+	}{
+		{golden: "receipt_80_native.escpos", paperWidth: 80, printerQR: service.PrinterQRNative},
+		{golden: "receipt_58_raster.escpos", paperWidth: 58, printerQR: service.PrinterQRRaster},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.golden, func(t *testing.T) {
+			order, items := testOrder()
+			orders := new(mocks.OrderRepository)
+			orders.On("GetOrder", mock.Anything, 42).Return(order, items, nil)
+			orders.On("GetRestaurantWithDeleted", mock.Anything, 7).Return(&domain.Restaurant{ID: 7}, nil)
+			settings := service.DefaultReceiptSettings(7)
+			settings.PaperWidth, settings.PrinterQR, settings.FooterText = testCase.paperWidth, testCase.printerQR, "Спасибо!"
+			repo := new(mocks.ReceiptRepository)
+			repo.On("GetReceiptSettings", mock.Anything, 7).Return(settings, nil)
+			svc := service.NewReceiptService(repo, orders, nil, nil, nil, service.ReviewLinks{BaseURL: "https://cafe.example"})
+
+			got, contentType, err := svc.Render(context.Background(), 42, service.ReceiptESCPOS)
+			require.NoError(t, err)
+			assert.Equal(t, "application/octet-stream", contentType)
+
+			path := filepath.Join("testdata", testCase.golden)
+			if *updateGolden {
+				require.NoError(t, os.MkdirAll("testdata", 0755))
+				require.NoError(t, os.WriteFile(path, got, 0644))
+			}
+			want, err := os.ReadFile(path)
+			require.NoError(t, err)
+			assert.Equal(t, want, got)
+		})
+	}
+}
@@ -0,0 +1,133 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	httpapi "overcooked-simplified/dish-svc/internal/api/http"
+	"overcooked-simplified/dish-svc/internal/domain"
+	"overcooked-simplified/dish-svc/internal/mocks"
+	"overcooked-simplified/dish-svc/internal/service"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestSearchService_Search(t *testing.T) {
+	price := func(v float64) *float64 { return &v }
+	tests := []struct {
+		name    string
+		query   domain.SearchQuery
+		want    domain.SearchQuery
+		wantErr error
+	}{
+		{
+			name:  "defaults",
+			query: domain.SearchQuery{Text: "  плов   с  бараниной "},
+			want:  domain.SearchQuery{Text: "плов с бараниной", ExcludeAllergens: []string{}, Limit: 20},
+		},
+		{
+			name:  "filters",
+			query: domain.SearchQuery{Text: "pilaf", MinPrice: price(100), MaxPrice: price(500), MinRating: price(4), ExcludeAllergens: []string{" Gluten"}, RestaurantID: 7, Limit: 500},
+			want:  domain.SearchQuery{Text: "pilaf", MinPrice: price(100), MaxPrice: price(500), MinRating: price(4), ExcludeAllergens: []string{"gluten"}, RestaurantID: 7, Limit: 50},
+		},
+		{name: "empty text", query: domain.SearchQuery{Text: " "}, wantErr: service.ErrInvalidSearch},
+		{name: "negative price", query: domain.SearchQuery{Text: "плов", MinPrice: price(-1)}, wantErr: service.ErrInvalidSearch},
+		{name: "min above max", query: domain.SearchQuery{Text: "плов", MinPrice: price(500), MaxPrice: price(100)}, wantErr: service.ErrInvalidSearch},
+		{name: "rating above five", query: domain.SearchQuery{Text: "плов", MinRating: price(6)}, wantErr: service.ErrInvalidSearch},
+		{name: "unknown allergen", query: domain.SearchQuery{Text: "плов", ExcludeAllergens: []string{"kryptonite"}}, wantErr: service.ErrInvalidSearch},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			repo := new(mocks.SearchRepository)
+			svc := service.NewSearchService(repo)
+			if testCase.wantErr == nil {
+				repo.On("Search", mock.Anything, testCase.want).Return(&domain.SearchResults{}, nil).Once()
+			}
+
+			results, err := svc.Search(context.Background(), testCase.query)
+
+			if testCase.wantErr != nil {
+				assert.ErrorIs(t, err, testCase.wantErr)
+				repo.AssertNotCalled(t, "Search", mock.Anything)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.want.Text, results.Query)
+			assert.NotNil(t, results.Restaurants)
+			assert.NotNil(t, results.Dishes)
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestSearchHandler(t *testing.T) {
+	tests := []struct {
+		name       string
+		url        string
+		setupMock  func(*mocks.SearchServiceInterface)
+		wantStatus int
+	}{
+		{
+			name: "filters from the query string",
+			url:  "/api/search?q=плов&min_price=100&max_price=500.5&min_rating=4&exclude_allergens=gluten,nuts&restaurant_id=7&limit=5",
+			setupMock: func(svc *mocks.SearchServiceInterface) {
+				svc.On("Search", mock.Anything, mock.MatchedBy(func(q domain.SearchQuery) bool {
+					return q.Text == "плов" && *q.MinPrice == 100 && *q.MaxPrice == 500.5 && *q.MinRating == 4 &&
+						assert.ObjectsAreEqual([]string{"gluten", "nuts"}, q.ExcludeAllergens) && q.RestaurantID == 7 && q.Limit == 5
+				})).Return(&domain.SearchResults{
+					Query:  "плов",
+					Dishes: []domain.DishHit{{ID: 1, RestaurantID: 7, RestaurantName: "Чайхона", Name: "Плов", Score: 1.2}},
+				}, nil)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{name: "malformed price", url: "/api/search?q=плов&min_price=cheap", wantStatus: http.StatusBadRequest},
+		{name: "not a number", url: "/api/search?q=плов&min_rating=NaN", wantStatus: http.StatusBadRequest},
+		{
+			name: "invalid query",
+			url:  "/api/search",
+			setupMock: func(svc *mocks.SearchServiceInterface) {
+				svc.On("Search", mock.Anything, mock.Anything).Return(nil, service.ErrInvalidSearch)
+			},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name: "storage failure",
+			url:  "/api/search?q=плов",
+			setupMock: func(svc *mocks.SearchServiceInterface) {
+				svc.On("Search", mock.Anything, mock.Anything).Return(nil, errors.New("db down"))
+			},
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			svc := new(mocks.SearchServiceInterface)
+			if testCase.setupMock != nil {
+				testCase.setupMock(svc)
+			}
+			handler := httpapi.NewHandler(httpapi.Deps{Search: svc})
+			r := mux.NewRouter()
+			handler.RegisterRoutes(r)
+
+			rr := httptest.NewRecorder()
+			r.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, testCase.url, nil))
+
+			assert.Equal(t, testCase.wantStatus, rr.Code)
+			if testCase.wantStatus == http.StatusOK {
+				var results domain.SearchResults
+				assert.NoError(t, json.NewDecoder(rr.Body).Decode(&results))
+				assert.Equal(t, "Чайхона", results.Dishes[0].RestaurantName)
+			}
+			svc.AssertExpectations(t)
+		})
+	}
+}
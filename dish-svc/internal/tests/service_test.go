@@ -1,10 +1,23 @@
 package tests
 
 import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"os"
 	"overcooked-simplified/dish-svc/internal/domain"
 	"overcooked-simplified/dish-svc/internal/mocks"
 	"overcooked-simplified/dish-svc/internal/service"
+	"overcooked-simplified/dish-svc/internal/storage"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -39,11 +52,11 @@ func TestRestaurantService_Create(t *testing.T) {
 	for _, testCase := range tests {
 		t.Run(testCase.name, func(t *testing.T) {
 			mockRepo := new(mocks.RestaurantRepository)
-			svc := service.NewRestaurantService(mockRepo)
+			svc := service.NewRestaurantService(mockRepo, nil)
 
-			mockRepo.On("CreateRestaurant", testCase.input).Return(testCase.mockError).Once()
+			mockRepo.On("CreateRestaurant", mock.Anything, testCase.input).Return(testCase.mockError).Once()
 
-			err := svc.Create(testCase.input)
+			err := svc.Create(context.Background(), testCase.input)
 
 			if testCase.wantErr {
 				assert.Error(t, err)
@@ -55,6 +68,57 @@ func TestRestaurantService_Create(t *testing.T) {
 	}
 }
 
+func TestRestaurantService_Purge(t *testing.T) {
+	deletedAt := time.Now()
+	tests := []struct {
+		name    string
+		rest    *domain.Restaurant
+		confirm string
+		purge   bool
+		wantErr error
+	}{
+		{
+			name:    "not soft-deleted",
+			rest:    &domain.Restaurant{ID: 1, Name: "Pizza"},
+			confirm: "Pizza",
+			wantErr: service.ErrNotDeleted,
+		},
+		{
+			name:    "wrong confirmation",
+			rest:    &domain.Restaurant{ID: 1, Name: "Pizza", DeletedAt: &deletedAt},
+			confirm: "pizza",
+			wantErr: service.ErrPurgeNotConfirmed,
+		},
+		{
+			name:    "confirmed",
+			rest:    &domain.Restaurant{ID: 1, Name: "Pizza", DeletedAt: &deletedAt},
+			confirm: "Pizza",
+			purge:   true,
+		},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			mockRepo := new(mocks.RestaurantRepository)
+			svc := service.NewRestaurantService(mockRepo, nil)
+
+			mockRepo.On("GetRestaurantWithDeleted", mock.Anything, 1).Return(testCase.rest, nil).Once()
+			if testCase.purge {
+				mockRepo.On("PurgeRestaurant", mock.Anything, 1).Return(int64(1), nil).Once()
+			}
+
+			err := svc.Purge(context.Background(), 1, testCase.confirm)
+
+			if testCase.wantErr != nil {
+				assert.ErrorIs(t, err, testCase.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
 func TestDishService_Get(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -83,15 +147,15 @@ func TestDishService_Get(t *testing.T) {
 	for _, testCase := range tests {
 		t.Run(testCase.name, func(t *testing.T) {
 			mockRepo := new(mocks.DishRepository)
-			svc := service.NewDishService(mockRepo)
+			svc := service.NewDishService(mockRepo, nil)
 
 			if testCase.mockError != nil {
-				mockRepo.On("GetDish", testCase.restID, testCase.dishID).Return(nil, testCase.mockError).Once()
+				mockRepo.On("GetDish", mock.Anything, testCase.restID, testCase.dishID).Return(nil, testCase.mockError).Once()
 			} else {
-				mockRepo.On("GetDish", testCase.restID, testCase.dishID).Return(testCase.mockDish, nil).Once()
+				mockRepo.On("GetDish", mock.Anything, testCase.restID, testCase.dishID).Return(testCase.mockDish, nil).Once()
 			}
 
-			result, err := svc.Get(testCase.restID, testCase.dishID)
+			result, err := svc.Get(context.Background(), testCase.restID, testCase.dishID)
 
 			if testCase.wantErr {
 				assert.Error(t, err)
@@ -104,11 +168,59 @@ func TestDishService_Get(t *testing.T) {
 	}
 }
 
+func TestDishService_CategoryMustBelongToRestaurant(t *testing.T) {
+	own, foreign := 3, 8
+	tests := []struct {
+		name       string
+		categoryID *int
+		wantErr    error
+	}{
+		{name: "no category", categoryID: nil},
+		{name: "own category", categoryID: &own},
+		{name: "category of another restaurant", categoryID: &foreign, wantErr: service.ErrUnknownCategory},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			mockRepo := new(mocks.DishRepository)
+			svc := service.NewDishService(mockRepo, nil)
+			mockRepo.On("GetCategory", mock.Anything, 1, 3).Return(&domain.MenuCategory{ID: 3, RestaurantID: 1}, nil)
+			mockRepo.On("GetCategory", mock.Anything, 1, 8).Return(nil, sql.ErrNoRows)
+			mockRepo.On("CreateDish", mock.Anything, mock.Anything).Return(nil)
+			mockRepo.On("UpdateDish", mock.Anything, mock.Anything).Return(nil)
+
+			createErr := svc.Create(context.Background(), &domain.Dish{RestaurantID: 1, Name: "Плов", CategoryID: testCase.categoryID})
+			updateErr := svc.Update(context.Background(), &domain.Dish{ID: 5, RestaurantID: 1, Name: "Плов", CategoryID: testCase.categoryID})
+
+			if testCase.wantErr != nil {
+				assert.ErrorIs(t, createErr, testCase.wantErr)
+				assert.ErrorIs(t, updateErr, testCase.wantErr)
+				mockRepo.AssertNotCalled(t, "CreateDish", mock.Anything)
+				mockRepo.AssertNotCalled(t, "UpdateDish", mock.Anything)
+				return
+			}
+			assert.NoError(t, createErr)
+			assert.NoError(t, updateErr)
+		})
+	}
+}
+
 func TestOrderService_CreateValidation(t *testing.T) {
+	dishes := map[int]domain.Dish{
+		1: {ID: 1, RestaurantID: 1, Name: "Плов", Price: 350},
+		2: {ID: 2, RestaurantID: 2, Name: "Ролл", Price: 420},
+		3: {ID: 3, RestaurantID: 1, Name: "Лагман", Price: 320, StopListed: true},
+		4: {ID: 4, RestaurantID: 1, Name: "Пицца", Price: 450},
+	}
+	modifiers := map[int][]domain.ModifierGroup{
+		4: pizzaModifiers(),
+	}
+
 	tests := []struct {
-		name    string
-		order   *domain.Order
-		wantErr bool
+		name         string
+		order        *domain.Order
+		wantErr      bool
+		wantItemErrs int
 	}{
 		{
 			name:    "invalid: no restaurant ID",
@@ -120,9 +232,42 @@ func TestOrderService_CreateValidation(t *testing.T) {
 			order:   &domain.Order{RestaurantID: 1, Items: []domain.OrderItem{}},
 			wantErr: true,
 		},
+		{
+			name:         "invalid: non-positive quantity",
+			order:        &domain.Order{RestaurantID: 1, Items: []domain.OrderItem{{DishID: 1, Quantity: 0}}},
+			wantErr:      true,
+			wantItemErrs: 1,
+		},
+		{
+			name:         "invalid: dish from another restaurant and unknown dish",
+			order:        &domain.Order{RestaurantID: 1, Items: []domain.OrderItem{{DishID: 2, Quantity: 1}, {DishID: 99, Quantity: 1}}},
+			wantErr:      true,
+			wantItemErrs: 2,
+		},
+		{
+			name:         "invalid: stop-listed dish",
+			order:        &domain.Order{RestaurantID: 1, Items: []domain.OrderItem{{DishID: 3, Quantity: 1}}},
+			wantErr:      true,
+			wantItemErrs: 1,
+		},
+		{
+			name:         "invalid: required size not selected",
+			order:        &domain.Order{RestaurantID: 1, Items: []domain.OrderItem{{DishID: 4, Quantity: 1}}},
+			wantErr:      true,
+			wantItemErrs: 1,
+		},
+		{
+			name: "invalid: two sizes and an option of another dish",
+			order: &domain.Order{RestaurantID: 1, Items: []domain.OrderItem{
+				{DishID: 4, Quantity: 1, Options: []domain.OrderItemOption{{OptionID: 10}, {OptionID: 11}}},
+				{DishID: 1, Quantity: 1, Options: []domain.OrderItemOption{{OptionID: 20}}},
+			}},
+			wantErr:      true,
+			wantItemErrs: 2,
+		},
 		{
 			name:    "valid order",
-			order:   &domain.Order{RestaurantID: 1, Items: []domain.OrderItem{{DishID: 1}}},
+			order:   &domain.Order{RestaurantID: 1, Items: []domain.OrderItem{{DishID: 1, Quantity: 1}}},
 			wantErr: false,
 		},
 	}
@@ -131,18 +276,26 @@ func TestOrderService_CreateValidation(t *testing.T) {
 		t.Run(testCase.name, func(t *testing.T) {
 			mockRepo := new(mocks.OrderRepository)
 			mockQR := new(mocks.QRGenerator)
-			svc := service.NewOrderService(mockRepo, mockQR)
+			svc := service.NewOrderService(mockRepo, mockQR, service.ReviewLinks{BaseURL: "https://overcooked.example"})
 
+			mockRepo.On("GetDishesByIDs", mock.Anything, mock.Anything).Return(dishes, nil)
+			mockRepo.On("ListModifierGroups", mock.Anything, mock.Anything).Return(modifiers, nil)
+			mockRepo.On("GetRestaurantWithDeleted", mock.Anything, 1).Return(&domain.Restaurant{ID: 1}, nil)
 			if !testCase.wantErr {
-				mockRepo.On("CreateOrder", testCase.order).Return(nil)
-				mockQR.On("Generate", mock.Anything).Return([]byte("qr"), nil)
-				mockRepo.On("SaveQRCode", mock.Anything, mock.Anything).Return(nil)
+				mockRepo.On("CreateOrder", mock.Anything, testCase.order).Return(nil)
+				mockQR.On("Generate", "https://overcooked.example/r/"+service.ShortCode(testCase.order.ID)).Return([]byte("qr"), nil)
+				mockRepo.On("SaveQRCode", mock.Anything, mock.Anything, mock.Anything).Return(nil)
 			}
 
-			err := svc.Create(testCase.order)
+			err := svc.Create(context.Background(), testCase.order)
 
 			if testCase.wantErr {
 				assert.Error(t, err)
+				var validationErr *service.OrderValidationError
+				if testCase.wantItemErrs > 0 && assert.ErrorAs(t, err, &validationErr) {
+					assert.Len(t, validationErr.Items, testCase.wantItemErrs)
+				}
+				mockRepo.AssertNotCalled(t, "CreateOrder", mock.Anything)
 			} else {
 				assert.NoError(t, err)
 			}
@@ -150,10 +303,592 @@ func TestOrderService_CreateValidation(t *testing.T) {
 	}
 }
 
+func TestOrderService_CreateUsesServerPrices(t *testing.T) {
+	mockRepo := new(mocks.OrderRepository)
+	svc := service.NewOrderService(mockRepo, nil, service.ReviewLinks{})
+
+	mockRepo.On("GetDishesByIDs", mock.Anything, []int{1, 2}).Return(map[int]domain.Dish{
+		1: {ID: 1, RestaurantID: 1, Name: "Плов", Price: 350},
+		2: {ID: 2, RestaurantID: 1, Name: "Шашлык", Price: 280},
+	}, nil).Once()
+	mockRepo.On("ListModifierGroups", mock.Anything, []int{1, 2}).Return(map[int][]domain.ModifierGroup{}, nil).Once()
+	mockRepo.On("GetRestaurantWithDeleted", mock.Anything, 1).Return(&domain.Restaurant{ID: 1}, nil).Once()
+	mockRepo.On("CreateOrder", mock.Anything, mock.AnythingOfType("*domain.Order")).Return(nil).Once()
+
+	order := &domain.Order{
+		RestaurantID: 1,
+		TotalAmount:  1,
+		Items: []domain.OrderItem{
+			{DishID: 1, Quantity: 2, Price: 1},
+			{DishID: 2, Quantity: 1, Price: 1},
+		},
+	}
+
+	err := svc.Create(context.Background(), order)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 980.0, order.TotalAmount)
+	assert.Equal(t, 350.0, order.Items[0].Price)
+	assert.Equal(t, "Шашлык", order.Items[1].DishName)
+	mockRepo.AssertExpectations(t)
+}
+
+func pizzaModifiers() []domain.ModifierGroup {
+	return []domain.ModifierGroup{
+		{ID: 1, DishID: 4, Name: "Размер", MinSelect: 1, MaxSelect: 1, Options: []domain.ModifierOption{
+			{ID: 10, GroupID: 1, Name: "30 см"},
+			{ID: 11, GroupID: 1, Name: "40 см", PriceDelta: 150},
+		}},
+		{ID: 2, DishID: 4, Name: "Добавки", MinSelect: 0, MaxSelect: 2, Options: []domain.ModifierOption{
+			{ID: 20, GroupID: 2, Name: "Сыр", PriceDelta: 60},
+			{ID: 21, GroupID: 2, Name: "Халапеньо", PriceDelta: 40},
+		}},
+	}
+}
+
+func TestModifierService_UpdateRejectsDuplicateOptionIDs(t *testing.T) {
+	mockRepo := new(mocks.ModifierRepository)
+	mockDishes := new(mocks.DishRepository)
+	svc := service.NewModifierService(mockRepo, mockDishes)
+
+	group := &domain.ModifierGroup{ID: 2, DishID: 5, Name: "Size", MinSelect: 1, MaxSelect: 1, Options: []domain.ModifierOption{
+		{ID: 10, Name: "Small"},
+		{ID: 10, Name: "Large"},
+	}}
+	err := svc.Update(context.Background(), 1, group)
+
+	assert.ErrorIs(t, err, service.ErrInvalidModifierGroup)
+	mockRepo.AssertNotCalled(t, "UpdateModifierGroup", mock.Anything)
+}
+
+func TestOrderService_CreateWithModifiers(t *testing.T) {
+	mockRepo := new(mocks.OrderRepository)
+	svc := service.NewOrderService(mockRepo, nil, service.ReviewLinks{})
+
+	mockRepo.On("GetDishesByIDs", mock.Anything, []int{4}).Return(map[int]domain.Dish{
+		4: {ID: 4, RestaurantID: 1, Name: "Пицца", Price: 450},
+	}, nil).Once()
+	mockRepo.On("ListModifierGroups", mock.Anything, []int{4}).Return(map[int][]domain.ModifierGroup{4: pizzaModifiers()}, nil).Once()
+	mockRepo.On("GetRestaurantWithDeleted", mock.Anything, 1).Return(&domain.Restaurant{ID: 1}, nil).Once()
+	mockRepo.On("CreateOrder", mock.Anything, mock.AnythingOfType("*domain.Order")).Return(nil).Once()
+
+	order := &domain.Order{
+		RestaurantID: 1,
+		Items: []domain.OrderItem{
+			{DishID: 4, Quantity: 2, Options: []domain.OrderItemOption{{OptionID: 20}, {OptionID: 11}}},
+		},
+	}
+
+	err := svc.Create(context.Background(), order)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 660.0, order.Items[0].Price)
+	assert.Equal(t, 1320.0, order.TotalAmount)
+	if assert.Len(t, order.Items[0].Options, 2) {
+		assert.Equal(t, "40 см", order.Items[0].Options[0].Name)
+		assert.Equal(t, "Размер", order.Items[0].Options[0].GroupName)
+		assert.Equal(t, "Сыр", order.Items[0].Options[1].Name)
+	}
+	mockRepo.AssertExpectations(t)
+}
+
+func TestCategoryService_Menu(t *testing.T) {
+	mockCategories := new(mocks.CategoryRepository)
+	mockDishes := new(mocks.DishRepository)
+	svc := service.NewCategoryService(mockCategories, mockDishes)
+
+	mains, drinks, hidden := 1, 2, 3
+	mockCategories.On("ListCategories", mock.Anything, 10).Return([]domain.MenuCategory{
+		{ID: drinks, RestaurantID: 10, Name: "Напитки", SortOrder: 0, Visible: true},
+		{ID: mains, RestaurantID: 10, Name: "Горячее", SortOrder: 1, Visible: true},
+		{ID: hidden, RestaurantID: 10, Name: "Сезонное", SortOrder: 2, Visible: false},
+	}, nil).Once()
+	mockDishes.On("ListDishes", mock.Anything, 10).Return([]domain.Dish{
+		{ID: 1, Name: "Плов", CategoryID: &mains},
+		{ID: 2, Name: "Чай", CategoryID: &drinks},
+		{ID: 3, Name: "Окрошка", CategoryID: &hidden},
+		{ID: 4, Name: "Хлеб"},
+	}, nil).Once()
+
+	sections, err := svc.Menu(context.Background(), 10, domain.DishFilter{})
+
+	assert.NoError(t, err)
+	if assert.Len(t, sections, 3) {
+		assert.Equal(t, "Напитки", sections[0].Category.Name)
+		assert.Equal(t, "Чай", sections[0].Dishes[0].Name)
+		assert.Equal(t, "Горячее", sections[1].Category.Name)
+		assert.Nil(t, sections[2].Category)
+		assert.Equal(t, "Хлеб", sections[2].Dishes[0].Name)
+	}
+	mockCategories.AssertExpectations(t)
+	mockDishes.AssertExpectations(t)
+}
+
+func TestMenuVersionService_Preview(t *testing.T) {
+	mockDrafts := new(mocks.MenuVersionRepository)
+	mockDishes := new(mocks.DishRepository)
+	mockCategories := new(mocks.CategoryRepository)
+	svc := service.NewMenuVersionService(mockDrafts, mockDishes, mockCategories)
+
+	mains, drinks := 1, 2
+	mockCategories.On("ListCategories", mock.Anything, 10).Return([]domain.MenuCategory{
+		{ID: mains, RestaurantID: 10, Name: "Горячее", Visible: true},
+		{ID: drinks, RestaurantID: 10, Name: "Напитки", Visible: true},
+	}, nil).Once()
+	mockDishes.On("ListDishes", mock.Anything, 10).Return([]domain.Dish{
+		{ID: 1, Name: "Плов", Price: 350, CategoryID: &mains, ImageURL: "/uploads/plov.jpg"},
+		{ID: 2, Name: "Чай", Price: 80, CategoryID: &drinks},
+		{ID: 3, Name: "Шашлык", Price: 280, CategoryID: &mains},
+	}, nil).Once()
+	mockDrafts.On("ListMenuDraft", mock.Anything, 10).Return([]domain.MenuDraftChange{
+		{ID: 1, Entity: domain.DraftEntityDish, Action: domain.DraftActionUpsert, EntityID: 1,
+			Dish: &domain.Dish{ID: 1, Name: "Плов праздничный", Price: 390, CategoryID: &mains}},
+		{ID: 2, Entity: domain.DraftEntityDish, Action: domain.DraftActionDelete, EntityID: 3},
+		{ID: 3, Entity: domain.DraftEntityCategory, Action: domain.DraftActionDelete, EntityID: drinks},
+		{ID: 4, Entity: domain.DraftEntityDish, Action: domain.DraftActionUpsert,
+			Dish: &domain.Dish{Name: "Лагман", Price: 320, CategoryID: &mains}},
+	}, nil).Once()
+
+	sections, err := svc.Preview(context.Background(), 10)
+
+	assert.NoError(t, err)
+	if assert.Len(t, sections, 2) {
+		assert.Equal(t, "Горячее", sections[0].Category.Name)
+		if assert.Len(t, sections[0].Dishes, 2) {
+			assert.Equal(t, "Плов праздничный", sections[0].Dishes[0].Name)
+			assert.Equal(t, 390.0, sections[0].Dishes[0].Price)
+			assert.Equal(t, "/uploads/plov.jpg", sections[0].Dishes[0].ImageURL)
+			assert.Equal(t, "Лагман", sections[0].Dishes[1].Name)
+		}
+		// the dish of the deleted category falls back to the uncategorized section
+		assert.Nil(t, sections[1].Category)
+		assert.Equal(t, "Чай", sections[1].Dishes[0].Name)
+	}
+	mockDrafts.AssertExpectations(t)
+	mockDishes.AssertExpectations(t)
+	mockCategories.AssertExpectations(t)
+}
+
+func TestMenuVersionService_Publish(t *testing.T) {
+	t.Run("empty draft", func(t *testing.T) {
+		mockDrafts := new(mocks.MenuVersionRepository)
+		svc := service.NewMenuVersionService(mockDrafts, nil, nil)
+		mockDrafts.On("ListMenuDraft", mock.Anything, 10).Return([]domain.MenuDraftChange{}, nil).Once()
+
+		version, err := svc.Publish(context.Background(), 10)
+
+		assert.ErrorIs(t, err, service.ErrEmptyDraft)
+		assert.Nil(t, version)
+		mockDrafts.AssertNotCalled(t, "PublishMenuDraft", mock.Anything)
+	})
+
+	t.Run("publishes staged changes", func(t *testing.T) {
+		mockDrafts := new(mocks.MenuVersionRepository)
+		svc := service.NewMenuVersionService(mockDrafts, nil, nil)
+		mockDrafts.On("ListMenuDraft", mock.Anything, 10).Return([]domain.MenuDraftChange{
+			{ID: 1, Entity: domain.DraftEntityDish, Action: domain.DraftActionDelete, EntityID: 3},
+		}, nil).Once()
+		mockDrafts.On("PublishMenuDraft", mock.Anything, 10).Return(&domain.MenuVersion{RestaurantID: 10, Version: 4, Current: true}, nil).Once()
+
+		version, err := svc.Publish(context.Background(), 10)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 4, version.Version)
+		mockDrafts.AssertExpectations(t)
+	})
+}
+
+func TestMenuVersionService_StageDishValidation(t *testing.T) {
+	mockDrafts := new(mocks.MenuVersionRepository)
+	svc := service.NewMenuVersionService(mockDrafts, nil, nil)
+
+	_, err := svc.StageDish(context.Background(), &domain.Dish{RestaurantID: 10, Price: 100})
+	assert.ErrorIs(t, err, service.ErrInvalidDraftChange)
+
+	_, err = svc.StageDish(context.Background(), &domain.Dish{RestaurantID: 10, Name: "Хачапури", Price: 300, Allergens: []string{"pineapple"}})
+	assert.ErrorIs(t, err, service.ErrUnknownAllergen)
+
+	mockDrafts.AssertNotCalled(t, "StageMenuChange", mock.Anything)
+}
+
+func TestDishAvailableAt(t *testing.T) {
+	breakfast := []domain.AvailabilityWindow{{From: "08:00", To: "11:00"}}
+	lateNight := []domain.AvailabilityWindow{{Weekdays: []int{5}, From: "22:00", To: "02:00"}}
+	// 2026-10-16 is a Friday
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2026, 10, day, hour, minute, 0, 0, time.UTC)
+	}
+	moscow, err := time.LoadLocation("Europe/Moscow")
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name string
+		dish domain.Dish
+		at   time.Time
+		want bool
+	}{
+		{name: "no windows", dish: domain.Dish{}, at: at(16, 3, 0), want: true},
+		{name: "stop-listed", dish: domain.Dish{StopListed: true}, at: at(16, 12, 0), want: false},
+		{name: "inside breakfast", dish: domain.Dish{AvailabilityWindows: breakfast}, at: at(16, 10, 59), want: true},
+		{name: "breakfast is over", dish: domain.Dish{AvailabilityWindows: breakfast}, at: at(16, 11, 0), want: false},
+		{name: "friday night before midnight", dish: domain.Dish{AvailabilityWindows: lateNight}, at: at(16, 23, 30), want: true},
+		{name: "friday night after midnight", dish: domain.Dish{AvailabilityWindows: lateNight}, at: at(17, 1, 30), want: true},
+		{name: "saturday night", dish: domain.Dish{AvailabilityWindows: lateNight}, at: at(17, 23, 30), want: false},
+		// windows are in the zone of t: 06:30 UTC is 09:30 in Moscow
+		{name: "breakfast in restaurant time", dish: domain.Dish{AvailabilityWindows: breakfast}, at: at(16, 6, 30).In(moscow), want: true},
+		{name: "breakfast over in restaurant time", dish: domain.Dish{AvailabilityWindows: breakfast}, at: at(16, 9, 30).In(moscow), want: false},
+		{name: "friday night in restaurant time", dish: domain.Dish{AvailabilityWindows: lateNight}, at: at(16, 19, 30).In(moscow), want: true},
+		{name: "past midnight in restaurant time", dish: domain.Dish{AvailabilityWindows: lateNight}, at: at(16, 22, 30).In(moscow), want: true},
+		{name: "saturday night in restaurant time", dish: domain.Dish{AvailabilityWindows: lateNight}, at: at(17, 19, 30).In(moscow), want: false},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			assert.Equal(t, testCase.want, service.DishAvailableAt(testCase.dish, testCase.at))
+		})
+	}
+}
+
+func TestDishService_ListAvailableInRestaurantZone(t *testing.T) {
+	mockRepo := new(mocks.DishRepository)
+	svc := service.NewDishService(mockRepo, nil)
+
+	mockRepo.On("GetRestaurant", mock.Anything, 1).Return(&domain.Restaurant{ID: 1, TimeZone: "Asia/Tokyo"}, nil)
+	mockRepo.On("ListDishes", mock.Anything, 1).Return([]domain.Dish{
+		{ID: 1, Name: "Тамагояки", AvailabilityWindows: []domain.AvailabilityWindow{{From: "07:00", To: "10:00"}}},
+		{ID: 2, Name: "Рамен", AvailabilityWindows: []domain.AvailabilityWindow{{From: "18:00", To: "23:00"}}},
+	}, nil)
+
+	// 23:30 UTC is 08:30 the next morning in Tokyo
+	now := time.Date(2026, 10, 15, 23, 30, 0, 0, time.UTC)
+	dishes, err := svc.List(context.Background(), 1, domain.DishFilter{AvailableAt: &now})
+
+	assert.NoError(t, err)
+	if assert.Len(t, dishes, 1) {
+		assert.Equal(t, "Тамагояки", dishes[0].Name)
+	}
+}
+
+func TestDishService_SetAvailabilityValidation(t *testing.T) {
+	mockRepo := new(mocks.DishRepository)
+	svc := service.NewDishService(mockRepo, nil)
+
+	_, err := svc.SetAvailability(context.Background(), 1, 1, false, []domain.AvailabilityWindow{{From: "25:00", To: "11:00"}})
+	assert.ErrorIs(t, err, service.ErrInvalidAvailability)
+
+	mockRepo.On("UpdateDishAvailability", mock.Anything, 1, 1, true, []domain.AvailabilityWindow{}).Return(int64(1), nil).Once()
+	rows, err := svc.SetAvailability(context.Background(), 1, 1, true, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), rows)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestRestaurantOpenAt(t *testing.T) {
+	rest := domain.Restaurant{
+		TimeZone: "Asia/Tashkent",
+		OpeningHours: &domain.OpeningHours{
+			Weekly: []domain.AvailabilityWindow{
+				{Weekdays: []int{1, 2, 3, 4}, From: "10:00", To: "22:00"},
+				{Weekdays: []int{5, 6}, From: "10:00", To: "02:00"},
+			},
+			Exceptions: []domain.HoursException{
+				{Date: "2026-10-19", Note: "Санитарный день"},
+				{Date: "2026-10-20", Windows: []domain.AvailabilityWindow{{From: "12:00", To: "18:00"}}},
+			},
+		},
+	}
+	// times are UTC, Tashkent is UTC+5; 2026-10-16 is a Friday
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2026, 10, day, hour, minute, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name string
+		rest domain.Restaurant
+		at   time.Time
+		want bool
+	}{
+		{name: "no opening hours", rest: domain.Restaurant{TimeZone: "Asia/Tashkent"}, at: at(16, 0, 0), want: true},
+		{name: "thursday before opening", rest: rest, at: at(15, 4, 59), want: false},
+		{name: "thursday evening local", rest: rest, at: at(15, 16, 30), want: true},
+		{name: "thursday after closing", rest: rest, at: at(15, 17, 0), want: false},
+		{name: "friday night after midnight", rest: rest, at: at(16, 20, 30), want: true},
+		{name: "sunday is closed", rest: rest, at: at(18, 9, 0), want: false},
+		{name: "saturday night spills into sunday", rest: rest, at: at(17, 20, 0), want: true},
+		{name: "holiday monday", rest: rest, at: at(19, 9, 0), want: false},
+		{name: "short tuesday", rest: rest, at: at(20, 12, 0), want: true},
+		{name: "short tuesday is over", rest: rest, at: at(20, 13, 0), want: false},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			assert.Equal(t, testCase.want, service.RestaurantOpenAt(testCase.rest, testCase.at))
+		})
+	}
+}
+
+func TestRestaurantService_CreateLocale(t *testing.T) {
+	tests := []struct {
+		name         string
+		input        domain.Restaurant
+		wantErr      error
+		wantTimeZone string
+		wantLocale   string
+	}{
+		{name: "defaults", input: domain.Restaurant{Name: "Test"}, wantTimeZone: "UTC", wantLocale: "ru-RU"},
+		{name: "canonical locale", input: domain.Restaurant{Name: "Test", TimeZone: "Europe/Moscow", Locale: "uz_latn_uz"}, wantTimeZone: "Europe/Moscow", wantLocale: "uz-Latn-UZ"},
+		{name: "unknown time zone", input: domain.Restaurant{Name: "Test", TimeZone: "Mars/Olympus"}, wantErr: service.ErrInvalidTimeZone},
+		{name: "server local time zone", input: domain.Restaurant{Name: "Test", TimeZone: "Local"}, wantErr: service.ErrInvalidTimeZone},
+		{name: "bad locale", input: domain.Restaurant{Name: "Test", Locale: "not a locale"}, wantErr: service.ErrInvalidLocale},
+		{
+			name: "duplicate exception",
+			input: domain.Restaurant{Name: "Test", OpeningHours: &domain.OpeningHours{Exceptions: []domain.HoursException{
+				{Date: "2026-12-31"}, {Date: "2026-12-31"},
+			}}},
+			wantErr: service.ErrInvalidOpeningHours,
+		},
+		{
+			name: "bad weekly window",
+			input: domain.Restaurant{Name: "Test", OpeningHours: &domain.OpeningHours{Weekly: []domain.AvailabilityWindow{
+				{From: "10:00", To: "10:00"},
+			}}},
+			wantErr: service.ErrInvalidOpeningHours,
+		},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			mockRepo := new(mocks.RestaurantRepository)
+			svc := service.NewRestaurantService(mockRepo, nil)
+			rest := testCase.input
+			if testCase.wantErr == nil {
+				mockRepo.On("CreateRestaurant", mock.Anything, &rest).Return(nil).Once()
+			}
+
+			err := svc.Create(context.Background(), &rest)
+
+			if testCase.wantErr != nil {
+				assert.ErrorIs(t, err, testCase.wantErr)
+				mockRepo.AssertNotCalled(t, "CreateRestaurant", mock.Anything)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.wantTimeZone, rest.TimeZone)
+			assert.Equal(t, testCase.wantLocale, rest.Locale)
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestNormalizeAllergens(t *testing.T) {
+	allergens, err := service.NormalizeAllergens([]string{" Milk", "dairy", "Tree Nuts", "soybeans"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"milk", "nuts", "soy"}, allergens)
+
+	_, err = service.NormalizeAllergens([]string{"pineapple"})
+	assert.ErrorIs(t, err, service.ErrUnknownAllergen)
+}
+
+func TestFilterDishes_Dietary(t *testing.T) {
+	dishes := []domain.Dish{
+		{ID: 1, Name: "Маргарита", Allergens: []string{"gluten", "milk"}, DietaryTags: []string{"vegetarian"}},
+		{ID: 2, Name: "Салат", DietaryTags: []string{"gluten-free", "vegan", "vegetarian"}},
+		{ID: 3, Name: "Плов", Allergens: []string{}, DietaryTags: []string{"halal"}},
+	}
+
+	filtered := service.FilterDishes(dishes, domain.DishFilter{ExcludeAllergens: []string{"milk"}})
+	assert.Len(t, filtered, 2)
+
+	filtered = service.FilterDishes(dishes, domain.DishFilter{Tags: []string{"vegan", "gluten-free"}})
+	if assert.Len(t, filtered, 1) {
+		assert.Equal(t, 2, filtered[0].ID)
+	}
+}
+
 func TestDefaultQRGenerator(t *testing.T) {
-	gen := &service.DefaultQRGenerator{BaseURL: "http://localhost"}
-	qr, err := gen.Generate(123)
+	gen := service.DefaultQRGenerator{}
+	qr, err := gen.Generate("http://localhost/r/3f")
 
 	assert.NoError(t, err)
 	assert.NotEmpty(t, qr)
 }
+
+func TestReviewLinks(t *testing.T) {
+	links := service.ReviewLinks{BaseURL: "https://overcooked.example"}
+
+	assert.Equal(t, "https://overcooked.example/r/2n9c", links.URL(&domain.Restaurant{ID: 1}, 123456))
+	assert.Equal(t, "https://plov.example/r/2n9c", links.URL(&domain.Restaurant{ID: 1, PublicBaseURL: "https://plov.example"}, 123456))
+
+	id, ok := service.ParseShortCode("2N9C")
+	assert.True(t, ok)
+	assert.Equal(t, 123456, id)
+	for _, code := range []string{"", "-1", "0", "zzzzzzzzzzzz", "a/b"} {
+		_, ok := service.ParseShortCode(code)
+		assert.False(t, ok, code)
+	}
+
+	base, err := service.NormalizeBaseURL(" https://plov.example/menu/ ")
+	assert.NoError(t, err)
+	assert.Equal(t, "https://plov.example/menu", base)
+	for _, raw := range []string{"plov.example", "ftp://plov.example", "https://plov.example/?a=1", "https://user@plov.example", "/relative"} {
+		_, err := service.NormalizeBaseURL(raw)
+		assert.ErrorIs(t, err, service.ErrInvalidBaseURL, raw)
+	}
+}
+
+func TestOrderService_RegenerateQRCodes(t *testing.T) {
+	mockRepo := new(mocks.OrderRepository)
+	mockQR := new(mocks.QRGenerator)
+	svc := service.NewOrderService(mockRepo, mockQR, service.ReviewLinks{BaseURL: "https://overcooked.example"})
+
+	mockRepo.On("GetRestaurantWithDeleted", mock.Anything, 7).Return(&domain.Restaurant{ID: 7, PublicBaseURL: "https://plov.example"}, nil)
+	mockRepo.On("ListOrderIDs", mock.Anything, 7).Return(map[int][]int{7: {10, 11}}, nil).Once()
+	mockQR.On("Generate", "https://plov.example/r/a").Return([]byte("qr-10"), nil).Once()
+	mockQR.On("Generate", "https://plov.example/r/b").Return([]byte("qr-11"), nil).Once()
+	mockRepo.On("SaveQRCode", mock.Anything, 10, []byte("qr-10")).Return(nil).Once()
+	mockRepo.On("SaveQRCode", mock.Anything, 11, []byte("qr-11")).Return(nil).Once()
+
+	count, err := svc.RegenerateQRCodes(context.Background(), 7)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, count)
+	mockRepo.AssertExpectations(t)
+	mockQR.AssertExpectations(t)
+
+	mockRepo.On("GetRestaurantWithDeleted", mock.Anything, 404).Return(nil, sql.ErrNoRows).Once()
+	_, err = svc.RegenerateQRCodes(context.Background(), 404)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func TestMenuImportService_DryRunReportsRowErrors(t *testing.T) {
+	mockImports := new(mocks.MenuImportRepository)
+	svc := service.NewMenuImportService(mockImports, nil, nil)
+
+	csvBody := "sku,name,price,allergens\n" +
+		"PLOV-1,Плов,350,\n" +
+		"PLOV-1,Плов большой,abc,\n" +
+		",Лагман,320,pineapple\n"
+
+	report, err := svc.Import(context.Background(), 10, service.MenuFormatCSV, strings.NewReader(csvBody), true)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, report.Rows)
+	var fields []string
+	for _, rowErr := range report.Errors {
+		fields = append(fields, fmt.Sprintf("%d:%s", rowErr.Row, rowErr.Field))
+	}
+	assert.ElementsMatch(t, []string{"2:price", "3:sku", "3:allergens"}, fields)
+	mockImports.AssertNotCalled(t, "ImportMenu", mock.Anything, mock.Anything)
+}
+
+func TestMenuImportService_ExportRoundTrip(t *testing.T) {
+	mockImports := new(mocks.MenuImportRepository)
+	mockDishes := new(mocks.DishRepository)
+	mockCategories := new(mocks.CategoryRepository)
+	svc := service.NewMenuImportService(mockImports, mockDishes, mockCategories)
+
+	mains := 1
+	mockCategories.On("ListCategories", mock.Anything, 10).Return([]domain.MenuCategory{
+		{ID: mains, RestaurantID: 10, Name: "Горячее", Visible: false},
+	}, nil)
+	mockDishes.On("ListDishes", mock.Anything, 10).Return([]domain.Dish{
+		{ID: 1, SKU: "PLOV-1", Name: "Плов", Description: "С бараниной, \"по-ферганский\"", Price: 350.5, CategoryID: &mains,
+			Allergens: []string{"celery", "sesame"}, Nutrition: &domain.NutritionFacts{Calories: 620, ServingGrams: 300}},
+		{ID: 2, SKU: "TEA", Name: "Чай", Price: 80, DietaryTags: []string{"vegan"}},
+	}, nil)
+
+	exported, err := svc.Export(context.Background(), 10)
+	assert.NoError(t, err)
+
+	var buf bytes.Buffer
+	assert.NoError(t, service.WriteMenuCSV(&buf, exported))
+	// nil and empty lists both come back empty, so compare the JSON form
+	sameRows := mock.MatchedBy(func(rows []domain.MenuRow) bool {
+		got, _ := json.Marshal(rows)
+		want, _ := json.Marshal(exported)
+		return string(got) == string(want)
+	})
+	mockImports.On("ImportMenu", mock.Anything, 10, sameRows).Return(&domain.MenuImportReport{Rows: 2, Updated: 2, MenuVersion: 3}, nil).Once()
+
+	report, err := svc.Import(context.Background(), 10, service.MenuFormatCSV, &buf, false)
+
+	assert.NoError(t, err)
+	assert.Empty(t, report.Errors)
+	assert.Equal(t, 3, report.MenuVersion)
+	mockImports.AssertExpectations(t)
+}
+
+func encodeTestImage(t *testing.T, width, height int) []byte {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, color.RGBA{R: uint8(x), G: uint8(y), B: 80, A: 255})
+		}
+	}
+	var buf bytes.Buffer
+	assert.NoError(t, jpeg.Encode(&buf, img, nil))
+	return buf.Bytes()
+}
+
+// withOrientation inserts an EXIF segment with the given orientation right
+// after the JPEG start marker.
+func withOrientation(data []byte, orientation uint16) []byte {
+	tiff := []byte{'M', 'M', 0, 42, 0, 0, 0, 8, 0, 1, 0x01, 0x12, 0, 3, 0, 0, 0, 1, byte(orientation >> 8), byte(orientation), 0, 0, 0, 0, 0, 0}
+	segment := append([]byte("Exif\x00\x00"), tiff...)
+	size := len(segment) + 2
+	app1 := append([]byte{0xFF, 0xE1, byte(size >> 8), byte(size)}, segment...)
+	return append(append(append([]byte{}, data[:2]...), app1...), data[2:]...)
+}
+
+func TestImagePipeline_Save(t *testing.T) {
+	dir := t.TempDir()
+	pipeline := service.NewImagePipeline(storage.NewFileBlobStore(dir, "/uploads/", "/api/files", nil))
+	upload := withOrientation(encodeTestImage(t, 800, 400), 6)
+
+	set, err := pipeline.Save(bytes.NewReader(upload))
+	assert.NoError(t, err)
+	assert.Regexp(t, `^/uploads/[0-9a-f]{32}\.jpg$`, set.URL)
+	// orientation 6 rotates the picture, so the full image is 400 wide
+	assert.Equal(t, strings.Replace(set.URL, ".jpg", "-320w.jpg", 1)+" 320w, "+set.URL+" 400w", set.Srcset)
+	assert.Contains(t, set.WebPSrcset, ".webp 400w")
+
+	full, err := os.ReadFile(filepath.Join(dir, filepath.Base(set.URL)))
+	assert.NoError(t, err)
+	cfg, err := jpeg.DecodeConfig(bytes.NewReader(full))
+	assert.NoError(t, err)
+	assert.Equal(t, 400, cfg.Width)
+	assert.Equal(t, 800, cfg.Height)
+	assert.NotContains(t, string(full), "Exif")
+
+	again, err := pipeline.Save(bytes.NewReader(upload))
+	assert.NoError(t, err)
+	assert.Equal(t, set, again)
+
+	_, err = pipeline.Save(strings.NewReader("<svg onload=alert(1)></svg>"))
+	assert.ErrorIs(t, err, service.ErrUnsupportedImage)
+
+	assert.NoError(t, pipeline.Remove(set.URL))
+	files, err := os.ReadDir(dir)
+	assert.NoError(t, err)
+	assert.Empty(t, files)
+}
+
+func TestDishService_UpdateImageRemovesReplaced(t *testing.T) {
+	mockRepo := new(mocks.DishRepository)
+	images := new(mocks.ImageStore)
+	svc := service.NewDishService(mockRepo, images)
+	upload := strings.NewReader("image")
+	set := &domain.ImageSet{URL: "/uploads/new.jpg", Srcset: "/uploads/new.jpg 640w"}
+
+	mockRepo.On("GetDish", mock.Anything, 1, 2).Return(&domain.Dish{ID: 2, RestaurantID: 1, ImageURL: "/uploads/old.jpg"}, nil).Once()
+	images.On("Save", upload).Return(set, nil).Once()
+	mockRepo.On("UpdateDishImage", mock.Anything, 1, 2, *set).Return(nil).Once()
+	mockRepo.On("ImageInUse", mock.Anything, "/uploads/old.jpg").Return(false, nil).Once()
+	images.On("Remove", "/uploads/old.jpg").Return(nil).Once()
+
+	got, err := svc.UpdateImage(context.Background(), 1, 2, upload)
+	assert.NoError(t, err)
+	assert.Equal(t, set, got)
+	mockRepo.AssertExpectations(t)
+	images.AssertExpectations(t)
+}
@@ -0,0 +1,99 @@
+package tests
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	httpapi "overcooked-simplified/dish-svc/internal/api/http"
+	"overcooked-simplified/dish-svc/internal/domain"
+	"overcooked-simplified/dish-svc/internal/mocks"
+	"overcooked-simplified/dish-svc/internal/service"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestTableService_Create(t *testing.T) {
+	tests := []struct {
+		name    string
+		table   domain.Table
+		wantErr error
+	}{
+		{name: "valid", table: domain.Table{RestaurantID: 7, Label: " 12 ", Section: "Веранда"}},
+		{name: "no label", table: domain.Table{RestaurantID: 7, Label: "  "}, wantErr: service.ErrInvalidTable},
+		{name: "unknown restaurant", table: domain.Table{RestaurantID: 404, Label: "1"}, wantErr: sql.ErrNoRows},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			repo := new(mocks.TableRepository)
+			restaurants := new(mocks.RestaurantRepository)
+			restaurants.On("GetRestaurant", mock.Anything, 7).Return(&domain.Restaurant{ID: 7}, nil)
+			restaurants.On("GetRestaurant", mock.Anything, 404).Return(nil, sql.ErrNoRows)
+			repo.On("CreateTable", mock.Anything, mock.AnythingOfType("*domain.Table")).Return(nil)
+			svc := service.NewTableService(repo, restaurants, nil, service.ReviewLinks{})
+
+			table := testCase.table
+			err := svc.Create(context.Background(), &table)
+
+			if testCase.wantErr != nil {
+				assert.ErrorIs(t, err, testCase.wantErr)
+				repo.AssertNotCalled(t, "CreateTable", mock.Anything)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, "12", table.Label)
+			assert.Regexp(t, `^[a-z2-7]{8}$`, table.Code)
+		})
+	}
+}
+
+func TestTableService_QRCode(t *testing.T) {
+	repo := new(mocks.TableRepository)
+	restaurants := new(mocks.RestaurantRepository)
+	qr := new(mocks.QRGenerator)
+	repo.On("GetTable", mock.Anything, 7, 3).Return(&domain.Table{ID: 3, RestaurantID: 7, Label: "3", Code: "abcdefgh"}, nil)
+	restaurants.On("GetRestaurant", mock.Anything, 7).Return(&domain.Restaurant{ID: 7, PublicBaseURL: "https://plov.example"}, nil)
+	qr.On("Generate", "https://plov.example/t/abcdefgh").Return([]byte("png"), nil).Once()
+	svc := service.NewTableService(repo, restaurants, qr, service.ReviewLinks{BaseURL: "https://overcooked.example"})
+
+	png, err := svc.QRCode(context.Background(), 7, 3)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("png"), png)
+	qr.AssertExpectations(t)
+}
+
+func TestOrderService_CreateRejectsForeignTable(t *testing.T) {
+	mockRepo := new(mocks.OrderRepository)
+	svc := service.NewOrderService(mockRepo, nil, service.ReviewLinks{})
+	mockRepo.On("GetTable", mock.Anything, 1, 9).Return(nil, sql.ErrNoRows).Once()
+
+	tableID := 9
+	err := svc.Create(context.Background(), &domain.Order{RestaurantID: 1, TableID: &tableID, Items: []domain.OrderItem{{DishID: 1, Quantity: 1}}})
+
+	assert.ErrorIs(t, err, service.ErrInvalidOrder)
+	mockRepo.AssertNotCalled(t, "CreateOrder", mock.Anything)
+}
+
+func TestFollowTableLinkHandler(t *testing.T) {
+	tables := new(mocks.TableServiceInterface)
+	tables.On("ByCode", mock.Anything, "abcdefgh").Return(&domain.Table{ID: 3, RestaurantID: 7, Code: "abcdefgh"}, nil)
+	tables.On("ByCode", mock.Anything, "missing").Return(nil, sql.ErrNoRows)
+	handler := httpapi.NewHandler(httpapi.Deps{Tables: tables})
+	r := mux.NewRouter()
+	handler.RegisterRoutes(r)
+
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/t/abcdefgh", nil))
+	assert.Equal(t, http.StatusFound, rr.Code)
+	assert.Equal(t, "/index.html?table=abcdefgh", rr.Header().Get("Location"))
+
+	rr = httptest.NewRecorder()
+	r.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/t/missing", nil))
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
@@ -0,0 +1,124 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	httpapi "overcooked-simplified/dish-svc/internal/api/http"
+	"overcooked-simplified/dish-svc/internal/domain"
+	"overcooked-simplified/dish-svc/internal/mocks"
+	"overcooked-simplified/dish-svc/internal/service"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"golang.org/x/text/language"
+)
+
+func TestTranslationService_Put(t *testing.T) {
+	tests := []struct {
+		name     string
+		tr       domain.DishTranslation
+		wantLang string
+		wantErr  error
+	}{
+		{name: "canonical language", tr: domain.DishTranslation{DishID: 5, Lang: "EN-us", Name: " Pilaf "}, wantLang: "en-US"},
+		{name: "unknown language", tr: domain.DishTranslation{DishID: 5, Lang: "english please", Name: "Pilaf"}, wantErr: service.ErrInvalidTranslation},
+		{name: "no name", tr: domain.DishTranslation{DishID: 5, Lang: "en", Name: " "}, wantErr: service.ErrInvalidTranslation},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			repo := new(mocks.TranslationRepository)
+			dishes := new(mocks.DishRepository)
+			svc := service.NewTranslationService(repo, dishes, nil)
+			tr := testCase.tr
+			if testCase.wantErr == nil {
+				dishes.On("GetDish", mock.Anything, 7, 5).Return(&domain.Dish{ID: 5, RestaurantID: 7}, nil).Once()
+				repo.On("UpsertDishTranslation", mock.Anything, &tr).Return(nil).Once()
+			}
+
+			err := svc.Put(context.Background(), 7, &tr)
+
+			if testCase.wantErr != nil {
+				assert.ErrorIs(t, err, testCase.wantErr)
+				repo.AssertNotCalled(t, "UpsertDishTranslation", mock.Anything)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.wantLang, tr.Lang)
+			assert.Equal(t, "Pilaf", tr.Name)
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestTranslationService_Localize(t *testing.T) {
+	translations := map[int][]domain.DishTranslation{
+		1: {
+			{DishID: 1, Lang: "en", Name: "Pilaf", Description: "Rice with lamb"},
+			{DishID: 1, Lang: "uz-Latn", Name: "Palov"},
+		},
+		2: {{DishID: 2, Lang: "uz-Latn", Name: "Somsa"}},
+	}
+	tests := []struct {
+		name      string
+		requested []language.Tag
+		want      []string
+	}{
+		{name: "nothing requested", requested: nil, want: []string{"Плов", "Самса"}},
+		{name: "english where translated", requested: []language.Tag{language.MustParse("en-GB")}, want: []string{"Pilaf", "Самса"}},
+		{name: "uzbek", requested: []language.Tag{language.MustParse("uz")}, want: []string{"Palov", "Somsa"}},
+		{name: "restaurant locale first", requested: []language.Tag{language.Russian, language.English}, want: []string{"Плов", "Самса"}},
+		{name: "no match falls back", requested: []language.Tag{language.German}, want: []string{"Плов", "Самса"}},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			repo := new(mocks.TranslationRepository)
+			restaurants := new(mocks.RestaurantRepository)
+			repo.On("ListDishTranslations", mock.Anything, []int{1, 2}).Return(translations, nil).Maybe()
+			restaurants.On("GetRestaurant", mock.Anything, 7).Return(&domain.Restaurant{ID: 7, Locale: "ru-RU"}, nil).Maybe()
+			svc := service.NewTranslationService(repo, nil, restaurants)
+			sections := []domain.MenuSection{
+				{Dishes: []domain.Dish{{ID: 1, Name: "Плов", Description: "Рис с бараниной"}}},
+				{Dishes: []domain.Dish{{ID: 2, Name: "Самса"}}},
+			}
+
+			assert.NoError(t, svc.LocalizeMenu(context.Background(), 7, sections, testCase.requested))
+
+			assert.Equal(t, testCase.want, []string{sections[0].Dishes[0].Name, sections[1].Dishes[0].Name})
+		})
+	}
+}
+
+func TestGetDishHandler_Localized(t *testing.T) {
+	dishes := new(mocks.DishServiceInterface)
+	dishes.On("Get", mock.Anything, 7, 1).Return(&domain.Dish{ID: 1, RestaurantID: 7, Name: "Плов"}, nil)
+	translations := new(mocks.TranslationServiceInterface)
+	translations.On("Localize", mock.Anything, 7, mock.Anything, []language.Tag{language.English}).
+		Run(func(args mock.Arguments) {
+			dishes := args.Get(2).([]domain.Dish)
+			dishes[0].Name = "Pilaf"
+			dishes[0].Lang = "en"
+		}).
+		Return(nil)
+	handler := httpapi.NewHandler(httpapi.Deps{Dishes: dishes, Translations: translations})
+	r := mux.NewRouter()
+	handler.RegisterRoutes(r)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/restaurants/7/dishes/1?lang=en", nil)
+	req.Header.Set("Accept-Language", "uz")
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var dish domain.Dish
+	assert.NoError(t, json.NewDecoder(rr.Body).Decode(&dish))
+	assert.Equal(t, "Pilaf", dish.Name)
+	assert.Equal(t, "en", dish.Lang)
+	assert.Equal(t, "Accept-Language", rr.Header().Get("Vary"))
+}
@@ -1,30 +1,145 @@
 package main
 
 import (
+	"context"
+	"flag"
 	"log"
+	"os"
 	httpapi "overcooked-simplified/dish-svc/internal/api/http"
 	"overcooked-simplified/dish-svc/internal/service"
 	"overcooked-simplified/dish-svc/internal/storage"
+	_ "time/tzdata" // restaurant time zones; the alpine image has no zoneinfo
 
 	"overcooked-simplified/config"
+	"overcooked-simplified/database"
+	"overcooked-simplified/health"
+	"overcooked-simplified/server"
+	"overcooked-simplified/tracing"
 )
 
 func main() {
-	db := config.MustInitPostgres()
-	defer db.Close()
+	cfg := config.MustLoad(":8081", config.NeedPostgres)
+
+	shutdownTracing, err := tracing.Init("dish-svc", cfg.Tracing)
+	if err != nil {
+		log.Fatal("Failed to set up tracing: ", err)
+	}
+
+	db := config.MustInitPostgres(cfg.Postgres, cfg.Startup)
+
+	// the server's ordered shutdown closes the pool; commands that return
+	// before it exists close it themselves
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		err := database.RunCommand(db, os.Args[2:])
+		db.Close()
+		if err != nil {
+			log.Fatal("Migration failed: ", err)
+		}
+		return
+	}
+	database.MustMigrate(db, cfg.Migrate.OnStart, cfg.Migrate.SeedDemoData)
 
 	repo := storage.NewPostgresRepository(db)
-	if err := repo.EnsureSchema(); err != nil {
-		log.Fatal("Failed to ensure schema:", err)
+	blobs := mustInitBlobStore(cfg.Blob)
+
+	if len(os.Args) > 1 && os.Args[1] == "migrate-uploads" {
+		migrateUploads(repo, blobs, os.Args[2:])
+		db.Close()
+		return
 	}
 
-	restSvc := service.NewRestaurantService(repo)
-	dishSvc := service.NewDishService(repo)
-	qrGen := service.DefaultQRGenerator{BaseURL: "http://localhost"}
-	orderSvc := service.NewOrderService(repo, qrGen)
+	images := service.NewImagePipeline(blobs)
+	restSvc := service.NewRestaurantService(repo, images)
+	dishSvc := service.NewDishService(repo, images)
+	// where guests reach the review page: QR codes link to PUBLIC_BASE_URL/r/<code>
+	publicBaseURL, err := service.NormalizeBaseURL(cfg.PublicBaseURL)
+	if err != nil {
+		log.Fatal("Invalid PUBLIC_BASE_URL:", err)
+	}
+	links := service.ReviewLinks{BaseURL: publicBaseURL}
+	receiptSvc := service.NewReceiptService(repo, repo, repo, images, blobs, links)
+	orderSvc := service.NewOrderService(repo, service.DefaultQRGenerator{}, links)
+	tableSvc := service.NewTableService(repo, repo, service.DefaultQRGenerator{}, links)
+	categorySvc := service.NewCategoryService(repo, repo)
+	modifierSvc := service.NewModifierService(repo, repo)
+	translationSvc := service.NewTranslationService(repo, repo, repo)
+	searchSvc := service.NewSearchService(repo)
 
-	handler := httpapi.NewHandler(restSvc, dishSvc, orderSvc)
+	menuSvc := service.NewMenuVersionService(repo, repo, repo)
+	importSvc := service.NewMenuImportService(repo, repo, repo)
+	checker := health.NewChecker("dish-svc")
+	checker.Add("postgres", db.PingContext)
+	handler := httpapi.NewHandler(httpapi.Deps{
+		Restaurants:  restSvc,
+		Dishes:       dishSvc,
+		Orders:       orderSvc,
+		Categories:   categorySvc,
+		Modifiers:    modifierSvc,
+		Menu:         menuSvc,
+		Imports:      importSvc,
+		Blobs:        blobs,
+		Receipts:     receiptSvc,
+		Tables:       tableSvc,
+		Translations: translationSvc,
+		Search:       searchSvc,
+		Health:       checker,
+	})
 	router := httpapi.NewRouter(handler)
 
-	httpapi.StartServer(":8081", router)
+	srv := server.New("dish-svc", cfg.HTTP, router)
+	srv.Health = checker
+	srv.OnShutdown("postgres", db.Close)
+	srv.OnShutdown("tracing", func() error { return shutdownTracing(context.Background()) })
+	if err := srv.Run(); err != nil {
+		log.Fatal("Server stopped with errors: ", err)
+	}
+}
+
+// mustInitBlobStore builds the upload store selected by BLOB_BACKEND: "fs"
+// (the default) keeps files under UPLOADS_DIR, "s3" in an S3-compatible
+// bucket. Signed URLs of the fs backend are served under /api/files and need
+// BLOB_SIGNING_KEY.
+func mustInitBlobStore(cfg config.BlobConfig) service.BlobStore {
+	if cfg.Backend == "fs" {
+		return storage.NewFileBlobStore(cfg.Dir, cfg.URL, "/api/files", []byte(cfg.SigningKey))
+	}
+	store, err := storage.NewS3BlobStore(storage.S3Config{
+		Endpoint:  cfg.S3.Endpoint,
+		Region:    cfg.S3.Region,
+		Bucket:    cfg.S3.Bucket,
+		AccessKey: cfg.S3.AccessKey,
+		SecretKey: cfg.S3.SecretKey,
+		PublicURL: cfg.S3.PublicURL,
+		PathStyle: cfg.S3.PathStyle,
+	}, nil)
+	if err != nil {
+		log.Fatal("Failed to configure S3 blob store:", err)
+	}
+	if err := store.EnsureBucket(); err != nil {
+		log.Fatal("Failed to create S3 bucket:", err)
+	}
+	return store
+}
+
+// migrateUploads moves files from a local uploads directory into the
+// configured store: dish-svc migrate-uploads [-from-dir ./uploads] [-delete].
+func migrateUploads(repo *storage.PostgresRepository, to service.BlobStore, args []string) {
+	flags := flag.NewFlagSet("migrate-uploads", flag.ExitOnError)
+	fromDir := flags.String("from-dir", "./uploads", "directory the files are in now")
+	fromURL := flags.String("from-url", "/uploads", "URL prefix the files are served under now")
+	removeSource := flags.Bool("delete", false, "delete the files from the source directory afterwards")
+	flags.Parse(args)
+
+	from := storage.NewFileBlobStore(*fromDir, *fromURL, "", nil)
+	if from.URL("") == to.URL("") {
+		log.Fatal("Source and target are the same store; set BLOB_BACKEND or UPLOADS_URL for the target")
+	}
+	report, err := service.MigrateBlobs(context.Background(), from, to, repo, *removeSource)
+	if report != nil {
+		log.Printf("Uploads migrated: %d copied, %d already there, %d rows updated, %d deleted",
+			report.Copied, report.Skipped, report.RowsUpdated, report.Deleted)
+	}
+	if err != nil {
+		log.Fatal("Migration failed:", err)
+	}
 }
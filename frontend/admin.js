@@ -3,35 +3,65 @@ const API_URL = 'http://localhost:8080';
 
 // Загрузка данных при загрузке страницы
 document.addEventListener('DOMContentLoaded', function() {
+    loadChecksCafeFilter();
     loadRecentChecks();
 });
 
-// Загрузка последних чеков
-async function loadRecentChecks() {
+// Чеки грузятся страницами; курсор следующей страницы приходит в заголовке X-Next-Cursor
+const CHECKS_PAGE_SIZE = 50;
+let checksNextCursor = null;
+
+// Список кафе для фильтра чеков: все страницы подряд, пока есть X-Next-Cursor
+async function loadChecksCafeFilter() {
+    try {
+        const select = document.getElementById('checks-cafe-filter');
+        let cursor = null;
+        do {
+            const params = new URLSearchParams({ sort: 'name', limit: 500 });
+            if (cursor) params.set('cursor', cursor);
+            const response = await fetch(`${API_URL}/api/cafes?${params}`);
+            if (!response.ok) throw new Error(`HTTP ${response.status}`);
+            const cafes = await response.json();
+            cafes.forEach(cafe => {
+                const option = document.createElement('option');
+                option.value = cafe.id;
+                option.textContent = cafe.name;
+                select.appendChild(option);
+            });
+            cursor = response.headers.get('X-Next-Cursor');
+        } while (cursor);
+    } catch (error) {
+        console.error('Ошибка при загрузке кафе для фильтра:', error);
+    }
+}
+
+// Параметры запроса чеков из фильтров
+function checksQuery(cursor) {
+    const params = new URLSearchParams({ limit: CHECKS_PAGE_SIZE });
+    const cafeId = document.getElementById('checks-cafe-filter').value;
+    const from = document.getElementById('checks-from').value;
+    const to = document.getElementById('checks-to').value;
+    const minTotal = document.getElementById('checks-min-total').value;
+    if (cafeId) params.set('restaurant_id', cafeId);
+    if (from) params.set('from', from);
+    if (to) params.set('to', to);
+    if (minTotal) params.set('min_total', minTotal);
+    params.set('sort', document.getElementById('checks-sort').value);
+    if (cursor) params.set('cursor', cursor);
+    return params.toString();
+}
+
+// Загрузка последних чеков: первая страница или, с append, следующая
+async function loadRecentChecks(append = false) {
     try {
-        const response = await fetch(`${API_URL}/api/orders`);
+        const response = await fetch(`${API_URL}/api/orders?${checksQuery(append ? checksNextCursor : null)}`);
         if (!response.ok) throw new Error(`HTTP ${response.status}`);
 
         const checks = await response.json();
+        checksNextCursor = response.headers.get('X-Next-Cursor');
+        document.getElementById('checks-more').classList.toggle('hidden', !checksNextCursor);
 
-        for (let check of checks) {
-            if (!check.cafe_name && check.restaurant_id) {
-                try {
-                    const cafeResponse = await fetch(`${API_URL}/api/restaurants/${check.restaurant_id}`);
-                    if (cafeResponse.ok) {
-                        const cafe = await cafeResponse.json();
-                        check.cafe_name = cafe.name || `Кафе #${check.restaurant_id}`;
-                    }
-                } catch {
-                    check.cafe_name = `Кафе #${check.restaurant_id}`;
-                }
-            }
-            if (!check.total_amount && check.total_amount !== 0) {
-                check.total_amount = check.total || 0;
-            }
-        }
-
-        displayChecks(checks);
+        displayChecks(checks, append);
     } catch (error) {
         console.error('Ошибка при загрузке чеков:', error);
         document.getElementById('checks-loading').innerHTML = '<p class="text-red-500">Ошибка при загрузке данных</p>';
@@ -39,14 +69,14 @@ async function loadRecentChecks() {
 }
 
 // Отображение чеков в таблице
-function displayChecks(checks) {
+function displayChecks(checks, append) {
     document.getElementById('checks-loading').classList.add('hidden');
     document.getElementById('checks-content').classList.remove('hidden');
 
     const tbody = document.getElementById('checks-table-body');
-    tbody.innerHTML = '';
+    if (!append) tbody.innerHTML = '';
 
-    if (!checks || checks.length === 0) {
+    if (!append && (!checks || checks.length === 0)) {
         tbody.innerHTML = '<tr><td colspan="5" class="text-center text-gray-500 py-4">Чеки не найдены</td></tr>';
         return;
     }
@@ -68,6 +98,9 @@ function createCheckRow(check) {
             <button onclick="generateQRCode(${check.id})" class="text-blue-600 hover:text-blue-900 mr-3">
                 <i class="fas fa-qrcode mr-1"></i>QR
             </button>
+            <a href="${API_URL}/api/orders/${check.id}/receipt?format=pdf" target="_blank" class="text-gray-600 hover:text-gray-900 mr-3">
+                <i class="fas fa-print mr-1"></i>Чек
+            </a>
             <button onclick="viewCheck(${check.id})" class="text-green-600 hover:text-green-900">
                 <i class="fas fa-eye mr-1"></i>Просмотр
             </button>
@@ -125,7 +158,8 @@ function hideCreateCheckModal() {
 // ИСПРАВЛЕННАЯ ФУНКЦИЯ loadCafeDishes
 async function loadCafeDishes(cafeId) {
     try {
-        const response = await fetch(`${API_URL}/api/cafe/${cafeId}/menu`);
+        // Плоский список: в чек можно добавить и блюда из скрытых категорий
+        const response = await fetch(`${API_URL}/api/restaurants/${cafeId}/dishes`);
         if (!response.ok) throw new Error(`HTTP ${response.status}: ${response.statusText}`);
 
         // ✅ СОХРАНЯЕМ РЕЗУЛЬТАТ В ПЕРЕМЕННУЮ
@@ -194,21 +228,18 @@ document.getElementById('create-check-form').addEventListener('submit', async fu
         return;
     }
 
+    // Цены и итог считает сервер по текущему меню
     const checkItems = Array.from(selectedDishes).map(checkbox => {
         const dishId = checkbox.value;
         const quantity = parseInt(document.getElementById(`quantity-${dishId}`).value);
-        const priceText = checkbox.nextElementSibling.textContent;
-        const price = parseFloat(priceText.match(/\d+\.?\d*/)[0]);
-        return { dish_id: parseInt(dishId), quantity, price };
+        return { dish_id: parseInt(dishId), quantity };
     });
 
-    const totalAmount = checkItems.reduce((sum, item) => sum + (item.price * item.quantity), 0);
-
     try {
         const response = await fetch(`${API_URL}/api/orders`, {
             method: 'POST',
             headers: { 'Content-Type': 'application/json' },
-            body: JSON.stringify({ restaurant_id: parseInt(cafeId), items: checkItems, total_amount: totalAmount })
+            body: JSON.stringify({ restaurant_id: parseInt(cafeId), items: checkItems })
         });
 
         if (response.ok) {
@@ -216,6 +247,12 @@ document.getElementById('create-check-form').addEventListener('submit', async fu
             hideCreateCheckModal();
             setTimeout(loadRecentChecks, 500);
         } else {
+            const body = await response.json().catch(() => null);
+            if (body && body.items) {
+                const messages = body.items.map(item => `блюдо #${item.dish_id}: ${item.message}`);
+                showNotification('Ошибка в позициях чека: ' + messages.join('; '), 'error');
+                return;
+            }
             throw new Error('Failed to create order');
         }
     } catch (error) {
@@ -288,6 +325,10 @@ function showCafeForm(cafe = null) {
         document.getElementById('cafe-id').value = cafe.id;
         document.getElementById('cafe-name').value = cafe.name;
         document.getElementById('cafe-description').value = cafe.description || '';
+        document.getElementById('cafe-public-base-url').value = cafe.public_base_url || '';
+        document.getElementById('cafe-time-zone').value = cafe.time_zone || '';
+        document.getElementById('cafe-locale').value = cafe.locale || '';
+        document.getElementById('cafe-opening-hours').value = cafe.opening_hours ? JSON.stringify(cafe.opening_hours, null, 2) : '';
         const preview = document.getElementById('cafe-image-preview');
         if (cafe.image_url) {
             preview.src = cafe.image_url;
@@ -386,9 +427,22 @@ document.getElementById('cafe-form')?.addEventListener('submit', async function(
 
         const data = {
             name: document.getElementById('cafe-name').value.trim(),
-            description: document.getElementById('cafe-description').value.trim()
+            description: document.getElementById('cafe-description').value.trim(),
+            public_base_url: document.getElementById('cafe-public-base-url').value.trim(),
+            time_zone: document.getElementById('cafe-time-zone').value.trim(),
+            locale: document.getElementById('cafe-locale').value.trim()
         };
 
+        const openingHours = document.getElementById('cafe-opening-hours').value.trim();
+        if (openingHours) {
+            try {
+                data.opening_hours = JSON.parse(openingHours);
+            } catch (e) {
+                showNotification('Часы работы: неверный JSON', 'error');
+                return;
+            }
+        }
+
         if (!data.name) {
             showNotification('Название кафе обязательно!', 'error');
             return;
@@ -413,7 +467,7 @@ document.getElementById('cafe-form')?.addEventListener('submit', async function(
                 method: 'POST',
                 body: formData
             });
-            if (!imgResponse.ok) throw new Error('Failed to upload image');
+            if (!imgResponse.ok) throw new Error(`Фото не загружено: ${(await imgResponse.text()).trim()}`);
         }
 
         showNotification(isNew ? '✅ Кафе создано' : '✅ Кафе обновлено', 'success');
@@ -482,11 +536,15 @@ document.getElementById('dish-manager-cafe-select').addEventListener('change', a
     if (!cafeId) {
         tbody.innerHTML = '<tr><td colspan="4" class="text-center py-4 text-gray-500">Выберите кафе</td></tr>';
         btnAdd.disabled = true;
+        document.getElementById('menu-draft-status').textContent = '';
+        document.getElementById('btn-publish-menu').disabled = true;
+        document.getElementById('btn-discard-draft').disabled = true;
         return;
     }
 
     btnAdd.disabled = false;
     tbody.innerHTML = '<tr><td colspan="4" class="text-center py-4"><i class="fas fa-spinner fa-spin"></i> Загрузка...</td></tr>';
+    loadMenuDraftStatus(cafeId);
 
     try {
         const response = await fetch(`${API_URL}/api/restaurants/${cafeId}/dishes`);
@@ -519,7 +577,7 @@ document.getElementById('dish-manager-cafe-select').addEventListener('change', a
                 <button onclick='openEditDishModal(${JSON.stringify(dish).replace(/'/g, "&#39;")})' class="text-blue-600 hover:text-blue-900 mr-3">
                     <i class="fas fa-edit"></i>
                 </button>
-                <button onclick="deleteDish(${dish.id})" class="text-red-600 hover:text-red-900">
+                <button onclick="deleteDish(${dish.dish_id})" class="text-red-600 hover:text-red-900">
                     <i class="fas fa-trash"></i>
                 </button>
             </td>
@@ -569,34 +627,26 @@ document.getElementById('dish-form').addEventListener('submit', async function(e
             description: document.getElementById('dish-description').value
         };
 
-        let savedDishId;
-
-        // Если есть ID -> делаем PUT (обновление)
-        // Если нет ID -> делаем POST (создание)
-        if (dishId) {
-            const updateResp = await fetch(`${API_URL}/api/restaurants/${cafeId}/dishes/${dishId}`, {
-                method: 'PUT',
-                headers: { 'Content-Type': 'application/json' },
-                body: JSON.stringify(dishData)
-            });
-            if (!updateResp.ok) throw new Error('Failed to update dish');
-            savedDishId = dishId;
-            showNotification('Блюдо обновлено!', 'success');
-        } else {
-            const createResp = await fetch(`${API_URL}/api/restaurants/${cafeId}/dishes`, {
-                method: 'POST',
-                headers: { 'Content-Type': 'application/json' },
-                body: JSON.stringify(dishData)
-            });
-            if (!createResp.ok) throw new Error('Failed to create dish');
-            const createdDish = await createResp.json();
-            savedDishId = createdDish.id;
-            showNotification('Блюдо создано!', 'success');
-        }
+        // Изменения попадают в черновик меню и видны гостям только после публикации.
+        // Если есть ID -> PUT (правка блюда), если нет -> POST (новое блюдо)
+        const draftUrl = dishId
+            ? `${API_URL}/api/restaurants/${cafeId}/menu/draft/dishes/${dishId}`
+            : `${API_URL}/api/restaurants/${cafeId}/menu/draft/dishes`;
+        const draftResp = await fetch(draftUrl, {
+            method: dishId ? 'PUT' : 'POST',
+            headers: { 'Content-Type': 'application/json' },
+            body: JSON.stringify(dishData)
+        });
+        if (!draftResp.ok) throw new Error('Failed to stage dish');
+        showNotification('Изменение добавлено в черновик', 'success');
 
-        // Загрузка фото (работает одинаково для создания и обновления)
+        // Фото не версионируется: у существующего блюда меняется сразу,
+        // новому блюду его можно добавить после публикации
         const fileInput = document.getElementById('dish-image');
-        if (fileInput.files[0]) {
+        const savedDishId = dishId;
+        if (fileInput.files[0] && !savedDishId) {
+            showNotification('Фото можно загрузить после публикации меню', 'info');
+        } else if (fileInput.files[0]) {
             const formData = new FormData();
             formData.append('image', fileInput.files[0]);
 
@@ -604,7 +654,7 @@ document.getElementById('dish-form').addEventListener('submit', async function(e
                 method: 'POST',
                 body: formData
             });
-            if (!imgResp.ok) console.error("Ошибка загрузки фото");
+            if (!imgResp.ok) showNotification(`❌ Фото не загружено: ${(await imgResp.text()).trim()}`, 'error');
         }
 
         hideDishFormModal();
@@ -681,12 +731,12 @@ async function deleteDish(dishId) {
     const cafeId = document.getElementById('dish-manager-cafe-select').value;
 
     try {
-        const response = await fetch(`${API_URL}/api/restaurants/${cafeId}/dishes/${dishId}`, {
+        const response = await fetch(`${API_URL}/api/restaurants/${cafeId}/menu/draft/dishes/${dishId}`, {
             method: 'DELETE'
         });
 
         if (response.ok) {
-            showNotification('Блюдо удалено', 'success');
+            showNotification('Удаление добавлено в черновик', 'success');
             // Обновляем таблицу
             document.getElementById('dish-manager-cafe-select').dispatchEvent(new Event('change'));
         } else {
@@ -698,6 +748,59 @@ async function deleteDish(dishId) {
     }
 }
 
+// Черновик меню: число неопубликованных изменений и кнопки публикации
+async function loadMenuDraftStatus(cafeId) {
+    const status = document.getElementById('menu-draft-status');
+    try {
+        const [draftResp, versionsResp] = await Promise.all([
+            fetch(`${API_URL}/api/restaurants/${cafeId}/menu/draft`),
+            fetch(`${API_URL}/api/restaurants/${cafeId}/menu/versions`)
+        ]);
+        const changes = draftResp.ok ? await draftResp.json() : [];
+        const versions = versionsResp.ok ? await versionsResp.json() : [];
+        const current = versions.find(v => v.current);
+
+        status.textContent = `Версия меню: ${current ? current.version : 'не публиковалось'}. ` +
+            (changes.length ? `Неопубликованных изменений: ${changes.length}` : 'Черновик пуст');
+        document.getElementById('btn-publish-menu').disabled = changes.length === 0;
+        document.getElementById('btn-discard-draft').disabled = changes.length === 0;
+    } catch (error) {
+        console.error(error);
+        status.textContent = '';
+    }
+}
+
+async function publishMenu() {
+    const cafeId = document.getElementById('dish-manager-cafe-select').value;
+    if (!cafeId || !confirm('Опубликовать изменения меню?')) return;
+
+    try {
+        const response = await fetch(`${API_URL}/api/restaurants/${cafeId}/menu/publish`, { method: 'POST' });
+        if (!response.ok) throw new Error(await response.text());
+        const version = await response.json();
+        showNotification(`Опубликована версия меню ${version.version}`, 'success');
+        document.getElementById('dish-manager-cafe-select').dispatchEvent(new Event('change'));
+    } catch (error) {
+        console.error(error);
+        showNotification('Ошибка публикации меню', 'error');
+    }
+}
+
+async function discardMenuDraft() {
+    const cafeId = document.getElementById('dish-manager-cafe-select').value;
+    if (!cafeId || !confirm('Удалить все неопубликованные изменения?')) return;
+
+    try {
+        const response = await fetch(`${API_URL}/api/restaurants/${cafeId}/menu/draft`, { method: 'DELETE' });
+        if (!response.ok) throw new Error('Failed to discard draft');
+        showNotification('Черновик сброшен', 'success');
+        loadMenuDraftStatus(cafeId);
+    } catch (error) {
+        console.error(error);
+        showNotification('Ошибка сброса черновика', 'error');
+    }
+}
+
 // Закрытие модальных окон по клику вне их
 document.addEventListener('click', function(e) {
     if (e.target.id === 'create-check-modal') hideCreateCheckModal();
@@ -4,8 +4,65 @@ const API_URL = 'http://localhost:8080';
 // Загрузка кафе при загрузке страницы
 document.addEventListener('DOMContentLoaded', function() {
     loadCafes();
+    const tableCode = new URLSearchParams(window.location.search).get('table');
+    if (tableCode) {
+        openTable(tableCode);
+    }
 });
 
+// Гость отсканировал QR-код на столе: меню ресторана и оценка визита
+let currentTableCode = null;
+let visitRating = 0;
+
+async function openTable(code) {
+    try {
+        const response = await fetch(`${API_URL}/api/tables/${encodeURIComponent(code)}`);
+        if (!response.ok) {
+            throw new Error(`HTTP ${response.status}`);
+        }
+        const table = await response.json();
+        currentTableCode = table.code;
+        await showMenu(table.restaurant_id, table.cafe_name);
+        document.getElementById('visit-table-label').textContent = table.label;
+        document.getElementById('visit-section').classList.remove('hidden');
+    } catch (error) {
+        console.error('Ошибка при загрузке стола:', error);
+        showNotification('QR-код стола не найден', 'error');
+    }
+}
+
+function setVisitRating(rating) {
+    visitRating = rating;
+    document.querySelectorAll('#visit-stars button').forEach((star, i) => {
+        star.classList.toggle('text-yellow-400', i < rating);
+        star.classList.toggle('text-gray-300', i >= rating);
+    });
+}
+
+async function submitVisitRating() {
+    if (!visitRating) {
+        showNotification('Выберите оценку', 'error');
+        return;
+    }
+    try {
+        const response = await fetch(`${API_URL}/api/visit-ratings`, {
+            method: 'POST',
+            headers: { 'Content-Type': 'application/json' },
+            body: JSON.stringify({
+                table_code: currentTableCode,
+                rating: visitRating,
+                comment: document.getElementById('visit-comment').value.trim()
+            })
+        });
+        if (!response.ok) throw new Error(await response.text());
+        document.getElementById('visit-section').innerHTML =
+            '<p class="text-green-600 font-medium">Спасибо за оценку!</p>';
+    } catch (error) {
+        console.error('Ошибка при отправке оценки:', error);
+        showNotification('Не удалось отправить оценку', 'error');
+    }
+}
+
 // Загрузка списка кафе
 async function loadCafes() {
     try {
@@ -48,7 +105,7 @@ function createCafeCard(cafe) {
         // Если есть картинка
         imageContent = `
             <div class="h-48 overflow-hidden relative">
-                <img src="${cafe.image_url}" alt="${cafe.name}" class="w-full h-full object-cover transition duration-500 group-hover:scale-110">
+                ${responsiveImage(cafe, cafe.name, 'w-full h-full object-cover transition duration-500 group-hover:scale-110')}
                 <div class="absolute inset-0 bg-black bg-opacity-20 group-hover:bg-opacity-10 transition"></div>
             </div>
         `;
@@ -61,10 +118,18 @@ function createCafeCard(cafe) {
         `;
     }
 
+    // open_now приходит только у кафе с заданными часами работы
+    let openBadge = '';
+    if (cafe.open_now === true) {
+        openBadge = '<span class="ml-2 text-xs font-medium text-green-700 bg-green-100 px-2 py-1 rounded align-middle">Открыто</span>';
+    } else if (cafe.open_now === false) {
+        openBadge = '<span class="ml-2 text-xs font-medium text-gray-600 bg-gray-200 px-2 py-1 rounded align-middle">Закрыто</span>';
+    }
+
     card.innerHTML = `
         ${imageContent}
         <div class="p-6">
-            <h4 class="text-xl font-bold text-gray-900 mb-2">${cafe.name}</h4>
+            <h4 class="text-xl font-bold text-gray-900 mb-2">${cafe.name}${openBadge}</h4>
             <p class="text-gray-600 mb-4 line-clamp-2">${cafe.description || 'Описание отсутствует'}</p>
             <div class="flex items-center justify-between">
                 <span class="text-sm text-gray-500 bg-gray-100 px-2 py-1 rounded">ID: ${cafe.id}</span>
@@ -82,13 +147,13 @@ function createCafeCard(cafe) {
 // ИСПРАВЛЕННАЯ ФУНКЦИЯ showMenu
 async function showMenu(cafeId, cafeName) {
     try {
-        const response = await fetch(`${API_URL}/api/cafe/${cafeId}/menu`);
+        const response = await fetch(`${API_URL}/api/cafe/${cafeId}/menu?available=true`);
         if (!response.ok) {
             throw new Error(`HTTP ${response.status}`);
         }
 
         const data = await response.json();
-        const dishes = Array.isArray(data) ? data : [];
+        const sections = Array.isArray(data) ? data : [];
 
         // ✅ ПРОВЕРКА СУЩЕСТВОВАНИЯ ЭЛЕМЕНТА
         const titleEl = document.getElementById('cafe-name-title');
@@ -104,12 +169,19 @@ async function showMenu(cafeId, cafeName) {
         const menuGrid = document.getElementById('menu-grid');
         menuGrid.innerHTML = '';
 
-        if (dishes.length === 0) {
+        if (sections.every(section => (section.dishes || []).length === 0)) {
             menuGrid.innerHTML = '<p class="text-gray-500 text-center w-full col-span-full">Нет блюд для отображения</p>';
         } else {
-            dishes.forEach(dish => {
-                const dishCard = createDishCard(dish);
-                menuGrid.appendChild(dishCard);
+            sections.forEach(section => {
+                if (!section.dishes || section.dishes.length === 0) return;
+                const heading = document.createElement('h4');
+                heading.className = 'text-2xl font-bold text-gray-800 col-span-full mt-4';
+                heading.textContent = section.category ? section.category.name : 'Другое';
+                menuGrid.appendChild(heading);
+                section.dishes.forEach(dish => {
+                    const dishCard = createDishCard(dish);
+                    menuGrid.appendChild(dishCard);
+                });
             });
         }
     } catch (error) {
@@ -136,9 +208,8 @@ function createDishCard(dish) {
 
     // БЕЗОПАСНАЯ ПРОВЕРКА: Проверяем, что это строка И она не пустая
     if (dish.image_url && typeof dish.image_url === 'string' && dish.image_url.trim() !== "") {
-        // Добавляем timestamp для сброса кэша
-        const imageUrl = `${dish.image_url}?t=${new Date().getTime()}`;
-        imageContent = `<img src="${imageUrl}" alt="${dish.name}" class="w-full h-48 object-cover">`;
+        // Имена файлов содержат хэш содержимого, сбрасывать кэш не нужно
+        imageContent = responsiveImage(dish, dish.name, 'w-full h-48 object-cover');
     } else {
         imageContent = `
             <div class="bg-gradient-to-r from-gray-200 to-gray-300 h-48 flex items-center justify-center">
@@ -212,4 +283,16 @@ function showQRCode(url, checkId) {
     `;
     document.body.appendChild(modal);
     modal.addEventListener('click', (e) => { if (e.target === modal) modal.remove(); });
-}
\ No newline at end of file
+}
+
+// Картинка с миниатюрами: WebP, если браузер умеет, иначе JPEG/PNG из srcset
+function responsiveImage(item, alt, classes) {
+    const sizes = '(min-width: 768px) 33vw, 100vw';
+    if (!item.image_srcset) {
+        return `<img src="${item.image_url}" alt="${alt}" class="${classes}" loading="lazy">`;
+    }
+    const webp = item.image_webp_srcset
+        ? `<source type="image/webp" srcset="${item.image_webp_srcset}" sizes="${sizes}">`
+        : '';
+    return `<picture>${webp}<img src="${item.image_url}" srcset="${item.image_srcset}" sizes="${sizes}" alt="${alt}" class="${classes}" loading="lazy"></picture>`;
+}
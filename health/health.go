@@ -0,0 +1,98 @@
+// Package health serves the probes every service exposes: /livez answers as
+// long as the process runs, /readyz only when its dependencies answer too.
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// checkTimeout bounds each readiness check, so a hanging dependency makes
+// the probe fail instead of time out.
+const checkTimeout = 2 * time.Second
+
+type Check func(ctx context.Context) error
+
+type Checker struct {
+	Service  string
+	names    []string
+	checks   map[string]Check
+	draining atomic.Bool
+}
+
+func NewChecker(service string) *Checker {
+	return &Checker{Service: service, checks: make(map[string]Check)}
+}
+
+// Add registers a dependency /readyz checks, e.g. "postgres" with db.PingContext.
+func (c *Checker) Add(name string, check Check) {
+	if _, ok := c.checks[name]; !ok {
+		c.names = append(c.names, name)
+	}
+	c.checks[name] = check
+}
+
+// Drain makes /readyz fail from now on, while the service finishes the
+// requests it has before shutting down.
+func (c *Checker) Drain() {
+	c.draining.Store(true)
+}
+
+func (c *Checker) RegisterRoutes(r *mux.Router) {
+	r.HandleFunc("/livez", c.Live).Methods("GET")
+	r.HandleFunc("/readyz", c.Ready).Methods("GET")
+}
+
+func (c *Checker) Live(w http.ResponseWriter, r *http.Request) {
+	writeStatus(w, http.StatusOK, map[string]interface{}{"status": "ok", "service": c.Service})
+}
+
+// Ready runs the checks at once and answers 503 with the failing ones when
+// any fails.
+func (c *Checker) Ready(w http.ResponseWriter, r *http.Request) {
+	if c.draining.Load() {
+		writeStatus(w, http.StatusServiceUnavailable, map[string]interface{}{"status": "draining", "service": c.Service})
+		return
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), checkTimeout)
+	defer cancel()
+
+	results := make(map[string]string, len(c.names))
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, name := range c.names {
+		wg.Add(1)
+		go func(name string, check Check) {
+			defer wg.Done()
+			result := "ok"
+			if err := check(ctx); err != nil {
+				result = err.Error()
+			}
+			mu.Lock()
+			results[name] = result
+			mu.Unlock()
+		}(name, c.checks[name])
+	}
+	wg.Wait()
+
+	status, code := "ok", http.StatusOK
+	for _, result := range results {
+		if result != "ok" {
+			status, code = "unavailable", http.StatusServiceUnavailable
+		}
+	}
+	writeStatus(w, code, map[string]interface{}{"status": status, "service": c.Service, "checks": results})
+}
+
+func writeStatus(w http.ResponseWriter, code int, body map[string]interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,66 @@
+package health_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"overcooked-simplified/health"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHealthProbes(t *testing.T) {
+	tests := []struct {
+		name       string
+		path       string
+		redis      error
+		wantCode   int
+		wantStatus string
+		wantChecks map[string]string
+	}{
+		{name: "live while redis is down", path: "/livez", redis: errors.New("connection refused"), wantCode: http.StatusOK, wantStatus: "ok"},
+		{
+			name:       "ready",
+			path:       "/readyz",
+			wantCode:   http.StatusOK,
+			wantStatus: "ok",
+			wantChecks: map[string]string{"postgres": "ok", "redis": "ok"},
+		},
+		{
+			name:       "not ready while redis is down",
+			path:       "/readyz",
+			redis:      errors.New("connection refused"),
+			wantCode:   http.StatusServiceUnavailable,
+			wantStatus: "unavailable",
+			wantChecks: map[string]string{"postgres": "ok", "redis": "connection refused"},
+		},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			checker := health.NewChecker("dish-svc")
+			checker.Add("postgres", func(context.Context) error { return nil })
+			checker.Add("redis", func(context.Context) error { return testCase.redis })
+			router := mux.NewRouter()
+			checker.RegisterRoutes(router)
+
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, testCase.path, nil))
+
+			assert.Equal(t, testCase.wantCode, rec.Code)
+			var body struct {
+				Status string            `json:"status"`
+				Checks map[string]string `json:"checks"`
+			}
+			require.NoError(t, json.NewDecoder(rec.Body).Decode(&body))
+			assert.Equal(t, testCase.wantStatus, body.Status)
+			assert.Equal(t, testCase.wantChecks, body.Checks)
+		})
+	}
+}
@@ -0,0 +1,76 @@
+// Package i18n picks the language dish names and descriptions are shown in.
+// A dish's own name is written in its restaurant's default locale; other
+// languages come from the dish_translations table.
+package i18n
+
+import (
+	"net/http"
+	"sort"
+
+	"golang.org/x/text/language"
+)
+
+// Requested returns the languages a request asks for, most preferred first:
+// the ?lang= parameter if set, otherwise the Accept-Language header.
+func Requested(r *http.Request) []language.Tag {
+	if lang := r.URL.Query().Get("lang"); lang != "" {
+		if tag, err := language.Parse(lang); err == nil {
+			return []language.Tag{tag}
+		}
+	}
+	tags, _, err := language.ParseAcceptLanguage(r.Header.Get("Accept-Language"))
+	if err != nil {
+		return nil
+	}
+	return tags
+}
+
+// Pick chooses the translation to show from the available languages. It
+// returns "" when the restaurant's default locale fits the request at least as
+// well as any translation, or when nothing fits at all.
+func Pick(requested []language.Tag, defaultLocale string, available []string) string {
+	if len(requested) == 0 || len(available) == 0 {
+		return ""
+	}
+	base, err := language.Parse(defaultLocale)
+	if err != nil {
+		base = language.Und
+	}
+	supported := []language.Tag{base}
+	langs := []string{""}
+	for _, lang := range available {
+		tag, err := language.Parse(lang)
+		if err != nil {
+			continue
+		}
+		supported = append(supported, tag)
+		langs = append(langs, lang)
+	}
+	_, index, confidence := language.NewMatcher(supported).Match(requested...)
+	if confidence == language.No {
+		return ""
+	}
+	return langs[index]
+}
+
+// Names is a dish name in the restaurant's default locale together with its
+// translations keyed by language tag.
+type Names struct {
+	Default      string
+	Locale       string
+	Translations map[string]string
+}
+
+// In returns the name to show for the requested languages.
+func (n Names) In(requested []language.Tag) string {
+	available := make([]string, 0, len(n.Translations))
+	for lang := range n.Translations {
+		available = append(available, lang)
+	}
+	// map order would make ties between close languages random
+	sort.Strings(available)
+	if lang := Pick(requested, n.Locale, available); lang != "" {
+		return n.Translations[lang]
+	}
+	return n.Default
+}
@@ -0,0 +1,59 @@
+// Package metrics exposes Prometheus metrics: HTTP requests for every
+// gorilla/mux router, Postgres queries, Redis commands and Kafka messages.
+// Services add their own business metrics next to the code they count.
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+var httpRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+	Name:    "http_request_duration_seconds",
+	Help:    "Time taken to answer HTTP requests, by route template and status code.",
+	Buckets: prometheus.DefBuckets,
+}, []string{"method", "route", "code"})
+
+// Instrument serves /metrics on r and times every request r routes. Routes
+// match in order, so call it before adding a catch-all.
+func Instrument(r *mux.Router) {
+	r.Handle("/metrics", promhttp.Handler()).Methods("GET")
+	r.Use(middleware)
+}
+
+func middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// the template, not the path, so /api/restaurants/{id} is one series
+		route := "unknown"
+		if current := mux.CurrentRoute(r); current != nil {
+			if template, err := current.GetPathTemplate(); err == nil {
+				route = template
+			}
+		}
+		recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		start := time.Now()
+		next.ServeHTTP(recorder, r)
+		httpRequestDuration.WithLabelValues(r.Method, route, strconv.Itoa(recorder.status)).Observe(time.Since(start).Seconds())
+	})
+}
+
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// Unwrap lets http.ResponseController reach the writer underneath.
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
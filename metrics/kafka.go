@@ -0,0 +1,38 @@
+package metrics
+
+import (
+	"strconv"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/segmentio/kafka-go"
+)
+
+var (
+	kafkaPublished = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "kafka_messages_published_total",
+		Help: "Messages written to Kafka, by topic and result.",
+	}, []string{"topic", "result"})
+	kafkaConsumed = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "kafka_messages_consumed_total",
+		Help: "Messages read from Kafka, by topic and result.",
+	}, []string{"topic", "result"})
+	kafkaConsumerLag = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "kafka_consumer_lag",
+		Help: "Messages in the partition after the last one consumed.",
+	}, []string{"topic", "partition"})
+)
+
+func KafkaPublished(topic string, count int, err error) {
+	kafkaPublished.WithLabelValues(topic, result(err)).Add(float64(count))
+}
+
+// KafkaConsumed counts a message read from Kafka; a message that could not
+// be handled counts with err, and its lag is recorded either way.
+func KafkaConsumed(message kafka.Message, err error) {
+	kafkaConsumed.WithLabelValues(message.Topic, result(err)).Inc()
+	if message.HighWaterMark > 0 {
+		lag := message.HighWaterMark - message.Offset - 1
+		kafkaConsumerLag.WithLabelValues(message.Topic, strconv.Itoa(message.Partition)).Set(float64(lag))
+	}
+}
@@ -0,0 +1,50 @@
+package metrics_test
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"overcooked-simplified/metrics"
+
+	"github.com/gorilla/mux"
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func scrape(t *testing.T, router http.Handler) string {
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	body, _ := io.ReadAll(rec.Body)
+	return string(body)
+}
+
+func TestMetrics(t *testing.T) {
+	router := mux.NewRouter()
+	metrics.Instrument(router)
+	router.HandleFunc("/api/restaurants/{id}", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "not found", http.StatusNotFound)
+	}).Methods("GET")
+
+	t.Run("requests by route template", func(t *testing.T) {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/restaurants/42", nil))
+
+		body := scrape(t, router)
+
+		assert.Contains(t, body, `http_request_duration_seconds_count{code="404",method="GET",route="/api/restaurants/{id}"} 1`)
+		assert.NotContains(t, body, "/api/restaurants/42")
+	})
+
+	t.Run("kafka consumer lag", func(t *testing.T) {
+		metrics.KafkaConsumed(kafka.Message{Topic: "reviews", Partition: 2, Offset: 9, HighWaterMark: 15}, nil)
+		metrics.KafkaConsumed(kafka.Message{Topic: "reviews", Partition: 2, Offset: 10, HighWaterMark: 15}, errors.New("bad json"))
+
+		body := scrape(t, router)
+
+		assert.Contains(t, body, `kafka_consumer_lag{partition="2",topic="reviews"} 4`)
+		assert.Contains(t, body, `kafka_messages_consumed_total{result="ok",topic="reviews"} 1`)
+		assert.Contains(t, body, `kafka_messages_consumed_total{result="error",topic="reviews"} 1`)
+	})
+}
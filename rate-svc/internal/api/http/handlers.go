@@ -1,10 +1,14 @@
 package httpapi
 
 import (
+	"database/sql"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"strconv"
 
+	"overcooked-simplified/health"
+	"overcooked-simplified/i18n"
 	"overcooked-simplified/rate-svc/internal/domain"
 	"overcooked-simplified/rate-svc/internal/service"
 
@@ -13,6 +17,11 @@ import (
 
 type Handler struct {
 	Reviews service.ReviewServiceInterface
+	// Visits takes visit ratings from table QR codes; routes are only
+	// registered when it is set.
+	Visits service.VisitServiceInterface
+	// Health serves /livez and /readyz when set.
+	Health *health.Checker
 }
 
 func NewHandler(reviews service.ReviewServiceInterface) *Handler {
@@ -20,9 +29,16 @@ func NewHandler(reviews service.ReviewServiceInterface) *Handler {
 }
 
 func (h *Handler) RegisterRoutes(r *mux.Router) {
+	if h.Health != nil {
+		h.Health.RegisterRoutes(r)
+	}
 	r.HandleFunc("/api/restaurants/{restaurantId}/dishes/{dishId}/reviews", h.createReview).Methods("POST")
 	r.HandleFunc("/api/restaurants/{restaurantId}/dishes/{dishId}/reviews", h.getDishReviews).Methods("GET")
 	r.HandleFunc("/api/reviews", h.createBulkReviews).Methods("POST")
+	if h.Visits != nil {
+		r.HandleFunc("/api/visit-ratings", h.createVisitRating).Methods("POST")
+		r.HandleFunc("/api/visit-ratings/{id}/order", h.linkVisitOrder).Methods("PUT")
+	}
 }
 
 func (h *Handler) createReview(w http.ResponseWriter, r *http.Request) {
@@ -52,12 +68,13 @@ func (h *Handler) getDishReviews(w http.ResponseWriter, r *http.Request) {
 	dishID, _ := strconv.Atoi(mux.Vars(r)["dishId"])
 	restaurantID, _ := strconv.Atoi(mux.Vars(r)["restaurantId"])
 
-	reviews, err := h.Reviews.ListDishReviews(dishID, restaurantID)
+	reviews, err := h.Reviews.ListDishReviews(dishID, restaurantID, i18n.Requested(r))
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
+	w.Header().Set("Vary", "Accept-Language")
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(reviews)
 }
@@ -131,3 +148,53 @@ func (h *Handler) createBulkReviews(w http.ResponseWriter, r *http.Request) {
 		"failed":    len(results) - successCount,
 	})
 }
+
+func (h *Handler) createVisitRating(w http.ResponseWriter, r *http.Request) {
+	var rating domain.VisitRating
+	if err := json.NewDecoder(r.Body).Decode(&rating); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.Visits.Rate(&rating); err != nil {
+		writeVisitError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(rating)
+}
+
+func (h *Handler) linkVisitOrder(w http.ResponseWriter, r *http.Request) {
+	ratingID, _ := strconv.Atoi(mux.Vars(r)["id"])
+	var payload struct {
+		OrderID int `json:"order_id"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil || payload.OrderID <= 0 {
+		http.Error(w, "Missing order_id", http.StatusBadRequest)
+		return
+	}
+
+	rating, err := h.Visits.LinkOrder(ratingID, payload.OrderID)
+	if err != nil {
+		writeVisitError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(rating)
+}
+
+func writeVisitError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		http.Error(w, "Visit rating not found", http.StatusNotFound)
+	case errors.Is(err, service.ErrInvalidRating), errors.Is(err, service.ErrUnknownTable), errors.Is(err, service.ErrOrderNotAtTable):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, service.ErrVisitLinked):
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -9,6 +9,21 @@ type Review struct {
 	RestaurantID int       `json:"restaurant_id"`
 	Rating       int       `json:"rating"`
 	Comment      string    `json:"comment"`
+	DishName     string    `json:"dish_name,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// VisitRating is a guest's rating of the whole visit, left from the static
+// QR code on a table. OrderID stays nil until an order of that table is
+// linked to it.
+type VisitRating struct {
+	ID           int       `json:"id"`
+	RestaurantID int       `json:"restaurant_id"`
+	TableID      int       `json:"table_id"`
+	TableCode    string    `json:"table_code,omitempty"`
+	OrderID      *int      `json:"order_id,omitempty"`
+	Rating       int       `json:"rating"`
+	Comment      string    `json:"comment"`
 	CreatedAt    time.Time `json:"created_at"`
 }
 
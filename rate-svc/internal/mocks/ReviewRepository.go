@@ -3,6 +3,8 @@
 package mocks
 
 import (
+	context "context"
+	i18n "overcooked-simplified/i18n"
 	domain "overcooked-simplified/rate-svc/internal/domain"
 
 	mock "github.com/stretchr/testify/mock"
@@ -13,9 +15,39 @@ type ReviewRepository struct {
 	mock.Mock
 }
 
-// GetExistingReviewID provides a mock function with given fields: dishID, orderID, restaurantID
-func (_m *ReviewRepository) GetExistingReviewID(dishID int, orderID int, restaurantID int) (int, error) {
-	ret := _m.Called(dishID, orderID, restaurantID)
+// DishNames provides a mock function with given fields: dishIDs
+func (_m *ReviewRepository) DishNames(dishIDs []int) (map[int]i18n.Names, error) {
+	ret := _m.Called(dishIDs)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DishNames")
+	}
+
+	var r0 map[int]i18n.Names
+	var r1 error
+	if rf, ok := ret.Get(0).(func([]int) (map[int]i18n.Names, error)); ok {
+		return rf(dishIDs)
+	}
+	if rf, ok := ret.Get(0).(func([]int) map[int]i18n.Names); ok {
+		r0 = rf(dishIDs)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[int]i18n.Names)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func([]int) error); ok {
+		r1 = rf(dishIDs)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetExistingReviewID provides a mock function with given fields: ctx, dishID, orderID, restaurantID
+func (_m *ReviewRepository) GetExistingReviewID(ctx context.Context, dishID int, orderID int, restaurantID int) (int, error) {
+	ret := _m.Called(ctx, dishID, orderID, restaurantID)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetExistingReviewID")
@@ -23,17 +55,17 @@ func (_m *ReviewRepository) GetExistingReviewID(dishID int, orderID int, restaur
 
 	var r0 int
 	var r1 error
-	if rf, ok := ret.Get(0).(func(int, int, int) (int, error)); ok {
-		return rf(dishID, orderID, restaurantID)
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, int) (int, error)); ok {
+		return rf(ctx, dishID, orderID, restaurantID)
 	}
-	if rf, ok := ret.Get(0).(func(int, int, int) int); ok {
-		r0 = rf(dishID, orderID, restaurantID)
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, int) int); ok {
+		r0 = rf(ctx, dishID, orderID, restaurantID)
 	} else {
 		r0 = ret.Get(0).(int)
 	}
 
-	if rf, ok := ret.Get(1).(func(int, int, int) error); ok {
-		r1 = rf(dishID, orderID, restaurantID)
+	if rf, ok := ret.Get(1).(func(context.Context, int, int, int) error); ok {
+		r1 = rf(ctx, dishID, orderID, restaurantID)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -41,17 +73,17 @@ func (_m *ReviewRepository) GetExistingReviewID(dishID int, orderID int, restaur
 	return r0, r1
 }
 
-// InsertReview provides a mock function with given fields: review
-func (_m *ReviewRepository) InsertReview(review *domain.Review) error {
-	ret := _m.Called(review)
+// InsertReview provides a mock function with given fields: ctx, review
+func (_m *ReviewRepository) InsertReview(ctx context.Context, review *domain.Review) error {
+	ret := _m.Called(ctx, review)
 
 	if len(ret) == 0 {
 		panic("no return value specified for InsertReview")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(*domain.Review) error); ok {
-		r0 = rf(review)
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Review) error); ok {
+		r0 = rf(ctx, review)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -89,17 +121,17 @@ func (_m *ReviewRepository) ListDishReviews(dishID int, restaurantID int) ([]dom
 	return r0, r1
 }
 
-// UpdateReview provides a mock function with given fields: id, review
-func (_m *ReviewRepository) UpdateReview(id int, review *domain.Review) error {
-	ret := _m.Called(id, review)
+// UpdateReview provides a mock function with given fields: ctx, id, review
+func (_m *ReviewRepository) UpdateReview(ctx context.Context, id int, review *domain.Review) error {
+	ret := _m.Called(ctx, id, review)
 
 	if len(ret) == 0 {
 		panic("no return value specified for UpdateReview")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(int, *domain.Review) error); ok {
-		r0 = rf(id, review)
+	if rf, ok := ret.Get(0).(func(context.Context, int, *domain.Review) error); ok {
+		r0 = rf(ctx, id, review)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -107,9 +139,9 @@ func (_m *ReviewRepository) UpdateReview(id int, review *domain.Review) error {
 	return r0
 }
 
-// ValidateDishInOrder provides a mock function with given fields: dishID, orderID, restaurantID
-func (_m *ReviewRepository) ValidateDishInOrder(dishID int, orderID int, restaurantID int) (bool, error) {
-	ret := _m.Called(dishID, orderID, restaurantID)
+// ValidateDishInOrder provides a mock function with given fields: ctx, dishID, orderID, restaurantID
+func (_m *ReviewRepository) ValidateDishInOrder(ctx context.Context, dishID int, orderID int, restaurantID int) (bool, error) {
+	ret := _m.Called(ctx, dishID, orderID, restaurantID)
 
 	if len(ret) == 0 {
 		panic("no return value specified for ValidateDishInOrder")
@@ -117,17 +149,17 @@ func (_m *ReviewRepository) ValidateDishInOrder(dishID int, orderID int, restaur
 
 	var r0 bool
 	var r1 error
-	if rf, ok := ret.Get(0).(func(int, int, int) (bool, error)); ok {
-		return rf(dishID, orderID, restaurantID)
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, int) (bool, error)); ok {
+		return rf(ctx, dishID, orderID, restaurantID)
 	}
-	if rf, ok := ret.Get(0).(func(int, int, int) bool); ok {
-		r0 = rf(dishID, orderID, restaurantID)
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, int) bool); ok {
+		r0 = rf(ctx, dishID, orderID, restaurantID)
 	} else {
 		r0 = ret.Get(0).(bool)
 	}
 
-	if rf, ok := ret.Get(1).(func(int, int, int) error); ok {
-		r1 = rf(dishID, orderID, restaurantID)
+	if rf, ok := ret.Get(1).(func(context.Context, int, int, int) error); ok {
+		r1 = rf(ctx, dishID, orderID, restaurantID)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -6,6 +6,8 @@ import (
 	context "context"
 	domain "overcooked-simplified/rate-svc/internal/domain"
 
+	language "golang.org/x/text/language"
+
 	mock "github.com/stretchr/testify/mock"
 )
 
@@ -32,9 +34,9 @@ func (_m *ReviewServiceInterface) CreateOrUpdate(ctx context.Context, review *do
 	return r0
 }
 
-// ListDishReviews provides a mock function with given fields: dishID, restaurantID
-func (_m *ReviewServiceInterface) ListDishReviews(dishID int, restaurantID int) ([]domain.Review, error) {
-	ret := _m.Called(dishID, restaurantID)
+// ListDishReviews provides a mock function with given fields: dishID, restaurantID, requested
+func (_m *ReviewServiceInterface) ListDishReviews(dishID int, restaurantID int, requested []language.Tag) ([]domain.Review, error) {
+	ret := _m.Called(dishID, restaurantID, requested)
 
 	if len(ret) == 0 {
 		panic("no return value specified for ListDishReviews")
@@ -42,19 +44,19 @@ func (_m *ReviewServiceInterface) ListDishReviews(dishID int, restaurantID int)
 
 	var r0 []domain.Review
 	var r1 error
-	if rf, ok := ret.Get(0).(func(int, int) ([]domain.Review, error)); ok {
-		return rf(dishID, restaurantID)
+	if rf, ok := ret.Get(0).(func(int, int, []language.Tag) ([]domain.Review, error)); ok {
+		return rf(dishID, restaurantID, requested)
 	}
-	if rf, ok := ret.Get(0).(func(int, int) []domain.Review); ok {
-		r0 = rf(dishID, restaurantID)
+	if rf, ok := ret.Get(0).(func(int, int, []language.Tag) []domain.Review); ok {
+		r0 = rf(dishID, restaurantID, requested)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]domain.Review)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(int, int) error); ok {
-		r1 = rf(dishID, restaurantID)
+	if rf, ok := ret.Get(1).(func(int, int, []language.Tag) error); ok {
+		r1 = rf(dishID, restaurantID, requested)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -0,0 +1,157 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	domain "overcooked-simplified/rate-svc/internal/domain"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// VisitRepository is an autogenerated mock type for the VisitRepository type
+type VisitRepository struct {
+	mock.Mock
+}
+
+// FindTable provides a mock function with given fields: code
+func (_m *VisitRepository) FindTable(code string) (int, int, error) {
+	ret := _m.Called(code)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindTable")
+	}
+
+	var r0 int
+	var r1 int
+	var r2 error
+	if rf, ok := ret.Get(0).(func(string) (int, int, error)); ok {
+		return rf(code)
+	}
+	if rf, ok := ret.Get(0).(func(string) int); ok {
+		r0 = rf(code)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(string) int); ok {
+		r1 = rf(code)
+	} else {
+		r1 = ret.Get(1).(int)
+	}
+
+	if rf, ok := ret.Get(2).(func(string) error); ok {
+		r2 = rf(code)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetVisitRating provides a mock function with given fields: id
+func (_m *VisitRepository) GetVisitRating(id int) (*domain.VisitRating, error) {
+	ret := _m.Called(id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetVisitRating")
+	}
+
+	var r0 *domain.VisitRating
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int) (*domain.VisitRating, error)); ok {
+		return rf(id)
+	}
+	if rf, ok := ret.Get(0).(func(int) *domain.VisitRating); ok {
+		r0 = rf(id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.VisitRating)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(int) error); ok {
+		r1 = rf(id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// InsertVisitRating provides a mock function with given fields: rating
+func (_m *VisitRepository) InsertVisitRating(rating *domain.VisitRating) error {
+	ret := _m.Called(rating)
+
+	if len(ret) == 0 {
+		panic("no return value specified for InsertVisitRating")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*domain.VisitRating) error); ok {
+		r0 = rf(rating)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// OrderTable provides a mock function with given fields: orderID, restaurantID
+func (_m *VisitRepository) OrderTable(orderID int, restaurantID int) (int, error) {
+	ret := _m.Called(orderID, restaurantID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for OrderTable")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int, int) (int, error)); ok {
+		return rf(orderID, restaurantID)
+	}
+	if rf, ok := ret.Get(0).(func(int, int) int); ok {
+		r0 = rf(orderID, restaurantID)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(int, int) error); ok {
+		r1 = rf(orderID, restaurantID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SetVisitOrder provides a mock function with given fields: id, orderID
+func (_m *VisitRepository) SetVisitOrder(id int, orderID int) error {
+	ret := _m.Called(id, orderID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetVisitOrder")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int, int) error); ok {
+		r0 = rf(id, orderID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewVisitRepository creates a new instance of VisitRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewVisitRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *VisitRepository {
+	mock := &VisitRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,76 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	domain "overcooked-simplified/rate-svc/internal/domain"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// VisitServiceInterface is an autogenerated mock type for the VisitServiceInterface type
+type VisitServiceInterface struct {
+	mock.Mock
+}
+
+// LinkOrder provides a mock function with given fields: ratingID, orderID
+func (_m *VisitServiceInterface) LinkOrder(ratingID int, orderID int) (*domain.VisitRating, error) {
+	ret := _m.Called(ratingID, orderID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for LinkOrder")
+	}
+
+	var r0 *domain.VisitRating
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int, int) (*domain.VisitRating, error)); ok {
+		return rf(ratingID, orderID)
+	}
+	if rf, ok := ret.Get(0).(func(int, int) *domain.VisitRating); ok {
+		r0 = rf(ratingID, orderID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.VisitRating)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(int, int) error); ok {
+		r1 = rf(ratingID, orderID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Rate provides a mock function with given fields: rating
+func (_m *VisitServiceInterface) Rate(rating *domain.VisitRating) error {
+	ret := _m.Called(rating)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Rate")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*domain.VisitRating) error); ok {
+		r0 = rf(rating)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewVisitServiceInterface creates a new instance of VisitServiceInterface. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewVisitServiceInterface(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *VisitServiceInterface {
+	mock := &VisitServiceInterface{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
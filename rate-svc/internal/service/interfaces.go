@@ -2,20 +2,41 @@ package service
 
 import (
 	"context"
+	"overcooked-simplified/i18n"
 	"overcooked-simplified/rate-svc/internal/domain"
+
+	"golang.org/x/text/language"
 )
 
 type ReviewServiceInterface interface {
 	CreateOrUpdate(ctx context.Context, review *domain.Review) error
-	ListDishReviews(dishID, restaurantID int) ([]domain.Review, error)
+	ListDishReviews(dishID, restaurantID int, requested []language.Tag) ([]domain.Review, error)
+}
+
+type VisitServiceInterface interface {
+	Rate(rating *domain.VisitRating) error
+	LinkOrder(ratingID, orderID int) (*domain.VisitRating, error)
 }
 
 type ReviewRepository interface {
-	ValidateDishInOrder(dishID, orderID, restaurantID int) (bool, error)
-	GetExistingReviewID(dishID, orderID, restaurantID int) (int, error)
-	InsertReview(review *domain.Review) error
-	UpdateReview(id int, review *domain.Review) error
+	ValidateDishInOrder(ctx context.Context, dishID, orderID, restaurantID int) (bool, error)
+	GetExistingReviewID(ctx context.Context, dishID, orderID, restaurantID int) (int, error)
+	InsertReview(ctx context.Context, review *domain.Review) error
+	UpdateReview(ctx context.Context, id int, review *domain.Review) error
 	ListDishReviews(dishID, restaurantID int) ([]domain.Review, error)
+	// DishNames returns each dish's name with its translations, keyed by
+	// dish ID.
+	DishNames(dishIDs []int) (map[int]i18n.Names, error)
+}
+
+type VisitRepository interface {
+	// FindTable resolves a table QR code to the table and its restaurant.
+	FindTable(code string) (tableID, restaurantID int, err error)
+	// OrderTable returns the table an order was placed at, 0 for none.
+	OrderTable(orderID, restaurantID int) (int, error)
+	InsertVisitRating(rating *domain.VisitRating) error
+	GetVisitRating(id int) (*domain.VisitRating, error)
+	SetVisitOrder(id, orderID int) error
 }
 
 type ReviewCache interface {
@@ -29,3 +50,4 @@ type ReviewPublisher interface {
 }
 
 var _ ReviewServiceInterface = (*ReviewService)(nil)
+var _ VisitServiceInterface = (*VisitService)(nil)
@@ -0,0 +1,18 @@
+package service
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	reviewsSaved = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "reviews_saved_total",
+		Help: "Dish reviews saved, by whether they were created or updated.",
+	}, []string{"action"})
+	reviewRatings = promauto.NewHistogram(prometheus.HistogramOpts{
+		Name:    "review_rating",
+		Help:    "Ratings of new dish reviews, 1 to 5 stars; edits are not counted again.",
+		Buckets: []float64{1, 2, 3, 4, 5},
+	})
+)
@@ -7,6 +7,8 @@ import (
 	"time"
 
 	"overcooked-simplified/rate-svc/internal/domain"
+
+	"golang.org/x/text/language"
 )
 
 var (
@@ -30,7 +32,7 @@ func NewReviewService(repository ReviewRepository, cache ReviewCache, publisher
 
 func (s *ReviewService) CreateOrUpdate(ctx context.Context, review *domain.Review) error {
 	// 1. Validate that the dish is actually part of the order/check
-	valid, err := s.repository.ValidateDishInOrder(review.DishID, review.OrderID, review.RestaurantID)
+	valid, err := s.repository.ValidateDishInOrder(ctx, review.DishID, review.OrderID, review.RestaurantID)
 	if err != nil {
 		return fmt.Errorf("failed to validate order: %w", err)
 	}
@@ -43,23 +45,27 @@ func (s *ReviewService) CreateOrUpdate(ctx context.Context, review *domain.Revie
 	// preventing the code from reaching the Update logic below.
 
 	// 2. Check Database to see if this is an Insert or an Update
-	existingID, err := s.repository.GetExistingReviewID(review.DishID, review.OrderID, review.RestaurantID)
+	existingID, err := s.repository.GetExistingReviewID(ctx, review.DishID, review.OrderID, review.RestaurantID)
 
 	// If err is nil and ID > 0, it means the review exists in DB
 	isUpdate := err == nil && existingID > 0
 
 	if isUpdate {
 		// UPDATE PATH
-		if err := s.repository.UpdateReview(existingID, review); err != nil {
+		if err := s.repository.UpdateReview(ctx, existingID, review); err != nil {
 			return err
 		}
 		// Set the ID to the existing one so the response is correct
 		review.ID = existingID
+		reviewsSaved.WithLabelValues("updated").Inc()
 	} else {
 		// INSERT PATH
-		if err := s.repository.InsertReview(review); err != nil {
+		if err := s.repository.InsertReview(ctx, review); err != nil {
 			return err
 		}
+		reviewsSaved.WithLabelValues("created").Inc()
+		// only new reviews, so users who edit theirs do not count twice
+		reviewRatings.Observe(float64(review.Rating))
 	}
 
 	// 3. Update/Refresh the Cache Marker
@@ -88,6 +94,22 @@ func (s *ReviewService) CreateOrUpdate(ctx context.Context, review *domain.Revie
 	return nil
 }
 
-func (s *ReviewService) ListDishReviews(dishID, restaurantID int) ([]domain.Review, error) {
-	return s.repository.ListDishReviews(dishID, restaurantID)
+// ListDishReviews returns the reviews of a dish, each carrying the dish name
+// in the requested language.
+func (s *ReviewService) ListDishReviews(dishID, restaurantID int, requested []language.Tag) ([]domain.Review, error) {
+	reviews, err := s.repository.ListDishReviews(dishID, restaurantID)
+	if err != nil || len(reviews) == 0 {
+		return reviews, err
+	}
+	names, err := s.repository.DishNames([]int{dishID})
+	if err != nil {
+		return nil, err
+	}
+	if n, ok := names[dishID]; ok {
+		name := n.In(requested)
+		for i := range reviews {
+			reviews[i].DishName = name
+		}
+	}
+	return reviews, nil
 }
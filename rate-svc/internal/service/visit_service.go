@@ -0,0 +1,76 @@
+package service
+
+import (
+	"database/sql"
+	"errors"
+	"strings"
+
+	"overcooked-simplified/rate-svc/internal/domain"
+)
+
+var (
+	ErrInvalidRating   = errors.New("rating must be between 1 and 5")
+	ErrUnknownTable    = errors.New("unknown table")
+	ErrOrderNotAtTable = errors.New("order was not placed at this table")
+	ErrVisitLinked     = errors.New("visit rating is already linked to another order")
+)
+
+// VisitService takes ratings of a visit from the table QR code. Guests can
+// rate before their order is known; the order is linked afterwards, and only
+// an order placed at the same table qualifies.
+type VisitService struct {
+	repository VisitRepository
+}
+
+func NewVisitService(repository VisitRepository) *VisitService {
+	return &VisitService{repository: repository}
+}
+
+func (s *VisitService) Rate(rating *domain.VisitRating) error {
+	if rating.Rating < 1 || rating.Rating > 5 {
+		return ErrInvalidRating
+	}
+	tableID, restaurantID, err := s.repository.FindTable(strings.ToLower(strings.TrimSpace(rating.TableCode)))
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrUnknownTable
+	}
+	if err != nil {
+		return err
+	}
+	rating.TableID, rating.RestaurantID = tableID, restaurantID
+	if rating.OrderID != nil {
+		if err := s.checkOrderTable(*rating.OrderID, rating); err != nil {
+			return err
+		}
+	}
+	return s.repository.InsertVisitRating(rating)
+}
+
+func (s *VisitService) LinkOrder(ratingID, orderID int) (*domain.VisitRating, error) {
+	rating, err := s.repository.GetVisitRating(ratingID)
+	if err != nil {
+		return nil, err
+	}
+	if rating.OrderID != nil {
+		if *rating.OrderID == orderID {
+			return rating, nil
+		}
+		return nil, ErrVisitLinked
+	}
+	if err := s.checkOrderTable(orderID, rating); err != nil {
+		return nil, err
+	}
+	if err := s.repository.SetVisitOrder(ratingID, orderID); err != nil {
+		return nil, err
+	}
+	rating.OrderID = &orderID
+	return rating, nil
+}
+
+func (s *VisitService) checkOrderTable(orderID int, rating *domain.VisitRating) error {
+	tableID, err := s.repository.OrderTable(orderID, rating.RestaurantID)
+	if errors.Is(err, sql.ErrNoRows) || (err == nil && (tableID == 0 || tableID != rating.TableID)) {
+		return ErrOrderNotAtTable
+	}
+	return err
+}
@@ -5,7 +5,9 @@ import (
 	"encoding/json"
 	"strconv"
 
+	"overcooked-simplified/metrics"
 	"overcooked-simplified/rate-svc/internal/domain"
+	"overcooked-simplified/tracing"
 
 	"github.com/segmentio/kafka-go"
 )
@@ -20,8 +22,13 @@ func NewKafkaPublisher(writer *kafka.Writer) *KafkaPublisher {
 
 func (p *KafkaPublisher) PublishReview(ctx context.Context, msg domain.KafkaMessage) error {
 	payload, _ := json.Marshal(msg)
-	return p.Writer.WriteMessages(ctx, kafka.Message{
+	message := kafka.Message{
 		Key:   []byte(strconv.Itoa(msg.DishID)),
 		Value: payload,
-	})
+	}
+	done := tracing.StartPublish(ctx, p.Writer.Topic, &message)
+	err := p.Writer.WriteMessages(ctx, message)
+	done(err)
+	metrics.KafkaPublished(p.Writer.Topic, 1, err)
+	return err
 }
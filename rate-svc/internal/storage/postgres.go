@@ -1,10 +1,14 @@
 package storage
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 
+	"overcooked-simplified/i18n"
 	"overcooked-simplified/rate-svc/internal/domain"
+
+	"github.com/lib/pq"
 )
 
 type PostgresRepository struct {
@@ -15,9 +19,9 @@ func NewPostgresRepository(db *sql.DB) *PostgresRepository {
 	return &PostgresRepository{DB: db}
 }
 
-func (r *PostgresRepository) ValidateDishInOrder(dishID, orderID, restaurantID int) (bool, error) {
+func (r *PostgresRepository) ValidateDishInOrder(ctx context.Context, dishID, orderID, restaurantID int) (bool, error) {
 	var exists bool
-	err := r.DB.QueryRow(`
+	err := r.DB.QueryRowContext(ctx, `
 		SELECT EXISTS(
 			SELECT 1 FROM order_items oi
 			JOIN orders o ON oi.order_id = o.id
@@ -27,9 +31,9 @@ func (r *PostgresRepository) ValidateDishInOrder(dishID, orderID, restaurantID i
 	return exists, err
 }
 
-func (r *PostgresRepository) GetExistingReviewID(dishID, orderID, restaurantID int) (int, error) {
+func (r *PostgresRepository) GetExistingReviewID(ctx context.Context, dishID, orderID, restaurantID int) (int, error) {
 	var id int
-	err := r.DB.QueryRow(`
+	err := r.DB.QueryRowContext(ctx, `
 		SELECT id FROM reviews
 		WHERE dish_id = $1 AND order_id = $2 AND restaurant_id = $3
 	`, dishID, orderID, restaurantID).Scan(&id)
@@ -39,8 +43,8 @@ func (r *PostgresRepository) GetExistingReviewID(dishID, orderID, restaurantID i
 	return id, nil
 }
 
-func (r *PostgresRepository) InsertReview(review *domain.Review) error {
-	return r.DB.QueryRow(`
+func (r *PostgresRepository) InsertReview(ctx context.Context, review *domain.Review) error {
+	return r.DB.QueryRowContext(ctx, `
 		INSERT INTO reviews (dish_id, order_id, restaurant_id, rating, comment)
 		VALUES ($1, $2, $3, $4, $5)
 		RETURNING id, created_at
@@ -48,8 +52,8 @@ func (r *PostgresRepository) InsertReview(review *domain.Review) error {
 		Scan(&review.ID, &review.CreatedAt)
 }
 
-func (r *PostgresRepository) UpdateReview(id int, review *domain.Review) error {
-	_, err := r.DB.Exec(`
+func (r *PostgresRepository) UpdateReview(ctx context.Context, id int, review *domain.Review) error {
+	_, err := r.DB.ExecContext(ctx, `
 		UPDATE reviews
 		SET rating = $1, comment = $2, created_at = CURRENT_TIMESTAMP
 		WHERE id = $3
@@ -126,3 +130,35 @@ func (r *PostgresRepository) GlobalDistribution() (map[string]int, error) {
 	}
 	return distribution, nil
 }
+
+func (r *PostgresRepository) DishNames(dishIDs []int) (map[int]i18n.Names, error) {
+	rows, err := r.DB.Query(`
+		SELECT d.id, d.name, r.locale, t.lang, t.name
+		FROM dishes d
+		JOIN restaurants r ON r.id = d.restaurant_id
+		LEFT JOIN dish_translations t ON t.dish_id = d.id
+		WHERE d.id = ANY($1)`, pq.Array(dishIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	names := make(map[int]i18n.Names)
+	for rows.Next() {
+		var dishID int
+		var name, locale string
+		var lang, translated sql.NullString
+		if err := rows.Scan(&dishID, &name, &locale, &lang, &translated); err != nil {
+			return nil, err
+		}
+		n, ok := names[dishID]
+		if !ok {
+			n = i18n.Names{Default: name, Locale: locale, Translations: map[string]string{}}
+		}
+		if lang.Valid {
+			n.Translations[lang.String] = translated.String
+		}
+		names[dishID] = n
+	}
+	return names, rows.Err()
+}
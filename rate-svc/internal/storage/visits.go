@@ -0,0 +1,57 @@
+package storage
+
+import (
+	"database/sql"
+
+	"overcooked-simplified/rate-svc/internal/domain"
+)
+
+func (r *PostgresRepository) FindTable(code string) (int, int, error) {
+	var tableID, restaurantID int
+	err := r.DB.QueryRow(`
+		SELECT t.id, t.restaurant_id
+		FROM tables t
+		JOIN restaurants rs ON rs.id = t.restaurant_id
+		WHERE t.code = $1 AND rs.deleted_at IS NULL
+	`, code).Scan(&tableID, &restaurantID)
+	return tableID, restaurantID, err
+}
+
+func (r *PostgresRepository) OrderTable(orderID, restaurantID int) (int, error) {
+	var tableID sql.NullInt64
+	err := r.DB.QueryRow(`
+		SELECT table_id FROM orders WHERE id = $1 AND restaurant_id = $2
+	`, orderID, restaurantID).Scan(&tableID)
+	return int(tableID.Int64), err
+}
+
+func (r *PostgresRepository) InsertVisitRating(rating *domain.VisitRating) error {
+	return r.DB.QueryRow(`
+		INSERT INTO visit_ratings (restaurant_id, table_id, order_id, rating, comment)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at
+	`, rating.RestaurantID, rating.TableID, rating.OrderID, rating.Rating, rating.Comment).
+		Scan(&rating.ID, &rating.CreatedAt)
+}
+
+func (r *PostgresRepository) GetVisitRating(id int) (*domain.VisitRating, error) {
+	var rating domain.VisitRating
+	var tableID, orderID sql.NullInt64
+	if err := r.DB.QueryRow(`
+		SELECT id, restaurant_id, table_id, order_id, rating, COALESCE(comment, ''), created_at
+		FROM visit_ratings WHERE id = $1
+	`, id).Scan(&rating.ID, &rating.RestaurantID, &tableID, &orderID, &rating.Rating, &rating.Comment, &rating.CreatedAt); err != nil {
+		return nil, err
+	}
+	rating.TableID = int(tableID.Int64)
+	if orderID.Valid {
+		id := int(orderID.Int64)
+		rating.OrderID = &id
+	}
+	return &rating, nil
+}
+
+func (r *PostgresRepository) SetVisitOrder(id, orderID int) error {
+	_, err := r.DB.Exec(`UPDATE visit_ratings SET order_id = $1 WHERE id = $2`, orderID, id)
+	return err
+}
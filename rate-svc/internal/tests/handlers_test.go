@@ -16,6 +16,7 @@ import (
 	"github.com/gorilla/mux"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"golang.org/x/text/language"
 )
 
 func setupTestRouter(mockSvc *mocks.ReviewServiceInterface) *mux.Router {
@@ -86,9 +87,10 @@ func TestHandler_getDishReviews(t *testing.T) {
 		{DishID: 1, OrderID: 100, Rating: 4},
 	}
 
-	mockSvc.On("ListDishReviews", 1, 10).Return(expectedReviews, nil).Once()
+	mockSvc.On("ListDishReviews", 1, 10, []language.Tag{language.English}).Return(expectedReviews, nil).Once()
 
-	req := httptest.NewRequest("GET", "/api/restaurants/10/dishes/1/reviews", nil)
+	req := httptest.NewRequest("GET", "/api/restaurants/10/dishes/1/reviews?lang=en", nil)
+	req.Header.Set("Accept-Language", "de")
 	recorder := httptest.NewRecorder()
 	router.ServeHTTP(recorder, req)
 
@@ -6,12 +6,15 @@ import (
 	"testing"
 	"time"
 
+	"overcooked-simplified/i18n"
 	"overcooked-simplified/rate-svc/internal/domain"
 	"overcooked-simplified/rate-svc/internal/mocks"
 	"overcooked-simplified/rate-svc/internal/service"
 
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"golang.org/x/text/language"
 )
 
 func TestReviewService_CreateOrUpdate(t *testing.T) {
@@ -35,11 +38,11 @@ func TestReviewService_CreateOrUpdate(t *testing.T) {
 				DishID: 1, OrderID: 99, RestaurantID: 10, Rating: 5, Comment: "Great!",
 			},
 			prepareMocks: func() {
-				repository.On("ValidateDishInOrder", 1, 99, 10).Return(true, nil).Once()
+				repository.On("ValidateDishInOrder", ctx, 1, 99, 10).Return(true, nil).Once()
 				cache.On("ReviewMarkerKey", 1, 99).Return("review:1:99").Once()
 				cache.On("Exists", ctx, "review:1:99").Return(false, nil).Once()
-				repository.On("GetExistingReviewID", 1, 99, 10).Return(0, errors.New("not found")).Once()
-				repository.On("InsertReview", mock.Anything).Return(nil).Once()
+				repository.On("GetExistingReviewID", ctx, 1, 99, 10).Return(0, errors.New("not found")).Once()
+				repository.On("InsertReview", ctx, mock.Anything).Return(nil).Once()
 				cache.On("SetMarker", ctx, "review:1:99").Return(nil).Once()
 				publisher.On("PublishReview", ctx, mock.Anything).Return(nil).Once()
 			},
@@ -51,7 +54,7 @@ func TestReviewService_CreateOrUpdate(t *testing.T) {
 				DishID: 2, OrderID: 99, RestaurantID: 10, Rating: 3,
 			},
 			prepareMocks: func() {
-				repository.On("ValidateDishInOrder", 2, 99, 10).Return(false, nil).Once()
+				repository.On("ValidateDishInOrder", ctx, 2, 99, 10).Return(false, nil).Once()
 			},
 			expectedError: service.ErrDishNotInOrder,
 		},
@@ -61,7 +64,7 @@ func TestReviewService_CreateOrUpdate(t *testing.T) {
 				DishID: 3, OrderID: 99, RestaurantID: 10, Rating: 4,
 			},
 			prepareMocks: func() {
-				repository.On("ValidateDishInOrder", 3, 99, 10).Return(true, nil).Once()
+				repository.On("ValidateDishInOrder", ctx, 3, 99, 10).Return(true, nil).Once()
 				cache.On("ReviewMarkerKey", 3, 99).Return("review:3:99").Once()
 				cache.On("Exists", ctx, "review:3:99").Return(true, nil).Once()
 			},
@@ -73,11 +76,11 @@ func TestReviewService_CreateOrUpdate(t *testing.T) {
 				DishID: 4, OrderID: 99, RestaurantID: 10, Rating: 5, Comment: "Updated",
 			},
 			prepareMocks: func() {
-				repository.On("ValidateDishInOrder", 4, 99, 10).Return(true, nil).Once()
+				repository.On("ValidateDishInOrder", ctx, 4, 99, 10).Return(true, nil).Once()
 				cache.On("ReviewMarkerKey", 4, 99).Return("review:4:99").Once()
 				cache.On("Exists", ctx, "review:4:99").Return(false, nil).Once()
-				repository.On("GetExistingReviewID", 4, 99, 10).Return(42, nil).Once()
-				repository.On("UpdateReview", 42, mock.Anything).Return(nil).Once()
+				repository.On("GetExistingReviewID", ctx, 4, 99, 10).Return(42, nil).Once()
+				repository.On("UpdateReview", ctx, 42, mock.Anything).Return(nil).Once()
 				cache.On("SetMarker", ctx, "review:4:99").Return(nil).Once()
 				publisher.On("PublishReview", ctx, mock.Anything).Return(nil).Once()
 			},
@@ -94,6 +97,40 @@ func TestReviewService_CreateOrUpdate(t *testing.T) {
 	}
 }
 
+// ratingCount is how many ratings the review_rating histogram has seen.
+func ratingCount(t *testing.T) uint64 {
+	families, err := prometheus.DefaultGatherer.Gather()
+	assert.NoError(t, err)
+	for _, family := range families {
+		if family.GetName() == "review_rating" {
+			return family.GetMetric()[0].GetHistogram().GetSampleCount()
+		}
+	}
+	return 0
+}
+
+func TestReviewService_RatingObservedOncePerReview(t *testing.T) {
+	repository := mocks.NewReviewRepository(t)
+	cache := mocks.NewReviewCache(t)
+	svc := service.NewReviewService(repository, cache, nil)
+	ctx := context.Background()
+
+	repository.On("ValidateDishInOrder", ctx, 5, 99, 10).Return(true, nil)
+	cache.On("ReviewMarkerKey", 5, 99).Return("review:5:99")
+	cache.On("SetMarker", ctx, "review:5:99").Return(nil)
+	repository.On("GetExistingReviewID", ctx, 5, 99, 10).Return(0, errors.New("not found")).Once()
+	repository.On("InsertReview", ctx, mock.Anything).Return(nil).Once()
+	repository.On("GetExistingReviewID", ctx, 5, 99, 10).Return(43, nil)
+	repository.On("UpdateReview", ctx, 43, mock.Anything).Return(nil)
+
+	before := ratingCount(t)
+	assert.NoError(t, svc.CreateOrUpdate(ctx, &domain.Review{DishID: 5, OrderID: 99, RestaurantID: 10, Rating: 2}))
+	assert.NoError(t, svc.CreateOrUpdate(ctx, &domain.Review{DishID: 5, OrderID: 99, RestaurantID: 10, Rating: 4}))
+	assert.NoError(t, svc.CreateOrUpdate(ctx, &domain.Review{DishID: 5, OrderID: 99, RestaurantID: 10, Rating: 5}))
+
+	assert.Equal(t, before+1, ratingCount(t), "edits of a review are not rated again")
+}
+
 func TestReviewService_ListDishReviews(t *testing.T) {
 	repository := mocks.NewReviewRepository(t)
 	cache := mocks.NewReviewCache(t)
@@ -107,9 +144,23 @@ func TestReviewService_ListDishReviews(t *testing.T) {
 	}
 
 	repository.On("ListDishReviews", 1, 10).Return(expectedReviews, nil).Once()
+	repository.On("DishNames", []int{1}).Return(map[int]i18n.Names{
+		1: {Default: "Плов", Locale: "ru-RU", Translations: map[string]string{"en": "Pilaf", "uz-Latn": "Palov"}},
+	}, nil).Once()
 
-	reviews, err := svc.ListDishReviews(1, 10)
+	reviews, err := svc.ListDishReviews(1, 10, []language.Tag{language.MustParse("en-GB"), language.Russian})
 	assert.NoError(t, err)
 	assert.Len(t, reviews, 2)
 	assert.Equal(t, expectedReviews, reviews)
+	assert.Equal(t, "Pilaf", reviews[0].DishName)
+	assert.Equal(t, "Pilaf", reviews[1].DishName)
+}
+
+func TestDishNamesFallBackToRestaurantLocale(t *testing.T) {
+	names := i18n.Names{Default: "Плов", Locale: "ru-RU", Translations: map[string]string{"en": "Pilaf"}}
+
+	assert.Equal(t, "Плов", names.In(nil))
+	assert.Equal(t, "Плов", names.In([]language.Tag{language.German}))
+	assert.Equal(t, "Плов", names.In([]language.Tag{language.Russian, language.English}))
+	assert.Equal(t, "Pilaf", names.In([]language.Tag{language.AmericanEnglish}))
 }
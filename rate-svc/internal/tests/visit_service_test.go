@@ -0,0 +1,100 @@
+package tests
+
+import (
+	"database/sql"
+	"testing"
+
+	"overcooked-simplified/rate-svc/internal/domain"
+	"overcooked-simplified/rate-svc/internal/mocks"
+	"overcooked-simplified/rate-svc/internal/service"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func intPtr(v int) *int { return &v }
+
+func TestVisitService_Rate(t *testing.T) {
+	tests := []struct {
+		name         string
+		rating       domain.VisitRating
+		prepareMocks func(*mocks.VisitRepository)
+		wantErr      error
+	}{
+		{
+			name:   "before the order",
+			rating: domain.VisitRating{TableCode: " ABCDEFGH", Rating: 4, Comment: "Уютно"},
+			prepareMocks: func(repository *mocks.VisitRepository) {
+				repository.On("FindTable", "abcdefgh").Return(3, 10, nil).Once()
+				repository.On("InsertVisitRating", mock.MatchedBy(func(r *domain.VisitRating) bool {
+					return r.TableID == 3 && r.RestaurantID == 10 && r.OrderID == nil
+				})).Return(nil).Once()
+			},
+		},
+		{
+			name:   "with an order of the table",
+			rating: domain.VisitRating{TableCode: "abcdefgh", Rating: 5, OrderID: intPtr(99)},
+			prepareMocks: func(repository *mocks.VisitRepository) {
+				repository.On("FindTable", "abcdefgh").Return(3, 10, nil).Once()
+				repository.On("OrderTable", 99, 10).Return(3, nil).Once()
+				repository.On("InsertVisitRating", mock.Anything).Return(nil).Once()
+			},
+		},
+		{
+			name:   "with an order of another table",
+			rating: domain.VisitRating{TableCode: "abcdefgh", Rating: 5, OrderID: intPtr(98)},
+			prepareMocks: func(repository *mocks.VisitRepository) {
+				repository.On("FindTable", "abcdefgh").Return(3, 10, nil).Once()
+				repository.On("OrderTable", 98, 10).Return(4, nil).Once()
+			},
+			wantErr: service.ErrOrderNotAtTable,
+		},
+		{
+			name:         "rating out of range",
+			rating:       domain.VisitRating{TableCode: "abcdefgh", Rating: 6},
+			prepareMocks: func(repository *mocks.VisitRepository) {},
+			wantErr:      service.ErrInvalidRating,
+		},
+		{
+			name:   "unknown table",
+			rating: domain.VisitRating{TableCode: "zzzzzzzz", Rating: 3},
+			prepareMocks: func(repository *mocks.VisitRepository) {
+				repository.On("FindTable", "zzzzzzzz").Return(0, 0, sql.ErrNoRows).Once()
+			},
+			wantErr: service.ErrUnknownTable,
+		},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			repository := mocks.NewVisitRepository(t)
+			testCase.prepareMocks(repository)
+			svc := service.NewVisitService(repository)
+
+			rating := testCase.rating
+			err := svc.Rate(&rating)
+
+			if testCase.wantErr != nil {
+				assert.ErrorIs(t, err, testCase.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestVisitService_LinkOrder(t *testing.T) {
+	repository := mocks.NewVisitRepository(t)
+	svc := service.NewVisitService(repository)
+
+	repository.On("GetVisitRating", 1).Return(&domain.VisitRating{ID: 1, RestaurantID: 10, TableID: 3, Rating: 4}, nil).Once()
+	repository.On("OrderTable", 99, 10).Return(3, nil).Once()
+	repository.On("SetVisitOrder", 1, 99).Return(nil).Once()
+	rating, err := svc.LinkOrder(1, 99)
+	assert.NoError(t, err)
+	assert.Equal(t, intPtr(99), rating.OrderID)
+
+	repository.On("GetVisitRating", 2).Return(&domain.VisitRating{ID: 2, RestaurantID: 10, TableID: 3, OrderID: intPtr(50)}, nil).Once()
+	_, err = svc.LinkOrder(2, 99)
+	assert.ErrorIs(t, err, service.ErrVisitLinked)
+}
@@ -1,23 +1,45 @@
 package main
 
 import (
+	"context"
+	"log"
+	"os"
 	httpapi "overcooked-simplified/rate-svc/internal/api/http"
 	"overcooked-simplified/rate-svc/internal/service"
 	"overcooked-simplified/rate-svc/internal/storage"
 	"time"
 
 	"overcooked-simplified/config"
+	"overcooked-simplified/database"
+	"overcooked-simplified/health"
+	"overcooked-simplified/server"
+	"overcooked-simplified/tracing"
 )
 
 func main() {
-	db := config.MustInitPostgres()
-	defer db.Close()
+	cfg := config.MustLoad(":8082", config.NeedPostgres|config.NeedRedis|config.NeedKafka)
 
-	rdb := config.MustInitRedis()
-	defer rdb.Close()
+	shutdownTracing, err := tracing.Init("rate-svc", cfg.Tracing)
+	if err != nil {
+		log.Fatal("Failed to set up tracing: ", err)
+	}
 
-	kafkaWriter := config.NewKafkaWriter("reviews")
-	defer kafkaWriter.Close()
+	db := config.MustInitPostgres(cfg.Postgres, cfg.Startup)
+
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		err := database.RunCommand(db, os.Args[2:])
+		db.Close()
+		if err != nil {
+			log.Fatal("Migration failed: ", err)
+		}
+		return
+	}
+	database.MustMigrate(db, cfg.Migrate.OnStart, cfg.Migrate.SeedDemoData)
+
+	rdb := config.MustInitRedis(cfg.Redis, cfg.Startup)
+
+	config.MustWaitForKafka(cfg.Kafka, cfg.Startup)
+	kafkaWriter := config.NewKafkaWriter(cfg.Kafka, "reviews")
 
 	repository := storage.NewPostgresRepository(db)
 	cache := storage.NewRedisCache(rdb, 24*7*time.Hour)
@@ -25,7 +47,20 @@ func main() {
 	reviewService := service.NewReviewService(repository, cache, publisher)
 
 	handler := httpapi.NewHandler(reviewService)
+	handler.Visits = service.NewVisitService(repository)
+	handler.Health = health.NewChecker("rate-svc")
+	handler.Health.Add("postgres", db.PingContext)
+	handler.Health.Add("redis", func(ctx context.Context) error { return rdb.Ping(ctx).Err() })
+	handler.Health.Add("kafka", func(ctx context.Context) error { return config.PingKafka(ctx, cfg.Kafka) })
 	router := httpapi.NewRouter(handler)
 
-	httpapi.StartServer(":8082", router)
+	srv := server.New("rate-svc", cfg.HTTP, router)
+	srv.Health = handler.Health
+	srv.OnShutdown("kafka writer", kafkaWriter.Close)
+	srv.OnShutdown("redis", rdb.Close)
+	srv.OnShutdown("postgres", db.Close)
+	srv.OnShutdown("tracing", func() error { return shutdownTracing(context.Background()) })
+	if err := srv.Run(); err != nil {
+		log.Fatal("Server stopped with errors: ", err)
+	}
 }
@@ -0,0 +1,138 @@
+// Package server runs the HTTP side of a service: timeouts and header
+// limits on every connection, and an orderly stop on SIGINT or SIGTERM that
+// drains traffic, lets requests in flight finish and then closes the
+// service's clients.
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"overcooked-simplified/config"
+	"overcooked-simplified/health"
+)
+
+type closer struct {
+	name  string
+	close func() error
+}
+
+type Server struct {
+	Name string
+	HTTP *http.Server
+	// Health, when set, starts failing /readyz as soon as shutdown begins.
+	Health *health.Checker
+
+	drainPeriod     time.Duration
+	shutdownTimeout time.Duration
+	closers         []closer
+	served          atomic.Int64
+	inFlight        atomic.Int64
+}
+
+func New(name string, cfg config.HTTPConfig, handler http.Handler) *Server {
+	s := &Server{
+		Name:            name,
+		drainPeriod:     cfg.DrainPeriod,
+		shutdownTimeout: cfg.ShutdownTimeout,
+	}
+	s.HTTP = &http.Server{
+		Addr:              cfg.Addr,
+		Handler:           s.count(handler),
+		ReadHeaderTimeout: cfg.ReadHeaderTimeout,
+		ReadTimeout:       cfg.ReadTimeout,
+		WriteTimeout:      cfg.WriteTimeout,
+		IdleTimeout:       cfg.IdleTimeout,
+		MaxHeaderBytes:    cfg.MaxHeaderBytes,
+	}
+	return s
+}
+
+// OnShutdown adds a client to close once the server has stopped. Clients
+// are closed in the order they were added, so add whatever writes through
+// the others first: the Kafka writer before the pools, and the tracer
+// provider last so it flushes the spans of everything closed before it.
+func (s *Server) OnShutdown(name string, close func() error) {
+	s.closers = append(s.closers, closer{name: name, close: close})
+}
+
+// Run serves until SIGINT or SIGTERM and then shuts down. It returns an
+// error if the server could not start or did not stop cleanly.
+func (s *Server) Run() error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	listener, err := net.Listen("tcp", s.HTTP.Addr)
+	if err != nil {
+		return err
+	}
+	return s.Serve(ctx, listener)
+}
+
+// Serve is Run on a listener that is already open, stopping when ctx ends.
+func (s *Server) Serve(ctx context.Context, listener net.Listener) error {
+	started := time.Now()
+	failed := make(chan error, 1)
+	go func() {
+		if err := s.HTTP.Serve(listener); !errors.Is(err, http.ErrServerClosed) {
+			failed <- err
+		}
+	}()
+	log.Printf("%s starting on %s", s.Name, listener.Addr())
+
+	var errs []error
+	select {
+	case err := <-failed:
+		errs = append(errs, fmt.Errorf("serve: %w", err))
+	case <-ctx.Done():
+		log.Printf("%s shutting down, draining for %s", s.Name, s.drainPeriod)
+		if s.Health != nil {
+			s.Health.Drain()
+		}
+		time.Sleep(s.drainPeriod)
+	}
+
+	stopping := time.Now()
+	inFlight := s.inFlight.Load()
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), s.shutdownTimeout)
+	defer cancel()
+	if err := s.HTTP.Shutdown(shutdownCtx); err != nil {
+		errs = append(errs, fmt.Errorf("shutdown: %w", err))
+	}
+	cutOff := s.inFlight.Load()
+
+	var closed []string
+	for _, c := range s.closers {
+		if err := c.close(); err != nil {
+			errs = append(errs, fmt.Errorf("close %s: %w", c.name, err))
+			continue
+		}
+		closed = append(closed, c.name)
+	}
+
+	log.Printf("%s stopped after %s: %d requests served, %d in flight at shutdown, %d cut off, closed [%s] in %s",
+		s.Name, time.Since(started).Round(time.Second), s.served.Load(), inFlight, cutOff,
+		strings.Join(closed, ", "), time.Since(stopping).Round(time.Millisecond))
+	return errors.Join(errs...)
+}
+
+func (s *Server) count(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.inFlight.Add(1)
+		defer func() {
+			s.inFlight.Add(-1)
+			s.served.Add(1)
+		}()
+		next.ServeHTTP(w, r)
+	})
+}
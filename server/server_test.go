@@ -0,0 +1,78 @@
+package server_test
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"overcooked-simplified/config"
+	"overcooked-simplified/health"
+	"overcooked-simplified/server"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServerGracefulShutdown(t *testing.T) {
+	checker := health.NewChecker("dish-svc")
+	started := make(chan struct{})
+	router := mux.NewRouter()
+	checker.RegisterRoutes(router)
+	router.HandleFunc("/slow", func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		time.Sleep(100 * time.Millisecond)
+		w.Write([]byte("done"))
+	})
+
+	srv := server.New("dish-svc", config.HTTPConfig{
+		ReadHeaderTimeout: time.Second,
+		ReadTimeout:       time.Second,
+		WriteTimeout:      time.Second,
+		IdleTimeout:       time.Second,
+		MaxHeaderBytes:    1 << 16,
+		DrainPeriod:       50 * time.Millisecond,
+		ShutdownTimeout:   time.Second,
+	}, router)
+	srv.Health = checker
+	var closed []string
+	srv.OnShutdown("kafka writer", func() error { closed = append(closed, "kafka writer"); return nil })
+	srv.OnShutdown("postgres", func() error { closed = append(closed, "postgres"); return nil })
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	ctx, shutdown := context.WithCancel(context.Background())
+	stopped := make(chan error, 1)
+	go func() { stopped <- srv.Serve(ctx, listener) }()
+	baseURL := "http://" + listener.Addr().String()
+
+	slow := make(chan string, 1)
+	go func() {
+		resp, err := http.Get(baseURL + "/slow")
+		if err != nil {
+			slow <- err.Error()
+			return
+		}
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		slow <- string(body)
+	}()
+	<-started
+	shutdown()
+
+	assert.Eventually(t, func() bool {
+		resp, err := http.Get(baseURL + "/readyz")
+		if err != nil {
+			return false
+		}
+		resp.Body.Close()
+		return resp.StatusCode == http.StatusServiceUnavailable
+	}, time.Second, 5*time.Millisecond, "readiness fails while draining")
+
+	assert.Equal(t, "done", <-slow, "requests in flight finish")
+	assert.NoError(t, <-stopped)
+	assert.Equal(t, []string{"kafka writer", "postgres"}, closed)
+}
@@ -25,10 +25,10 @@ echo.
 echo ============================
 echo Health checks...
 echo ============================
-curl -s http://localhost:8080/health > nul && echo ✅ API Gateway || echo ❌ API Gateway
-curl -s http://localhost:8081/health > nul && echo ✅ Dish Service || echo ❌ Dish Service
-curl -s http://localhost:8082/health > nul && echo ✅ Rate Service || echo ❌ Rate Service
-curl -s http://localhost:8084/health > nul && echo ✅ Analytics Service || echo ❌ Analytics Service
+curl -sf http://localhost:8080/readyz > nul && echo ✅ API Gateway || echo ❌ API Gateway
+curl -sf http://localhost:8081/readyz > nul && echo ✅ Dish Service || echo ❌ Dish Service
+curl -sf http://localhost:8082/readyz > nul && echo ✅ Rate Service || echo ❌ Rate Service
+curl -sf http://localhost:8084/readyz > nul && echo ✅ Analytics Service || echo ❌ Analytics Service
 
 echo.
 echo Tests completed.
@@ -0,0 +1,59 @@
+package tracing
+
+import (
+	"context"
+
+	"github.com/segmentio/kafka-go"
+	"go.opentelemetry.io/otel"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// headerCarrier reads and writes the trace context in Kafka message headers.
+type headerCarrier struct {
+	message *kafka.Message
+}
+
+func (c headerCarrier) Get(key string) string {
+	for _, header := range c.message.Headers {
+		if header.Key == key {
+			return string(header.Value)
+		}
+	}
+	return ""
+}
+
+func (c headerCarrier) Set(key, value string) {
+	for i, header := range c.message.Headers {
+		if header.Key == key {
+			c.message.Headers[i].Value = []byte(value)
+			return
+		}
+	}
+	c.message.Headers = append(c.message.Headers, kafka.Header{Key: key, Value: []byte(value)})
+}
+
+func (c headerCarrier) Keys() []string {
+	keys := make([]string, len(c.message.Headers))
+	for i, header := range c.message.Headers {
+		keys[i] = header.Key
+	}
+	return keys
+}
+
+// StartPublish starts a producer span and puts its trace context into the
+// headers of message. Call the returned function with the write's error.
+func StartPublish(ctx context.Context, topic string, message *kafka.Message) func(error) {
+	ctx, span := tracer().Start(ctx, topic+" publish", trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(semconv.MessagingSystemKafka, semconv.MessagingDestinationName(topic), semconv.MessagingOperationTypePublish))
+	otel.GetTextMapPropagator().Inject(ctx, headerCarrier{message})
+	return func(err error) { End(span, err) }
+}
+
+// StartConsume starts a consumer span for message, continuing the trace its
+// producer put in the headers. End the span when the message is handled.
+func StartConsume(ctx context.Context, message kafka.Message) (context.Context, trace.Span) {
+	ctx = otel.GetTextMapPropagator().Extract(ctx, headerCarrier{&message})
+	return tracer().Start(ctx, message.Topic+" process", trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(semconv.MessagingSystemKafka, semconv.MessagingDestinationName(message.Topic), semconv.MessagingOperationTypeDeliver))
+}
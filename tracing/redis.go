@@ -0,0 +1,49 @@
+package tracing
+
+import (
+	"context"
+
+	"github.com/redis/go-redis/v9"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// RedisHook adds a client span for every command run within a trace.
+type RedisHook struct{}
+
+var _ redis.Hook = RedisHook{}
+
+func (RedisHook) DialHook(next redis.DialHook) redis.DialHook {
+	return next
+}
+
+func (RedisHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		if !trace.SpanFromContext(ctx).SpanContext().IsValid() {
+			return next(ctx, cmd)
+		}
+		ctx, span := tracer().Start(ctx, "redis "+cmd.Name(), trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(semconv.DBSystemRedis, semconv.DBOperationName(cmd.Name())))
+		err := next(ctx, cmd)
+		if err == redis.Nil {
+			// a missing key is an answer, not a failure
+			End(span, nil)
+		} else {
+			End(span, err)
+		}
+		return err
+	}
+}
+
+func (RedisHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		if !trace.SpanFromContext(ctx).SpanContext().IsValid() {
+			return next(ctx, cmds)
+		}
+		ctx, span := tracer().Start(ctx, "redis pipeline", trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(semconv.DBSystemRedis, semconv.DBOperationName("pipeline")))
+		err := next(ctx, cmds)
+		End(span, err)
+		return err
+	}
+}
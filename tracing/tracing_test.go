@@ -0,0 +1,122 @@
+package tracing_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"overcooked-simplified/tracing"
+
+	"github.com/gorilla/mux"
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func recordSpans(t *testing.T) *tracetest.SpanRecorder {
+	_, err := tracing.Init("dish-svc", tracing.Config{Exporter: "none"})
+	require.NoError(t, err)
+	recorder := tracetest.NewSpanRecorder()
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+	return recorder
+}
+
+// fakeConn answers every statement with an empty result.
+type fakeConn struct{}
+
+func (fakeConn) Prepare(string) (driver.Stmt, error) { return nil, driver.ErrSkip }
+func (fakeConn) Close() error                        { return nil }
+func (fakeConn) Begin() (driver.Tx, error)           { return nil, driver.ErrSkip }
+func (fakeConn) ExecContext(context.Context, string, []driver.NamedValue) (driver.Result, error) {
+	return driver.RowsAffected(0), nil
+}
+
+type fakeConnector struct{}
+
+func (fakeConnector) Connect(context.Context) (driver.Conn, error) { return fakeConn{}, nil }
+func (fakeConnector) Driver() driver.Driver                        { return nil }
+
+func TestTracing(t *testing.T) {
+	t.Run("server span continues the caller's trace", func(t *testing.T) {
+		recorder := recordSpans(t)
+		router := mux.NewRouter()
+		tracing.Instrument(router)
+		router.HandleFunc("/api/restaurants/{id}", func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		})
+
+		req := httptest.NewRequest(http.MethodGet, "/api/restaurants/7", nil)
+		req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+		router.ServeHTTP(httptest.NewRecorder(), req)
+
+		spans := recorder.Ended()
+		require.Len(t, spans, 1)
+		assert.Equal(t, "GET /api/restaurants/{id}", spans[0].Name())
+		assert.Equal(t, trace.SpanKindServer, spans[0].SpanKind())
+		assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", spans[0].SpanContext().TraceID().String())
+		assert.Equal(t, "00f067aa0ba902b7", spans[0].Parent().SpanID().String())
+		assert.Equal(t, "Error", spans[0].Status().Code.String())
+	})
+
+	t.Run("postgres statements are traced within a trace only", func(t *testing.T) {
+		recorder := recordSpans(t)
+		db := sql.OpenDB(tracing.Connector(fakeConnector{}))
+		defer db.Close()
+
+		_, err := db.ExecContext(context.Background(), "UPDATE dishes SET price = 1")
+		require.NoError(t, err)
+		assert.Empty(t, recorder.Ended(), "no span without a parent")
+
+		ctx, parent := otel.Tracer("test").Start(context.Background(), "PUT /api/dishes/{id}")
+		_, err = db.ExecContext(ctx, "\n\t\tUPDATE dishes SET price = 2")
+		require.NoError(t, err)
+		parent.End()
+
+		spans := recorder.Ended()
+		require.Len(t, spans, 2)
+		assert.Equal(t, "postgres update", spans[0].Name())
+		assert.Equal(t, trace.SpanKindClient, spans[0].SpanKind())
+		assert.Equal(t, parent.SpanContext().SpanID(), spans[0].Parent().SpanID())
+	})
+
+	t.Run("proxied request carries the trace", func(t *testing.T) {
+		recordSpans(t)
+		ctx, parent := otel.Tracer("test").Start(context.Background(), "gateway")
+		defer parent.End()
+
+		req := httptest.NewRequest(http.MethodPost, "http://rate-svc:8082/api/reviews", nil).WithContext(ctx)
+		req, span := tracing.StartClient(req, http.MethodPost)
+		tracing.EndClient(span, &http.Response{StatusCode: http.StatusOK}, nil)
+
+		assert.Contains(t, req.Header.Get("traceparent"), parent.SpanContext().TraceID().String())
+	})
+
+	t.Run("kafka headers link consumer to producer", func(t *testing.T) {
+		recorder := recordSpans(t)
+		ctx, parent := otel.Tracer("test").Start(context.Background(), "POST /api/reviews")
+
+		message := kafka.Message{Topic: "reviews", Value: []byte(`{}`)}
+		done := tracing.StartPublish(ctx, "reviews", &message)
+		done(nil)
+		parent.End()
+		_, span := tracing.StartConsume(context.Background(), message)
+		span.End()
+
+		spans := recorder.Ended()
+		require.Len(t, spans, 3)
+		producer, consumer := spans[0], spans[2]
+		assert.Equal(t, trace.SpanKindProducer, producer.SpanKind())
+		assert.Equal(t, trace.SpanKindConsumer, consumer.SpanKind())
+		assert.Equal(t, parent.SpanContext().TraceID(), consumer.SpanContext().TraceID())
+		assert.Equal(t, producer.SpanContext().SpanID(), consumer.Parent().SpanID())
+	})
+}